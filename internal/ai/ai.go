@@ -0,0 +1,146 @@
+// Package ai generates text with the backend chosen in settings: the claude
+// CLI, or the Anthropic, OpenAI or Ollama APIs.
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ihatemodels/gdev/internal/store"
+)
+
+// Backend generates a response to a prompt.
+type Backend interface {
+	// Name is the backend's name in settings, e.g. "ollama".
+	Name() string
+
+	// Model is the model asked, "default" for the backend's own default.
+	Model() string
+
+	// Check returns why the backend can't be used, such as a missing CLI
+	// or API key, or nil if it can.
+	Check() error
+
+	// CacheKey returns the key the response to prompt is cached under.
+	CacheKey(prompt string) store.CacheKey
+
+	// Generate writes the response to prompt to w as it is generated. It
+	// stops when ctx is canceled.
+	Generate(ctx context.Context, prompt string, w io.Writer) error
+}
+
+// Backends are the names of the available backends, the default first.
+var Backends = []string{"claude", "anthropic", "openai", "ollama"}
+
+// New returns the backend called name, asking model, or the backend's
+// default model if it is "".
+func New(name, model string) (Backend, error) {
+	switch name {
+	case "", "claude":
+		return claudeCLI{model: model}, nil
+	case "anthropic":
+		return anthropic{model: withDefault(model, "claude-sonnet-4-5")}, nil
+	case "openai":
+		return openAI{model: withDefault(model, "gpt-4o-mini")}, nil
+	case "ollama":
+		return ollama{model: withDefault(model, "llama3.2")}, nil
+	}
+	return nil, fmt.Errorf("unknown AI backend %q (one of %s)", name, strings.Join(Backends, ", "))
+}
+
+// Describe returns the backend and model for display, e.g. "ollama llama3.2".
+func Describe(b Backend) string {
+	return b.Name() + " " + b.Model()
+}
+
+func withDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
+
+// cacheKey returns the cache key of a request to an API backend.
+func cacheKey(b Backend, prompt string) store.CacheKey {
+	h := sha256.Sum256([]byte(prompt))
+	return store.CacheKey{Provider: b.Name(), Model: b.Model(), Hash: hex.EncodeToString(h[:])}
+}
+
+// client makes the API requests. The proxy and certificate settings reach
+// it through the environment, like they reach gh and git.
+var client = &http.Client{Timeout: 2 * time.Minute}
+
+// post posts req as JSON to url with headers. Error responses are
+// returned with their body.
+func post(ctx context.Context, url string, headers map[string]string, req any) (*http.Response, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	httpResp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if httpResp.StatusCode/100 != 2 {
+		defer httpResp.Body.Close()
+		data, _ := io.ReadAll(httpResp.Body)
+		return nil, fmt.Errorf("%s: %s", httpResp.Status, strings.TrimSpace(string(data)))
+	}
+	return httpResp, nil
+}
+
+// postStream posts req as JSON to url with headers and calls fn with each
+// line of the response as it arrives, until fn returns errStreamDone or
+// an error.
+func postStream(ctx context.Context, url string, headers map[string]string, req any, fn func(line string) error) error {
+	resp, err := post(ctx, url, headers, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return readLines(resp.Body, fn)
+}
+
+// errStreamDone stops reading a stream before it ends.
+var errStreamDone = errors.New("stream done")
+
+// readLines calls fn with each line read from r, until fn returns
+// errStreamDone or an error.
+func readLines(r io.Reader, fn func(line string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if err := fn(scanner.Text()); err == errStreamDone {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// sseData returns the data of a server-sent event line, and whether the
+// line holds data.
+func sseData(line string) (string, bool) {
+	data, ok := strings.CutPrefix(line, "data:")
+	return strings.TrimSpace(data), ok
+}
@@ -0,0 +1,95 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/messages":
+			fmt.Fprint(w, "event: message_start\ndata: {\"type\":\"message_start\"}\n\n")
+			for _, text := range []string{"feat: add ", "anthropic"} {
+				fmt.Fprintf(w, "event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":%q}}\n\n", text)
+			}
+			fmt.Fprint(w, "event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n")
+		case "/chat/completions":
+			for _, text := range []string{"feat: add ", "openai"} {
+				fmt.Fprintf(w, "data: {\"choices\":[{\"delta\":{\"content\":%q}}]}\n\n", text)
+			}
+			fmt.Fprint(w, "data: [DONE]\n\n")
+		case "/api/generate":
+			fmt.Fprint(w, "{\"response\":\"feat: add \",\"done\":false}\n{\"response\":\"ollama\",\"done\":true}\n")
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	t.Setenv("ANTHROPIC_BASE_URL", srv.URL)
+	t.Setenv("ANTHROPIC_API_KEY", "key")
+	t.Setenv("OPENAI_BASE_URL", srv.URL)
+	t.Setenv("OPENAI_API_KEY", "key")
+	t.Setenv("OLLAMA_HOST", strings.TrimPrefix(srv.URL, "http://"))
+
+	for _, name := range []string{"anthropic", "openai", "ollama"} {
+		b, err := New(name, "")
+		if err != nil {
+			t.Fatalf("New(%q) error = %v", name, err)
+		}
+		if err := b.Check(); err != nil {
+			t.Errorf("%s: Check() = %v", name, err)
+		}
+		var out strings.Builder
+		if err := b.Generate(context.Background(), "prompt", &out); err != nil || out.String() != "feat: add "+name {
+			t.Errorf("%s: Generate() = %q, %v; want %q", name, out.String(), err, "feat: add "+name)
+		}
+	}
+
+	if _, err := New("gpt-cli", ""); err == nil {
+		t.Error("New(gpt-cli) succeeded; want an unknown backend error")
+	}
+}
+
+func TestNewWithFallback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/generate":
+			http.Error(w, "model not loaded", http.StatusServiceUnavailable)
+		case "/chat/completions":
+			fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"```result\\nfeat: add openai\\n```\"}}]}\n\ndata: [DONE]\n\n")
+		}
+	}))
+	defer srv.Close()
+
+	t.Setenv("OPENAI_BASE_URL", srv.URL)
+	t.Setenv("OPENAI_API_KEY", "key")
+	t.Setenv("OLLAMA_HOST", strings.TrimPrefix(srv.URL, "http://"))
+
+	b, err := NewWithFallback("ollama", "", "openai", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	if err := b.Generate(context.Background(), "prompt", &out); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if got := Result(out.String()); got != "feat: add openai" {
+		t.Errorf("Result() = %q, want the fallback's message", got)
+	}
+	if note := FallbackNote(out.String()); !strings.HasPrefix(note, "openai gpt-4o-mini, as ollama llama3.2 failed: 503") {
+		t.Errorf("FallbackNote() = %q", note)
+	}
+
+	t.Setenv("OPENAI_API_KEY", "")
+	b, _ = NewWithFallback("ollama", "", "openai", "", 0)
+	if err := b.Generate(context.Background(), "prompt", io.Discard); err == nil {
+		t.Error("Generate() succeeded with neither backend working")
+	}
+}
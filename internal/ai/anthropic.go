@@ -0,0 +1,77 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/ihatemodels/gdev/internal/store"
+)
+
+// anthropic calls the Anthropic Messages API with ANTHROPIC_API_KEY.
+type anthropic struct {
+	model string
+}
+
+func (a anthropic) Name() string { return "anthropic" }
+
+func (a anthropic) Model() string { return a.model }
+
+func (a anthropic) Check() error {
+	if os.Getenv("ANTHROPIC_API_KEY") == "" {
+		return errors.New("ANTHROPIC_API_KEY is not set")
+	}
+	return nil
+}
+
+func (a anthropic) CacheKey(prompt string) store.CacheKey { return cacheKey(a, prompt) }
+
+func (a anthropic) Generate(ctx context.Context, prompt string, w io.Writer) error {
+	type message struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+	req := struct {
+		Model     string    `json:"model"`
+		MaxTokens int       `json:"max_tokens"`
+		Messages  []message `json:"messages"`
+		Stream    bool      `json:"stream"`
+	}{a.model, 1024, []message{{"user", prompt}}, true}
+
+	headers := map[string]string{
+		"x-api-key":         os.Getenv("ANTHROPIC_API_KEY"),
+		"anthropic-version": "2023-06-01",
+	}
+	url := withDefault(os.Getenv("ANTHROPIC_BASE_URL"), "https://api.anthropic.com") + "/v1/messages"
+	return postStream(ctx, url, headers, req, func(line string) error {
+		data, ok := sseData(line)
+		if !ok {
+			return nil
+		}
+		var event struct {
+			Type  string `json:"type"`
+			Delta struct {
+				Type string `json:"type"`
+				Text string `json:"text"`
+			} `json:"delta"`
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return err
+		}
+		switch {
+		case event.Type == "content_block_delta" && event.Delta.Type == "text_delta":
+			_, err := io.WriteString(w, event.Delta.Text)
+			return err
+		case event.Type == "message_stop":
+			return errStreamDone
+		case event.Type == "error":
+			return errors.New(event.Error.Message)
+		}
+		return nil
+	})
+}
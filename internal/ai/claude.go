@@ -0,0 +1,99 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os/exec"
+
+	"github.com/ihatemodels/gdev/internal/claude"
+	"github.com/ihatemodels/gdev/internal/store"
+)
+
+// claudeCLI runs the claude CLI, which uses the account it is logged in with.
+type claudeCLI struct {
+	model string
+}
+
+func (c claudeCLI) Name() string { return "claude" }
+
+func (c claudeCLI) Model() string { return withDefault(c.model, "default") }
+
+func (c claudeCLI) Check() error {
+	if _, err := exec.LookPath("claude"); err != nil {
+		return errors.New("the claude CLI is not installed")
+	}
+	return nil
+}
+
+// CacheKey is the key the claude package caches CLI responses under, so
+// responses cached before backends could be chosen are still found.
+func (c claudeCLI) CacheKey(prompt string) store.CacheKey {
+	return claude.CacheKey(c.args(prompt))
+}
+
+// Generate asks the CLI for stream-json with partial messages, and writes
+// their text as it arrives.
+func (c claudeCLI) Generate(ctx context.Context, prompt string, w io.Writer) error {
+	args := append(c.args(prompt), "--output-format", "stream-json", "--verbose", "--include-partial-messages")
+	cmd := exec.CommandContext(ctx, "claude", args...)
+	cmd.Stderr = w
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	streamed := false
+	var failed error
+	err = readLines(stdout, func(line string) error {
+		var event struct {
+			Type  string `json:"type"`
+			Event struct {
+				Type  string `json:"type"`
+				Delta struct {
+					Type string `json:"type"`
+					Text string `json:"text"`
+				} `json:"delta"`
+			} `json:"event"`
+			IsError bool   `json:"is_error"`
+			Result  string `json:"result"`
+		}
+		if json.Unmarshal([]byte(line), &event) != nil {
+			// Not an event, such as a warning
+			_, err := io.WriteString(w, line+"\n")
+			return err
+		}
+		switch {
+		case event.Type == "stream_event" && event.Event.Type == "content_block_delta" && event.Event.Delta.Type == "text_delta":
+			streamed = true
+			_, err := io.WriteString(w, event.Event.Delta.Text)
+			return err
+		case event.Type == "result" && event.IsError:
+			failed = errors.New(event.Result)
+		case event.Type == "result" && !streamed:
+			// Without partial messages before it, the result holds the response
+			_, err := io.WriteString(w, event.Result)
+			return err
+		}
+		return nil
+	})
+	if waitErr := cmd.Wait(); err == nil {
+		err = waitErr
+	}
+	if failed != nil {
+		return failed
+	}
+	return err
+}
+
+func (c claudeCLI) args(prompt string) []string {
+	args := []string{"-p", prompt}
+	if c.model != "" {
+		args = append(args, "--model", c.model)
+	}
+	return args
+}
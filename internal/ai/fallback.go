@@ -0,0 +1,148 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ihatemodels/gdev/internal/store"
+)
+
+// fallbackMarker starts the line a fallback chain writes before the
+// fallback's response, so readers of the output can tell it apart from
+// what the primary backend wrote before failing.
+const fallbackMarker = "↪ gdev fell back to "
+
+// NewWithFallback returns the backend called name, asking model, which
+// falls back to the one called fallback, asking fallbackModel, when it
+// fails or takes longer than timeout (0 for no limit). Without a fallback
+// it is the backend alone.
+func NewWithFallback(name, model, fallback, fallbackModel string, timeout time.Duration) (Backend, error) {
+	primary, err := New(name, model)
+	if err != nil || fallback == "" {
+		return primary, err
+	}
+	second, err := New(fallback, fallbackModel)
+	if err != nil {
+		return nil, fmt.Errorf("fallback: %w", err)
+	}
+	return chain{primary: primary, fallback: second, timeout: timeout}, nil
+}
+
+// chain asks primary, then fallback if primary can't be used, fails or
+// times out. It is named after primary, and its cache key is primary's;
+// responses fallback wrote aren't cached (see Cacheable).
+type chain struct {
+	primary, fallback Backend
+	timeout           time.Duration
+}
+
+func (c chain) Name() string { return c.primary.Name() }
+
+func (c chain) Model() string { return c.primary.Model() }
+
+// Check returns primary's problem if neither backend can be used.
+func (c chain) Check() error {
+	err := c.primary.Check()
+	if err != nil && c.fallback.Check() == nil {
+		return nil
+	}
+	return err
+}
+
+func (c chain) CacheKey(prompt string) store.CacheKey { return c.primary.CacheKey(prompt) }
+
+// Generate writes primary's response to w, or, if primary fails, a line
+// saying why (see FallbackNote) followed by fallback's. Stopping through
+// ctx doesn't fall back.
+func (c chain) Generate(ctx context.Context, prompt string, w io.Writer) error {
+	var reason string
+	if err := c.primary.Check(); err != nil {
+		reason = "can't be used: " + err.Error()
+	} else {
+		primaryCtx, cancel := withTimeout(ctx, c.timeout)
+		err := c.primary.Generate(primaryCtx, prompt, w)
+		timedOut := errors.Is(primaryCtx.Err(), context.DeadlineExceeded)
+		cancel()
+		switch {
+		case err == nil:
+			return nil
+		case ctx.Err() != nil:
+			return err
+		case timedOut:
+			reason = "timed out after " + c.timeout.String()
+		default:
+			reason = "failed: " + firstLine(err.Error())
+		}
+	}
+
+	if err := c.fallback.Check(); err != nil {
+		return fmt.Errorf("%s %s, and %s can't be used: %w", Describe(c.primary), reason, Describe(c.fallback), err)
+	}
+	if _, err := fmt.Fprintf(w, "\n%s%s, as %s %s\n", fallbackMarker, Describe(c.fallback), Describe(c.primary), reason); err != nil {
+		return err
+	}
+	if err := c.fallback.Generate(ctx, prompt, w); err != nil {
+		return fmt.Errorf("%s %s, then %s failed: %w", Describe(c.primary), reason, Describe(c.fallback), err)
+	}
+	return nil
+}
+
+// Chain returns the backends b may ask: b itself, or the backend it
+// falls back from and the one it falls back to.
+func Chain(b Backend) []Backend {
+	if c, ok := b.(chain); ok {
+		return []Backend{c.primary, c.fallback}
+	}
+	return []Backend{b}
+}
+
+// withTimeout returns ctx canceled after timeout, or only when canceled
+// itself if timeout is 0.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// FallbackNote returns which backend wrote the response in output, and
+// why, if the backend asked fell back to it, e.g. "ollama llama3.2, as
+// claude default timed out after 2m0s". It is "" if it didn't.
+func FallbackNote(output string) string {
+	i := strings.LastIndex(output, fallbackMarker)
+	if i < 0 {
+		return ""
+	}
+	return firstLine(output[i+len(fallbackMarker):])
+}
+
+// Cacheable reports whether output, the response of a backend, may be
+// cached under the key the backend gave for its prompt. Responses a
+// fallback wrote may not: the key is the primary's, which would then get
+// the fallback's response instead of being asked again.
+func Cacheable(output string) bool {
+	return FallbackNote(output) == ""
+}
+
+// afterFallback returns the part of output the fallback wrote, or all of
+// output if there was no fallback.
+func afterFallback(output string) string {
+	i := strings.LastIndex(output, fallbackMarker)
+	if i < 0 {
+		return output
+	}
+	if _, rest, ok := strings.Cut(output[i:], "\n"); ok {
+		return rest
+	}
+	return ""
+}
+
+// firstLine returns the first line of s, trimmed.
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(line)
+}
@@ -0,0 +1,57 @@
+package ai
+
+import (
+	"context"
+	"io"
+
+	"github.com/ihatemodels/gdev/internal/claude"
+)
+
+// Recorder records what a request to a backend used, in estimated tokens
+// and USD.
+type Recorder func(b Backend, tokens int, cost float64)
+
+// Metered returns b calling record after each response it generates,
+// including one cut short. Requests that wrote nothing, such as those that
+// couldn't connect, aren't recorded; nor are cached responses, as they
+// don't reach the backend. Each backend of a fallback chain is recorded
+// as itself.
+func Metered(b Backend, record Recorder) Backend {
+	if c, ok := b.(chain); ok {
+		c.primary, c.fallback = Metered(c.primary, record), Metered(c.fallback, record)
+		return c
+	}
+	return metered{Backend: b, record: record}
+}
+
+type metered struct {
+	Backend
+	record Recorder
+}
+
+func (m metered) Generate(ctx context.Context, prompt string, w io.Writer) error {
+	out := &countingWriter{w: w}
+	err := m.Backend.Generate(ctx, prompt, out)
+	if out.n > 0 {
+		u := claude.Usage{InputTokens: claude.EstimateTokens(prompt), OutputTokens: (out.n + 3) / 4}
+		model := m.Model()
+		if model == "default" {
+			model = claude.DefaultModel
+		}
+		// Models without a known price, such as local ones, cost nothing
+		m.record(m.Backend, u.Total(), claude.EstimateCost(model, u))
+	}
+	return err
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += n
+	return n, err
+}
@@ -0,0 +1,67 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ihatemodels/gdev/internal/store"
+)
+
+// ollama calls a local Ollama server, at OLLAMA_HOST if it is set.
+type ollama struct {
+	model string
+}
+
+func (o ollama) Name() string { return "ollama" }
+
+func (o ollama) Model() string { return o.model }
+
+// Check always succeeds; whether the server is running shows when generating.
+func (o ollama) Check() error { return nil }
+
+func (o ollama) CacheKey(prompt string) store.CacheKey { return cacheKey(o, prompt) }
+
+func (o ollama) Generate(ctx context.Context, prompt string, w io.Writer) error {
+	req := struct {
+		Model  string `json:"model"`
+		Prompt string `json:"prompt"`
+		Stream bool   `json:"stream"`
+	}{o.model, prompt, true}
+
+	return postStream(ctx, ollamaHost()+"/api/generate", nil, req, func(line string) error {
+		if strings.TrimSpace(line) == "" {
+			return nil
+		}
+		var chunk struct {
+			Response string `json:"response"`
+			Done     bool   `json:"done"`
+			Error    string `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(line), &chunk); err != nil {
+			return err
+		}
+		if chunk.Error != "" {
+			return errors.New(chunk.Error)
+		}
+		if _, err := io.WriteString(w, chunk.Response); err != nil {
+			return err
+		}
+		if chunk.Done {
+			return errStreamDone
+		}
+		return nil
+	})
+}
+
+// ollamaHost returns the URL of the Ollama server.
+func ollamaHost() string {
+	host := withDefault(os.Getenv("OLLAMA_HOST"), "http://localhost:11434")
+	if !strings.Contains(host, "://") {
+		host = "http://" + host
+	}
+	return strings.TrimRight(host, "/")
+}
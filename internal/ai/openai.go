@@ -0,0 +1,68 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/ihatemodels/gdev/internal/store"
+)
+
+// openAI calls the OpenAI Chat Completions API with OPENAI_API_KEY, or a
+// compatible API at OPENAI_BASE_URL.
+type openAI struct {
+	model string
+}
+
+func (o openAI) Name() string { return "openai" }
+
+func (o openAI) Model() string { return o.model }
+
+func (o openAI) Check() error {
+	if os.Getenv("OPENAI_API_KEY") == "" {
+		return errors.New("OPENAI_API_KEY is not set")
+	}
+	return nil
+}
+
+func (o openAI) CacheKey(prompt string) store.CacheKey { return cacheKey(o, prompt) }
+
+func (o openAI) Generate(ctx context.Context, prompt string, w io.Writer) error {
+	type message struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+	req := struct {
+		Model    string    `json:"model"`
+		Messages []message `json:"messages"`
+		Stream   bool      `json:"stream"`
+	}{o.model, []message{{"user", prompt}}, true}
+
+	headers := map[string]string{"Authorization": "Bearer " + os.Getenv("OPENAI_API_KEY")}
+	url := withDefault(os.Getenv("OPENAI_BASE_URL"), "https://api.openai.com/v1") + "/chat/completions"
+	return postStream(ctx, url, headers, req, func(line string) error {
+		data, ok := sseData(line)
+		if !ok {
+			return nil
+		}
+		if data == "[DONE]" {
+			return errStreamDone
+		}
+		var chunk struct {
+			Choices []struct {
+				Delta message `json:"delta"`
+			} `json:"choices"`
+		}
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return err
+		}
+		for _, c := range chunk.Choices {
+			if _, err := io.WriteString(w, c.Delta.Content); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,157 @@
+package ai
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// ResultFence is the info string of the fenced block holding the part of a
+// response gdev uses, when the prompt asks for one:
+//
+//	Here is the message:
+//
+//	```result
+//	feat: add timesheets
+//	```
+//
+// Text around the block is ignored. A result containing fenced code of its
+// own is still read whole, as nested fences have an info string or are
+// closed in pairs; a longer fence (````result) also works.
+const ResultFence = "result"
+
+// preambles start the lines models write before the answer they were asked
+// for, and chatter the ones after it, lowercased.
+var (
+	preambles = []string{"here is", "here's", "here are", "sure", "certainly", "okay", "ok,", "of course",
+		"based on", "looking at", "i've", "i'll", "i have", "i analyzed", "after reviewing"}
+	chatter = []string{"let me know", "i hope", "feel free", "this commit message", "this message",
+		"this captures", "this rewrite", "this version", "note:"}
+)
+
+// Result returns the part of a response gdev uses, from what the fallback
+// wrote if the backend fell back (see NewWithFallback). It is, in order:
+//
+//   - the last ResultFence block, even if the response ended before the
+//     block was closed;
+//   - the result of claude's JSON output mode ({"type": "result", "result": ...}),
+//     itself read as a response;
+//   - the first fenced block, if nothing but a preamble comes before it;
+//   - the response without a preamble ("Here is the message:") and closing
+//     chatter ("Let me know if...").
+//
+// Line endings are normalized and the result is trimmed.
+func Result(output string) string {
+	output = strings.TrimSpace(strings.ReplaceAll(afterFallback(output), "\r\n", "\n"))
+
+	var envelope struct {
+		Type   string  `json:"type"`
+		Result *string `json:"result"`
+	}
+	if strings.HasPrefix(output, "{") && json.Unmarshal([]byte(output), &envelope) == nil &&
+		envelope.Type == "result" && envelope.Result != nil {
+		output = strings.TrimSpace(strings.ReplaceAll(*envelope.Result, "\r\n", "\n"))
+	}
+
+	lines := strings.Split(output, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if fence, info := fenceOf(lines[i]); fence != "" && info == ResultFence {
+			return fencedBlock(lines[i+1:], fence)
+		}
+	}
+
+	lines = lines[skipPreamble(lines):]
+	if len(lines) > 0 {
+		if fence, _ := fenceOf(lines[0]); fence != "" {
+			if block := fencedBlock(lines[1:], fence); block != "" {
+				return block
+			}
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines[:dropChatter(lines)], "\n"))
+}
+
+// fenceOf returns the backticks opening or closing a fenced block on line,
+// and the info string after them, or "" if line isn't a fence.
+func fenceOf(line string) (fence, info string) {
+	trimmed := strings.TrimSpace(line)
+	n := len(trimmed) - len(strings.TrimLeft(trimmed, "`"))
+	if n < 3 {
+		return "", ""
+	}
+	return trimmed[:n], strings.ToLower(strings.TrimSpace(trimmed[n:]))
+}
+
+// fencedBlock returns the content of the block opened with fence, lines
+// being the ones after it, up to its closing fence or the end. Blocks
+// nested within it are kept.
+func fencedBlock(lines []string, fence string) string {
+	depth := 0
+	for i, line := range lines {
+		f, info := fenceOf(line)
+		switch {
+		case len(f) < len(fence):
+			continue
+		case info != "":
+			depth++
+		case depth > 0 && len(f) == len(fence):
+			depth--
+		default:
+			return strings.TrimSpace(strings.Join(lines[:i], "\n"))
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// skipPreamble returns the index of the first line of lines after the
+// preamble: lines on their own before a blank line or a fence that end
+// with a colon or start like a preamble.
+func skipPreamble(lines []string) int {
+	i := 0
+	for i < len(lines) {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" {
+			i++
+			continue
+		}
+		if next := i + 1; next < len(lines) && strings.TrimSpace(lines[next]) != "" {
+			if f, _ := fenceOf(lines[next]); f == "" {
+				break
+			}
+		}
+		lower := strings.ToLower(trimmed)
+		structured := strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "-") || strings.HasPrefix(trimmed, "*")
+		if structured || (!strings.HasSuffix(trimmed, ":") && !hasAnyPrefix(lower, preambles)) {
+			break
+		}
+		i++
+	}
+	return i
+}
+
+// dropChatter returns how many of lines to keep without the closing
+// paragraph, if it is chatter.
+func dropChatter(lines []string) int {
+	end := len(lines)
+	for end > 0 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	start := end
+	for start > 0 && strings.TrimSpace(lines[start-1]) != "" {
+		start--
+	}
+	// A response that is all chatter is kept as it is
+	if start == 0 || !hasAnyPrefix(strings.ToLower(strings.TrimSpace(lines[start])), chatter) {
+		return end
+	}
+	return start
+}
+
+// hasAnyPrefix reports whether s starts with one of prefixes.
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package ai
+
+import "testing"
+
+func TestResult(t *testing.T) {
+	const msg = "feat: add timesheets\n\n* per repository and day"
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{"raw", msg, msg},
+		{"result block", "```result\n" + msg + "\n```", msg},
+		{"result block with chatter", "Here is the message:\n\n```result\n" + msg + "\n```\n\nThis captures the timesheet export.", msg},
+		{"last result block", "```result\nfix: draft\n```\nOn second thought:\n```result\n" + msg + "\n```", msg},
+		{"unclosed result block", "Sure!\n```result\n" + msg, msg},
+		{"nested fences", "````result\nRun:\n\n```sh\ngo test ./...\n```\n\nThen commit.\n````", "Run:\n\n```sh\ngo test ./...\n```\n\nThen commit."},
+		{"nested with info string", "```result\nRun:\n```sh\ngo test\n```\nDone.\n```\nLet me know!", "Run:\n```sh\ngo test\n```\nDone."},
+		{"plain fence after preamble", "Here's the commit message:\n```\n" + msg + "\n```\nFeel free to adjust it.", msg},
+		{"text fence", "```text\n" + msg + "\n```", msg},
+		{"preamble paragraphs", "Based on the diff, here is a message.\n\nCommit message:\n\n" + msg, msg},
+		{"closing chatter", msg + "\n\nLet me know if you want a shorter subject.", msg},
+		{"crlf", "```result\r\n" + "feat: add timesheets\r\n\r\n* per repository and day" + "\r\n```\r\n", msg},
+		{"json output mode", `{"type":"result","subtype":"success","result":"Here:\n\n` + "```result\\n" + `feat: add timesheets\n\n* per repository and day` + "\\n```" + `"}`, msg},
+		{"json that isn't the envelope", `{"files": ["a.go"]}`, `{"files": ["a.go"]}`},
+		{"heading kept", "## Risks:\n\n- None", "## Risks:\n\n- None"},
+		{"all chatter", "Let me know what to change.", "Let me know what to change."},
+		{"empty", "  \n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Result(tt.output); got != tt.want {
+				t.Errorf("Result(%q) = %q, want %q", tt.output, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+// Package askpass answers the credential prompts of git and ssh from the TUI.
+//
+// Commands run in gdev's terminal are pointed with GIT_ASKPASS and
+// SSH_ASKPASS at a link to the gdev binary named Helper, so the prompts are
+// answered in the modal rather than in the terminal they run in. Run under
+// that name, gdev forwards the prompt over a unix socket to the Server of
+// the running TUI and prints the answer it gets back.
+package askpass
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// EnvSocket is set to the server's socket for the commands it serves.
+const EnvSocket = "GDEV_ASKPASS_SOCKET"
+
+// Helper is the name of the link to the gdev binary that git and ssh run to
+// ask for credentials, see IsHelper.
+const Helper = "gdev-askpass"
+
+// IsHelper reports whether the program was run as the askpass helper, given
+// its os.Args[0]. git and ssh pass the prompt as the only argument, which
+// can't be told apart from a command of gdev's own.
+func IsHelper(arg0 string) bool {
+	return filepath.Base(arg0) == Helper
+}
+
+// ErrCanceled is returned to the helper when a prompt is dismissed.
+var ErrCanceled = errors.New("prompt canceled")
+
+// Prompt is a question from git or ssh waiting for an answer.
+type Prompt struct {
+	Text string
+
+	// Secret is true for passwords, tokens and passphrases, which are not echoed.
+	Secret bool
+
+	reply chan reply
+	once  sync.Once
+}
+
+type reply struct {
+	answer string
+	ok     bool
+}
+
+// Answer sends answer to the waiting command.
+func (p *Prompt) Answer(answer string) {
+	p.once.Do(func() { p.reply <- reply{answer: answer, ok: true} })
+}
+
+// Cancel dismisses the prompt, which makes the command fail to authenticate.
+func (p *Prompt) Cancel() {
+	p.once.Do(func() { p.reply <- reply{} })
+}
+
+// request and response are the messages exchanged over the socket, one JSON
+// line each.
+type (
+	request struct {
+		Prompt string `json:"prompt"`
+	}
+
+	response struct {
+		Answer string `json:"answer"`
+		OK     bool   `json:"ok"`
+	}
+)
+
+// Server receives the prompts of the commands started with its Env.
+type Server struct {
+	dir      string
+	socket   string
+	helper   string
+	listener net.Listener
+	prompts  chan *Prompt
+	done     chan struct{}
+}
+
+// Listen starts a server on a socket in a new temporary directory,
+// which only the current user can access.
+func Listen() (*Server, error) {
+	dir, err := os.MkdirTemp("", "gdev-askpass-")
+	if err != nil {
+		return nil, err
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	helper := filepath.Join(dir, Helper)
+	if err := os.Symlink(exe, helper); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	socket := filepath.Join(dir, "socket")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	s := &Server{dir: dir, socket: socket, helper: helper, listener: l, prompts: make(chan *Prompt), done: make(chan struct{})}
+	go s.serve()
+	return s, nil
+}
+
+// Prompts returns the channel prompts arrive on. It is closed by Close.
+func (s *Server) Prompts() <-chan *Prompt {
+	return s.prompts
+}
+
+// Env returns the environment variables that send the credential prompts
+// of git and ssh to the server.
+func (s *Server) Env() []string {
+	return []string{
+		EnvSocket + "=" + s.socket,
+		"GIT_ASKPASS=" + s.helper,
+		"SSH_ASKPASS=" + s.helper,
+		"SSH_ASKPASS_REQUIRE=force",
+	}
+}
+
+// Close stops the server and removes its socket. Prompts still waiting are canceled.
+func (s *Server) Close() error {
+	close(s.done)
+	err := s.listener.Close()
+	os.RemoveAll(s.dir)
+	return err
+}
+
+func (s *Server) serve() {
+	var wg sync.WaitGroup
+	defer func() {
+		wg.Wait()
+		close(s.prompts)
+	}()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.handle(conn)
+		}()
+	}
+}
+
+// handle forwards one prompt from a helper and sends back the answer.
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+
+	var req request
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		return
+	}
+
+	p := &Prompt{Text: strings.TrimSpace(req.Prompt), Secret: isSecret(req.Prompt), reply: make(chan reply, 1)}
+	select {
+	case s.prompts <- p:
+	case <-s.done:
+		return
+	}
+
+	var r reply
+	select {
+	case r = <-p.reply:
+	case <-s.done:
+	}
+
+	json.NewEncoder(conn).Encode(response{Answer: r.answer, OK: r.ok})
+}
+
+// isSecret reports whether the prompt asks for something that should not be echoed.
+func isSecret(prompt string) bool {
+	prompt = strings.ToLower(prompt)
+	for _, word := range []string{"password", "passphrase", "token", "pin for"} {
+		if strings.Contains(prompt, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// Ask sends prompt to the server at socket and returns the answer.
+// It is what the gdev binary does when run as an askpass helper.
+func Ask(socket, prompt string) (string, error) {
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	if err := json.NewEncoder(conn).Encode(request{Prompt: prompt}); err != nil {
+		return "", err
+	}
+
+	var resp response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return "", fmt.Errorf("no answer: %w", err)
+	}
+	if !resp.OK {
+		return "", ErrCanceled
+	}
+	return resp.Answer, nil
+}
@@ -0,0 +1,50 @@
+package askpass
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAsk(t *testing.T) {
+	srv, err := Listen()
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	defer srv.Close()
+
+	go func() {
+		for p := range srv.Prompts() {
+			if p.Secret {
+				p.Answer("hunter2")
+			} else {
+				p.Cancel()
+			}
+		}
+	}()
+
+	answer, err := Ask(srv.socket, "Password for 'https://github.com': ")
+	if err != nil || answer != "hunter2" {
+		t.Errorf("Ask(password) = %q, %v; want hunter2", answer, err)
+	}
+
+	if _, err := Ask(srv.socket, "Username for 'https://github.com': "); !errors.Is(err, ErrCanceled) {
+		t.Errorf("Ask(username) error = %v; want ErrCanceled", err)
+	}
+}
+
+func TestIsHelper(t *testing.T) {
+	tests := []struct {
+		arg0 string
+		want bool
+	}{
+		{"/tmp/gdev-askpass-123/gdev-askpass", true},
+		{"gdev-askpass", true},
+		{"/usr/local/bin/gdev", false},
+		{"gdev", false},
+	}
+	for _, tt := range tests {
+		if got := IsHelper(tt.arg0); got != tt.want {
+			t.Errorf("IsHelper(%q) = %v, want %v", tt.arg0, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+// Package budget checks what AI providers were used for against the
+// budgets set in settings (config.Budget), from the usage log gdev keeps
+// of its requests.
+package budget
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ihatemodels/gdev/internal/ai"
+	"github.com/ihatemodels/gdev/internal/claude"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/store"
+)
+
+// Level is how close a provider is to its budget.
+type Level int
+
+const (
+	OK   Level = iota
+	Warn       // past the warning threshold of a limit
+	Over       // a limit is used up
+)
+
+// defaultWarnPercent is the warning threshold of budgets that don't set one.
+const defaultWarnPercent = 80
+
+// Status is how close a provider is to its budget, going by the limit
+// that is most used.
+type Status struct {
+	Level    Level
+	Provider string
+	Period   string // "daily" or "monthly"
+	Tokens   bool   // the limit is in tokens rather than USD
+	Used     float64
+	Limit    float64
+}
+
+// String describes the status, e.g. "claude used $4.20 of its $5.00 daily
+// budget", or "" if no limit is set.
+func (s Status) String() string {
+	if s.Limit == 0 {
+		return ""
+	}
+	if s.Tokens {
+		return fmt.Sprintf("%s used %s of its %s %s tokens", s.Provider,
+			claude.FormatTokens(int(s.Used)), claude.FormatTokens(int(s.Limit)), s.Period)
+	}
+	return fmt.Sprintf("%s used %s of its %s %s budget", s.Provider,
+		claude.FormatCost(s.Used), claude.FormatCost(s.Limit), s.Period)
+}
+
+// Check returns the status of provider against b at now, given the usage
+// entries of now's month.
+func Check(entries []store.UsageEntry, provider string, b config.Budget, now time.Time) Status {
+	y, m, d := now.Local().Date()
+	var dayTokens, monthTokens int
+	var dayCost, monthCost float64
+	for _, e := range entries {
+		t := e.Time.Local()
+		if e.Provider != provider || t.Year() != y || t.Month() != m {
+			continue
+		}
+		monthTokens += e.Tokens
+		monthCost += e.Cost
+		if t.Day() == d {
+			dayTokens += e.Tokens
+			dayCost += e.Cost
+		}
+	}
+
+	limits := []Status{
+		{Period: "daily", Tokens: true, Used: float64(dayTokens), Limit: float64(b.DailyTokens)},
+		{Period: "monthly", Tokens: true, Used: float64(monthTokens), Limit: float64(b.MonthlyTokens)},
+		{Period: "daily", Used: dayCost, Limit: b.DailyCost},
+		{Period: "monthly", Used: monthCost, Limit: b.MonthlyCost},
+	}
+	warn := b.WarnPercent
+	if warn <= 0 {
+		warn = defaultWarnPercent
+	}
+
+	// The limit most used
+	status := Status{Provider: provider}
+	for _, l := range limits {
+		if l.Limit > 0 && (status.Limit == 0 || l.Used/l.Limit > status.Used/status.Limit) {
+			status.Period, status.Tokens, status.Used, status.Limit = l.Period, l.Tokens, l.Used, l.Limit
+		}
+	}
+	switch {
+	case status.Limit == 0:
+	case status.Used >= status.Limit:
+		status.Level = Over
+	case status.Used*100 >= status.Limit*float64(warn):
+		status.Level = Warn
+	}
+	return status
+}
+
+// For returns the status of provider against its budget in cfg's
+// settings, from this month's usage log. Providers without a budget are OK.
+func For(cfg *config.Config, provider string) (Status, error) {
+	b, ok := cfg.Settings.Budgets[provider]
+	if !ok {
+		return Status{Provider: provider}, nil
+	}
+	now := time.Now()
+	entries, err := cfg.MonthUsage(now)
+	if err != nil {
+		return Status{Provider: provider}, err
+	}
+	return Check(entries, provider, b, now), nil
+}
+
+// ForBackend returns the status of the provider of b, or of the one closer
+// to its budget if b falls back to another.
+func ForBackend(cfg *config.Config, b ai.Backend) (Status, error) {
+	var worst Status
+	for i, backend := range ai.Chain(b) {
+		s, err := For(cfg, backend.Name())
+		if err != nil {
+			return s, err
+		}
+		if i == 0 || s.Level > worst.Level || (s.Level == worst.Level && s.used() > worst.used()) {
+			worst = s
+		}
+	}
+	return worst, nil
+}
+
+// used returns how much of its limit s used, 0 without a limit.
+func (s Status) used() float64 {
+	if s.Limit == 0 {
+		return 0
+	}
+	return s.Used / s.Limit
+}
+
+// Backend returns the AI backend chosen in cfg's settings, with its
+// fallback, recording what each request uses in the usage log.
+func Backend(cfg *config.Config) (ai.Backend, error) {
+	st := cfg.Settings
+	b, err := ai.NewWithFallback(st.AIBackend, st.AIModel, st.AIFallback, st.AIFallbackModel, st.FallbackTimeout())
+	if err != nil {
+		return nil, err
+	}
+	return ai.Metered(b, func(b ai.Backend, tokens int, cost float64) {
+		cfg.RecordUsage(b.Name(), b.Model(), tokens, cost)
+	}), nil
+}
@@ -0,0 +1,80 @@
+package budget
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ihatemodels/gdev/internal/ai"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/store"
+)
+
+func TestCheck(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.Local)
+	entries := []store.UsageEntry{
+		{Time: now.Add(-time.Hour), Provider: "claude", Tokens: 30_000, Cost: 3},
+		{Time: now.AddDate(0, 0, -3), Provider: "claude", Tokens: 50_000, Cost: 1},
+		{Time: now.AddDate(0, -1, 0), Provider: "claude", Tokens: 900_000, Cost: 40},
+		{Time: now, Provider: "ollama", Tokens: 500_000},
+	}
+
+	tests := []struct {
+		name   string
+		budget config.Budget
+		level  Level
+		want   string
+	}{
+		{"no limits", config.Budget{}, OK, ""},
+		{"under", config.Budget{DailyCost: 10, MonthlyTokens: 1_000_000}, OK, "claude used $3.00 of its $10.00 daily budget"},
+		{"warns", config.Budget{DailyCost: 3.5, MonthlyTokens: 1_000_000}, Warn, "claude used $3.00 of its $3.50 daily budget"},
+		{"warns later", config.Budget{DailyCost: 3.5, WarnPercent: 90}, OK, "claude used $3.00 of its $3.50 daily budget"},
+		{"over", config.Budget{DailyCost: 10, MonthlyTokens: 80_000}, Over, "claude used 80.0k of its 80.0k monthly tokens"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Check(entries, "claude", tt.budget, now)
+			if s.Level != tt.level || s.String() != tt.want {
+				t.Errorf("Check() = %v %q, want %v %q", s.Level, s, tt.level, tt.want)
+			}
+		})
+	}
+}
+
+func TestForBackend_ChecksFallback(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	s, err := store.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.Load(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Settings.Budgets = map[string]config.Budget{
+		"anthropic": {DailyTokens: 100_000},
+		"ollama":    {DailyTokens: 1_000},
+	}
+	if err := cfg.RecordUsage("anthropic", "claude-sonnet-4-5", 10_000, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.RecordUsage("ollama", "llama3.2", 2_000, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ai.NewWithFallback("anthropic", "", "ollama", "", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, err := ForBackend(cfg, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Level != Over || status.Provider != "ollama" {
+		t.Errorf("ForBackend() = %v %q, want the fallback's budget to be over", status.Level, status)
+	}
+
+	primary, _ := ai.New("anthropic", "")
+	if status, _ := ForBackend(cfg, primary); status.Level != OK {
+		t.Errorf("ForBackend() without a fallback = %v %q, want OK", status.Level, status)
+	}
+}
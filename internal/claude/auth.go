@@ -0,0 +1,49 @@
+package claude
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// Account is the account the claude CLI is logged in with.
+type Account struct {
+	Email        string `json:"emailAddress"`
+	Organization string `json:"organizationName"`
+}
+
+// LoggedInAccount returns the account claude is logged in with, or nil if it
+// is not logged in. The account is read from .claude.json, which lives in the
+// home directory, or in CLAUDE_CONFIG_DIR when that is set.
+func LoggedInAccount() (*Account, error) {
+	dir := os.Getenv("CLAUDE_CONFIG_DIR")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = home
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, ".claude.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg struct {
+		OAuthAccount *Account `json:"oauthAccount"`
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	return cfg.OAuthAccount, nil
+}
+
+// LoginArgs returns the claude CLI arguments that start the login flow.
+func LoginArgs() []string {
+	return []string{"/login"}
+}
@@ -0,0 +1,28 @@
+package claude
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+
+	"github.com/ihatemodels/gdev/internal/store"
+)
+
+// CacheKey returns the key the response to a claude request made with args
+// is cached under. The model is the one passed with --model, or the default
+// set in the environment.
+func CacheKey(args []string) store.CacheKey {
+	model := os.Getenv("ANTHROPIC_MODEL")
+	h := sha256.New()
+	for i, a := range args {
+		if a == "--model" && i+1 < len(args) {
+			model = args[i+1]
+		}
+		h.Write([]byte(a))
+		h.Write([]byte{0})
+	}
+	if model == "" {
+		model = "default"
+	}
+	return store.CacheKey{Provider: "claude", Model: model, Hash: hex.EncodeToString(h.Sum(nil))}
+}
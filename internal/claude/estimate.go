@@ -0,0 +1,70 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// DefaultModel is what costs are estimated with when claude runs with its
+// default model.
+const DefaultModel = "sonnet"
+
+// promptOverhead is roughly how many tokens claude sends with every prompt
+// besides the conversation: its system prompt and tool definitions.
+const promptOverhead = 20_000
+
+// EstimateTokens returns roughly how many tokens text is, at about four
+// characters a token.
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// EstimatePrompts estimates the usage of sending prompts in turn in one
+// session in repoRoot, with model or DefaultModel. Every prompt carries
+// claude's system prompt, its tools and the CLAUDE.md files it loads,
+// cached after the first, and the prompts before it. What the model writes
+// and reads with its tools can't be known up front, so this is a lower
+// bound of what the prompts use.
+func EstimatePrompts(repoRoot, model string, prompts []string) Usage {
+	context := promptOverhead + memoryTokens(repoRoot)
+
+	var u Usage
+	history := 0
+	for i, p := range prompts {
+		if i == 0 {
+			u.CacheCreationTokens += context
+		} else {
+			u.CacheReadTokens += context + history
+		}
+		n := EstimateTokens(p)
+		u.InputTokens += n
+		history += n
+	}
+
+	if model == "" {
+		model = DefaultModel
+	}
+	u.Cost = EstimateCost(model, u)
+	return u
+}
+
+// memoryTokens estimates the size of the CLAUDE.md files claude loads into
+// every session in repoRoot: the user's and the project's.
+func memoryTokens(repoRoot string) int {
+	files := []string{
+		filepath.Join(repoRoot, "CLAUDE.md"),
+		filepath.Join(repoRoot, "CLAUDE.local.md"),
+		filepath.Join(repoRoot, ".claude", "CLAUDE.md"),
+	}
+	if dir, err := ConfigDir(); err == nil {
+		files = append(files, filepath.Join(dir, "CLAUDE.md"))
+	}
+
+	n := 0
+	for _, f := range files {
+		if info, err := os.Stat(f); err == nil && !info.IsDir() {
+			n += int(info.Size()+3) / 4
+		}
+	}
+	return n
+}
@@ -0,0 +1,78 @@
+package claude
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// systemTags matches blocks injected into prompts by Claude Code
+// that are not part of the conversation.
+var systemTags = regexp.MustCompile(`(?s)<(system-reminder|command-[a-z]+|local-command-[a-z]+)>.*?</(system-reminder|command-[a-z]+|local-command-[a-z]+)>`)
+
+// Markdown renders a session transcript as markdown for sharing.
+// Injected system text is removed and tool calls are shown as code spans.
+func Markdown(s Session, turns []Turn) string {
+	var b strings.Builder
+
+	title := s.Title
+	if title == "" {
+		title = "Claude session"
+	}
+	fmt.Fprintf(&b, "# %s\n\n", strings.TrimSpace(strings.SplitN(title, "\n", 2)[0]))
+
+	fmt.Fprintf(&b, "- **Session:** `%s`\n", s.ID)
+	if s.Branch != "" {
+		fmt.Fprintf(&b, "- **Branch:** `%s`\n", s.Branch)
+	}
+	if !s.StartedAt.IsZero() {
+		fmt.Fprintf(&b, "- **Started:** %s\n", s.StartedAt.Local().Format(time.DateTime))
+	}
+	if s.Usage.Total() > 0 {
+		fmt.Fprintf(&b, "- **Usage:** %s tokens (%s)\n", FormatTokens(s.Usage.Total()), FormatCost(s.Usage.Cost))
+	}
+
+	for _, t := range turns {
+		text := strings.TrimSpace(systemTags.ReplaceAllString(t.Text, ""))
+		if text == "" {
+			continue
+		}
+
+		role := "Claude"
+		if t.Role == "user" {
+			role = "You"
+		}
+		b.WriteString("\n---\n\n")
+		if t.Timestamp.IsZero() {
+			fmt.Fprintf(&b, "### %s\n\n", role)
+		} else {
+			fmt.Fprintf(&b, "### %s · %s\n\n", role, t.Timestamp.Local().Format("15:04"))
+		}
+
+		wasTool := false
+		for i, line := range strings.Split(text, "\n") {
+			isTool := t.Role == "assistant" && strings.HasPrefix(line, "[")
+			// Keep tool calls in their own list, apart from the prose
+			if i > 0 && isTool != wasTool {
+				b.WriteString("\n")
+			}
+			if isTool {
+				line = "- " + codeSpan(line)
+			}
+			b.WriteString(line)
+			b.WriteString("\n")
+			wasTool = isTool
+		}
+	}
+
+	return b.String()
+}
+
+// codeSpan wraps s in backticks, using a longer fence if s contains backticks.
+func codeSpan(s string) string {
+	if !strings.Contains(s, "`") {
+		return "`" + s + "`"
+	}
+	return "`` " + s + " ``"
+}
@@ -0,0 +1,219 @@
+// Package claude reads Claude Code session transcripts stored under ~/.claude.
+package claude
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Session is a Claude Code conversation recorded for a project.
+type Session struct {
+	ID        string
+	Title     string // summary, or the first prompt if there is none
+	Branch    string // git branch the session started on
+	Messages  int    // user and assistant messages
+	StartedAt time.Time
+	UpdatedAt time.Time
+	Usage     Usage // tokens and estimated cost, including subagents
+}
+
+// entry is a line of a session transcript; only the fields gdev uses are decoded.
+type entry struct {
+	Type        string    `json:"type"`
+	Timestamp   time.Time `json:"timestamp"`
+	GitBranch   string    `json:"gitBranch"`
+	IsMeta      bool      `json:"isMeta"`
+	IsSidechain bool      `json:"isSidechain"`
+	Summary     string    `json:"summary"`
+	Message     struct {
+		ID      string          `json:"id"`
+		Model   string          `json:"model"`
+		Usage   *Usage          `json:"usage"`
+		Content json.RawMessage `json:"content"`
+	} `json:"message"`
+}
+
+// ConfigDir returns the Claude configuration directory,
+// honoring CLAUDE_CONFIG_DIR like the claude CLI does.
+func ConfigDir() (string, error) {
+	if dir := os.Getenv("CLAUDE_CONFIG_DIR"); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".claude"), nil
+}
+
+// ProjectDir returns the directory holding the sessions for repoRoot.
+// Claude names it after the project path with every non-alphanumeric
+// character replaced by a dash.
+func ProjectDir(repoRoot string) (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '-'
+	}, repoRoot)
+	return filepath.Join(dir, "projects", name), nil
+}
+
+// ListSessions returns the sessions for repoRoot, most recently updated first.
+// A repository without any sessions returns an empty list.
+func ListSessions(repoRoot string) ([]Session, error) {
+	dir, err := ProjectDir(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []Session
+	for _, file := range files {
+		s, err := readSession(file)
+		if err != nil || s.Messages == 0 {
+			continue
+		}
+		sessions = append(sessions, s)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].UpdatedAt.After(sessions[j].UpdatedAt)
+	})
+	return sessions, nil
+}
+
+// NewSessionID returns a random UUID for a new session, passed to claude
+// with --session-id.
+func NewSessionID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// SessionUsage returns the usage of the session id of repoRoot. If claude
+// never wrote the session, the error is fs.ErrNotExist.
+func SessionUsage(repoRoot, id string) (Usage, error) {
+	dir, err := ProjectDir(repoRoot)
+	if err != nil {
+		return Usage{}, err
+	}
+	s, err := readSession(filepath.Join(dir, id+".jsonl"))
+	return s.Usage, err
+}
+
+// readSession reads the metadata of a session transcript.
+func readSession(path string) (Session, error) {
+	s := Session{ID: strings.TrimSuffix(filepath.Base(path), ".jsonl")}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return s, err
+	}
+	defer f.Close()
+
+	var firstPrompt string
+	// An API response is logged once per content block, each with the same usage
+	counted := make(map[string]bool)
+	r := bufio.NewReader(f)
+	for {
+		// Lines can be very long (tool output), so read without a size limit
+		line, err := r.ReadBytes('\n')
+		if len(line) > 0 {
+			var e entry
+			if json.Unmarshal(line, &e) == nil {
+				switch {
+				case e.Type == "summary" && e.Summary != "":
+					s.Title = e.Summary
+				case (e.Type == "user" || e.Type == "assistant") && !e.IsSidechain:
+					s.Messages++
+					if s.StartedAt.IsZero() {
+						s.StartedAt = e.Timestamp
+						s.Branch = e.GitBranch
+					}
+					if e.Timestamp.After(s.UpdatedAt) {
+						s.UpdatedAt = e.Timestamp
+					}
+					if firstPrompt == "" && e.Type == "user" && !e.IsMeta {
+						firstPrompt = promptText(e.Message.Content)
+					}
+				}
+				// Subagent usage is billed too, so sidechains are counted
+				if u := e.Message.Usage; e.Type == "assistant" && u != nil &&
+					(e.Message.ID == "" || !counted[e.Message.ID]) {
+					counted[e.Message.ID] = true
+					u.Cost = EstimateCost(e.Message.Model, *u)
+					s.Usage.Add(*u)
+				}
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return s, err
+		}
+	}
+
+	if s.Title == "" {
+		s.Title = firstPrompt
+	}
+	if s.UpdatedAt.IsZero() {
+		if info, err := f.Stat(); err == nil {
+			s.UpdatedAt = info.ModTime()
+		}
+	}
+	return s, nil
+}
+
+// promptText extracts the text typed by the user from message content,
+// which is either a string or a list of content blocks. Tool results and
+// command wrappers (text starting with "<") are ignored.
+func promptText(content json.RawMessage) string {
+	var text string
+	if json.Unmarshal(content, &text) != nil {
+		var blocks []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		}
+		if json.Unmarshal(content, &blocks) != nil {
+			return ""
+		}
+		for _, b := range blocks {
+			if b.Type == "text" {
+				text = b.Text
+				break
+			}
+		}
+	}
+
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "<") {
+		return ""
+	}
+	return text
+}
+
+// ResumeArgs returns the claude CLI arguments to resume a session.
+func ResumeArgs(id string) []string {
+	return []string{"--resume", id}
+}
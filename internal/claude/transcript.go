@@ -0,0 +1,106 @@
+package claude
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Turn is a single user or assistant message in a transcript.
+type Turn struct {
+	Role      string // "user" or "assistant"
+	Text      string
+	Timestamp time.Time
+}
+
+// contentBlock is an element of a message's content list.
+type contentBlock struct {
+	Type  string          `json:"type"`
+	Text  string          `json:"text"`
+	Name  string          `json:"name"`  // tool_use
+	Input json.RawMessage `json:"input"` // tool_use
+}
+
+// LoadTranscript reads the conversation of a session as a list of turns.
+// Assistant text and tool calls are kept; thinking, tool results and
+// sidechain (subagent) messages are skipped.
+func LoadTranscript(repoRoot, id string) ([]Turn, error) {
+	dir, err := ProjectDir(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(filepath.Join(dir, id+".jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var turns []Turn
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(line) > 0 {
+			var e entry
+			if json.Unmarshal(line, &e) == nil && !e.IsSidechain && !e.IsMeta &&
+				(e.Type == "user" || e.Type == "assistant") {
+				if text := turnText(e.Message.Content); text != "" {
+					turns = append(turns, Turn{Role: e.Type, Text: text, Timestamp: e.Timestamp})
+				}
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return turns, err
+		}
+	}
+	return turns, nil
+}
+
+// turnText renders message content as plain text.
+func turnText(content json.RawMessage) string {
+	var text string
+	if json.Unmarshal(content, &text) == nil {
+		return strings.TrimSpace(text)
+	}
+
+	var blocks []contentBlock
+	if json.Unmarshal(content, &blocks) != nil {
+		return ""
+	}
+
+	var parts []string
+	for _, b := range blocks {
+		switch b.Type {
+		case "text":
+			if t := strings.TrimSpace(b.Text); t != "" {
+				parts = append(parts, t)
+			}
+		case "tool_use":
+			parts = append(parts, toolSummary(b))
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// toolSummary describes a tool call on one line, e.g. "[Edit] internal/app.go".
+func toolSummary(b contentBlock) string {
+	var input map[string]any
+	_ = json.Unmarshal(b.Input, &input)
+
+	for _, key := range []string{"file_path", "path", "command", "pattern", "url", "description"} {
+		if v, ok := input[key].(string); ok && v != "" {
+			v, _, _ = strings.Cut(v, "\n")
+			return fmt.Sprintf("[%s] %s", b.Name, v)
+		}
+	}
+	return fmt.Sprintf("[%s]", b.Name)
+}
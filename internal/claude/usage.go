@@ -0,0 +1,98 @@
+package claude
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Usage is the token usage reported by the API, with its estimated cost.
+type Usage struct {
+	InputTokens         int `json:"input_tokens"`
+	OutputTokens        int `json:"output_tokens"`
+	CacheCreationTokens int `json:"cache_creation_input_tokens"`
+	CacheReadTokens     int `json:"cache_read_input_tokens"`
+
+	// Cost is the estimated cost in USD; zero for unknown models
+	Cost float64 `json:"-"`
+}
+
+// Total returns the total number of tokens.
+func (u Usage) Total() int {
+	return u.InputTokens + u.OutputTokens + u.CacheCreationTokens + u.CacheReadTokens
+}
+
+// Add accumulates o into u.
+func (u *Usage) Add(o Usage) {
+	u.InputTokens += o.InputTokens
+	u.OutputTokens += o.OutputTokens
+	u.CacheCreationTokens += o.CacheCreationTokens
+	u.CacheReadTokens += o.CacheReadTokens
+	u.Cost += o.Cost
+}
+
+// price is the list price of a model in USD per million tokens.
+type price struct {
+	Input  float64
+	Output float64
+}
+
+// prices maps model name fragments to list prices. More specific fragments
+// come first. Cache writes cost 1.25x and cache reads 0.1x the input price.
+var prices = []struct {
+	Model string
+	Price price
+}{
+	{"opus-4-5", price{Input: 5, Output: 25}},
+	{"opus", price{Input: 15, Output: 75}},
+	{"sonnet", price{Input: 3, Output: 15}},
+	{"haiku-4-5", price{Input: 1, Output: 5}},
+	{"haiku", price{Input: 0.8, Output: 4}},
+}
+
+// EstimateCost returns the estimated cost in USD of u on model.
+// Unknown models cost zero.
+func EstimateCost(model string, u Usage) float64 {
+	for _, p := range prices {
+		if !strings.Contains(model, p.Model) {
+			continue
+		}
+		in := p.Price.Input / 1e6
+		return float64(u.InputTokens)*in +
+			float64(u.CacheCreationTokens)*in*1.25 +
+			float64(u.CacheReadTokens)*in*0.1 +
+			float64(u.OutputTokens)*p.Price.Output/1e6
+	}
+	return 0
+}
+
+// RepoUsage returns the combined usage of all sessions for repoRoot.
+func RepoUsage(repoRoot string) (Usage, error) {
+	sessions, err := ListSessions(repoRoot)
+	if err != nil {
+		return Usage{}, err
+	}
+	var u Usage
+	for _, s := range sessions {
+		u.Add(s.Usage)
+	}
+	return u, nil
+}
+
+// FormatTokens formats a token count compactly, e.g. 950, 12.3k, 4.1M.
+func FormatTokens(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1e6)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1e3)
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+// FormatCost formats a cost in USD.
+func FormatCost(c float64) string {
+	if c > 0 && c < 0.01 {
+		return "<$0.01"
+	}
+	return fmt.Sprintf("$%.2f", c)
+}
@@ -0,0 +1,63 @@
+package claude
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEstimateCost(t *testing.T) {
+	u := Usage{
+		InputTokens:         1_000_000,
+		OutputTokens:        1_000_000,
+		CacheCreationTokens: 1_000_000,
+		CacheReadTokens:     1_000_000,
+	}
+
+	tests := []struct {
+		model string
+		want  float64
+	}{
+		{"claude-sonnet-4-5-20250929", 3 + 15 + 3.75 + 0.3},
+		{"claude-opus-4-1-20250805", 15 + 75 + 18.75 + 1.5},
+		{"claude-opus-4-5-20251101", 5 + 25 + 6.25 + 0.5},
+		{"claude-haiku-4-5-20251001", 1 + 5 + 1.25 + 0.1},
+		{"<synthetic>", 0},
+	}
+
+	for _, tt := range tests {
+		if got := EstimateCost(tt.model, u); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("EstimateCost(%q) = %v, want %v", tt.model, got, tt.want)
+		}
+	}
+}
+
+func TestEstimatePrompts(t *testing.T) {
+	t.Setenv("CLAUDE_CONFIG_DIR", t.TempDir())
+	repo := t.TempDir()
+
+	u := EstimatePrompts(repo, "", []string{strings.Repeat("a", 400), strings.Repeat("b", 800)})
+	want := Usage{
+		InputTokens:         300,
+		CacheCreationTokens: promptOverhead,
+		CacheReadTokens:     promptOverhead + 100,
+	}
+	want.Cost = EstimateCost(DefaultModel, want)
+	if u != want {
+		t.Errorf("EstimatePrompts() = %+v, want %+v", u, want)
+	}
+
+	// The project's CLAUDE.md is sent with every prompt
+	if err := os.WriteFile(filepath.Join(repo, "CLAUDE.md"), []byte(strings.Repeat("c", 4000)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	u = EstimatePrompts(repo, "opus", []string{"fix it"})
+	if u.CacheCreationTokens != promptOverhead+1000 {
+		t.Errorf("CacheCreationTokens = %d, want %d", u.CacheCreationTokens, promptOverhead+1000)
+	}
+	if u.Cost != EstimateCost("opus", u) {
+		t.Errorf("Cost = %v, want the price of opus", u.Cost)
+	}
+}
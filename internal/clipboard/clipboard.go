@@ -0,0 +1,70 @@
+// Package clipboard copies text to the system clipboard.
+package clipboard
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Copy puts text on the clipboard with the platform's clipboard command. Over
+// ssh, or when no command is installed, it writes an OSC 52 escape sequence
+// instead, which most terminals answer by setting their own clipboard.
+func Copy(text string) error {
+	if os.Getenv("SSH_TTY") == "" {
+		if name, args := command(); name != "" {
+			cmd := exec.Command(name, args...)
+			cmd.Stdin = strings.NewReader(text)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				return fmt.Errorf("%s: %w: %s", name, err, strings.TrimSpace(string(out)))
+			}
+			return nil
+		}
+	}
+	return osc52(text)
+}
+
+// command returns the first clipboard command found for the platform, or ""
+// if there is none.
+func command() (string, []string) {
+	var candidates [][]string
+	switch runtime.GOOS {
+	case "darwin":
+		candidates = [][]string{{"pbcopy"}}
+	case "windows":
+		candidates = [][]string{{"clip.exe"}}
+	default:
+		if os.Getenv("WAYLAND_DISPLAY") != "" {
+			candidates = append(candidates, []string{"wl-copy"})
+		}
+		if os.Getenv("DISPLAY") != "" {
+			candidates = append(candidates,
+				[]string{"xclip", "-selection", "clipboard"},
+				[]string{"xsel", "--clipboard", "--input"})
+		}
+		// WSL
+		candidates = append(candidates, []string{"clip.exe"})
+	}
+
+	for _, c := range candidates {
+		if _, err := exec.LookPath(c[0]); err == nil {
+			return c[0], c[1:]
+		}
+	}
+	return "", nil
+}
+
+// osc52 asks the terminal to set its clipboard. It writes to stderr, since
+// stdout belongs to the TUI, and wraps the sequence for tmux, which would
+// swallow it otherwise.
+func osc52(text string) error {
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+	if os.Getenv("TMUX") != "" {
+		seq = "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+	}
+	_, err := os.Stderr.WriteString(seq)
+	return err
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Binding is a single configurable key, addressed by the group and action
+// names used in keybindings.json.
+type Binding struct {
+	Group  string // e.g. "form"
+	Action string // e.g. "submit"
+	Key    string
+}
+
+// Name returns the binding's name, e.g. "form.submit".
+func (b Binding) Name() string {
+	return b.Group + "." + b.Action
+}
+
+// Bindings lists all keybindings in declaration order.
+func (kb *Keybindings) Bindings() []Binding {
+	var bindings []Binding
+	groups := reflect.ValueOf(kb).Elem()
+	for i := 0; i < groups.NumField(); i++ {
+		group := groups.Field(i)
+		groupName := jsonName(groups.Type().Field(i))
+		for j := 0; j < group.NumField(); j++ {
+			bindings = append(bindings, Binding{
+				Group:  groupName,
+				Action: jsonName(group.Type().Field(j)),
+				Key:    group.Field(j).String(),
+			})
+		}
+	}
+	return bindings
+}
+
+// SetBinding assigns key to the binding group.action.
+func (kb *Keybindings) SetBinding(group, action, key string) error {
+	groups := reflect.ValueOf(kb).Elem()
+	for i := 0; i < groups.NumField(); i++ {
+		if jsonName(groups.Type().Field(i)) != group {
+			continue
+		}
+		g := groups.Field(i)
+		for j := 0; j < g.NumField(); j++ {
+			if jsonName(g.Type().Field(j)) == action {
+				g.Field(j).SetString(key)
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("unknown keybinding %s.%s", group, action)
+}
+
+// ResetGroup sets every binding in group to its default.
+func (kb *Keybindings) ResetGroup(group string) error {
+	groups := reflect.ValueOf(kb).Elem()
+	defaults := reflect.ValueOf(DefaultKeybindings()).Elem()
+	for i := 0; i < groups.NumField(); i++ {
+		if jsonName(groups.Type().Field(i)) == group {
+			groups.Field(i).Set(defaults.Field(i))
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown keybinding group %s", group)
+}
+
+// views lists the groups whose keys are matched in the same view, such as
+// the TODO list with the detail beside it. Global keys are matched in
+// nearly every view, so they go with every group.
+var views = [][]string{
+	{"list", "detail"},             // TODOs, their details and run summaries
+	{"list", "detail", "sessions"}, // Claude sessions and their transcripts
+	{"list", "pr"},                 // pull requests
+	{"detail", "pr"},               // a pull request and its AI review
+	{"form", "editor"},             // forms with multi-line fields
+	{"list", "commit", "diff", "form"},
+	{"list", "diff"},
+	{"list", "branch", "patch"}, // branches
+	{"list", "timeline"},
+	{"list", "terminal"},
+	{"list", "settings", "form"},
+}
+
+// together reports whether the keys of groups a and b are matched in the
+// same view.
+func together(a, b string) bool {
+	if a == b || a == "global" || b == "global" {
+		return true
+	}
+	for _, v := range views {
+		if slices.Contains(v, a) && slices.Contains(v, b) {
+			return true
+		}
+	}
+	return false
+}
+
+// Conflicts returns the other bindings key is already bound to that are
+// matched in the same view as group.action: those of its group, global and
+// the groups beside it (see views). Keys may repeat across views, and
+// bindings that share their default key, like global.quit and form.cancel
+// on esc, do the same thing where both apply and don't conflict.
+func (kb *Keybindings) Conflicts(group, action, key string) []Binding {
+	defaults := make(map[string]string)
+	for _, b := range DefaultKeybindings().Bindings() {
+		defaults[b.Name()] = normalizeBinding(b.Key)
+	}
+	own := defaults[group+"."+action]
+
+	var conflicts []Binding
+	for _, b := range kb.Bindings() {
+		if b.Group == group && b.Action == action || !together(group, b.Group) {
+			continue
+		}
+		if normalizeBinding(b.Key) == normalizeBinding(key) && defaults[b.Name()] != own {
+			conflicts = append(conflicts, b)
+		}
+	}
+	return conflicts
+}
+
+// jsonName returns the JSON name of a struct field.
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// bundleVersion is the format version written to exported bundles.
+const bundleVersion = 1
+
+// Bundle is a portable copy of the configuration for moving a setup
+// between machines.
+type Bundle struct {
+	Version     int          `json:"version"`
+	Keybindings *Keybindings `json:"keybindings,omitempty"`
+	Settings    *Settings    `json:"settings,omitempty"`
+}
+
+// Export returns the configuration as a bundle.
+func (c *Config) Export() ([]byte, error) {
+	return json.MarshalIndent(Bundle{
+		Version:     bundleVersion,
+		Keybindings: c.Keybindings,
+		Settings:    c.Settings,
+	}, "", "  ")
+}
+
+// Import replaces the configuration with the sections of an exported bundle
+// and saves it. Sections missing from the bundle are left unchanged, and
+// fields missing from a section, e.g. in an older bundle, get their defaults.
+func (c *Config) Import(data []byte) error {
+	var b struct {
+		Version     int             `json:"version"`
+		Keybindings json.RawMessage `json:"keybindings"`
+		Settings    json.RawMessage `json:"settings"`
+	}
+	if err := json.Unmarshal(data, &b); err != nil {
+		return fmt.Errorf("invalid config bundle: %w", err)
+	}
+	if b.Version > bundleVersion {
+		return fmt.Errorf("config bundle version %d is newer than supported version %d", b.Version, bundleVersion)
+	}
+
+	kb, st := c.Keybindings, c.Settings
+	if b.Keybindings != nil {
+		var imported Keybindings
+		if err := json.Unmarshal(b.Keybindings, &imported); err != nil {
+			return fmt.Errorf("invalid keybindings: %w", err)
+		}
+		merged := mergeWithDefaults(&imported)
+		kb = &merged
+	}
+	if b.Settings != nil {
+		st = DefaultSettings()
+		if err := json.Unmarshal(b.Settings, st); err != nil {
+			return fmt.Errorf("invalid settings: %w", err)
+		}
+		if err := st.CheckBudgets(); err != nil {
+			return fmt.Errorf("invalid settings: %w", err)
+		}
+	}
+
+	c.Keybindings, c.Settings = kb, st
+	return c.Save()
+}
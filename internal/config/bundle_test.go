@@ -0,0 +1,40 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/ihatemodels/gdev/internal/store"
+)
+
+func TestImport_KeepsMissingSections(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	s, err := store.New()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	cfg, err := Load(s)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	cfg.Settings.RememberPositions = true
+
+	err = cfg.Import([]byte(`{"version": 1, "keybindings": {"form": {"submit": "ctrl+x"}}}`))
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	if cfg.Keys().Form.Submit != "ctrl+x" {
+		t.Errorf("Expected Form.Submit to be 'ctrl+x', got '%s'", cfg.Keys().Form.Submit)
+	}
+	if cfg.Keys().Form.Cancel != "esc" {
+		t.Errorf("Expected missing Form.Cancel to default to 'esc', got '%s'", cfg.Keys().Form.Cancel)
+	}
+	if !cfg.Settings.RememberPositions {
+		t.Error("Expected settings missing from the bundle to be kept")
+	}
+
+	if err := cfg.Import([]byte(`{"version": 99}`)); err == nil {
+		t.Error("Expected error for newer bundle version")
+	}
+}
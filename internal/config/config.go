@@ -1,13 +1,31 @@
 package config
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
 	"github.com/ihatemodels/gdev/internal/store"
 )
 
+// RepoFile is a repository's own gdev config, in its root. It can make gdev
+// run commands, so it is only honored in repositories the user trusts, see
+// store.RepoState.Trusted.
+const RepoFile = ".gdev.json"
+
 // Config holds all application configuration.
 type Config struct {
 	store       *store.Store
 	Keybindings *Keybindings
+	Settings    *Settings
+
+	ctx   context.Context // canceled when gdev exits
+	debug bool            // diagnostics for developers, see SetDebug
 }
 
 // Load loads the application configuration from the store.
@@ -18,15 +36,24 @@ func Load(s *store.Store) (*Config, error) {
 		return nil, err
 	}
 
+	st, err := LoadSettings(s)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Config{
 		store:       s,
 		Keybindings: kb,
+		Settings:    st,
 	}, nil
 }
 
 // Save persists the current configuration to the store.
 func (c *Config) Save() error {
-	return SaveKeybindings(c.store, c.Keybindings)
+	if err := SaveKeybindings(c.store, c.Keybindings); err != nil {
+		return err
+	}
+	return SaveSettings(c.store, c.Settings)
 }
 
 // ResetKeybindings resets keybindings to their defaults.
@@ -35,7 +62,267 @@ func (c *Config) ResetKeybindings() error {
 	return c.Save()
 }
 
+// ResetKeybindingGroup resets one group of keybindings, e.g. "form", to its defaults.
+func (c *Config) ResetKeybindingGroup(group string) error {
+	if err := c.Keybindings.ResetGroup(group); err != nil {
+		return err
+	}
+	return c.Save()
+}
+
+// Context returns the context commands are run with. It is canceled when gdev
+// exits, which stops the commands still running.
+func (c *Config) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// SetContext sets the context commands are run with, usually the program's lifetime.
+func (c *Config) SetContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
+// Debug reports whether debug mode is enabled.
+func (c *Config) Debug() bool {
+	return c.debug
+}
+
+// SetDebug enables or disables debug mode, which makes diagnostics of gdev
+// itself available, such as how long rendering takes.
+func (c *Config) SetDebug(enabled bool) {
+	c.debug = enabled
+}
+
+// DryRun reports whether mutating operations should only be shown, not performed.
+func (c *Config) DryRun() bool {
+	return c.store != nil && c.store.DryRun()
+}
+
+// SetDryRun enables or disables dry-run mode.
+func (c *Config) SetDryRun(enabled bool) {
+	if c.store != nil {
+		c.store.SetDryRun(enabled)
+	}
+}
+
+// ReadOnly reports whether mutating actions are refused, see store.SetReadOnly.
+func (c *Config) ReadOnly() bool {
+	return c.store != nil && c.store.ReadOnly()
+}
+
+// SetReadOnly enables or disables read-only mode.
+func (c *Config) SetReadOnly(enabled bool) {
+	if c.store != nil {
+		c.store.SetReadOnly(enabled)
+	}
+}
+
+// Audit records a mutating command run in repo to the repository's audit log.
+func (c *Config) Audit(repo, command string, exitCode int) error {
+	if c.store == nil {
+		return nil
+	}
+	return c.store.AppendAudit(store.AuditEntry{
+		Time:     time.Now(),
+		Repo:     repo,
+		Command:  command,
+		ExitCode: exitCode,
+	})
+}
+
+// RecordCommand adds a command run in repo to the repository's command
+// history, from which the terminal runs commands again.
+func (c *Config) RecordCommand(repo string, args []string, mutating bool) error {
+	if c.store == nil {
+		return nil
+	}
+	return c.store.AddCommand(repo, store.Command{Args: args, Mutating: mutating, Ran: time.Now()})
+}
+
+// Commands returns the command history of repo, most recent first.
+func (c *Config) Commands(repo string) ([]store.Command, error) {
+	if c.store == nil {
+		return nil, nil
+	}
+	return c.store.GetCommands(repo)
+}
+
+// PromptOverride returns the user's version of a built-in prompt, read from
+// prompts/<name>.md in the store, e.g. prompts/commit.md. It is "" if the
+// user has none.
+func (c *Config) PromptOverride(name string) (string, error) {
+	if c.store == nil {
+		return "", nil
+	}
+	data, err := c.store.Read(filepath.Join("prompts", name+".md"))
+	if errors.Is(err, store.ErrNotFound) {
+		return "", nil
+	}
+	return string(data), err
+}
+
+// CreateLog creates a file for the output of a command, in ~/.gdev/logs.
+// In dry-run and read-only mode, or without a store, it is created in the
+// temporary directory.
+func (c *Config) CreateLog(name string) (*os.File, error) {
+	if c.store == nil || c.DryRun() || c.ReadOnly() {
+		return os.CreateTemp("", "gdev-"+name+"-*.log")
+	}
+	return c.store.CreateLog(name)
+}
+
+// SaveRun saves the output of a command to a log named after name and lists
+// it in the history, see store.SaveRun.
+func (c *Config) SaveRun(r store.Run, name string, output io.Reader) (store.Run, error) {
+	if c.store == nil {
+		return r, errors.New("no store to save to")
+	}
+	return c.store.SaveRun(r, name, output)
+}
+
+// Cleanup deletes the files of each category beyond its retention settings,
+// except those keep returns true for. Categories without retention settings
+// are left alone. In dry-run mode the deletions are only planned.
+func (c *Config) Cleanup(keep func(path string) bool) ([]store.Reclaimed, error) {
+	var reclaimed []store.Reclaimed
+	if c.store == nil {
+		return nil, nil
+	}
+	for _, category := range RetentionCategories {
+		r, ok := c.Settings.Retention[category]
+		if !ok {
+			continue
+		}
+		p, err := r.Policy()
+		if err != nil {
+			return reclaimed, fmt.Errorf("retention for %s: %w", category, err)
+		}
+		res, err := c.store.Cleanup(category, p, keep)
+		if err != nil {
+			return reclaimed, err
+		}
+		reclaimed = append(reclaimed, res)
+	}
+	return reclaimed, nil
+}
+
+// CheckStore measures ~/.gdev and looks for unreadable and orphaned files.
+func (c *Config) CheckStore() (store.Report, error) {
+	if c.store == nil {
+		return store.Report{}, nil
+	}
+	return c.store.Check()
+}
+
+// RemoveStoreFiles deletes files in ~/.gdev by their full path, such as the
+// problems of a store report. In dry-run mode the deletions are only planned.
+func (c *Config) RemoveStoreFiles(paths ...string) error {
+	if c.store == nil {
+		return nil
+	}
+	return c.store.Remove(paths...)
+}
+
+// KnownRepos returns the repositories gdev has been opened in, most recent first.
+func (c *Config) KnownRepos() ([]store.RepoState, error) {
+	if c.store == nil {
+		return nil, nil
+	}
+	return c.store.ListRepos()
+}
+
+// RepoTodos returns how many todos are kept for a repository.
+func (c *Config) RepoTodos(repoPath string) int {
+	if c.store == nil {
+		return 0
+	}
+	list, err := c.store.GetTodos(repoPath)
+	if err != nil {
+		return 0
+	}
+	return len(list.Todos)
+}
+
+// ForgetRepo deletes the state, todo list and audit log kept for a
+// repository. With export set, the todo list is first copied to
+// ~/.gdev/exports, whose path is returned.
+func (c *Config) ForgetRepo(repoPath string, export bool) (string, error) {
+	if c.store == nil {
+		return "", nil
+	}
+	var exported string
+	if export {
+		var err error
+		if exported, err = c.store.ExportTodos(repoPath); err != nil {
+			return "", err
+		}
+	}
+	return exported, c.store.ForgetRepo(repoPath)
+}
+
+// DetachedJobs returns the commands earlier runs of gdev left running.
+func (c *Config) DetachedJobs() ([]store.Job, error) {
+	if c.store == nil {
+		return nil, nil
+	}
+	return c.store.GetJobs()
+}
+
+// RecordUsage adds what a request to an AI provider, asking model, used
+// to the usage log budgets are checked against.
+func (c *Config) RecordUsage(provider, model string, tokens int, cost float64) error {
+	if c.store == nil {
+		return nil
+	}
+	return c.store.AppendUsage(store.UsageEntry{Time: time.Now(), Provider: provider, Model: model, Tokens: tokens, Cost: cost})
+}
+
+// MonthUsage returns the usage log of t's month, oldest first.
+func (c *Config) MonthUsage(t time.Time) ([]store.UsageEntry, error) {
+	if c.store == nil {
+		return nil, nil
+	}
+	return c.store.MonthUsage(t)
+}
+
+// CachedResponse returns the AI response cached under key, if caching is
+// enabled and it has not expired.
+func (c *Config) CachedResponse(key store.CacheKey) ([]string, bool) {
+	if c.store == nil || c.Settings.CacheTTL() == 0 {
+		return nil, false
+	}
+	e, err := c.store.GetCache(key, c.Settings.CacheTTL())
+	if err != nil || e == nil {
+		return nil, false
+	}
+	return e.Output, true
+}
+
+// CacheResponse stores an AI response under key. Like the audit log, the
+// cache is left alone in dry-run mode.
+func (c *Config) CacheResponse(key store.CacheKey, output []string) error {
+	if c.store == nil || c.DryRun() || c.Settings.CacheTTL() == 0 {
+		return nil
+	}
+	return c.store.PutCache(key, output)
+}
+
 // Keys returns the keybindings for convenient access.
 func (c *Config) Keys() *Keybindings {
 	return c.Keybindings
 }
+
+// ExpandHome replaces a leading ~ in a path the user typed with their home
+// directory.
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/ihatemodels/gdev/internal/store"
+)
+
+func TestCachedResponse(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	s, err := store.New()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	cfg, err := Load(s)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	key := store.CacheKey{Provider: "claude", Model: "claude-sonnet-4-5", Hash: "abc"}
+	output := []string{"feat: add cache", "", "Body"}
+
+	if _, ok := cfg.CachedResponse(key); ok {
+		t.Fatal("CachedResponse() hit before anything was cached")
+	}
+
+	// Nothing is cached in dry-run mode
+	cfg.SetDryRun(true)
+	if err := cfg.CacheResponse(key, output); err != nil {
+		t.Fatalf("CacheResponse() error = %v", err)
+	}
+	cfg.SetDryRun(false)
+	if _, ok := cfg.CachedResponse(key); ok {
+		t.Error("CachedResponse() hit after a dry-run write")
+	}
+
+	if err := cfg.CacheResponse(key, output); err != nil {
+		t.Fatalf("CacheResponse() error = %v", err)
+	}
+	if got, ok := cfg.CachedResponse(key); !ok || !slices.Equal(got, output) {
+		t.Errorf("CachedResponse() = %v, %v; want %v", got, ok, output)
+	}
+
+	// Disabling the cache hides entries that were stored
+	cfg.Settings.AICacheTTL = "0"
+	if _, ok := cfg.CachedResponse(key); ok {
+		t.Error("CachedResponse() hit with the cache disabled")
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"512", 512},
+		{"100MB", 100 << 20},
+		{"1.5gb", 3 << 29},
+		{"64 KB", 64 << 10},
+	}
+	for _, tt := range tests {
+		if got, err := ParseSize(tt.in); err != nil || got != tt.want {
+			t.Errorf("ParseSize(%q) = %d, %v; want %d", tt.in, got, err, tt.want)
+		}
+	}
+	for _, in := range []string{"", "MB", "-1KB", "10TB"} {
+		if _, err := ParseSize(in); err == nil {
+			t.Errorf("ParseSize(%q) succeeded; want an error", in)
+		}
+	}
+}
+
+func TestReadOnly_RefusesImportAndCleanup(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	s, err := store.New()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	cfg, err := Load(s)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	cache, err := s.SubDir("cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Write("old.json", []byte("{}")); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Settings.Retention = map[string]Retention{"cache": {MaxSize: "1"}}
+	cfg.SetReadOnly(true)
+
+	if err := cfg.Import([]byte(`{"version": 1, "keybindings": {"form": {"submit": "ctrl+x"}}}`)); !errors.Is(err, store.ErrReadOnly) {
+		t.Errorf("Import() error = %v, want ErrReadOnly", err)
+	}
+	if _, err := cfg.Cleanup(nil); !errors.Is(err, store.ErrReadOnly) {
+		t.Errorf("Cleanup() error = %v, want ErrReadOnly", err)
+	}
+	if !s.Exists("cache/old.json") {
+		t.Error("Cleanup() deleted a file in read-only mode")
+	}
+
+	reloaded, err := Load(s)
+	if err != nil {
+		t.Fatalf("Failed to reload config: %v", err)
+	}
+	if reloaded.Keys().Form.Submit == "ctrl+x" {
+		t.Error("Import() saved keybindings in read-only mode")
+	}
+}
+
+func TestCheckBudgets(t *testing.T) {
+	st := DefaultSettings()
+	st.Budgets = map[string]Budget{
+		"claude": {DailyCost: 5},
+		"ollama": {MonthlyTokens: 1_000_000},
+	}
+	if err := st.CheckBudgets(); err != nil {
+		t.Errorf("CheckBudgets() error = %v", err)
+	}
+
+	// Requests to unpriced providers cost nothing, so the limit would never be reached
+	st.Budgets["openai"] = Budget{MonthlyCost: 20}
+	if err := st.CheckBudgets(); err == nil {
+		t.Error("CheckBudgets() accepted a cost limit on openai")
+	}
+}
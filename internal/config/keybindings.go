@@ -26,60 +26,230 @@ type Keybindings struct {
 
 	// Detail view keybindings
 	Detail DetailKeys `json:"detail"`
+
+	// Pull request view keybindings
+	PR PRKeys `json:"pr"`
+
+	// Review keybindings (accept/reject proposed changes)
+	Review ReviewKeys `json:"review"`
+
+	// Confirmation dialog keybindings
+	Confirm ConfirmKeys `json:"confirm"`
+
+	// Claude session keybindings
+	Sessions SessionKeys `json:"sessions"`
+
+	// Diff viewer keybindings
+	Diff DiffKeys `json:"diff"`
+
+	// Error screen keybindings
+	Error ErrorKeys `json:"error"`
+
+	// Settings view keybindings
+	Settings SettingsKeys `json:"settings"`
+
+	// Smart Commit keybindings
+	Commit CommitKeys `json:"commit"`
+
+	// Terminal modal keybindings
+	Terminal TerminalKeys `json:"terminal"`
+
+	// Branches view keybindings
+	Branch BranchKeys `json:"branch"`
+
+	// Patch export and import keybindings
+	Patch PatchKeys `json:"patch"`
+
+	// Timeline keybindings
+	Timeline TimelineKeys `json:"timeline"`
 }
 
 // GlobalKeys are keybindings that work across multiple views.
 type GlobalKeys struct {
-	Quit      string `json:"quit"`       // Quit/back
-	QuitAlt   string `json:"quit_alt"`   // Alternative quit key
-	Help      string `json:"help"`       // Show help
-	MoveUp    string `json:"move_up"`    // Move cursor up
-	MoveDown  string `json:"move_down"`  // Move cursor down
-	MoveUpAlt string `json:"move_up_alt"`   // Alternative move up (arrow key)
+	Quit        string `json:"quit"`          // Quit/back
+	QuitAlt     string `json:"quit_alt"`      // Alternative quit key
+	Help        string `json:"help"`          // Show help
+	MoveUp      string `json:"move_up"`       // Move cursor up
+	MoveDown    string `json:"move_down"`     // Move cursor down
+	MoveUpAlt   string `json:"move_up_alt"`   // Alternative move up (arrow key)
 	MoveDownAlt string `json:"move_down_alt"` // Alternative move down (arrow key)
+	DryRun      string `json:"dry_run"`       // Toggle dry-run mode (main menu)
+	AuditLog    string `json:"audit_log"`     // Show the repository's audit log (main menu)
+	Cancel      string `json:"cancel"`        // Cancel the running command
+	Jobs        string `json:"jobs"`          // Show commands left running by earlier runs (main menu)
+	History     string `json:"history"`       // Show the saved command output (main menu)
+	Reflog      string `json:"reflog"`        // Show where HEAD has been, to rescue lost commits (main menu)
+	Timeline    string `json:"timeline"`      // Show what was done, day by day, across repositories (main menu)
+	FindFile    string `json:"find_file"`     // Find a file in the repository and open it in $EDITOR (main menu)
+	Diagnostics string `json:"diagnostics"`   // Toggle the render diagnostics (--debug only)
 }
 
 // ListKeys are keybindings for list views.
 type ListKeys struct {
-	Select     string `json:"select"`       // Select/enter item
-	New        string `json:"new"`          // Create new item
-	Delete     string `json:"delete"`       // Delete item
-	Edit       string `json:"edit"`         // Edit item
-	Top        string `json:"top"`          // Jump to top
-	Bottom     string `json:"bottom"`       // Jump to bottom
-	PageUp     string `json:"page_up"`      // Page up
-	PageDown   string `json:"page_down"`    // Page down
+	Select   string `json:"select"`    // Select/enter item
+	New      string `json:"new"`       // Create new item
+	Delete   string `json:"delete"`    // Delete item
+	Edit     string `json:"edit"`      // Edit item
+	Top      string `json:"top"`       // Jump to top
+	Bottom   string `json:"bottom"`    // Jump to bottom
+	PageUp   string `json:"page_up"`   // Page up
+	PageDown string `json:"page_down"` // Page down
+	Snooze   string `json:"snooze"`    // Snooze item until a date
+	Sort     string `json:"sort"`      // Cycle the sort order
+	Stats    string `json:"stats"`     // Show what the todos' runs cost
+	Status   string `json:"status"`    // Cycle the status: open, in progress, done
+	Search   string `json:"search"`    // Search the todos, narrowing the list while typing
+	Details  string `json:"details"`   // Open the item's details
+
+	// Quick filters of the todo list, each toggled by its key
+	FilterBranch  string `json:"filter_branch"`  // Only the current branch
+	FilterDue     string `json:"filter_due"`     // Only due within a week
+	FilterPrompts string `json:"filter_prompts"` // Only with prompts
+	FilterActive  string `json:"filter_active"`  // Only not snoozed
+	FilterStatus  string `json:"filter_status"`  // Cycle the statuses shown
 }
 
 // FormKeys are keybindings for form/input views.
 type FormKeys struct {
-	Submit       string `json:"submit"`         // Submit form
-	Cancel       string `json:"cancel"`         // Cancel form
-	NextField    string `json:"next_field"`     // Move to next field
-	PrevField    string `json:"prev_field"`     // Move to previous field
-	AddPrompt    string `json:"add_prompt"`     // Add new prompt
-	DeletePrompt string `json:"delete_prompt"`  // Delete current prompt
-	EditPrompt   string `json:"edit_prompt"`    // Open prompt editor
+	Submit        string `json:"submit"`         // Submit form
+	Cancel        string `json:"cancel"`         // Cancel form
+	NextField     string `json:"next_field"`     // Move to next field
+	PrevField     string `json:"prev_field"`     // Move to previous field
+	AddPrompt     string `json:"add_prompt"`     // Add new prompt
+	DeletePrompt  string `json:"delete_prompt"`  // Delete current prompt
+	EditPrompt    string `json:"edit_prompt"`    // Open prompt editor
 	ImprovePrompt string `json:"improve_prompt"` // Improve prompt with AI
+	ImproveAll    string `json:"improve_all"`    // Improve all prompts at once
 }
 
 // EditorKeys are keybindings for the multi-line text editor.
 type EditorKeys struct {
-	Save         string `json:"save"`           // Save and exit editor
-	Cancel       string `json:"cancel"`         // Cancel editing
-	LineStart    string `json:"line_start"`     // Move to line start
-	LineEnd      string `json:"line_end"`       // Move to line end
-	DeleteLine   string `json:"delete_line"`    // Delete current line
-	NewLine      string `json:"new_line"`       // Insert new line
+	Save       string `json:"save"`        // Save and exit editor
+	Cancel     string `json:"cancel"`      // Cancel editing
+	LineStart  string `json:"line_start"`  // Move to line start
+	LineEnd    string `json:"line_end"`    // Move to line end
+	DeleteLine string `json:"delete_line"` // Delete current line
+	NewLine    string `json:"new_line"`    // Insert new line
+	Preview    string `json:"preview"`     // Show the rendered markdown beside the text
 }
 
 // DetailKeys are keybindings for detail/view screens.
 type DetailKeys struct {
-	Back         string `json:"back"`           // Go back
-	Edit         string `json:"edit"`           // Edit item
-	Delete       string `json:"delete"`         // Delete item
-	ScrollUp     string `json:"scroll_up"`      // Scroll up
-	ScrollDown   string `json:"scroll_down"`    // Scroll down
+	Back       string `json:"back"`        // Go back
+	Edit       string `json:"edit"`        // Edit item
+	Delete     string `json:"delete"`      // Delete item
+	ScrollUp   string `json:"scroll_up"`   // Scroll up
+	ScrollDown string `json:"scroll_down"` // Scroll down
+	Run        string `json:"run"`         // Run the item's prompts
+	Export     string `json:"export"`      // Export the run summary to markdown
+	Rollback   string `json:"rollback"`    // Roll back to the selected checkpoint
+	Subtask    string `json:"subtask"`     // Select the next subtask
+	Toggle     string `json:"toggle"`      // Mark the selected subtask done or not
+}
+
+// PRKeys are keybindings for pull request views.
+type PRKeys struct {
+	Checkout string `json:"checkout"` // Check out PR branch locally
+	Refresh  string `json:"refresh"`  // Reload PR list
+	Merge    string `json:"merge"`    // Merge PR
+	Review   string `json:"review"`   // Generate AI review summary
+}
+
+// ReviewKeys are keybindings for reviewing proposed changes.
+type ReviewKeys struct {
+	Accept string `json:"accept"` // Accept proposed change
+	Reject string `json:"reject"` // Reject and keep original
+	Edit   string `json:"edit"`   // Edit proposed change before accepting
+}
+
+// ConfirmKeys are keybindings for confirmation dialogs.
+type ConfirmKeys struct {
+	Yes string `json:"yes"` // Confirm action
+	No  string `json:"no"`  // Cancel action
+}
+
+// SessionKeys are keybindings for the Claude sessions view.
+type SessionKeys struct {
+	Resume      string `json:"resume"`       // Resume session in the real terminal
+	ResumeModal string `json:"resume_modal"` // Resume session in the terminal modal
+	Refresh     string `json:"refresh"`      // Reload session list
+	Transcript  string `json:"transcript"`   // View session transcript
+	Search      string `json:"search"`       // Search in transcript
+	NextMatch   string `json:"next_match"`   // Jump to next search match
+	PrevMatch   string `json:"prev_match"`   // Jump to previous search match
+	Export      string `json:"export"`       // Export transcript to markdown
+}
+
+// DiffKeys are keybindings for the diff viewer.
+type DiffKeys struct {
+	Open       string `json:"open"`        // Open diff viewer
+	NextFile   string `json:"next_file"`   // Show next file
+	PrevFile   string `json:"prev_file"`   // Show previous file
+	SwitchPane string `json:"switch_pane"` // Toggle file list / content focus
+}
+
+// ErrorKeys are keybindings for the error screen.
+type ErrorKeys struct {
+	Retry   string `json:"retry"`   // Run the failed command again
+	Details string `json:"details"` // Toggle error details
+}
+
+// SettingsKeys are keybindings for the settings view.
+type SettingsKeys struct {
+	ResetGroup string `json:"reset_group"` // Reset the selected keybinding group to defaults
+	ResetAll   string `json:"reset_all"`   // Reset all keybindings to defaults
+}
+
+// CommitKeys are keybindings for Smart Commit.
+type CommitKeys struct {
+	Continue        string `json:"continue"`         // Continue an interrupted merge/rebase
+	Abort           string `json:"abort"`            // Abort an interrupted merge/rebase
+	ToggleFile      string `json:"toggle_file"`      // Include/exclude the selected file
+	ToggleAll       string `json:"toggle_all"`       // Include/exclude all files
+	TogglePreview   string `json:"toggle_preview"`   // Show/hide the diff beside the message
+	Regenerate      string `json:"regenerate"`       // Generate the message again, with an optional hint
+	RegenerateFresh string `json:"regenerate_fresh"` // Regenerate, skipping the response cache
+	CoAuthor        string `json:"co_author"`        // Add/remove a co-author of the commit
+	StagedOnly      string `json:"staged_only"`      // Switch between committing the staged changes and choosing files
+	Split           string `json:"split"`            // Propose splitting the selected files into several commits
+}
+
+// TerminalKeys are keybindings for the terminal modal.
+type TerminalKeys struct {
+	Visual string `json:"visual"` // Start/stop selecting lines of output
+	Copy   string `json:"copy"`   // Copy the selected lines, or all output, to the clipboard
+
+	NextLocation string `json:"next_location"` // Select the next file:line in the output
+	PrevLocation string `json:"prev_location"` // Select the previous file:line in the output
+	Open         string `json:"open"`          // Open the selected file:line in $EDITOR
+
+	Input string `json:"input"` // Type keys to the running command
+	Save  string `json:"save"`  // Save the output to a log listed in the history
+	Rerun string `json:"rerun"` // Pick a command run in the repository to run again
+
+	Fullscreen string `json:"fullscreen"` // Expand the modal to the whole screen and back
+}
+
+// BranchKeys are keybindings for the Branches view.
+type BranchKeys struct {
+	Push string `json:"push"` // Push the selected branch to its upstream
+}
+
+// PatchKeys are keybindings for exporting commits as patches and applying
+// patches, from the Branches view.
+type PatchKeys struct {
+	Export string `json:"export"` // Choose commits of the selected branch to export
+	Apply  string `json:"apply"`  // Apply patch files to the current branch
+	Toggle string `json:"toggle"` // Include/exclude the selected commit
+}
+
+// TimelineKeys are keybindings for the timeline of what was done.
+type TimelineKeys struct {
+	Repo    string `json:"repo"`     // Cycle the repositories shown: all, then each
+	Earlier string `json:"earlier"`  // Show the day before
+	Later   string `json:"later"`    // Show the day after
+	AllDays string `json:"all_days"` // Show every day, or back to a single one
+	Export  string `json:"export"`   // Export the events shown as a timesheet
 }
 
 // DefaultKeybindings returns the default keybinding configuration.
@@ -93,6 +263,15 @@ func DefaultKeybindings() *Keybindings {
 			MoveDown:    "j",
 			MoveUpAlt:   "up",
 			MoveDownAlt: "down",
+			DryRun:      "D",
+			AuditLog:    "a",
+			Cancel:      "ctrl+c",
+			Jobs:        "J",
+			History:     "H",
+			Reflog:      "R",
+			Timeline:    "T",
+			FindFile:    "F",
+			Diagnostics: "f12",
 		},
 		List: ListKeys{
 			Select:   "enter",
@@ -103,6 +282,18 @@ func DefaultKeybindings() *Keybindings {
 			Bottom:   "G",
 			PageUp:   "ctrl+u",
 			PageDown: "ctrl+d",
+			Snooze:   "z",
+			Sort:     "o",
+			Stats:    "S",
+			Status:   "x",
+			Search:   "/",
+			Details:  "v",
+
+			FilterBranch:  "1",
+			FilterDue:     "2",
+			FilterPrompts: "3",
+			FilterActive:  "4",
+			FilterStatus:  "5",
 		},
 		Form: FormKeys{
 			Submit:        "ctrl+s",
@@ -113,6 +304,7 @@ func DefaultKeybindings() *Keybindings {
 			DeletePrompt:  "ctrl+d",
 			EditPrompt:    "ctrl+e",
 			ImprovePrompt: "ctrl+i",
+			ImproveAll:    "alt+i",
 		},
 		Editor: EditorKeys{
 			Save:       "ctrl+s",
@@ -121,6 +313,7 @@ func DefaultKeybindings() *Keybindings {
 			LineEnd:    "ctrl+e",
 			DeleteLine: "ctrl+k",
 			NewLine:    "enter",
+			Preview:    "ctrl+p",
 		},
 		Detail: DetailKeys{
 			Back:       "esc",
@@ -128,6 +321,88 @@ func DefaultKeybindings() *Keybindings {
 			Delete:     "d",
 			ScrollUp:   "k",
 			ScrollDown: "j",
+			Run:        "r",
+			Export:     "x",
+			Rollback:   "u",
+			Subtask:    "tab",
+			Toggle:     "space",
+		},
+		PR: PRKeys{
+			Checkout: "c",
+			Refresh:  "r",
+			Merge:    "m",
+			Review:   "a",
+		},
+		Review: ReviewKeys{
+			Accept: "y",
+			Reject: "n",
+			Edit:   "e",
+		},
+		Confirm: ConfirmKeys{
+			Yes: "y",
+			No:  "n",
+		},
+		Sessions: SessionKeys{
+			Resume:      "enter",
+			ResumeModal: "t",
+			Refresh:     "r",
+			Transcript:  "v",
+			Search:      "/",
+			NextMatch:   "n",
+			PrevMatch:   "N",
+			Export:      "e",
+		},
+		Diff: DiffKeys{
+			Open:       "ctrl+o",
+			NextFile:   "]",
+			PrevFile:   "[",
+			SwitchPane: "tab",
+		},
+		Error: ErrorKeys{
+			Retry:   "r",
+			Details: "d",
+		},
+		Settings: SettingsKeys{
+			ResetGroup: "r",
+			ResetAll:   "R",
+		},
+		Commit: CommitKeys{
+			Continue:        "c",
+			Abort:           "A",
+			ToggleFile:      "space",
+			ToggleAll:       "a",
+			TogglePreview:   "ctrl+p",
+			Regenerate:      "ctrl+r",
+			RegenerateFresh: "alt+r",
+			CoAuthor:        "ctrl+t",
+			StagedOnly:      "s",
+			Split:           "S",
+		},
+		Terminal: TerminalKeys{
+			Visual:       "v",
+			Copy:         "y",
+			NextLocation: "n",
+			PrevLocation: "N",
+			Open:         "enter",
+			Input:        "i",
+			Save:         "s",
+			Rerun:        "r",
+			Fullscreen:   "f",
+		},
+		Branch: BranchKeys{
+			Push: "P",
+		},
+		Patch: PatchKeys{
+			Export: "p",
+			Apply:  "A",
+			Toggle: "space",
+		},
+		Timeline: TimelineKeys{
+			Repo:    "r",
+			Earlier: "left",
+			Later:   "right",
+			AllDays: "a",
+			Export:  "e",
 		},
 	}
 }
@@ -189,6 +464,33 @@ func mergeWithDefaults(kb *Keybindings) Keybindings {
 	if result.Global.MoveDownAlt == "" {
 		result.Global.MoveDownAlt = defaults.Global.MoveDownAlt
 	}
+	if result.Global.DryRun == "" {
+		result.Global.DryRun = defaults.Global.DryRun
+	}
+	if result.Global.AuditLog == "" {
+		result.Global.AuditLog = defaults.Global.AuditLog
+	}
+	if result.Global.Cancel == "" {
+		result.Global.Cancel = defaults.Global.Cancel
+	}
+	if result.Global.Jobs == "" {
+		result.Global.Jobs = defaults.Global.Jobs
+	}
+	if result.Global.History == "" {
+		result.Global.History = defaults.Global.History
+	}
+	if result.Global.Reflog == "" {
+		result.Global.Reflog = defaults.Global.Reflog
+	}
+	if result.Global.Timeline == "" {
+		result.Global.Timeline = defaults.Global.Timeline
+	}
+	if result.Global.FindFile == "" {
+		result.Global.FindFile = defaults.Global.FindFile
+	}
+	if result.Global.Diagnostics == "" {
+		result.Global.Diagnostics = defaults.Global.Diagnostics
+	}
 
 	// List
 	if result.List.Select == "" {
@@ -215,6 +517,39 @@ func mergeWithDefaults(kb *Keybindings) Keybindings {
 	if result.List.PageDown == "" {
 		result.List.PageDown = defaults.List.PageDown
 	}
+	if result.List.Snooze == "" {
+		result.List.Snooze = defaults.List.Snooze
+	}
+	if result.List.Sort == "" {
+		result.List.Sort = defaults.List.Sort
+	}
+	if result.List.Stats == "" {
+		result.List.Stats = defaults.List.Stats
+	}
+	if result.List.Status == "" {
+		result.List.Status = defaults.List.Status
+	}
+	if result.List.Search == "" {
+		result.List.Search = defaults.List.Search
+	}
+	if result.List.Details == "" {
+		result.List.Details = defaults.List.Details
+	}
+	if result.List.FilterBranch == "" {
+		result.List.FilterBranch = defaults.List.FilterBranch
+	}
+	if result.List.FilterDue == "" {
+		result.List.FilterDue = defaults.List.FilterDue
+	}
+	if result.List.FilterPrompts == "" {
+		result.List.FilterPrompts = defaults.List.FilterPrompts
+	}
+	if result.List.FilterActive == "" {
+		result.List.FilterActive = defaults.List.FilterActive
+	}
+	if result.List.FilterStatus == "" {
+		result.List.FilterStatus = defaults.List.FilterStatus
+	}
 
 	// Form
 	if result.Form.Submit == "" {
@@ -241,6 +576,9 @@ func mergeWithDefaults(kb *Keybindings) Keybindings {
 	if result.Form.ImprovePrompt == "" {
 		result.Form.ImprovePrompt = defaults.Form.ImprovePrompt
 	}
+	if result.Form.ImproveAll == "" {
+		result.Form.ImproveAll = defaults.Form.ImproveAll
+	}
 
 	// Editor
 	if result.Editor.Save == "" {
@@ -261,6 +599,9 @@ func mergeWithDefaults(kb *Keybindings) Keybindings {
 	if result.Editor.NewLine == "" {
 		result.Editor.NewLine = defaults.Editor.NewLine
 	}
+	if result.Editor.Preview == "" {
+		result.Editor.Preview = defaults.Editor.Preview
+	}
 
 	// Detail
 	if result.Detail.Back == "" {
@@ -278,6 +619,204 @@ func mergeWithDefaults(kb *Keybindings) Keybindings {
 	if result.Detail.ScrollDown == "" {
 		result.Detail.ScrollDown = defaults.Detail.ScrollDown
 	}
+	if result.Detail.Run == "" {
+		result.Detail.Run = defaults.Detail.Run
+	}
+	if result.Detail.Export == "" {
+		result.Detail.Export = defaults.Detail.Export
+	}
+	if result.Detail.Rollback == "" {
+		result.Detail.Rollback = defaults.Detail.Rollback
+	}
+	if result.Detail.Subtask == "" {
+		result.Detail.Subtask = defaults.Detail.Subtask
+	}
+	if result.Detail.Toggle == "" {
+		result.Detail.Toggle = defaults.Detail.Toggle
+	}
+
+	// PR
+	if result.PR.Checkout == "" {
+		result.PR.Checkout = defaults.PR.Checkout
+	}
+	if result.PR.Refresh == "" {
+		result.PR.Refresh = defaults.PR.Refresh
+	}
+	if result.PR.Merge == "" {
+		result.PR.Merge = defaults.PR.Merge
+	}
+	if result.PR.Review == "" {
+		result.PR.Review = defaults.PR.Review
+	}
+
+	// Review
+	if result.Review.Accept == "" {
+		result.Review.Accept = defaults.Review.Accept
+	}
+	if result.Review.Reject == "" {
+		result.Review.Reject = defaults.Review.Reject
+	}
+	if result.Review.Edit == "" {
+		result.Review.Edit = defaults.Review.Edit
+	}
+
+	// Confirm
+	if result.Confirm.Yes == "" {
+		result.Confirm.Yes = defaults.Confirm.Yes
+	}
+	if result.Confirm.No == "" {
+		result.Confirm.No = defaults.Confirm.No
+	}
+
+	// Sessions
+	if result.Sessions.Resume == "" {
+		result.Sessions.Resume = defaults.Sessions.Resume
+	}
+	if result.Sessions.ResumeModal == "" {
+		result.Sessions.ResumeModal = defaults.Sessions.ResumeModal
+	}
+	if result.Sessions.Refresh == "" {
+		result.Sessions.Refresh = defaults.Sessions.Refresh
+	}
+	if result.Sessions.Transcript == "" {
+		result.Sessions.Transcript = defaults.Sessions.Transcript
+	}
+	if result.Sessions.Search == "" {
+		result.Sessions.Search = defaults.Sessions.Search
+	}
+	if result.Sessions.NextMatch == "" {
+		result.Sessions.NextMatch = defaults.Sessions.NextMatch
+	}
+	if result.Sessions.PrevMatch == "" {
+		result.Sessions.PrevMatch = defaults.Sessions.PrevMatch
+	}
+	if result.Sessions.Export == "" {
+		result.Sessions.Export = defaults.Sessions.Export
+	}
+
+	// Diff
+	if result.Diff.Open == "" {
+		result.Diff.Open = defaults.Diff.Open
+	}
+	if result.Diff.NextFile == "" {
+		result.Diff.NextFile = defaults.Diff.NextFile
+	}
+	if result.Diff.PrevFile == "" {
+		result.Diff.PrevFile = defaults.Diff.PrevFile
+	}
+	if result.Diff.SwitchPane == "" {
+		result.Diff.SwitchPane = defaults.Diff.SwitchPane
+	}
+
+	// Error
+	if result.Error.Retry == "" {
+		result.Error.Retry = defaults.Error.Retry
+	}
+	if result.Error.Details == "" {
+		result.Error.Details = defaults.Error.Details
+	}
+
+	// Settings
+	if result.Settings.ResetGroup == "" {
+		result.Settings.ResetGroup = defaults.Settings.ResetGroup
+	}
+	if result.Settings.ResetAll == "" {
+		result.Settings.ResetAll = defaults.Settings.ResetAll
+	}
+
+	// Commit
+	if result.Commit.Continue == "" {
+		result.Commit.Continue = defaults.Commit.Continue
+	}
+	if result.Commit.Abort == "" {
+		result.Commit.Abort = defaults.Commit.Abort
+	}
+	if result.Commit.ToggleFile == "" {
+		result.Commit.ToggleFile = defaults.Commit.ToggleFile
+	}
+	if result.Commit.ToggleAll == "" {
+		result.Commit.ToggleAll = defaults.Commit.ToggleAll
+	}
+	if result.Commit.TogglePreview == "" {
+		result.Commit.TogglePreview = defaults.Commit.TogglePreview
+	}
+	if result.Commit.Regenerate == "" {
+		result.Commit.Regenerate = defaults.Commit.Regenerate
+	}
+	if result.Commit.RegenerateFresh == "" {
+		result.Commit.RegenerateFresh = defaults.Commit.RegenerateFresh
+	}
+	if result.Commit.CoAuthor == "" {
+		result.Commit.CoAuthor = defaults.Commit.CoAuthor
+	}
+	if result.Commit.StagedOnly == "" {
+		result.Commit.StagedOnly = defaults.Commit.StagedOnly
+	}
+	if result.Commit.Split == "" {
+		result.Commit.Split = defaults.Commit.Split
+	}
+
+	// Terminal
+	if result.Terminal.Visual == "" {
+		result.Terminal.Visual = defaults.Terminal.Visual
+	}
+	if result.Terminal.Copy == "" {
+		result.Terminal.Copy = defaults.Terminal.Copy
+	}
+	if result.Terminal.NextLocation == "" {
+		result.Terminal.NextLocation = defaults.Terminal.NextLocation
+	}
+	if result.Terminal.PrevLocation == "" {
+		result.Terminal.PrevLocation = defaults.Terminal.PrevLocation
+	}
+	if result.Terminal.Open == "" {
+		result.Terminal.Open = defaults.Terminal.Open
+	}
+	if result.Terminal.Input == "" {
+		result.Terminal.Input = defaults.Terminal.Input
+	}
+	if result.Terminal.Save == "" {
+		result.Terminal.Save = defaults.Terminal.Save
+	}
+	if result.Terminal.Rerun == "" {
+		result.Terminal.Rerun = defaults.Terminal.Rerun
+	}
+	if result.Terminal.Fullscreen == "" {
+		result.Terminal.Fullscreen = defaults.Terminal.Fullscreen
+	}
+
+	// Branch
+	if result.Branch.Push == "" {
+		result.Branch.Push = defaults.Branch.Push
+	}
+
+	// Patch
+	if result.Patch.Export == "" {
+		result.Patch.Export = defaults.Patch.Export
+	}
+	if result.Patch.Apply == "" {
+		result.Patch.Apply = defaults.Patch.Apply
+	}
+	if result.Patch.Toggle == "" {
+		result.Patch.Toggle = defaults.Patch.Toggle
+	}
+
+	// Timeline
+	if result.Timeline.Repo == "" {
+		result.Timeline.Repo = defaults.Timeline.Repo
+	}
+	if result.Timeline.Earlier == "" {
+		result.Timeline.Earlier = defaults.Timeline.Earlier
+	}
+	if result.Timeline.Later == "" {
+		result.Timeline.Later = defaults.Timeline.Later
+	}
+	if result.Timeline.AllDays == "" {
+		result.Timeline.AllDays = defaults.Timeline.AllDays
+	}
+	if result.Timeline.Export == "" {
+		result.Timeline.Export = defaults.Timeline.Export
+	}
 
 	return result
 }
@@ -300,8 +839,12 @@ func MatchesAny(key string, bindings ...string) bool {
 }
 
 // normalizeBinding converts a binding string to match Bubble Tea's key format.
-// Specifically, "shift+x" becomes "X" for letter keys.
+// Specifically, "shift+x" becomes "X" for letter keys and "space" becomes " ".
 func normalizeBinding(binding string) string {
+	// Bubble Tea reports the space bar as " "
+	if binding == "space" {
+		return " "
+	}
 	// Handle shift+letter -> uppercase letter
 	if strings.HasPrefix(binding, "shift+") {
 		letter := strings.TrimPrefix(binding, "shift+")
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/ihatemodels/gdev/internal/store"
@@ -121,6 +122,9 @@ func TestMatches(t *testing.T) {
 	if Matches("ctrl+s", "ctrl+x") {
 		t.Error("Matches should return false for different strings")
 	}
+	if !Matches(" ", "space") {
+		t.Error("Matches should accept \"space\" for the space bar")
+	}
 }
 
 func TestMatches_ShiftLetters(t *testing.T) {
@@ -148,3 +152,52 @@ func TestMatches_ShiftLetters(t *testing.T) {
 		}
 	}
 }
+
+func TestBindings_SetResetAndConflicts(t *testing.T) {
+	kb := DefaultKeybindings()
+
+	if err := kb.SetBinding("form", "submit", "ctrl+x"); err != nil {
+		t.Fatalf("SetBinding failed: %v", err)
+	}
+	if kb.Form.Submit != "ctrl+x" {
+		t.Errorf("Expected Form.Submit to be 'ctrl+x', got '%s'", kb.Form.Submit)
+	}
+	if err := kb.SetBinding("form", "nope", "x"); err == nil {
+		t.Error("Expected error for unknown binding")
+	}
+
+	if err := kb.ResetGroup("form"); err != nil {
+		t.Fatalf("ResetGroup failed: %v", err)
+	}
+	if kb.Form.Submit != "ctrl+s" {
+		t.Errorf("Expected Form.Submit to be reset to 'ctrl+s', got '%s'", kb.Form.Submit)
+	}
+
+	for _, b := range DefaultKeybindings().Bindings() {
+		if conflicts := kb.Conflicts(b.Group, b.Action, b.Key); len(conflicts) != 0 {
+			t.Errorf("Default %s conflicts with %v", b.Name(), conflicts)
+		}
+	}
+
+	// Global and the editor's keys apply in forms too
+	conflicts := kb.Conflicts("form", "submit", "esc")
+	var names []string
+	for _, c := range conflicts {
+		names = append(names, c.Name())
+	}
+	if want := []string{"global.quit", "form.cancel", "editor.cancel"}; !slices.Equal(names, want) {
+		t.Errorf("Expected conflicts with %v, got %v", want, names)
+	}
+	// The detail beside the TODO list takes its keys in the list view
+	if conflicts := kb.Conflicts("list", "sort", "u"); len(conflicts) != 1 || conflicts[0].Name() != "detail.rollback" {
+		t.Errorf("Expected conflict with detail.rollback, got %v", conflicts)
+	}
+	// Keys shared by default do the same thing
+	if conflicts := kb.Conflicts("detail", "back", "esc"); len(conflicts) != 0 {
+		t.Errorf("Expected no conflicts for a default key, got %v", conflicts)
+	}
+	// Groups never matched in the same view don't conflict
+	if conflicts := kb.Conflicts("pr", "refresh", "y"); len(conflicts) != 0 {
+		t.Errorf("Expected no conflicts across views, got %v", conflicts)
+	}
+}
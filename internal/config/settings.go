@@ -0,0 +1,406 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ihatemodels/gdev/internal/sshagent"
+	"github.com/ihatemodels/gdev/internal/store"
+)
+
+const settingsFile = "settings.json"
+
+// Settings holds general application preferences.
+type Settings struct {
+	// RememberPositions keeps list cursor and scroll positions across gdev runs.
+	// Positions are always remembered while gdev is running.
+	RememberPositions bool `json:"remember_positions"`
+
+	// SSHAgent is how commits find an ssh-agent when SSH_AUTH_SOCK is not set.
+	SSHAgent sshagent.Strategy `json:"ssh_agent"`
+
+	// SSHAgentCommand prints the agent socket path, for the "command" strategy.
+	SSHAgentCommand string `json:"ssh_agent_command"`
+
+	// Proxy is used for HTTP and HTTPS unless HTTPS_PROXY or HTTP_PROXY is set.
+	Proxy string `json:"proxy"`
+
+	// NoProxy lists the hosts reached directly, unless NO_PROXY is set.
+	NoProxy string `json:"no_proxy"`
+
+	// CABundle is a PEM file of certificate authorities trusted by gh, claude and git.
+	CABundle string `json:"ca_bundle"`
+
+	// AIBackend writes commit messages: "claude" (the CLI), "anthropic",
+	// "openai" or "ollama".
+	AIBackend string `json:"ai_backend"`
+
+	// AIModel is the model the backend asks, "" for its default.
+	AIModel string `json:"ai_model"`
+
+	// AIFallback is the backend asked when AIBackend fails or takes longer
+	// than AITimeout, e.g. "ollama" during an outage; "" for none.
+	// AIFallbackModel is the model it asks, "" for its default.
+	AIFallback      string `json:"ai_fallback"`
+	AIFallbackModel string `json:"ai_fallback_model"`
+
+	// AITimeout is how long AIBackend may take before the fallback is
+	// asked, as a duration like "1m". "0" waits as long as it takes.
+	AITimeout string `json:"ai_timeout"`
+
+	// CommitStagedOnly makes Smart Commit commit what is already staged,
+	// instead of staging the files chosen in it.
+	CommitStagedOnly bool `json:"commit_staged_only"`
+
+	// CommitLint are the rules Smart Commit checks messages against.
+	CommitLint CommitLint `json:"commit_lint"`
+
+	// Gitmoji puts an emoji before commit subjects, picked for their type.
+	Gitmoji Gitmoji `json:"gitmoji"`
+
+	// AICacheTTL is how long AI responses are reused for identical requests,
+	// as a duration like "24h". "0" disables the cache.
+	AICacheTTL string `json:"ai_cache_ttl"`
+
+	// Retention limits the files kept in ~/.gdev, by category.
+	// The categories are RetentionCategories.
+	Retention map[string]Retention `json:"retention"`
+
+	// OutputBuffer is how much of a command's output the terminal keeps in
+	// memory, as a size like "16MB"; older lines are dropped. "0" keeps all.
+	OutputBuffer string `json:"output_buffer"`
+
+	// Highlights style the parts of terminal output matching their pattern.
+	// Where patterns overlap, the earlier rule wins.
+	Highlights []Highlight `json:"highlights"`
+
+	// TodoCards lays out the cards of the TODO list.
+	TodoCards TodoCards `json:"todo_cards"`
+
+	// TodoCheckpoints records the working tree before a TODO's prompts run
+	// and after each one, so the run can be rolled back to a checkpoint.
+	TodoCheckpoints bool `json:"todo_checkpoints"`
+
+	// ReadOnly starts gdev in read-only mode, as the --read-only flag does:
+	// browsing works, but commits, pushes, todo writes and other changes
+	// are refused.
+	ReadOnly bool `json:"read_only"`
+
+	// SingleInstance keeps to one gdev per repository: started again in a
+	// repository it already runs in, gdev forwards its command to the one
+	// running and exits.
+	SingleInstance bool `json:"single_instance"`
+
+	// TaskCommands limits the executables tasks from repositories' own
+	// config may run.
+	TaskCommands TaskCommands `json:"task_commands"`
+
+	// Timesheet is how the timeline is exported as a timesheet.
+	Timesheet Timesheet `json:"timesheet"`
+
+	// Budgets limit what AI requests use, by provider: "claude" (the CLI,
+	// also running TODO prompts), "anthropic", "openai" or "ollama". Only
+	// PricedProviders can have cost limits.
+	Budgets map[string]Budget `json:"budgets"`
+}
+
+// PricedProviders are the AI providers whose cost gdev can estimate, from
+// the prices of Claude models. Requests to the others are free as far as
+// budgets go, so only their tokens can be limited.
+var PricedProviders = []string{"claude", "anthropic"}
+
+// Budget limits what a provider is used for per day and per calendar
+// month, in tokens and estimated USD. Zero leaves a limit unset.
+type Budget struct {
+	DailyTokens   int     `json:"daily_tokens"`
+	MonthlyTokens int     `json:"monthly_tokens"`
+	DailyCost     float64 `json:"daily_cost"`
+	MonthlyCost   float64 `json:"monthly_cost"`
+
+	// WarnPercent is how much of a limit is used before gdev warns, 80 if
+	// unset.
+	WarnPercent int `json:"warn_percent"`
+}
+
+// CheckBudgets returns an error for cost limits on a provider gdev can't
+// price, which would never be reached.
+func (st *Settings) CheckBudgets() error {
+	for provider, b := range st.Budgets {
+		if (b.DailyCost > 0 || b.MonthlyCost > 0) && !slices.Contains(PricedProviders, provider) {
+			return fmt.Errorf("budgets: the cost of %s isn't known, so limit its daily_tokens or monthly_tokens instead", provider)
+		}
+	}
+	return nil
+}
+
+// Timesheet is how the timeline's events are counted as time spent, per
+// repository and day, when it is exported.
+type Timesheet struct {
+	// Format is "csv" or "json".
+	Format string `json:"format"`
+
+	// Gap is the longest break between two events of a session of work,
+	// as a duration like "30m".
+	Gap string `json:"gap"`
+
+	// MinSession is the least a session counts for, e.g. "15m".
+	MinSession string `json:"min_session"`
+
+	// Rounding rounds each repository's time on a day up to a multiple of
+	// it, e.g. "15m". "0" keeps whole minutes.
+	Rounding string `json:"rounding"`
+}
+
+// Durations returns the gap, minimum session and rounding of the
+// timesheet. Invalid durations keep their default.
+func (t Timesheet) Durations() (gap, minSession, rounding time.Duration) {
+	defaults := DefaultSettings().Timesheet
+	parse := func(s, def string) time.Duration {
+		d, err := time.ParseDuration(s)
+		if err != nil || d < 0 {
+			d, _ = time.ParseDuration(def)
+		}
+		return d
+	}
+	return parse(t.Gap, defaults.Gap), parse(t.MinSession, defaults.MinSession), parse(t.Rounding, defaults.Rounding)
+}
+
+// TodoCardFields are the fields a TODO card can show.
+var TodoCardFields = []string{"branch", "priority", "subtasks", "prompts", "model", "due", "snoozed", "created", "updated", "description"}
+
+// TodoCards are the fields shown on TODO cards, in order, and whether each
+// card takes a single line.
+type TodoCards struct {
+	// Fields are names from TodoCardFields. The description, if shown, has
+	// a line of its own below the others; unknown names are skipped.
+	Fields []string `json:"fields"`
+
+	// Compact puts each card on one line after its name, without the
+	// description.
+	Compact bool `json:"compact"`
+}
+
+// Highlight styles the parts of terminal output matching a regular expression.
+type Highlight struct {
+	Pattern string `json:"pattern"`
+
+	// Style is a color (green, red, yellow, cyan, pink, purple or subtle)
+	// and attributes (bold, italic, underline), separated by spaces.
+	Style string `json:"style"`
+}
+
+// CommitLint are rules for commit messages. Zero values turn a rule off.
+type CommitLint struct {
+	// MaxSubject is the longest subject line, type and scope included.
+	MaxSubject int `json:"max_subject"`
+
+	// Imperative requires the subject to start with a verb like "add",
+	// not "added", "adds" or "adding".
+	Imperative bool `json:"imperative"`
+
+	// Types are the conventional commit types allowed. When set, subjects
+	// need one of them.
+	Types []string `json:"types"`
+
+	// BlankLine requires a blank line between the subject and the body.
+	BlankLine bool `json:"blank_line"`
+
+	// BodyWrap is the longest line allowed in the body.
+	BodyWrap int `json:"body_wrap"`
+}
+
+// Gitmoji is whether commit subjects start with an emoji, and the emoji
+// each conventional commit type starts with.
+type Gitmoji struct {
+	Enabled bool              `json:"enabled"`
+	Types   map[string]string `json:"types"`
+}
+
+// RetentionCategories are the directories of ~/.gdev whose files can be
+// cleaned up: command logs, AI responses and exported transcripts.
+var RetentionCategories = []string{"logs", "cache", "exports"}
+
+// Retention is how long and how much of a category of files is kept.
+type Retention struct {
+	// MaxAge is a duration like "720h"; "" keeps files of any age.
+	MaxAge string `json:"max_age"`
+
+	// MaxSize is a total size like "100MB"; "" keeps any size.
+	MaxSize string `json:"max_size"`
+}
+
+// Policy parses the retention into a store cleanup policy.
+func (r Retention) Policy() (store.Policy, error) {
+	var p store.Policy
+	if r.MaxAge != "" {
+		age, err := time.ParseDuration(r.MaxAge)
+		if err != nil {
+			return p, fmt.Errorf("max_age: %w", err)
+		}
+		p.MaxAge = age
+	}
+	if r.MaxSize != "" {
+		size, err := ParseSize(r.MaxSize)
+		if err != nil {
+			return p, fmt.Errorf("max_size: %w", err)
+		}
+		p.MaxSize = size
+	}
+	return p, nil
+}
+
+// DefaultSettings returns the default settings.
+func DefaultSettings() *Settings {
+	return &Settings{
+		RememberPositions: false,
+		SSHAgent:          sshagent.UseExisting,
+		AIBackend:         "claude",
+		AITimeout:         "2m",
+		AICacheTTL:        "24h",
+		OutputBuffer:      "16MB",
+		CommitLint: CommitLint{
+			MaxSubject: 72,
+			Imperative: true,
+			BlankLine:  true,
+			BodyWrap:   72,
+		},
+		Gitmoji: Gitmoji{
+			Types: map[string]string{
+				"feat": "✨", "fix": "🐛", "refactor": "♻️", "perf": "⚡️", "docs": "📝", "style": "🎨",
+				"test": "✅", "build": "📦️", "ci": "👷", "chore": "🔧", "revert": "⏪️",
+			},
+		},
+		Retention: map[string]Retention{
+			"logs":  {MaxAge: "720h", MaxSize: "200MB"},
+			"cache": {MaxAge: "168h", MaxSize: "50MB"},
+		},
+		Highlights: []Highlight{
+			{Pattern: `\bFAIL\b|^panic:|\berror:`, Style: "red bold"},
+			{Pattern: `\bPASS\b|^ok\b`, Style: "green"},
+			{Pattern: `\bwarning:`, Style: "yellow"},
+			{Pattern: `[\w./-]+\.\w+:\d+(:\d+)?`, Style: "cyan underline"},
+		},
+		TodoCards: TodoCards{
+			Fields: []string{"branch", "priority", "subtasks", "prompts", "model", "due", "snoozed", "description"},
+		},
+		TaskCommands: TaskCommands{
+			Deny: []string{"sudo", "su", "doas"},
+		},
+		Timesheet: Timesheet{
+			Format:     "csv",
+			Gap:        "30m",
+			MinSession: "15m",
+			Rounding:   "15m",
+		},
+		Budgets: map[string]Budget{},
+	}
+}
+
+// CacheTTL returns how long AI responses are cached, 0 if caching is disabled
+// or the TTL is not a valid duration.
+func (st *Settings) CacheTTL() time.Duration {
+	ttl, err := time.ParseDuration(st.AICacheTTL)
+	if err != nil || ttl < 0 {
+		return 0
+	}
+	return ttl
+}
+
+// FallbackTimeout returns how long the AI backend may take before the
+// fallback is asked, 0 for no limit or if the timeout is not a valid
+// duration.
+func (st *Settings) FallbackTimeout() time.Duration {
+	timeout, err := time.ParseDuration(st.AITimeout)
+	if err != nil || timeout < 0 {
+		return 0
+	}
+	return timeout
+}
+
+// defaultOutputBuffer is the output kept when output_buffer is not a size.
+const defaultOutputBuffer = 16 << 20
+
+// OutputBufferSize returns how many bytes of a command's output the terminal
+// keeps in memory, 0 to keep all of it. An invalid size keeps the default.
+func (st *Settings) OutputBufferSize() int {
+	if st.OutputBuffer == "" {
+		return defaultOutputBuffer
+	}
+	size, err := ParseSize(st.OutputBuffer)
+	if err != nil {
+		return defaultOutputBuffer
+	}
+	return int(size)
+}
+
+// LoadSettings loads settings from the store.
+// If the settings file doesn't exist, it creates one with defaults.
+func LoadSettings(s *store.Store) (*Settings, error) {
+	// Start from defaults so fields missing from older files keep their default
+	st := DefaultSettings()
+
+	err := s.ReadJSON(settingsFile, st)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			if err := SaveSettings(s, st); err != nil {
+				return nil, err
+			}
+			return st, nil
+		}
+		return nil, err
+	}
+	if err := st.CheckBudgets(); err != nil {
+		return nil, err
+	}
+
+	return st, nil
+}
+
+// SaveSettings saves settings to the store.
+func SaveSettings(s *store.Store, st *Settings) error {
+	return s.WriteJSON(settingsFile, st)
+}
+
+// sizeUnits are the suffixes ParseSize accepts, largest first.
+var sizeUnits = []struct {
+	suffix string
+	bytes  int64
+}{
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"B", 1},
+}
+
+// ParseSize parses a size like "100MB", "1.5GB" or "512KB".
+// A number without a unit is in bytes.
+func ParseSize(s string) (int64, error) {
+	str := strings.ToUpper(strings.TrimSpace(s))
+	unit := int64(1)
+	for _, u := range sizeUnits {
+		if strings.HasSuffix(str, u.suffix) {
+			str = strings.TrimSpace(strings.TrimSuffix(str, u.suffix))
+			unit = u.bytes
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(str, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return int64(n * float64(unit)), nil
+}
+
+// FormatSize formats a number of bytes like "1.5 MB".
+func FormatSize(n int64) string {
+	for _, u := range sizeUnits[:3] {
+		if n >= u.bytes {
+			return fmt.Sprintf("%.1f %s", float64(n)/float64(u.bytes), u.suffix)
+		}
+	}
+	return fmt.Sprintf("%d B", n)
+}
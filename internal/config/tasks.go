@@ -0,0 +1,291 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Task is a command a repository defines in its own config (RepoFile),
+// run from the Tasks view, e.g. {"name": "test", "command": "go test ./..."}.
+type Task struct {
+	Name        string `json:"name"`
+	Command     string `json:"command"` // run with sh -c
+	Description string `json:"description,omitempty"`
+}
+
+// LoadTasks returns the tasks in the RepoFile of the repository at root and
+// the hash of the file they were read from, to check trust against. It
+// returns no tasks and an empty hash if the repository has no RepoFile.
+func LoadTasks(root string) ([]Task, string, error) {
+	data, err := os.ReadFile(filepath.Join(root, RepoFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	var repo struct {
+		Tasks []Task `json:"tasks"`
+	}
+	if err := json.Unmarshal(data, &repo); err != nil {
+		return nil, hash, fmt.Errorf("%s: %w", RepoFile, err)
+	}
+	return repo.Tasks, hash, nil
+}
+
+// TaskCommands limits the executables that tasks from a repository's own
+// config (RepoFile) may run. Names match an executable as written or by its
+// base name, so "rm" also denies "/bin/rm".
+type TaskCommands struct {
+	// Allow, when not empty, lists the only executables tasks may run.
+	Allow []string `json:"allow"`
+
+	// Deny lists executables tasks may never run, even if allowed.
+	Deny []string `json:"deny"`
+}
+
+// envAssignment is a variable set for a command, e.g. CGO_ENABLED=0
+var envAssignment = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*=`)
+
+// wrappers run the command in their arguments, which is checked too. Each
+// lists its options that take the next word as their value.
+var wrappers = map[string][]string{
+	"command": nil,
+	"env":     {"-u", "--unset", "-C", "--chdir"},
+	"nice":    {"-n", "--adjustment"},
+	"nohup":   nil,
+	"time":    {"-f", "--format", "-o", "--output"},
+	"xargs":   {"-a", "--arg-file", "-d", "--delimiter", "-E", "-I", "-L", "-n", "--max-args", "-P", "--max-procs", "-s", "--max-chars"},
+}
+
+// reserved are the shell's reserved words that may start a simple command
+// and are followed by one, as in if true; then rm -rf ~; fi.
+var reserved = []string{"if", "then", "elif", "else", "fi", "do", "done", "while", "until", "esac", "!", "{", "}"}
+
+// loops are the reserved words whose command is a list of words rather
+// than one to run, as in for f in a b; do ...; done.
+var loops = []string{"for", "select", "case"}
+
+// shells run the command line given with -c, which isn't checked.
+var shells = []string{"sh", "bash", "dash", "zsh", "ksh"}
+
+// redirection is a redirection word, e.g. 2>&1, >out or &>>log, with its
+// target in the second group, empty if it is the next word.
+var redirection = regexp.MustCompile(`^(?:[0-9]*(?:<<?|>>?|<>|>\||[<>]&)|&>>?)(.*)$`)
+
+// Executables returns the executables a shell command line runs, in order,
+// including the ones wrappers such as env and xargs run. Command lines
+// whose executables can't be told without running them are an error: ones
+// with command substitution or expansions in place of an executable, and
+// ones with backslashes or quotes inside a word, like s'u'do. Quoting
+// whole words, as in echo "hello world", is fine.
+func Executables(command string) ([]string, error) {
+	names, _, err := executables(command)
+	return names, err
+}
+
+// executables returns the executables of command, see Executables, and
+// those of them that run a command line of their own: eval, exec and
+// shells run with -c.
+func executables(command string) (names, opaque []string, err error) {
+	if strings.Contains(command, "$(") || strings.Contains(command, "`") {
+		return nil, nil, errors.New("command substitution hides what runs")
+	}
+	commands, err := splitCommands(command)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, words := range commands {
+		for len(words) > 0 {
+			words = skipPrefix(words)
+			if len(words) == 0 || slices.Contains(loops, words[0]) {
+				break
+			}
+			name := words[0]
+			if strings.ContainsAny(name, "$*?[~") {
+				return nil, nil, fmt.Errorf("%s: executable depends on expansion", name)
+			}
+			names = append(names, name)
+
+			base := filepath.Base(name)
+			if base == "eval" || base == "exec" || slices.Contains(shells, base) && hasCommandOption(words[1:]) {
+				opaque = append(opaque, name)
+			}
+			valued, ok := wrappers[base]
+			if !ok {
+				break
+			}
+			if words, err = wrapped(name, words[1:], valued); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil, errors.New("empty command")
+	}
+	return names, opaque, nil
+}
+
+// skipPrefix drops what comes before the executable of a simple command:
+// reserved words, variable assignments and redirections.
+func skipPrefix(words []string) []string {
+	for len(words) > 0 {
+		switch m := redirection.FindStringSubmatch(words[0]); {
+		case slices.Contains(reserved, words[0]), envAssignment.MatchString(words[0]):
+			words = words[1:]
+		case m != nil && m[1] == "":
+			words = words[min(2, len(words)):]
+		case m != nil:
+			words = words[1:]
+		default:
+			return words
+		}
+	}
+	return words
+}
+
+// hasCommandOption reports whether the options of a shell include -c,
+// alone or with others as in -ec.
+func hasCommandOption(args []string) bool {
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") || arg == "-" || arg == "--" {
+			return false
+		}
+		if !strings.HasPrefix(arg, "--") && strings.ContainsRune(arg, 'c') {
+			return true
+		}
+	}
+	return false
+}
+
+// wrapped returns the command a wrapper runs, given the wrapper's
+// arguments: they follow its options, whose values are in valued.
+func wrapped(wrapper string, args, valued []string) ([]string, error) {
+	for len(args) > 0 {
+		arg := args[0]
+		switch {
+		case arg == "--":
+			return args[1:], nil
+		case arg == "-S" || strings.HasPrefix(arg, "--split-string"):
+			return nil, fmt.Errorf("%s %s hides what runs", wrapper, arg)
+		case slices.Contains(valued, arg):
+			if len(args) < 2 {
+				return nil, nil
+			}
+			args = args[2:]
+		case strings.HasPrefix(arg, "-") && arg != "-":
+			args = args[1:]
+		default:
+			return args, nil
+		}
+	}
+	return nil, nil
+}
+
+// splitCommands splits a shell command line into its simple commands, at
+// ;, &, |, && and ||, parentheses and newlines, and each one into words,
+// with the quotes around whole words removed. The & of a redirection, as
+// in 2>&1 or &>log, is part of its word.
+func splitCommands(line string) ([][]string, error) {
+	var commands [][]string
+	var words []string
+	var word strings.Builder
+	inWord := false
+
+	endWord := func() {
+		if inWord {
+			words = append(words, word.String())
+			word.Reset()
+			inWord = false
+		}
+	}
+	endCommand := func() {
+		endWord()
+		if len(words) > 0 {
+			commands = append(commands, words)
+			words = nil
+		}
+	}
+
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; c {
+		case ' ', '\t':
+			endWord()
+		case '&':
+			if i > 0 && strings.IndexByte("<>", line[i-1]) >= 0 || i+1 < len(line) && line[i+1] == '>' {
+				word.WriteByte(c)
+				inWord = true
+				break
+			}
+			endCommand()
+		case ';', '|', '(', ')', '\n':
+			endCommand()
+		case '\\':
+			return nil, errors.New("backslashes hide what runs")
+		case '\'', '"':
+			if inWord {
+				return nil, fmt.Errorf("%s%c: quotes inside a word hide what runs", word.String(), c)
+			}
+			end := strings.IndexByte(line[i+1:], c)
+			if end < 0 {
+				return nil, errors.New("unterminated quote")
+			}
+			quoted := line[i+1 : i+1+end]
+			if strings.ContainsRune(quoted, '\\') {
+				return nil, errors.New("backslashes hide what runs")
+			}
+			i += end + 1
+			if i+1 < len(line) && !strings.ContainsRune(" \t;&|()\n", rune(line[i+1])) {
+				return nil, fmt.Errorf("%s: quotes inside a word hide what runs", line[i-end-1:])
+			}
+			word.WriteString(quoted)
+			inWord = true
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	endCommand()
+	return commands, nil
+}
+
+// Check returns an error naming the first executable of command that isn't
+// allowed, if any. eval, exec and shells run with -c must be allowed by
+// name, even without an allowlist, as what they run isn't checked.
+func (t TaskCommands) Check(command string) error {
+	names, opaque, err := executables(command)
+	if err != nil {
+		return err
+	}
+	for _, name := range opaque {
+		if !matchExecutable(t.Allow, name) {
+			return fmt.Errorf("%s runs commands task_commands can't check; allow it to run them", name)
+		}
+	}
+	for _, name := range names {
+		if matchExecutable(t.Deny, name) {
+			return fmt.Errorf("%s is denied by task_commands", name)
+		}
+		if len(t.Allow) > 0 && !matchExecutable(t.Allow, name) {
+			return fmt.Errorf("%s is not allowed by task_commands", name)
+		}
+	}
+	return nil
+}
+
+// matchExecutable reports whether list names the executable name.
+func matchExecutable(list []string, name string) bool {
+	return slices.Contains(list, name) || slices.Contains(list, filepath.Base(name))
+}
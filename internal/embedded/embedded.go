@@ -1,16 +1,38 @@
-// Package embedded provides embedded claude commands and prompts.
+// Package embedded provides embedded claude commands and prompts, and the
+// templates of new projects.
 package embedded
 
 import (
 	"embed"
 	"io/fs"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 )
 
 //go:embed claude/commands/*.md
 var claudeFS embed.FS
 
+// templatesFS holds a directory per project template. Dotfiles like
+// .gitignore are included, and Go files end in .tmpl so the go command
+// leaves them alone.
+//
+//go:embed all:templates
+var templatesFS embed.FS
+
+// Templates returns the project templates, a directory per template.
+func Templates() fs.FS {
+	sub, _ := fs.Sub(templatesFS, "templates")
+	return sub
+}
+
+// Commands returns the claude commands, a .md file per command.
+func Commands() fs.FS {
+	sub, _ := fs.Sub(claudeFS, "claude/commands")
+	return sub
+}
+
 // GetCommand returns the content of an embedded claude command by name.
 // Name should be without extension, e.g., "generate-commit-msg".
 func GetCommand(name string) (string, error) {
@@ -31,6 +53,37 @@ func GetCommandPrompt(name string) (string, error) {
 	return stripFrontmatter(content), nil
 }
 
+// placeholder matches a {{name}} placeholder in a prompt template.
+var placeholder = regexp.MustCompile(`\{\{\s*([a-z_]+)\s*\}\}`)
+
+// Expand replaces the {{name}} placeholders in template with their values
+// in vars. Placeholders without a value are left as they are. It reports
+// whether template used any of vars.
+func Expand(template string, vars map[string]string) (string, bool) {
+	used := false
+	expanded := placeholder.ReplaceAllStringFunc(template, func(p string) string {
+		value, ok := vars[placeholder.FindStringSubmatch(p)[1]]
+		if !ok {
+			return p
+		}
+		used = true
+		return value
+	})
+	return expanded, used
+}
+
+// Placeholders returns the names of the {{name}} placeholders in template,
+// in the order they first appear.
+func Placeholders(template string) []string {
+	var names []string
+	for _, m := range placeholder.FindAllStringSubmatch(template, -1) {
+		if !slices.Contains(names, m[1]) {
+			names = append(names, m[1])
+		}
+	}
+	return names
+}
+
 // ListCommands returns a list of all embedded command names.
 func ListCommands() ([]string, error) {
 	var commands []string
@@ -49,16 +102,24 @@ func ListCommands() ([]string, error) {
 
 // stripFrontmatter removes YAML frontmatter from markdown content.
 func stripFrontmatter(content string) string {
+	_, body := SplitFrontmatter(content)
+	return body
+}
+
+// SplitFrontmatter returns the YAML frontmatter of markdown content,
+// without its --- lines, and the content after it. Content without
+// frontmatter is returned as is.
+func SplitFrontmatter(content string) (front, body string) {
 	if !strings.HasPrefix(content, "---") {
-		return content
+		return "", content
 	}
 
 	// Find the closing ---
 	rest := content[3:]
 	idx := strings.Index(rest, "---")
 	if idx == -1 {
-		return content
+		return "", content
 	}
 
-	return strings.TrimSpace(rest[idx+3:])
+	return strings.TrimSpace(rest[:idx]), strings.TrimSpace(rest[idx+3:])
 }
@@ -0,0 +1,64 @@
+package embedded
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	vars := map[string]string{"diff": "+added", "branch": "main"}
+
+	got, used := Expand("Branch {{branch}}:\n{{ diff }}\n{{unknown}}", vars)
+	if want := "Branch main:\n+added\n{{unknown}}"; got != want || !used {
+		t.Errorf("Expand() = %q, %v; want %q, true", got, used, want)
+	}
+
+	if _, used := Expand("Use imperative mood.", vars); used {
+		t.Error("Expand() reported placeholders in a template without any")
+	}
+}
+
+func TestParseFrontmatter(t *testing.T) {
+	f, body := ParseFrontmatter(`---
+description: 'Explain a part of the code'
+model: sonnet
+allowed-tools: Read, Bash(git add:*, git status:*)
+params:
+  - path: File or directory to explain
+  - name: focus
+    description: What to focus on
+    default: the design
+    required: false
+unknown: ignored
+---
+Explain {{path}}.`)
+
+	if body != "Explain {{path}}." {
+		t.Errorf("body = %q", body)
+	}
+	if f.Description != "Explain a part of the code" || f.Model != "sonnet" {
+		t.Errorf("ParseFrontmatter() = %+v", f)
+	}
+	if want := []string{"Read", "Bash(git add:*, git status:*)"}; !slices.Equal(f.AllowedTools, want) {
+		t.Errorf("AllowedTools = %q, want %q", f.AllowedTools, want)
+	}
+	want := []Param{
+		{Name: "path", Description: "File or directory to explain", Required: true},
+		{Name: "focus", Description: "What to focus on", Default: "the design"},
+	}
+	if !slices.Equal(f.Params, want) {
+		t.Errorf("Params = %+v, want %+v", f.Params, want)
+	}
+}
+
+func TestParseFrontmatter_BlockList(t *testing.T) {
+	f, _ := ParseFrontmatter("---\nallowed_tools:\n  - Read\n  - \"Grep\"\n---\nPrompt")
+	if want := []string{"Read", "Grep"}; !slices.Equal(f.AllowedTools, want) {
+		t.Errorf("AllowedTools = %q, want %q", f.AllowedTools, want)
+	}
+
+	f, body := ParseFrontmatter("No frontmatter")
+	if body != "No frontmatter" || f.Description != "" {
+		t.Errorf("ParseFrontmatter() = %+v, %q", f, body)
+	}
+}
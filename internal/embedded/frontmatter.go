@@ -0,0 +1,149 @@
+package embedded
+
+import (
+	"strings"
+)
+
+// Frontmatter is the metadata of a claude command, from the YAML
+// frontmatter of its markdown.
+type Frontmatter struct {
+	Description  string
+	Model        string   // "" for claude's default
+	AllowedTools []string // tools claude may use without asking, e.g. "Bash(git diff:*)"
+	Params       []Param
+}
+
+// Param is a parameter of a command, filled into its {{name}} placeholder.
+type Param struct {
+	Name        string
+	Description string
+	Default     string
+	Required    bool
+}
+
+// ParseFrontmatter returns the metadata of a command from its content, and
+// the prompt after the frontmatter. Only the YAML commands use is read:
+//
+//	---
+//	description: Explain a part of the code
+//	model: sonnet
+//	allowed-tools: Read, Grep, Bash(git log:*)
+//	params:
+//	  - path: File or directory to explain
+//	  - name: focus
+//	    description: What to focus on
+//	    default: the design
+//	    required: false
+//	---
+//
+// Lists may also be written [a, b] or one "- item" line each. Parameters
+// are "name: description" lines, or maps with the keys above. Unknown keys
+// are ignored.
+func ParseFrontmatter(content string) (Frontmatter, string) {
+	front, body := SplitFrontmatter(content)
+	var f Frontmatter
+
+	// The key of the block the indented lines belong to, e.g. params
+	block := ""
+	var param *Param
+	for _, line := range strings.Split(front, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indented := trimmed != line && (line[0] == ' ' || line[0] == '\t')
+
+		if !indented {
+			key, value, _ := strings.Cut(trimmed, ":")
+			key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+			block, param = "", nil
+			switch strings.ReplaceAll(key, "_", "-") {
+			case "description":
+				f.Description = unquote(value)
+			case "model":
+				f.Model = unquote(value)
+			case "allowed-tools":
+				f.AllowedTools = parseList(value)
+				block = "allowed-tools"
+			case "params", "parameters", "arguments":
+				block = "params"
+			}
+			continue
+		}
+
+		switch block {
+		case "allowed-tools":
+			if item, ok := strings.CutPrefix(trimmed, "- "); ok {
+				f.AllowedTools = append(f.AllowedTools, unquote(strings.TrimSpace(item)))
+			}
+		case "params":
+			item, isItem := strings.CutPrefix(trimmed, "- ")
+			key, value, ok := strings.Cut(item, ":")
+			if !ok {
+				continue
+			}
+			key, value = strings.TrimSpace(key), unquote(strings.TrimSpace(value))
+			if isItem {
+				// A new parameter: "- name: description" or "- name: path"
+				f.Params = append(f.Params, Param{Name: key, Description: value, Required: true})
+				param = &f.Params[len(f.Params)-1]
+				if key == "name" {
+					param.Name, param.Description = value, ""
+				}
+				continue
+			}
+			if param == nil {
+				continue
+			}
+			switch key {
+			case "name":
+				param.Name = value
+			case "description":
+				param.Description = value
+			case "default":
+				param.Default = value
+			case "required":
+				param.Required = value != "false" && value != "no"
+			}
+		}
+	}
+	return f, body
+}
+
+// parseList parses an inline YAML list, [a, b], or a comma-separated one,
+// as Claude Code writes allowed tools. Commas inside parentheses, as in
+// Bash(git add:*, git commit:*), don't split items.
+func parseList(s string) []string {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+		s = s[1 : len(s)-1]
+	}
+	var items []string
+	depth, start := 0, 0
+	for i := 0; i <= len(s); i++ {
+		if i < len(s) {
+			switch s[i] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+			if s[i] != ',' || depth > 0 {
+				continue
+			}
+		}
+		if item := unquote(strings.TrimSpace(s[start:i])); item != "" {
+			items = append(items, item)
+		}
+		start = i + 1
+	}
+	return items
+}
+
+// unquote removes the quotes around a YAML string value.
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
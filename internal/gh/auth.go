@@ -0,0 +1,35 @@
+package gh
+
+import (
+	"errors"
+	"os/exec"
+	"regexp"
+)
+
+// loggedInAs matches the account in `gh auth status` output, in both the
+// "account <login>" and older "as <login>" forms.
+var loggedInAs = regexp.MustCompile(`Logged in to (\S+) (?:account|as) ([^\s(]+)`)
+
+// ErrNotInstalled is returned when the gh CLI is not on the PATH.
+var ErrNotInstalled = errors.New("gh is not installed")
+
+// AuthStatus returns the GitHub account gh is logged in with,
+// or "" if it is not logged in.
+func AuthStatus() (string, error) {
+	if _, err := exec.LookPath("gh"); err != nil {
+		return "", ErrNotInstalled
+	}
+
+	// gh exits non-zero when logged out, and writes the status to stderr
+	// on older versions
+	out, _ := exec.Command("gh", "auth", "status", "--hostname", "github.com").CombinedOutput()
+	if m := loggedInAs.FindSubmatch(out); m != nil {
+		return string(m[2]), nil
+	}
+	return "", nil
+}
+
+// LoginArgs returns the gh arguments that start the interactive login flow.
+func LoginArgs() []string {
+	return []string{"auth", "login", "--hostname", "github.com"}
+}
@@ -0,0 +1,116 @@
+// Package gh wraps the GitHub CLI for pull request operations.
+package gh
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// prFields are the JSON fields requested from `gh pr list`.
+const prFields = "number,title,headRefName,baseRefName,author,isDraft,state,url,updatedAt"
+
+// prDetailFields are the additional JSON fields requested from `gh pr view`.
+const prDetailFields = prFields + ",body,additions,deletions,changedFiles,mergeable,reviewDecision"
+
+// MergeStrategy is the method used to merge a pull request.
+type MergeStrategy string
+
+const (
+	MergeCommit MergeStrategy = "merge"
+	MergeSquash MergeStrategy = "squash"
+	MergeRebase MergeStrategy = "rebase"
+)
+
+// MergeStrategies lists the available merge strategies in display order.
+var MergeStrategies = []MergeStrategy{MergeCommit, MergeSquash, MergeRebase}
+
+// Author is the GitHub user that opened a pull request.
+type Author struct {
+	Login string `json:"login"`
+}
+
+// PR represents a GitHub pull request.
+type PR struct {
+	Number      int       `json:"number"`
+	Title       string    `json:"title"`
+	HeadRefName string    `json:"headRefName"`
+	BaseRefName string    `json:"baseRefName"`
+	Author      Author    `json:"author"`
+	IsDraft     bool      `json:"isDraft"`
+	State       string    `json:"state"`
+	URL         string    `json:"url"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+
+	// Detail fields, only populated by ViewPR
+	Body           string `json:"body,omitempty"`
+	Additions      int    `json:"additions,omitempty"`
+	Deletions      int    `json:"deletions,omitempty"`
+	ChangedFiles   int    `json:"changedFiles,omitempty"`
+	Mergeable      string `json:"mergeable,omitempty"`
+	ReviewDecision string `json:"reviewDecision,omitempty"`
+}
+
+// ListPRs returns the open pull requests for the repository at repoRoot.
+func ListPRs(ctx context.Context, repoRoot string) ([]PR, error) {
+	out, err := run(ctx, repoRoot, "pr", "list", "--state", "open", "--limit", "100", "--json", prFields)
+	if err != nil {
+		return nil, err
+	}
+
+	var prs []PR
+	if err := json.Unmarshal(out, &prs); err != nil {
+		return nil, err
+	}
+	return prs, nil
+}
+
+// ViewPR returns the full details of a single pull request.
+func ViewPR(ctx context.Context, repoRoot string, number int) (*PR, error) {
+	out, err := run(ctx, repoRoot, "pr", "view", strconv.Itoa(number), "--json", prDetailFields)
+	if err != nil {
+		return nil, err
+	}
+
+	var pr PR
+	if err := json.Unmarshal(out, &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// DiffPR returns the diff of a pull request against its base branch.
+func DiffPR(ctx context.Context, repoRoot string, number int) (string, error) {
+	out, err := run(ctx, repoRoot, "pr", "diff", strconv.Itoa(number))
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// MergeArgs returns the gh arguments for merging a pull request with the given strategy.
+func MergeArgs(number int, strategy MergeStrategy) []string {
+	return []string{"pr", "merge", strconv.Itoa(number), "--" + string(strategy)}
+}
+
+// run executes a gh command in dir and returns its stdout.
+// On failure the error includes gh's stderr output. The command is killed
+// when ctx is canceled.
+func run(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "gh", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("gh %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,65 @@
+package git
+
+import (
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Author is a person who committed to a repository.
+type Author struct {
+	Name    string
+	Email   string
+	Commits int // among the commits searched
+}
+
+// String formats the author as git does, e.g. "Jane Doe <jane@example.com>".
+func (a Author) String() string {
+	return a.Name + " <" + a.Email + ">"
+}
+
+// RecentAuthors returns the authors of the last n commits, most frequent
+// first, leaving out the configured user.
+func RecentAuthors(repoRoot string, n int) ([]Author, error) {
+	cmd := exec.Command("git", "log", "-n", strconv.Itoa(n), "--format=%an%x00%ae")
+	cmd.Dir = repoRoot
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	self := configValue(repoRoot, "user.email")
+	var authors []Author
+	index := make(map[string]int)
+	for _, line := range strings.Split(string(out), "\n") {
+		name, email, ok := strings.Cut(line, "\x00")
+		if !ok || email == "" || strings.EqualFold(email, self) {
+			continue
+		}
+		key := strings.ToLower(email)
+		if i, seen := index[key]; seen {
+			authors[i].Commits++
+			continue
+		}
+		index[key] = len(authors)
+		authors = append(authors, Author{Name: name, Email: email, Commits: 1})
+	}
+
+	// Stable, so authors with as many commits stay in order of recency
+	sort.SliceStable(authors, func(i, j int) bool {
+		return authors[i].Commits > authors[j].Commits
+	})
+	return authors, nil
+}
+
+// configValue returns a git config value, or "" if it is not set.
+func configValue(repoRoot, key string) string {
+	cmd := exec.Command("git", "config", "--get", key)
+	cmd.Dir = repoRoot
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
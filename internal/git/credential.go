@@ -0,0 +1,52 @@
+package git
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// authFailures are messages git and ssh print when a remote rejects or
+// cannot ask for credentials.
+var authFailures = []string{
+	"could not read Username",
+	"could not read Password",
+	"terminal prompts disabled",
+	"Authentication failed",
+	"Permission denied (publickey",
+	"Host key verification failed",
+}
+
+// CredentialHelper returns the configured credential helper, or "" if none is.
+func CredentialHelper(repoRoot string) string {
+	cmd := exec.Command("git", "config", "--get", "credential.helper")
+	cmd.Dir = repoRoot
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// CredentialHint returns advice for output of a failed command that shows it
+// could not authenticate with a remote, or "" if it doesn't.
+func CredentialHint(repoRoot string, output []string) string {
+	var failure string
+	for _, line := range output {
+		for _, f := range authFailures {
+			if strings.Contains(line, f) {
+				failure = f
+				break
+			}
+		}
+	}
+
+	switch {
+	case failure == "":
+		return ""
+	case strings.HasPrefix(failure, "Permission denied"), strings.HasPrefix(failure, "Host key"):
+		return "Hint: the SSH remote rejected the connection; check that your key is loaded (ssh-add -l) and the host is in known_hosts"
+	case CredentialHelper(repoRoot) == "":
+		return "Hint: no git credential helper is configured; run `gh auth setup-git` or set credential.helper to store HTTPS credentials"
+	}
+	return "Hint: the remote rejected the stored credentials; update them with your credential helper"
+}
@@ -0,0 +1,96 @@
+package git
+
+import (
+	"bytes"
+	"os/exec"
+	"strconv"
+)
+
+// FileChange is a file changed in a diff.
+type FileChange struct {
+	Path      string
+	OldPath   string // previous path for renames, empty otherwise
+	Additions int
+	Deletions int
+	Binary    bool
+}
+
+// ChangedFiles returns the files changed in `git diff <revs>` with their line counts,
+// without loading the diff content. Pass no revs for unstaged changes.
+func ChangedFiles(repoRoot string, revs ...string) ([]FileChange, error) {
+	args := append([]string{"diff", "--numstat", "-z", "-M"}, revs...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoRoot
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseNumstat(out), nil
+}
+
+// AddedFiles returns the files added in `git diff <revs>`; a renamed file
+// counts as added under its new path.
+func AddedFiles(repoRoot string, revs ...string) ([]string, error) {
+	args := append([]string{"diff", "--name-only", "-z", "--no-renames", "--diff-filter=A"}, revs...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoRoot
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, p := range bytes.Split(out, []byte{0}) {
+		if len(p) > 0 {
+			paths = append(paths, string(p))
+		}
+	}
+	return paths, nil
+}
+
+// parseNumstat parses `git diff --numstat -z` output. Each entry is
+// "<add>\t<del>\t<path>\0", or "<add>\t<del>\t\0<old>\0<new>\0" for renames.
+// Binary files report "-" for both counts.
+func parseNumstat(out []byte) []FileChange {
+	var files []FileChange
+	fields := bytes.Split(out, []byte{0})
+	for i := 0; i < len(fields); i++ {
+		parts := bytes.SplitN(fields[i], []byte{'\t'}, 3)
+		if len(parts) != 3 {
+			continue
+		}
+
+		var fc FileChange
+		if string(parts[0]) == "-" {
+			fc.Binary = true
+		} else {
+			fc.Additions, _ = strconv.Atoi(string(parts[0]))
+			fc.Deletions, _ = strconv.Atoi(string(parts[1]))
+		}
+
+		if len(parts[2]) > 0 {
+			fc.Path = string(parts[2])
+		} else if i+2 < len(fields) {
+			fc.OldPath = string(fields[i+1])
+			fc.Path = string(fields[i+2])
+			i += 2
+		}
+		files = append(files, fc)
+	}
+	return files
+}
+
+// FileDiff returns the diff of a single file for `git diff <revs>`.
+func FileDiff(repoRoot string, fc FileChange, revs ...string) (string, error) {
+	args := append([]string{"diff", "-M"}, revs...)
+	args = append(args, "--", fc.Path)
+	if fc.OldPath != "" {
+		args = append(args, fc.OldPath)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoRoot
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
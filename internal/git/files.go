@@ -0,0 +1,58 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"os/exec"
+)
+
+// StreamFiles lists tracked and untracked (but not ignored) files in the repository,
+// calling fn with batches of up to batchSize paths as `git ls-files` produces them.
+// Paths are relative to the repository root. Listing stops early if fn returns false.
+func StreamFiles(repoRoot string, batchSize int, fn func(paths []string) bool) error {
+	cmd := exec.Command("git", "ls-files", "-z", "--cached", "--others", "--exclude-standard")
+	cmd.Dir = repoRoot
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Split(splitNull)
+
+	batch := make([]string, 0, batchSize)
+	for scanner.Scan() {
+		batch = append(batch, scanner.Text())
+		if len(batch) == batchSize {
+			if !fn(batch) {
+				_ = cmd.Process.Kill()
+				_ = cmd.Wait()
+				return nil
+			}
+			batch = make([]string, 0, batchSize)
+		}
+	}
+	if len(batch) > 0 {
+		fn(batch)
+	}
+
+	if err := scanner.Err(); err != nil {
+		_ = cmd.Wait()
+		return err
+	}
+	return cmd.Wait()
+}
+
+// splitNull is a bufio.SplitFunc for NUL-separated output.
+func splitNull(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
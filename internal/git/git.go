@@ -2,6 +2,7 @@ package git
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"os"
 	"os/exec"
@@ -69,11 +70,24 @@ func getCurrentBranch(repoRoot string) (string, error) {
 	return strings.TrimSpace(string(out)), nil
 }
 
+// Refresh re-reads the current branch of the repository.
+func (r *Repo) Refresh() error {
+	branch, err := getCurrentBranch(r.Root)
+	if err != nil {
+		return err
+	}
+	r.Branch = branch
+	return nil
+}
+
 // HasRemoteChanges checks if there are unpulled changes from the remote.
-func (r *Repo) HasRemoteChanges() (bool, error) {
+// The fetch is stopped when ctx is canceled.
+func (r *Repo) HasRemoteChanges(ctx context.Context) (bool, error) {
 	// Fetch latest from remote (silently)
-	fetch := exec.Command("git", "fetch", "--dry-run")
+	fetch := exec.CommandContext(ctx, "git", "fetch", "--dry-run")
 	fetch.Dir = r.Root
+	// Fail instead of waiting for credentials nobody can type
+	fetch.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
 	fetchOut, _ := fetch.CombinedOutput()
 
 	// If fetch --dry-run has output, there are changes
@@ -0,0 +1,76 @@
+package git
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+// Branch notes are git's branch descriptions, kept in the repository's
+// config as branch.<name>.description, where `git branch --edit-description`,
+// format-patch and request-pull find them too.
+
+// noteKey returns the config key of the notes of branch.
+func noteKey(branch string) string {
+	return "branch." + branch + ".description"
+}
+
+// BranchNotes returns the notes of the repository's branches by name.
+// Branches without notes are left out.
+func BranchNotes(repoRoot string) (map[string]string, error) {
+	cmd := exec.Command("git", "config", "-z", "--local", "--get-regexp", `^branch\..*\.description$`)
+	cmd.Dir = repoRoot
+	out, err := cmd.Output()
+	notes := make(map[string]string)
+	if err != nil {
+		// Exit code 1: no branch has notes
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return notes, nil
+		}
+		return nil, err
+	}
+
+	// Each entry is the key and the value on the lines after it
+	for _, entry := range strings.Split(string(out), "\x00") {
+		key, value, ok := strings.Cut(entry, "\n")
+		if !ok {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(key, "branch."), ".description")
+		notes[name] = strings.TrimRight(value, "\n")
+	}
+	return notes, nil
+}
+
+// BranchNote returns the notes of branch, or "" if it has none.
+func BranchNote(repoRoot, branch string) (string, error) {
+	cmd := exec.Command("git", "config", "--local", "--get", noteKey(branch))
+	cmd.Dir = repoRoot
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return "", nil
+		}
+		return "", err
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
+
+// SetBranchNote replaces the notes of branch. Blank notes remove them.
+func SetBranchNote(repoRoot, branch, note string) error {
+	note = strings.TrimRight(note, " \t\n")
+	// Stored with a final newline, as git branch --edit-description does
+	args := []string{"config", "--local", noteKey(branch), note + "\n"}
+	if strings.TrimSpace(note) == "" {
+		if old, err := BranchNote(repoRoot, branch); err != nil || old == "" {
+			return err
+		}
+		args = []string{"config", "--local", "--unset", noteKey(branch)}
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoRoot
+	_, err := output(cmd)
+	return err
+}
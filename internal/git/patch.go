@@ -0,0 +1,96 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Commit is a commit as git log lists it.
+type Commit struct {
+	Hash    string
+	Subject string
+	Author  string
+	Time    time.Time
+}
+
+// Log returns the last limit commits of rev, newest first.
+func Log(repoRoot, rev string, limit int) ([]Commit, error) {
+	cmd := exec.Command("git", "log", "-n", strconv.Itoa(limit),
+		"--format=%H%x1f%ct%x1f%an%x1f%s", rev, "--")
+	cmd.Dir = repoRoot
+	out, err := output(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []Commit
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		parts := strings.SplitN(line, "\x1f", 4)
+		if len(parts) != 4 {
+			continue
+		}
+		c := Commit{Hash: parts[0], Author: parts[2], Subject: parts[3]}
+		if sec, err := strconv.ParseInt(parts[1], 10, 64); err == nil {
+			c.Time = time.Unix(sec, 0)
+		}
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
+
+// FormatPatchArgs returns the arguments of git format-patch writing the
+// commits, given newest first as Log lists them, to dir as one series
+// numbered oldest first, the order git am applies them in. The commits
+// don't have to be consecutive.
+func FormatPatchArgs(dir string, hashes []string) []string {
+	args := []string{"format-patch", "--output-directory", dir}
+	if len(hashes) == 1 {
+		// A single revision would be taken as "every commit since"
+		return append(args, "-1", hashes[0])
+	}
+	// Unsorted, format-patch numbers the commits in the reverse of the order given
+	args = append(args, "--no-walk=unsorted")
+	return append(args, hashes...)
+}
+
+// patchExts are the extensions of the files PatchFiles picks from a
+// directory: format-patch output and mailboxes saved from a mail client.
+var patchExts = []string{".patch", ".eml", ".mbox"}
+
+// PatchFiles returns the patches at path for git am: the file itself, or
+// the patch files in a directory in name order, which is the order
+// format-patch numbers them in.
+func PatchFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		for _, want := range patchExts {
+			if !e.IsDir() && ext == want {
+				files = append(files, filepath.Join(path, e.Name()))
+			}
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no %s files in %s", strings.Join(patchExts, ", "), path)
+	}
+	sort.Strings(files)
+	return files, nil
+}
@@ -0,0 +1,95 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// ErrNoUpstream is returned for a branch without an upstream to push to.
+var ErrNoUpstream = errors.New("the branch has no upstream; set one with git push -u")
+
+// ForcePush is pushing a local branch to its upstream (@{upstream}),
+// replacing the remote branch if the local one was rewritten, e.g. after a
+// rebase or amend. Paths that push must use PlanForcePush and its Args, so
+// the push is checked against what gdev last saw of the remote branch
+// (--force-with-lease).
+type ForcePush struct {
+	Remote string
+	Branch string // the local branch
+
+	// RemoteBranch is the branch on Remote it is pushed to.
+	RemoteBranch string
+
+	// Lease is the commit the remote branch must still be at for the push to
+	// go through: its remote-tracking branch, as last fetched. It is "" if
+	// the branch was never fetched, and the push then only goes through if
+	// there is no such branch on the remote.
+	Lease string
+
+	// Discarded is how many commits of the remote branch, as last fetched,
+	// the push drops: those the local branch doesn't have.
+	Discarded int
+}
+
+// PlanForcePush resolves the upstream of branch, to lease the push on its
+// remote-tracking branch and count the commits it would discard. It returns
+// ErrNoUpstream if branch has none.
+func PlanForcePush(repoRoot, branch string) (ForcePush, error) {
+	p := ForcePush{Branch: branch}
+
+	cmd := exec.Command("git", "for-each-ref",
+		"--format=%(upstream:remotename)%09%(upstream:remoteref)%09%(upstream)", "refs/heads/"+branch)
+	cmd.Dir = repoRoot
+	out, err := output(cmd)
+	if err != nil {
+		return p, err
+	}
+	fields := strings.Split(strings.TrimRight(string(out), "\n"), "\t")
+	if len(fields) != 3 || fields[0] == "" || !strings.HasPrefix(fields[1], "refs/heads/") {
+		return p, ErrNoUpstream
+	}
+	p.Remote = fields[0]
+	p.RemoteBranch = strings.TrimPrefix(fields[1], "refs/heads/")
+	tracking := fields[2]
+
+	cmd = exec.Command("git", "rev-parse", "--verify", "--quiet", tracking)
+	cmd.Dir = repoRoot
+	out, err = cmd.Output()
+	if err != nil {
+		// Never fetched: nothing known to discard
+		return p, nil
+	}
+	p.Lease = strings.TrimSpace(string(out))
+
+	cmd = exec.Command("git", "rev-list", "--count", "refs/heads/"+branch+".."+p.Lease)
+	cmd.Dir = repoRoot
+	out, err = output(cmd)
+	if err != nil {
+		return p, err
+	}
+	p.Discarded, err = strconv.Atoi(strings.TrimSpace(string(out)))
+	return p, err
+}
+
+// Leased reports whether the push is checked against a fetched remote
+// branch. Without a lease it fails if the remote branch exists.
+func (p ForcePush) Leased() bool {
+	return p.Lease != ""
+}
+
+// Forced reports whether the push may replace commits of the remote branch
+// rather than only add to it, so it needs confirming first.
+func (p ForcePush) Forced() bool {
+	return p.Discarded > 0 || !p.Leased()
+}
+
+// Args returns the arguments of git push. The push fails if the remote
+// branch moved since it was last fetched, or, never fetched, if it exists.
+func (p ForcePush) Args() []string {
+	ref := "refs/heads/" + p.RemoteBranch
+	return []string{"push", fmt.Sprintf("--force-with-lease=%s:%s", ref, p.Lease),
+		p.Remote, "refs/heads/" + p.Branch + ":" + ref}
+}
@@ -0,0 +1,130 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// run runs git in dir and returns its trimmed output.
+func run(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// clone returns a clone of a new bare repository with one commit on main
+// pushed to it, and the path of the bare repository.
+func clone(t *testing.T) (local, remote string) {
+	t.Helper()
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "gdev")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gdev@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gdev")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gdev@example.com")
+
+	dir := t.TempDir()
+	remote = filepath.Join(dir, "remote.git")
+	local = filepath.Join(dir, "local")
+	run(t, dir, "init", "--bare", "--initial-branch=main", remote)
+	run(t, dir, "clone", remote, local)
+	run(t, local, "checkout", "-b", "main")
+	run(t, local, "commit", "--allow-empty", "-m", "first")
+	run(t, local, "push", "-u", "origin", "main")
+	return local, remote
+}
+
+// push runs the push p in dir and reports whether it went through.
+func push(t *testing.T, dir string, p ForcePush) bool {
+	t.Helper()
+	cmd := exec.Command("git", p.Args()...)
+	cmd.Dir = dir
+	return cmd.Run() == nil
+}
+
+func TestPlanForcePush_Amended(t *testing.T) {
+	local, _ := clone(t)
+	run(t, local, "commit", "--amend", "--allow-empty", "-m", "amended")
+
+	p, err := PlanForcePush(local, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Remote != "origin" || p.RemoteBranch != "main" || !p.Leased() || p.Discarded != 1 || !p.Forced() {
+		t.Fatalf("PlanForcePush() = %+v, want a lease on origin/main discarding 1 commit", p)
+	}
+	if !push(t, local, p) {
+		t.Error("leased push failed though the remote branch didn't move")
+	}
+}
+
+func TestPlanForcePush_FastForward(t *testing.T) {
+	local, _ := clone(t)
+	run(t, local, "commit", "--allow-empty", "-m", "second")
+
+	p, err := PlanForcePush(local, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Forced() {
+		t.Errorf("PlanForcePush() = %+v, want a push that discards nothing", p)
+	}
+}
+
+func TestPlanForcePush_RemoteMoved(t *testing.T) {
+	local, remote := clone(t)
+
+	// Someone else pushes after the last fetch
+	other := filepath.Join(t.TempDir(), "other")
+	run(t, ".", "clone", remote, other)
+	run(t, other, "commit", "--allow-empty", "-m", "theirs")
+	run(t, other, "push", "origin", "main")
+
+	run(t, local, "commit", "--amend", "--allow-empty", "-m", "amended")
+	p, err := PlanForcePush(local, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if push(t, local, p) {
+		t.Error("push went through though the remote branch moved since the last fetch")
+	}
+}
+
+func TestPlanForcePush_NeverFetched(t *testing.T) {
+	local, _ := clone(t)
+	run(t, local, "update-ref", "-d", "refs/remotes/origin/main")
+	run(t, local, "commit", "--amend", "--allow-empty", "-m", "amended")
+
+	p, err := PlanForcePush(local, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Leased() || !p.Forced() {
+		t.Fatalf("PlanForcePush() = %+v, want no lease", p)
+	}
+	if slices.Contains(p.Args(), "--force") {
+		t.Errorf("Args() = %q, want no plain --force", p.Args())
+	}
+	if push(t, local, p) {
+		t.Error("push without a lease replaced an existing remote branch")
+	}
+}
+
+func TestPlanForcePush_NoUpstream(t *testing.T) {
+	local, _ := clone(t)
+	run(t, local, "checkout", "-b", "feature")
+
+	if _, err := PlanForcePush(local, "feature"); !errors.Is(err, ErrNoUpstream) {
+		t.Errorf("PlanForcePush() error = %v, want ErrNoUpstream", err)
+	}
+}
@@ -0,0 +1,118 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReflogEntry is a movement of HEAD recorded in the reflog.
+type ReflogEntry struct {
+	Selector string // e.g. "HEAD@{2}", which git commands accept
+	Hash     string
+	Action   string // what moved HEAD, e.g. "reset: moving to HEAD~1"
+	Subject  string // of the commit HEAD moved to
+	Time     time.Time
+
+	// Lost is set when no branch, tag or remote branch contains the commit,
+	// such as one left behind by a reset: only the reflog still finds it,
+	// until git expires the entry and prunes the commit.
+	Lost bool
+}
+
+// Reflog returns the last limit movements of HEAD, newest first.
+func Reflog(repoRoot string, limit int) ([]ReflogEntry, error) {
+	// With --date=unix the selectors hold the time instead of the index
+	cmd := exec.Command("git", "log", "--walk-reflogs", "--date=unix", "-n", strconv.Itoa(limit),
+		"--format=%H%x1f%gd%x1f%gs%x1f%s", "HEAD")
+	cmd.Dir = repoRoot
+	out, err := output(cmd)
+	if err != nil {
+		// A repository without commits has no reflog yet
+		if _, headErr := resolveHead(repoRoot); headErr != nil {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var entries []ReflogEntry
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		parts := strings.Split(line, "\x1f")
+		if len(parts) != 4 {
+			continue
+		}
+		e := ReflogEntry{
+			Selector: fmt.Sprintf("HEAD@{%d}", len(entries)),
+			Hash:     parts[0],
+			Action:   parts[2],
+			Subject:  parts[3],
+		}
+		// The selector is HEAD@{<unix time>}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(parts[1], "HEAD@{"), "}")
+		if sec, err := strconv.ParseInt(stamp, 10, 64); err == nil {
+			e.Time = time.Unix(sec, 0)
+		}
+		entries = append(entries, e)
+	}
+
+	lost, err := unreachable(repoRoot, entries)
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		entries[i].Lost = lost[entries[i].Hash]
+	}
+	return entries, nil
+}
+
+// unreachable returns which commits of entries no ref contains.
+func unreachable(repoRoot string, entries []ReflogEntry) (map[string]bool, error) {
+	lost := make(map[string]bool)
+	if len(entries) == 0 {
+		return lost, nil
+	}
+
+	// The commits reachable from the entries but from no ref, in one walk
+	args := []string{"rev-list", "--ignore-missing"}
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		if !seen[e.Hash] {
+			seen[e.Hash] = true
+			args = append(args, e.Hash)
+		}
+	}
+	args = append(args, "--not", "--all")
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoRoot
+	out, err := output(cmd)
+	if err != nil {
+		return nil, err
+	}
+	for _, hash := range strings.Fields(string(out)) {
+		if seen[hash] {
+			lost[hash] = true
+		}
+	}
+	return lost, nil
+}
+
+// resolveHead returns the commit HEAD points at.
+func resolveHead(repoRoot string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", "HEAD")
+	cmd.Dir = repoRoot
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+// CheckBranchName returns an error if name can't be the name of a branch.
+func CheckBranchName(repoRoot, name string) error {
+	cmd := exec.Command("git", "check-ref-format", "--branch", name)
+	cmd.Dir = repoRoot
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%q is not a valid branch name", name)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package git
+
+import (
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RefKind is the type of a git ref.
+type RefKind int
+
+const (
+	RefBranch RefKind = iota
+	RefRemote
+	RefTag
+)
+
+// String returns a short label for the ref kind.
+func (k RefKind) String() string {
+	switch k {
+	case RefRemote:
+		return "remote"
+	case RefTag:
+		return "tag"
+	}
+	return "branch"
+}
+
+// Ref is a branch, remote branch or tag.
+type Ref struct {
+	Name       string // short name, e.g. "main" or "origin/main"
+	Kind       RefKind
+	CommitDate time.Time
+
+	// Uses is how many times the ref was checked out according to the reflog.
+	Uses int
+	// LastUsed is the reflog position of the latest checkout (0 is most recent),
+	// or -1 if the ref was never checked out.
+	LastUsed int
+}
+
+// ListRefs returns the local branches, remote branches and tags of the repository,
+// ordered by most-used and most recently checked out, then by commit date.
+func ListRefs(repoRoot string) ([]Ref, error) {
+	cmd := exec.Command("git", "for-each-ref",
+		"--sort=-committerdate",
+		"--format=%(refname)%09%(refname:short)%09%(committerdate:unix)",
+		"refs/heads", "refs/remotes", "refs/tags")
+	cmd.Dir = repoRoot
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []Ref
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		parts := strings.Split(line, "\t")
+		if len(parts) != 3 || strings.HasSuffix(parts[0], "/HEAD") {
+			continue
+		}
+
+		ref := Ref{Name: parts[1], LastUsed: -1}
+		switch {
+		case strings.HasPrefix(parts[0], "refs/remotes/"):
+			ref.Kind = RefRemote
+		case strings.HasPrefix(parts[0], "refs/tags/"):
+			ref.Kind = RefTag
+		}
+		if ts, err := strconv.ParseInt(parts[2], 10, 64); err == nil {
+			ref.CommitDate = time.Unix(ts, 0)
+		}
+		refs = append(refs, ref)
+	}
+
+	// Usage stats are best effort; a missing reflog just means no ordering hints
+	checkouts, _ := recentCheckouts(repoRoot)
+	for i := range refs {
+		for pos, name := range checkouts {
+			if name != refs[i].Name {
+				continue
+			}
+			if refs[i].LastUsed == -1 {
+				refs[i].LastUsed = pos
+			}
+			refs[i].Uses++
+		}
+	}
+
+	SortRefs(refs)
+	return refs, nil
+}
+
+// SortRefs orders refs by checkout count, then by how recently they were
+// checked out, then by commit date.
+func SortRefs(refs []Ref) {
+	sort.SliceStable(refs, func(i, j int) bool {
+		a, b := refs[i], refs[j]
+		if a.Uses != b.Uses {
+			return a.Uses > b.Uses
+		}
+		if a.LastUsed != b.LastUsed {
+			if a.LastUsed == -1 || b.LastUsed == -1 {
+				return b.LastUsed == -1
+			}
+			return a.LastUsed < b.LastUsed
+		}
+		return a.CommitDate.After(b.CommitDate)
+	})
+}
+
+// recentCheckouts returns the targets of recent checkouts from the reflog, newest first.
+func recentCheckouts(repoRoot string) ([]string, error) {
+	cmd := exec.Command("git", "reflog", "-n", "500", "--format=%gs")
+	cmd.Dir = repoRoot
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, line := range strings.Split(string(out), "\n") {
+		// "checkout: moving from <from> to <to>"
+		rest, ok := strings.CutPrefix(line, "checkout: moving from ")
+		if !ok {
+			continue
+		}
+		if _, to, ok := strings.Cut(rest, " to "); ok {
+			names = append(names, strings.TrimSpace(to))
+		}
+	}
+	return names, nil
+}
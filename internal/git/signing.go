@@ -0,0 +1,47 @@
+package git
+
+import "strings"
+
+// Signing is how commits in a repository are signed, from its git config.
+type Signing struct {
+	Enabled bool   // commit.gpgsign is set
+	Format  string // gpg.format: "openpgp", "ssh" or "x509"
+	Key     string // user.signingkey, "" for the default key
+}
+
+// CommitSigning returns how commits in repoRoot are signed.
+func CommitSigning(repoRoot string) Signing {
+	s := Signing{
+		Enabled: isTrue(configValue(repoRoot, "commit.gpgsign")),
+		Format:  configValue(repoRoot, "gpg.format"),
+		Key:     configValue(repoRoot, "user.signingkey"),
+	}
+	if s.Format == "" {
+		s.Format = "openpgp"
+	}
+	return s
+}
+
+// String describes the signing, e.g. "signed with ssh key ~/.ssh/id.pub".
+func (s Signing) String() string {
+	if !s.Enabled {
+		return "not signed"
+	}
+	format := s.Format
+	if format == "openpgp" {
+		format = "gpg"
+	}
+	if s.Key == "" {
+		return "signed with the default " + format + " key"
+	}
+	return "signed with " + format + " key " + s.Key
+}
+
+// isTrue reports whether a git config value is a true boolean.
+func isTrue(value string) bool {
+	switch strings.ToLower(value) {
+	case "true", "yes", "on", "1":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Snapshot records the working tree of the repository, untracked files
+// included and ignored ones left out, and returns the hash of its tree.
+// The files are staged in a copy of the index, so the repository's own
+// index is left alone. Diff two snapshots with ChangedFiles.
+func Snapshot(repoRoot string) (string, error) {
+	tmp, err := os.CreateTemp("", "gdev-index-")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	// Starting from the real index spares hashing files that didn't change
+	out, err := exec.Command("git", "-C", repoRoot, "rev-parse", "--git-path", "index").Output()
+	if err != nil {
+		tmp.Close()
+		return "", err
+	}
+	index := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(index) {
+		index = filepath.Join(repoRoot, index)
+	}
+	if err := copyFile(tmp, index); err != nil {
+		return "", err
+	}
+
+	env := append(os.Environ(), "GIT_INDEX_FILE="+tmp.Name())
+	add := exec.Command("git", "add", "-A")
+	add.Dir = repoRoot
+	add.Env = env
+	if _, err := output(add); err != nil {
+		return "", err
+	}
+	writeTree := exec.Command("git", "write-tree")
+	writeTree.Dir = repoRoot
+	writeTree.Env = env
+	out, err = output(writeTree)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Checkpoint records the working tree like Snapshot, as a commit with
+// message, and returns its hash. Its parent is parent, or HEAD if parent is
+// "" and the repository has commits, so checkpoints taken in turn form a
+// history of their own. No branch points at it; ref, e.g.
+// refs/gdev/checkpoints/<run>, is moved to it instead, so it survives git gc.
+func Checkpoint(repoRoot, ref, message, parent string) (string, error) {
+	tree, err := Snapshot(repoRoot)
+	if err != nil {
+		return "", err
+	}
+	if parent == "" {
+		out, err := exec.Command("git", "-C", repoRoot, "rev-parse", "--verify", "-q", "HEAD").Output()
+		if err == nil {
+			parent = strings.TrimSpace(string(out))
+		}
+	}
+
+	args := []string{"commit-tree", tree, "-m", message}
+	if parent != "" {
+		args = append(args, "-p", parent)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoRoot
+	out, err := output(cmd)
+	if err != nil {
+		return "", err
+	}
+	commit := strings.TrimSpace(string(out))
+
+	cmd = exec.Command("git", "update-ref", "-m", message, ref, commit)
+	cmd.Dir = repoRoot
+	if _, err := output(cmd); err != nil {
+		return "", err
+	}
+	return commit, nil
+}
+
+// output runs cmd and returns its output. A failure reports the last line
+// git wrote to stderr, such as why the author identity is missing.
+func output(cmd *exec.Cmd) ([]byte, error) {
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(bytes.TrimSpace(exitErr.Stderr)) > 0 {
+		lines := strings.Split(strings.TrimSpace(string(exitErr.Stderr)), "\n")
+		return nil, fmt.Errorf("git %s: %s", cmd.Args[1], lines[len(lines)-1])
+	}
+	return out, err
+}
+
+// copyFile copies the file at path into dst and closes dst. A missing file,
+// like the index of a repository without commits, copies nothing.
+func copyFile(dst *os.File, path string) error {
+	src, err := os.Open(path)
+	if os.IsNotExist(err) {
+		// An empty index file is invalid; git creates it from scratch
+		dst.Close()
+		return os.Remove(dst.Name())
+	}
+	if err != nil {
+		dst.Close()
+		return err
+	}
+	defer src.Close()
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
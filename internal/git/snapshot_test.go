@@ -0,0 +1,77 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSnapshot_LeavesIndexAlone(t *testing.T) {
+	local, _ := clone(t)
+	os.WriteFile(filepath.Join(local, "tracked.txt"), []byte("one\n"), 0o644)
+	run(t, local, "add", "tracked.txt")
+	run(t, local, "commit", "-m", "tracked")
+
+	os.WriteFile(filepath.Join(local, "tracked.txt"), []byte("two\n"), 0o644)
+	os.WriteFile(filepath.Join(local, "untracked.txt"), []byte("new\n"), 0o644)
+	os.WriteFile(filepath.Join(local, ".gitignore"), []byte("ignored.txt\n"), 0o644)
+	os.WriteFile(filepath.Join(local, "ignored.txt"), []byte("secret\n"), 0o644)
+	status := run(t, local, "status", "--porcelain")
+
+	tree, err := Snapshot(local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := run(t, local, "status", "--porcelain"); got != status {
+		t.Errorf("status after Snapshot() = %q, want %q", got, status)
+	}
+	if got := run(t, local, "show", tree+":tracked.txt"); got != "two" {
+		t.Errorf("tracked.txt in the snapshot = %q, want the working tree's", got)
+	}
+	if got := run(t, local, "show", tree+":untracked.txt"); got != "new" {
+		t.Errorf("untracked.txt in the snapshot = %q, want it captured", got)
+	}
+	if got := run(t, local, "ls-tree", "--name-only", tree, "ignored.txt"); got != "" {
+		t.Errorf("snapshot has ignored file %q", got)
+	}
+}
+
+func TestCheckpoint_KeptUnderRef(t *testing.T) {
+	local, _ := clone(t)
+	head := run(t, local, "rev-parse", "HEAD")
+	ref := "refs/gdev/checkpoints/run"
+
+	os.WriteFile(filepath.Join(local, "a.txt"), []byte("a\n"), 0o644)
+	first, err := Checkpoint(local, ref, "first", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(local, "b.txt"), []byte("b\n"), 0o644)
+	second, err := Checkpoint(local, ref, "second", first)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := run(t, local, "rev-parse", ref); got != second {
+		t.Errorf("%s = %s, want the last checkpoint %s", ref, got, second)
+	}
+	if got := run(t, local, "rev-parse", second+"^"); got != first {
+		t.Errorf("parent of the second checkpoint = %s, want the first %s", got, first)
+	}
+	if got := run(t, local, "rev-parse", first+"^"); got != head {
+		t.Errorf("parent of the first checkpoint = %s, want HEAD %s", got, head)
+	}
+	if got := run(t, local, "rev-parse", "HEAD"); got != head {
+		t.Errorf("HEAD moved to %s", got)
+	}
+	if got := run(t, local, "status", "--porcelain"); got != "?? a.txt\n?? b.txt" {
+		t.Errorf("status after Checkpoint() = %q, want a.txt and b.txt untracked", got)
+	}
+
+	// Nothing but the ref keeps the checkpoints
+	run(t, local, "reflog", "expire", "--expire=now", "--all")
+	run(t, local, "gc", "--prune=now", "--quiet")
+	if got := run(t, local, "show", first+":a.txt"); got != "a" {
+		t.Errorf("a.txt in the first checkpoint after gc = %q", got)
+	}
+}
@@ -0,0 +1,82 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Operation is a multi-step git operation that stopped partway,
+// usually because of conflicts.
+type Operation string
+
+const (
+	OpNone       Operation = ""
+	OpMerge      Operation = "merge"
+	OpRebase     Operation = "rebase"
+	OpCherryPick Operation = "cherry-pick"
+	OpRevert     Operation = "revert"
+	OpAm         Operation = "am"
+)
+
+// opMarkers are the files or directories in the git directory that
+// show an operation is in progress, checked in order.
+var opMarkers = []struct {
+	op     Operation
+	marker string
+}{
+	{OpRebase, "rebase-merge"},
+	{OpAm, "rebase-apply/applying"}, // git am shares rebase-apply with rebase
+	{OpRebase, "rebase-apply"},
+	{OpMerge, "MERGE_HEAD"},
+	{OpCherryPick, "CHERRY_PICK_HEAD"},
+	{OpRevert, "REVERT_HEAD"},
+}
+
+// InProgress returns the operation in progress in the repository, if any.
+func InProgress(repoRoot string) (Operation, error) {
+	cmd := exec.Command("git", "rev-parse", "--absolute-git-dir")
+	cmd.Dir = repoRoot
+	out, err := cmd.Output()
+	if err != nil {
+		return OpNone, err
+	}
+	gitDir := strings.TrimSpace(string(out))
+
+	for _, m := range opMarkers {
+		if _, err := os.Stat(filepath.Join(gitDir, m.marker)); err == nil {
+			return m.op, nil
+		}
+	}
+	return OpNone, nil
+}
+
+// ConflictedFiles returns the paths with unresolved conflicts.
+func ConflictedFiles(repoRoot string) ([]string, error) {
+	cmd := exec.Command("git", "diff", "--name-only", "--diff-filter=U")
+	cmd.Dir = repoRoot
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// ContinueArgs returns the git arguments that continue the operation.
+// The editor is disabled so git keeps the prepared commit message
+// instead of waiting for input.
+func (op Operation) ContinueArgs() []string {
+	return []string{"-c", "core.editor=true", string(op), "--continue"}
+}
+
+// AbortArgs returns the git arguments that abort the operation.
+func (op Operation) AbortArgs() []string {
+	return []string{string(op), "--abort"}
+}
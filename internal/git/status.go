@@ -0,0 +1,50 @@
+package git
+
+import (
+	"bytes"
+	"os/exec"
+)
+
+// StatusFile is a file with uncommitted changes.
+type StatusFile struct {
+	Path    string
+	OldPath string // previous path for renames, empty otherwise
+	Status  string // two-letter porcelain status, e.g. " M", "A ", "??"
+}
+
+// Untracked reports whether the file is not yet tracked by git.
+func (f StatusFile) Untracked() bool {
+	return f.Status == "??"
+}
+
+// Status returns the files with staged, unstaged or untracked changes.
+// Untracked directories are listed file by file.
+func Status(repoRoot string) ([]StatusFile, error) {
+	cmd := exec.Command("git", "status", "--porcelain", "-z", "--untracked-files=all")
+	cmd.Dir = repoRoot
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseStatus(out), nil
+}
+
+// parseStatus parses `git status --porcelain -z` output. Each entry is
+// "XY <path>\0", followed by "<old>\0" for renames and copies.
+func parseStatus(out []byte) []StatusFile {
+	var files []StatusFile
+	fields := bytes.Split(out, []byte{0})
+	for i := 0; i < len(fields); i++ {
+		entry := fields[i]
+		if len(entry) < 4 {
+			continue
+		}
+		f := StatusFile{Status: string(entry[:2]), Path: string(entry[3:])}
+		if (entry[0] == 'R' || entry[0] == 'C') && i+1 < len(fields) {
+			i++
+			f.OldPath = string(fields[i])
+		}
+		files = append(files, f)
+	}
+	return files
+}
@@ -0,0 +1,195 @@
+// Package instance keeps to one gdev per repository, if the single_instance
+// setting asks for it.
+//
+// The first gdev started in a repository listens on a unix socket named
+// after it. gdev started there again sends its command line to that Server
+// instead of running it, prints the reply and exits, so two processes never
+// edit the repository's files in ~/.gdev at once.
+package instance
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ErrRunning is returned by Listen when gdev already runs for the socket.
+var ErrRunning = errors.New("gdev is already running for this repository")
+
+// ErrNotRunning is returned by Send when nothing listens on the socket.
+var ErrNotRunning = errors.New("gdev is not running for this repository")
+
+// errNoAnswer is sent back when the TUI doesn't handle a request in time.
+var errNoAnswer = errors.New("gdev is running for this repository but didn't answer")
+
+// replyTimeout is how long a request waits for the TUI to handle it.
+const replyTimeout = 5 * time.Second
+
+// Request is a command line forwarded by another gdev, waiting for a reply.
+type Request struct {
+	Args []string // without the program name and flags
+
+	reply chan response
+	once  sync.Once
+}
+
+// Reply sends text to the waiting gdev, which prints it. A non-nil err is
+// printed as an error and makes it exit with a failure.
+func (r *Request) Reply(text string, err error) {
+	resp := response{Text: text, OK: err == nil}
+	if err != nil {
+		resp.Text = err.Error()
+	}
+	r.once.Do(func() { r.reply <- resp })
+}
+
+// request and response are the messages exchanged over the socket, one JSON
+// line each.
+type (
+	request struct {
+		Args []string `json:"args"`
+	}
+
+	response struct {
+		Text string `json:"text"`
+		OK   bool   `json:"ok"`
+	}
+)
+
+// Server receives the command lines of the gdev started after it.
+type Server struct {
+	socket   string
+	lock     *os.File // held while the server runs, see Listen
+	listener net.Listener
+	requests chan *Request
+	done     chan struct{}
+}
+
+// Listen starts a server on socket. If a gdev answers on it already, it
+// returns ErrRunning; a socket left behind by one that crashed is replaced.
+// The directory of socket is made private to the current user.
+//
+// A lock file next to the socket is held from before the socket is checked
+// until the server is closed, so two gdev started at once can't both find
+// no server, and one remove the socket the other just listens on.
+func Listen(socket string) (*Server, error) {
+	// Only the current user may send commands. The socket is created with
+	// the umask's permissions, so it is kept where nobody else can reach it
+	// rather than restricted once it already accepts connections.
+	dir := filepath.Dir(socket)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(dir, 0700); err != nil {
+		return nil, err
+	}
+	lock, err := lock(socket + ".lock")
+	if err != nil {
+		return nil, err
+	}
+
+	if conn, err := net.Dial("unix", socket); err == nil {
+		conn.Close()
+		lock.Close()
+		return nil, ErrRunning
+	}
+	if err := os.Remove(socket); err != nil && !errors.Is(err, os.ErrNotExist) {
+		lock.Close()
+		return nil, err
+	}
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		lock.Close()
+		return nil, err
+	}
+
+	s := &Server{socket: socket, lock: lock, listener: l, requests: make(chan *Request), done: make(chan struct{})}
+	go s.serve()
+	return s, nil
+}
+
+// Requests returns the channel requests arrive on. It is closed by Close.
+func (s *Server) Requests() <-chan *Request {
+	return s.requests
+}
+
+// Close stops the server, removes its socket and releases its lock.
+func (s *Server) Close() error {
+	close(s.done)
+	err := s.listener.Close()
+	os.Remove(s.socket)
+	s.lock.Close()
+	return err
+}
+
+func (s *Server) serve() {
+	var wg sync.WaitGroup
+	defer func() {
+		wg.Wait()
+		close(s.requests)
+	}()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.handle(conn)
+		}()
+	}
+}
+
+// handle passes one request on and sends back the reply.
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+
+	var req request
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		return
+	}
+
+	r := &Request{Args: req.Args, reply: make(chan response, 1)}
+	resp := response{Text: errNoAnswer.Error()}
+	timeout := time.After(replyTimeout)
+	select {
+	case s.requests <- r:
+		select {
+		case resp = <-r.reply:
+		case <-s.done:
+		case <-timeout:
+		}
+	case <-s.done:
+	case <-timeout:
+	}
+	json.NewEncoder(conn).Encode(resp)
+}
+
+// Send forwards args to the gdev listening on socket and returns its reply.
+// It returns ErrNotRunning if there is none.
+func Send(socket string, args []string) (string, error) {
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return "", ErrNotRunning
+	}
+	defer conn.Close()
+
+	if err := json.NewEncoder(conn).Encode(request{Args: args}); err != nil {
+		return "", err
+	}
+
+	var resp response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return "", errNoAnswer
+	}
+	if !resp.OK {
+		return "", errors.New(resp.Text)
+	}
+	return resp.Text, nil
+}
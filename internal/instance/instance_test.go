@@ -0,0 +1,82 @@
+package instance
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSend(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "repo.sock")
+	if _, err := Send(socket, nil); !errors.Is(err, ErrNotRunning) {
+		t.Errorf("Send() without a server error = %v; want ErrNotRunning", err)
+	}
+
+	srv, err := Listen(socket)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	defer srv.Close()
+
+	if _, err := Listen(socket); !errors.Is(err, ErrRunning) {
+		t.Errorf("Listen() again error = %v; want ErrRunning", err)
+	}
+
+	go func() {
+		for r := range srv.Requests() {
+			if len(r.Args) == 2 && r.Args[0] == "capture" {
+				r.Reply("Added TODO "+r.Args[1], nil)
+			} else {
+				r.Reply("", errors.New("unknown command "+strings.Join(r.Args, " ")))
+			}
+		}
+	}()
+
+	reply, err := Send(socket, []string{"capture", "fix it"})
+	if err != nil || reply != "Added TODO fix it" {
+		t.Errorf("Send(capture) = %q, %v; want the reply", reply, err)
+	}
+	if _, err := Send(socket, []string{"frobnicate"}); err == nil || err.Error() != "unknown command frobnicate" {
+		t.Errorf("Send(frobnicate) error = %v; want the error replied", err)
+	}
+}
+
+func TestListen_ReplacesStaleSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "repo.sock")
+	srv, err := Listen(socket)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	// A gdev that crashed leaves its socket behind, and its lock released
+	srv.listener.(interface{ SetUnlinkOnClose(bool) }).SetUnlinkOnClose(false)
+	srv.listener.Close()
+	srv.lock.Close()
+
+	srv, err = Listen(socket)
+	if err != nil {
+		t.Fatalf("Listen() over a stale socket: %v", err)
+	}
+	srv.Close()
+}
+
+func TestListen_PrivateDirectory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "instances")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	srv, err := Listen(filepath.Join(dir, "repo.sock"))
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	defer srv.Close()
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0700 {
+		t.Errorf("socket directory permissions = %o, want 700", perm)
+	}
+}
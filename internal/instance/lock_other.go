@@ -0,0 +1,11 @@
+//go:build !unix
+
+package instance
+
+import "os"
+
+// lock opens the lock file at path without locking it where flock is not
+// supported; two gdev started at once may then both replace the socket.
+func lock(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+}
@@ -0,0 +1,28 @@
+//go:build unix
+
+package instance
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestListen_Locked(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "repo.sock")
+	// Another gdev is between checking the socket and listening on it
+	f, err := lock(socket + ".lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Listen(socket); !errors.Is(err, ErrRunning) {
+		t.Errorf("Listen() while locked error = %v; want ErrRunning", err)
+	}
+	f.Close()
+
+	srv, err := Listen(socket)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	srv.Close()
+}
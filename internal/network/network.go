@@ -0,0 +1,116 @@
+// Package network applies the proxy and certificate settings to the gh,
+// claude and git commands gdev runs, and checks that their APIs can be reached.
+package network
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ihatemodels/gdev/internal/config"
+)
+
+// Endpoints are the APIs gdev's integrations talk to.
+var Endpoints = []string{
+	"https://api.github.com",
+	"https://api.anthropic.com",
+}
+
+// Env returns the environment variables that apply the network settings.
+// Variables already set in the environment take precedence and are skipped.
+func Env(st *config.Settings) []string {
+	var env []string
+	set := func(name, value string) {
+		if value == "" || os.Getenv(name) != "" || os.Getenv(strings.ToLower(name)) != "" {
+			return
+		}
+		env = append(env, name+"="+value)
+	}
+
+	set("HTTPS_PROXY", st.Proxy)
+	set("HTTP_PROXY", st.Proxy)
+	set("NO_PROXY", st.NoProxy)
+
+	if st.CABundle != "" {
+		bundle := config.ExpandHome(st.CABundle)
+		set("SSL_CERT_FILE", bundle)       // gh and other Go programs
+		set("NODE_EXTRA_CA_CERTS", bundle) // claude
+		set("GIT_SSL_CAINFO", bundle)      // git over HTTPS
+	}
+	return env
+}
+
+// Apply sets the network settings in gdev's own environment, so every
+// command it runs inherits them.
+func Apply(st *config.Settings) error {
+	for _, kv := range Env(st) {
+		name, value, _ := strings.Cut(kv, "=")
+		if err := os.Setenv(name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Client returns an HTTP client that uses the proxy from the environment and
+// trusts the certificates in caBundle in addition to the system ones.
+func Client(caBundle string) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+
+	if caBundle != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		pem, err := os.ReadFile(config.ExpandHome(caBundle))
+		if err != nil {
+			return nil, err
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%s: no PEM certificates found", caBundle)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	return &http.Client{Transport: transport, Timeout: 10 * time.Second}, nil
+}
+
+// Check is the outcome of reaching an endpoint.
+type Check struct {
+	URL     string
+	Proxy   string // proxy the request went through, "" for a direct connection
+	Status  string // HTTP status, if the endpoint answered
+	Err     error
+	Elapsed time.Duration
+}
+
+// CheckEndpoint makes a request to url. Any HTTP response counts as reachable,
+// since the APIs answer unauthenticated requests with errors.
+func CheckEndpoint(client *http.Client, url string) Check {
+	c := Check{URL: url}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		c.Err = err
+		return c
+	}
+	if proxy, err := http.ProxyFromEnvironment(req); err == nil && proxy != nil {
+		c.Proxy = proxy.Redacted()
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	c.Elapsed = time.Since(start)
+	if err != nil {
+		c.Err = err
+		return c
+	}
+	resp.Body.Close()
+	c.Status = resp.Status
+	return c
+}
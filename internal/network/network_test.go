@@ -0,0 +1,34 @@
+package network
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/ihatemodels/gdev/internal/config"
+)
+
+func TestEnv(t *testing.T) {
+	for _, name := range []string{"HTTPS_PROXY", "https_proxy", "HTTP_PROXY", "http_proxy", "NO_PROXY", "no_proxy",
+		"SSL_CERT_FILE", "NODE_EXTRA_CA_CERTS", "GIT_SSL_CAINFO"} {
+		t.Setenv(name, "")
+	}
+	// Set in the environment, so the setting is ignored
+	t.Setenv("http_proxy", "http://env:3128")
+
+	st := &config.Settings{Proxy: "http://corp:8080", NoProxy: "localhost,.corp", CABundle: "/etc/corp.pem"}
+	got := Env(st)
+	want := []string{
+		"HTTPS_PROXY=http://corp:8080",
+		"NO_PROXY=localhost,.corp",
+		"SSL_CERT_FILE=/etc/corp.pem",
+		"NODE_EXTRA_CA_CERTS=/etc/corp.pem",
+		"GIT_SSL_CAINFO=/etc/corp.pem",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Env() = %v, want %v", got, want)
+	}
+
+	if env := Env(config.DefaultSettings()); len(env) != 0 {
+		t.Errorf("Env(defaults) = %v, want none", env)
+	}
+}
@@ -0,0 +1,194 @@
+// Package scaffold creates new projects from templates: those embedded in
+// gdev and the user's own in ~/.gdev/templates.
+package scaffold
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/ihatemodels/gdev/internal/embedded"
+)
+
+// metaFile describes a template and is not copied to the project.
+const metaFile = "template.json"
+
+// tmplExt is stripped from the names of the files a template creates, so
+// templates can hold Go files without the go command building them.
+const tmplExt = ".tmpl"
+
+// Template is a directory of files a new project starts with. Names and
+// contents may use the placeholders of Vars, e.g. {{name}}.
+type Template struct {
+	Name        string
+	Description string
+	User        bool // from the user's templates rather than gdev's
+
+	files fs.FS
+}
+
+// List returns gdev's templates and the user's in userDir, each a
+// directory, by name. A user template replaces gdev's of the same name.
+// userDir need not exist.
+func List(userDir string) ([]Template, error) {
+	byName := make(map[string]Template)
+	if err := collect(byName, embedded.Templates(), false); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(userDir); err == nil {
+		if err := collect(byName, os.DirFS(userDir), true); err != nil {
+			return nil, err
+		}
+	}
+
+	templates := make([]Template, 0, len(byName))
+	for _, t := range byName {
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+// collect adds the templates in the directories of fsys to byName.
+func collect(byName map[string]Template, fsys fs.FS, user bool) error {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		files, err := fs.Sub(fsys, e.Name())
+		if err != nil {
+			return err
+		}
+		t := Template{Name: e.Name(), User: user, files: files}
+		if data, err := fs.ReadFile(files, metaFile); err == nil {
+			var meta struct {
+				Description string `json:"description"`
+			}
+			if err := json.Unmarshal(data, &meta); err != nil {
+				return fmt.Errorf("template %s: %s: %w", e.Name(), metaFile, err)
+			}
+			t.Description = meta.Description
+		}
+		byName[t.Name] = t
+	}
+	return nil
+}
+
+// Vars returns the values of the placeholders for a project named name:
+// {{name}}, {{module}} (module, or name if it is empty), {{package}} (name
+// as a Go package name) and {{go_version}} (of the Go gdev was built with).
+func Vars(name, module string) map[string]string {
+	if module == "" {
+		module = name
+	}
+	return map[string]string{
+		"name":       name,
+		"module":     module,
+		"package":    packageName(name),
+		"go_version": goVersion(),
+	}
+}
+
+// packageName turns a project name into a Go package name: lower case
+// letters and digits only, e.g. "my-lib" becomes "mylib".
+func packageName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || (unicode.IsDigit(r) && b.Len() > 0) {
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() == 0 {
+		return "project"
+	}
+	return b.String()
+}
+
+// goVersion returns the language version of the Go gdev was built with,
+// e.g. "1.25" for go1.25.4.
+func goVersion() string {
+	v := strings.TrimPrefix(runtime.Version(), "go")
+	parts := strings.SplitN(v, ".", 3)
+	if len(parts) < 2 {
+		return "1.22"
+	}
+	return parts[0] + "." + strings.TrimFunc(parts[1], func(r rune) bool { return !unicode.IsDigit(r) })
+}
+
+// File is a file of a new project, its path relative to the project.
+type File struct {
+	Path string
+	Data []byte
+}
+
+// Render returns the files the template creates, with the placeholders in
+// their paths and contents replaced by vars.
+func (t Template) Render(vars map[string]string) ([]File, error) {
+	var files []File
+	err := fs.WalkDir(t.files, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || p == metaFile {
+			return nil
+		}
+		data, err := fs.ReadFile(t.files, p)
+		if err != nil {
+			return err
+		}
+		name, _ := embedded.Expand(strings.TrimSuffix(p, tmplExt), vars)
+		content, _ := embedded.Expand(string(data), vars)
+		files = append(files, File{Path: path.Clean(name), Data: []byte(content)})
+		return nil
+	})
+	return files, err
+}
+
+// ErrNotEmpty is returned by CheckTarget for a directory that has files.
+var ErrNotEmpty = errors.New("directory is not empty")
+
+// CheckTarget returns an error unless a project can be created in dir: it
+// must not exist, or be empty.
+func CheckTarget(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(entries) > 0 {
+		return fmt.Errorf("%s: %w", dir, ErrNotEmpty)
+	}
+	return nil
+}
+
+// Write creates the files in dir, creating it and the directories between.
+func Write(dir string, files []File) error {
+	for _, f := range files {
+		target := filepath.Join(dir, filepath.FromSlash(f.Path))
+		// A placeholder expanding to ../ must not write outside the project
+		if rel, err := filepath.Rel(dir, target); err != nil || strings.HasPrefix(rel, "..") {
+			return fmt.Errorf("%s is outside the project", f.Path)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, f.Data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package scaffold
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestList_UserReplacesEmbedded(t *testing.T) {
+	user := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(user, "go-cli"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(user, "go-cli", "README.md"), []byte("# {{name}}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	templates, err := List(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := map[string]bool{}
+	for _, tmpl := range templates {
+		names[tmpl.Name] = true
+		if tmpl.Name == "go-cli" && !tmpl.User {
+			t.Error("go-cli should be the user's template")
+		}
+	}
+	if !names["go-cli"] || !names["go-library"] {
+		t.Errorf("List() = %v, want go-cli and go-library", templates)
+	}
+}
+
+func TestRender_GoTemplatesBuild(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not installed")
+	}
+	templates, err := List(filepath.Join(t.TempDir(), "missing"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tmpl := range templates {
+		t.Run(tmpl.Name, func(t *testing.T) {
+			files, err := tmpl.Render(Vars("my-tool", "example.com/my-tool"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			dir := filepath.Join(t.TempDir(), "my-tool")
+			if err := Write(dir, files); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := os.Stat(filepath.Join(dir, metaFile)); err == nil {
+				t.Error("template.json was copied to the project")
+			}
+
+			cmd := exec.Command("go", "vet", "./...")
+			cmd.Dir = dir
+			cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOTOOLCHAIN=local")
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Errorf("go vet: %v\n%s", err, out)
+			}
+		})
+	}
+}
+
+func TestPackageName(t *testing.T) {
+	tests := map[string]string{
+		"my-lib":  "mylib",
+		"Parser2": "parser2",
+		"2fa":     "fa",
+		"---":     "project",
+	}
+	for name, want := range tests {
+		if got := packageName(name); got != want {
+			t.Errorf("packageName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestCheckTarget(t *testing.T) {
+	dir := t.TempDir()
+	if err := CheckTarget(dir); err != nil {
+		t.Errorf("empty directory: %v", err)
+	}
+	if err := CheckTarget(filepath.Join(dir, "new")); err != nil {
+		t.Errorf("missing directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "f"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckTarget(dir); !errors.Is(err, ErrNotEmpty) {
+		t.Errorf("directory with files: %v, want ErrNotEmpty", err)
+	}
+}
+
+func TestWrite_StaysInProject(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "p")
+	if err := Write(dir, []File{{Path: "../escape", Data: nil}}); err == nil {
+		t.Error("Write() wrote outside the project")
+	}
+}
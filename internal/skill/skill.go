@@ -0,0 +1,103 @@
+// Package skill lists the claude commands that can be run as skills:
+// those embedded in gdev and the user's own in ~/.gdev/claude/commands.
+package skill
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ihatemodels/gdev/internal/embedded"
+)
+
+// Skill is a claude command: a markdown prompt with optional frontmatter
+// giving its description, the model it runs with, the tools it may use and
+// what its parameters are (see embedded.ParseFrontmatter). The {{name}}
+// placeholders of the prompt are parameters too.
+type Skill struct {
+	Name string
+	embedded.Frontmatter
+	User bool // from the user's commands rather than gdev's
+
+	prompt string
+}
+
+// List returns gdev's skills and the user's in userDir, a .md file each,
+// by name. A user skill replaces gdev's of the same name. userDir need not
+// exist.
+func List(userDir string) ([]Skill, error) {
+	byName := make(map[string]Skill)
+	if err := collect(byName, embedded.Commands(), false); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(userDir); err == nil {
+		if err := collect(byName, os.DirFS(userDir), true); err != nil {
+			return nil, err
+		}
+	}
+
+	skills := make([]Skill, 0, len(byName))
+	for _, s := range byName {
+		skills = append(skills, s)
+	}
+	sort.Slice(skills, func(i, j int) bool { return skills[i].Name < skills[j].Name })
+	return skills, nil
+}
+
+// collect adds the skills of the .md files in fsys to byName.
+func collect(byName map[string]Skill, fsys fs.FS, user bool) error {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() || path.Ext(e.Name()) != ".md" {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return err
+		}
+		s := Parse(strings.TrimSuffix(e.Name(), ".md"), string(data))
+		s.User = user
+		byName[s.Name] = s
+	}
+	return nil
+}
+
+// Parse reads the skill called name from the content of its file.
+func Parse(name, content string) Skill {
+	front, prompt := embedded.ParseFrontmatter(content)
+	s := Skill{Name: name, Frontmatter: front, prompt: prompt}
+
+	// Placeholders the frontmatter doesn't declare are parameters too
+	for _, p := range embedded.Placeholders(prompt) {
+		if !slices.ContainsFunc(s.Params, func(d embedded.Param) bool { return d.Name == p }) {
+			s.Params = append(s.Params, embedded.Param{Name: p, Required: true})
+		}
+	}
+	return s
+}
+
+// Prompt returns the prompt of the skill with its parameters filled in
+// with values.
+func (s Skill) Prompt(values map[string]string) string {
+	prompt, _ := embedded.Expand(s.prompt, values)
+	return prompt
+}
+
+// Args returns the arguments of claude running the skill with values.
+func (s Skill) Args(values map[string]string) []string {
+	args := []string{"-p", s.Prompt(values)}
+	if s.Model != "" {
+		args = append(args, "--model", s.Model)
+	}
+	if len(s.AllowedTools) > 0 {
+		// Last, as the flag takes every argument after it
+		args = append(append(args, "--allowedTools"), s.AllowedTools...)
+	}
+	return args
+}
@@ -0,0 +1,66 @@
+package skill
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ihatemodels/gdev/internal/embedded"
+)
+
+func TestParse(t *testing.T) {
+	s := Parse("explain", `---
+description: "Explain a part of the code"
+model: sonnet
+allowed-tools: Read, Bash(git log:*)
+params:
+  - path: File or directory to explain
+---
+Explain {{path}} to someone new, focusing on {{ focus }}. Mention {{path}} by name.`)
+
+	if s.Description != "Explain a part of the code" || s.Model != "sonnet" {
+		t.Errorf("Parse() = %+v", s)
+	}
+	want := []embedded.Param{
+		{Name: "path", Description: "File or directory to explain", Required: true},
+		{Name: "focus", Required: true},
+	}
+	if !slices.Equal(s.Params, want) {
+		t.Errorf("Params = %+v, want %+v", s.Params, want)
+	}
+
+	prompt := s.Prompt(map[string]string{"path": "main.go", "focus": "errors"})
+	if want := "Explain main.go to someone new, focusing on errors. Mention main.go by name."; prompt != want {
+		t.Errorf("Prompt() = %q, want %q", prompt, want)
+	}
+	args := s.Args(nil)
+	if want := []string{"--model", "sonnet", "--allowedTools", "Read", "Bash(git log:*)"}; !slices.Equal(args[2:], want) {
+		t.Errorf("Args() = %q, want the prompt then %q", args, want)
+	}
+}
+
+func TestList_UserReplacesEmbedded(t *testing.T) {
+	user := t.TempDir()
+	if err := os.WriteFile(filepath.Join(user, "review-pr.md"), []byte("Review the branch."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(user, "notes.txt"), []byte("not a skill"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	skills, err := List(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := map[string]Skill{}
+	for _, s := range skills {
+		names[s.Name] = s
+	}
+	if !names["review-pr"].User || names["generate-commit-msg"].User {
+		t.Errorf("List() = %+v, want review-pr from the user", skills)
+	}
+	if _, ok := names["notes"]; ok {
+		t.Error("List() included a file that isn't markdown")
+	}
+}
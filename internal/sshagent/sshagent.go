@@ -0,0 +1,160 @@
+// Package sshagent locates an ssh-agent before running git commands that
+// may need one, such as signed commits.
+package sshagent
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Strategy is how an ssh-agent is found when SSH_AUTH_SOCK is not set.
+type Strategy string
+
+const (
+	// Off leaves SSH_AUTH_SOCK as gdev was started with.
+	Off Strategy = "off"
+	// UseExisting looks for the socket of an agent that is already running,
+	// e.g. one started by the desktop session. It never starts an agent.
+	UseExisting Strategy = "use-existing"
+	// Systemd starts the ssh-agent systemd user unit and uses its socket.
+	Systemd Strategy = "systemd-user-unit"
+	// Command runs a custom command that prints the socket path.
+	Command Strategy = "command"
+)
+
+// Strategies lists every strategy in the order settings cycles through them.
+var Strategies = []Strategy{Off, UseExisting, Systemd, Command}
+
+// Describe returns a short description of the strategy for display.
+func (s Strategy) Describe() string {
+	switch s {
+	case Off:
+		return "off"
+	case Systemd:
+		return "systemd user unit"
+	case Command:
+		return "custom command"
+	default:
+		return "use existing agent"
+	}
+}
+
+// Next returns the strategy after s in Strategies.
+func (s Strategy) Next() Strategy {
+	for i, st := range Strategies {
+		if st == s {
+			return Strategies[(i+1)%len(Strategies)]
+		}
+	}
+	return Strategies[0]
+}
+
+// socketCandidates returns the sockets agents started by desktop sessions
+// and password managers commonly listen on, in the order they are checked.
+func socketCandidates() []string {
+	var candidates []string
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		candidates = append(candidates,
+			filepath.Join(dir, "ssh-agent.socket"), // systemd user unit
+			filepath.Join(dir, "gcr", "ssh"),       // GNOME 46+
+			filepath.Join(dir, "keyring", "ssh"),   // GNOME keyring
+			filepath.Join(dir, "gnupg", "S.gpg-agent.ssh"),
+		)
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		candidates = append(candidates,
+			filepath.Join(home, ".gnupg", "S.gpg-agent.ssh"),
+			filepath.Join(home, ".1password", "agent.sock"),
+			filepath.Join(home, ".bitwarden-ssh-agent.sock"),
+		)
+	}
+	// ssh-agent started by hand or by the display manager
+	started, _ := filepath.Glob("/tmp/ssh-*/agent.*")
+	return append(candidates, started...)
+}
+
+// Agent is the ssh-agent found for a command, if any.
+type Agent struct {
+	Socket  string // "" when none was found
+	Problem string // why the strategy found none, if it failed
+}
+
+// String reports the socket that will be used, e.g. "Using ssh-agent at
+// /run/user/1000/ssh-agent.socket".
+func (a Agent) String() string {
+	switch {
+	case a.Socket != "":
+		return "Using ssh-agent at " + a.Socket
+	case a.Problem != "":
+		return "No ssh-agent found: " + a.Problem
+	}
+	return "No ssh-agent found"
+}
+
+// Env returns env with SSH_AUTH_SOCK pointing at the agent, or env as it is
+// when none was found.
+func (a Agent) Env(env []string) []string {
+	if a.Socket == "" {
+		return env
+	}
+	return append(env, "SSH_AUTH_SOCK="+a.Socket)
+}
+
+// Find looks for an ssh-agent using strategy, unless SSH_AUTH_SOCK already
+// points at a socket. command is the shell command of the Command strategy.
+// Unknown strategies are treated as UseExisting.
+func Find(strategy Strategy, command string) Agent {
+	if sock := os.Getenv("SSH_AUTH_SOCK"); isSocket(sock) {
+		return Agent{Socket: sock}
+	}
+
+	switch strategy {
+	case Off:
+		// Nothing to find
+		return Agent{}
+
+	case Systemd:
+		sock := filepath.Join(os.Getenv("XDG_RUNTIME_DIR"), "ssh-agent.socket")
+		if exec.Command("systemctl", "--user", "start", "ssh-agent.service").Run() != nil || !isSocket(sock) {
+			return Agent{Problem: "systemd user unit ssh-agent.service is not available"}
+		}
+		return Agent{Socket: sock}
+
+	case Command:
+		if strings.TrimSpace(command) == "" {
+			return Agent{Problem: "no command configured (ssh_agent_command)"}
+		}
+		out, err := shellCommand(command).Output()
+		if sock := strings.TrimSpace(string(out)); err == nil && isSocket(sock) {
+			return Agent{Socket: sock}
+		}
+		return Agent{Problem: "command did not print a socket path"}
+	}
+
+	for _, sock := range socketCandidates() {
+		if isSocket(sock) {
+			return Agent{Socket: sock}
+		}
+	}
+	return Agent{}
+}
+
+// shellCommand runs command with the system's shell.
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
+
+// isSocket reports whether path is a unix socket.
+func isSocket(path string) bool {
+	if path == "" {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && info.Mode()&os.ModeSocket != 0
+}
@@ -0,0 +1,46 @@
+package sshagent
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+	sock := filepath.Join(dir, "ssh-agent.socket")
+	l, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	defer l.Close()
+
+	tests := []struct {
+		name     string
+		strategy Strategy
+		command  string
+		env      map[string]string
+		want     string
+	}{
+		{"off", Off, "", map[string]string{"XDG_RUNTIME_DIR": dir}, "No ssh-agent found"},
+		{"inherited", Off, "", map[string]string{"SSH_AUTH_SOCK": sock}, "Using ssh-agent at " + sock},
+		{"existing", UseExisting, "", map[string]string{"XDG_RUNTIME_DIR": dir}, "Using ssh-agent at " + sock},
+		{"command", Command, "echo " + sock, nil, "Using ssh-agent at " + sock},
+		{"command not a socket", Command, "echo " + dir, nil, "No ssh-agent found: command did not print a socket path"},
+		{"command missing", Command, "", nil, "No ssh-agent found: no command configured (ssh_agent_command)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			t.Setenv("SSH_AUTH_SOCK", "")
+			t.Setenv("XDG_RUNTIME_DIR", "")
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			if got := Find(tt.strategy, tt.command).String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// AuditEntry records a mutating command gdev ran in a repository.
+type AuditEntry struct {
+	Time     time.Time `json:"time"`
+	Repo     string    `json:"repo"`
+	Command  string    `json:"command"`   // full command line, shell-quoted
+	ExitCode int       `json:"exit_code"` // -1 if the command could not be started
+}
+
+// AppendAudit adds an entry to the audit log of its repository.
+// The log is a JSON Lines file so entries are only ever appended.
+func (s *Store) AppendAudit(e AuditEntry) error {
+	audit, err := s.SubDir("audit")
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return audit.Append(repoID(e.Repo)+".jsonl", append(data, '\n'))
+}
+
+// GetAudit loads the audit log for a repository, oldest entry first.
+func (s *Store) GetAudit(repoPath string) ([]AuditEntry, error) {
+	audit, err := s.SubDir("audit")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := audit.Read(repoID(repoPath) + ".jsonl")
+	if err == ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		var e AuditEntry
+		// Skip lines torn by an interrupted write
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
@@ -0,0 +1,58 @@
+package store
+
+import (
+	"regexp"
+	"time"
+)
+
+// CacheKey identifies a cached AI response by the provider and model that
+// produced it and a hash of the request.
+type CacheKey struct {
+	Provider string
+	Model    string
+	Hash     string
+}
+
+// CacheEntry is a cached AI response.
+type CacheEntry struct {
+	Time   time.Time `json:"time"`
+	Output []string  `json:"output"`
+}
+
+// unsafeFileChars matches characters not kept in cache file names.
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+func (k CacheKey) file() string {
+	return unsafeFileChars.ReplaceAllString(k.Provider+"_"+k.Model, "_") + "_" + k.Hash + ".json"
+}
+
+// GetCache returns the response cached under key, or nil if there is none
+// younger than ttl.
+func (s *Store) GetCache(key CacheKey, ttl time.Duration) (*CacheEntry, error) {
+	cache, err := s.SubDir("cache")
+	if err != nil {
+		return nil, err
+	}
+
+	var e CacheEntry
+	err = cache.ReadJSON(key.file(), &e)
+	if err == ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if time.Since(e.Time) > ttl {
+		return nil, nil
+	}
+	return &e, nil
+}
+
+// PutCache stores a response under key, replacing any older one.
+func (s *Store) PutCache(key CacheKey, output []string) error {
+	cache, err := s.SubDir("cache")
+	if err != nil {
+		return err
+	}
+	return cache.WriteJSON(key.file(), CacheEntry{Time: time.Now(), Output: output})
+}
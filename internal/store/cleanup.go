@@ -0,0 +1,78 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Policy limits the files kept in a directory of the store.
+type Policy struct {
+	MaxAge  time.Duration // 0 keeps files of any age
+	MaxSize int64         // total bytes, 0 keeps any size
+}
+
+// Reclaimed is what cleaning up a directory deleted, or would delete in
+// dry-run mode.
+type Reclaimed struct {
+	Dir   string
+	Files int
+	Bytes int64
+}
+
+// Cleanup deletes the files in the subdirectory dir older than the policy's
+// MaxAge, then the oldest of the rest until they fit in MaxSize. Files keep
+// returns true for are never deleted but count towards the size.
+func (s *Store) Cleanup(dir string, p Policy, keep func(path string) bool) (Reclaimed, error) {
+	r := Reclaimed{Dir: dir}
+	sub, err := s.SubDir(dir)
+	if err != nil {
+		return r, err
+	}
+
+	entries, err := os.ReadDir(sub.path)
+	if err != nil {
+		return r, err
+	}
+
+	type file struct {
+		name    string
+		size    int64
+		modTime time.Time
+	}
+	var files []file
+	var total int64
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, file{name: e.Name(), size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+
+	for _, f := range files {
+		expired := p.MaxAge > 0 && time.Since(f.modTime) > p.MaxAge
+		oversize := p.MaxSize > 0 && total > p.MaxSize
+		if !expired && !oversize {
+			continue
+		}
+		if keep != nil && keep(filepath.Join(sub.path, f.name)) {
+			continue
+		}
+		if err := sub.Delete(f.name); err != nil && err != ErrNotFound {
+			return r, err
+		}
+		total -= f.size
+		r.Files++
+		r.Bytes += f.size
+	}
+	return r, nil
+}
@@ -0,0 +1,64 @@
+package store
+
+import (
+	"slices"
+	"time"
+)
+
+// maxCommands is how many commands are remembered per repository.
+const maxCommands = 50
+
+// Command is a command the terminal ran in a repository, kept so it can be
+// run again.
+type Command struct {
+	Args     []string  `json:"args"`               // the program and its arguments
+	Mutating bool      `json:"mutating,omitempty"` // changes the repository, so it is audited
+	Ran      time.Time `json:"ran"`
+}
+
+// commandList is the file of a repository's commands.
+type commandList struct {
+	RepoPath string    `json:"repo_path"`
+	Commands []Command `json:"commands"` // oldest first
+}
+
+// GetCommands loads the commands run in a repository, most recent first.
+func (s *Store) GetCommands(repoPath string) ([]Command, error) {
+	cmds, err := s.SubDir("commands")
+	if err != nil {
+		return nil, err
+	}
+
+	var list commandList
+	if err := cmds.ReadJSON(repoID(repoPath)+".json", &list); err != nil {
+		if err == ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	slices.Reverse(list.Commands)
+	return list.Commands, nil
+}
+
+// AddCommand records a command run in a repository. A command run before
+// moves to the top; the oldest are forgotten beyond maxCommands.
+func (s *Store) AddCommand(repoPath string, c Command) error {
+	commands, err := s.GetCommands(repoPath)
+	if err != nil {
+		return err
+	}
+	slices.Reverse(commands)
+	commands = slices.DeleteFunc(commands, func(old Command) bool {
+		return slices.Equal(old.Args, c.Args)
+	})
+	commands = append(commands, c)
+	if len(commands) > maxCommands {
+		commands = commands[len(commands)-maxCommands:]
+	}
+
+	cmds, err := s.SubDir("commands")
+	if err != nil {
+		return err
+	}
+	return cmds.WriteJSON(repoID(repoPath)+".json", commandList{RepoPath: repoPath, Commands: commands})
+}
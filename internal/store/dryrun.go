@@ -0,0 +1,46 @@
+package store
+
+import (
+	"fmt"
+	"sync"
+)
+
+// dryRun records the mutations a Store would perform instead of performing them.
+// It is shared by a Store and its subdirectories, and accessed from commands
+// running in the background.
+type dryRun struct {
+	mu      sync.Mutex
+	enabled bool
+	plan    []string
+}
+
+// SetDryRun enables or disables dry-run mode. While enabled, writes and
+// deletes are recorded with Plan instead of touching the filesystem.
+func (s *Store) SetDryRun(enabled bool) {
+	s.dry.mu.Lock()
+	defer s.dry.mu.Unlock()
+	s.dry.enabled = enabled
+}
+
+// DryRun reports whether dry-run mode is enabled.
+func (s *Store) DryRun() bool {
+	s.dry.mu.Lock()
+	defer s.dry.mu.Unlock()
+	return s.dry.enabled
+}
+
+// Plan records an action that was skipped because of dry-run mode.
+func (s *Store) Plan(format string, args ...any) {
+	s.dry.mu.Lock()
+	defer s.dry.mu.Unlock()
+	s.dry.plan = append(s.dry.plan, fmt.Sprintf(format, args...))
+}
+
+// TakePlan returns the recorded actions and clears them.
+func (s *Store) TakePlan() []string {
+	s.dry.mu.Lock()
+	defer s.dry.mu.Unlock()
+	plan := s.dry.plan
+	s.dry.plan = nil
+	return plan
+}
@@ -0,0 +1,95 @@
+package store
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Run is the output of a terminal command saved to a log, kept so it can
+// be reopened from the history.
+type Run struct {
+	Title   string    `json:"title"`
+	Command string    `json:"command"`
+	Dir     string    `json:"dir"`
+	Log     string    `json:"log"`             // file the output was saved to
+	Err     string    `json:"error,omitempty"` // why the command failed, if it did
+	Saved   time.Time `json:"saved"`
+}
+
+// historyFile holds the saved runs, oldest first.
+const historyFile = "history.json"
+
+// GetHistory loads the saved runs, oldest first.
+func (s *Store) GetHistory() ([]Run, error) {
+	var runs []Run
+	if err := s.ReadJSON(historyFile, &runs); err != nil {
+		if err == ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return runs, nil
+}
+
+// SaveRun copies output to a new log in ~/.gdev/logs, named like those of
+// CreateLog after name, and adds r to the history with its path.
+func (s *Store) SaveRun(r Run, name string, output io.Reader) (Run, error) {
+	r.Saved = time.Now()
+	if s.DryRun() {
+		r.Log = filepath.Join(s.path, "logs", r.Saved.Format("20060102-150405")+"-"+unsafeFileChars.ReplaceAllString(name, "_")+"-*.log")
+		n, err := io.Copy(io.Discard, output)
+		if err != nil {
+			return r, err
+		}
+		s.Plan("write %s (%d bytes)", r.Log, n)
+	} else {
+		log, err := s.CreateLog(name)
+		if err != nil {
+			return r, err
+		}
+		r.Log = log.Name()
+		if _, err := io.Copy(log, output); err != nil {
+			log.Close()
+			os.Remove(r.Log)
+			return r, err
+		}
+		if err := log.Close(); err != nil {
+			return r, err
+		}
+	}
+
+	runs, err := s.GetHistory()
+	if err != nil {
+		return r, err
+	}
+	return r, s.WriteJSON(historyFile, append(runs, r))
+}
+
+// RemoveRun forgets the run saved to log and deletes the log.
+func (s *Store) RemoveRun(log string) error {
+	runs, err := s.GetHistory()
+	if err != nil {
+		return err
+	}
+
+	kept := runs[:0]
+	for _, r := range runs {
+		if r.Log != log {
+			kept = append(kept, r)
+		}
+	}
+	if err := s.WriteJSON(historyFile, kept); err != nil {
+		return err
+	}
+
+	logs, err := s.SubDir("logs")
+	if err != nil {
+		return err
+	}
+	if err := logs.Delete(filepath.Base(log)); err != nil && err != ErrNotFound {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package store
+
+import (
+	"path/filepath"
+	"time"
+)
+
+// Job is a command left running when gdev quit, kept so a later run can
+// show whether it finished and what it printed.
+type Job struct {
+	Title   string    `json:"title"`
+	Command string    `json:"command"`
+	Dir     string    `json:"dir"`
+	Log     string    `json:"log"` // file the output is written to, unique per job
+	Pid     int       `json:"pid"`
+	Started time.Time `json:"started"`
+}
+
+// jobsFile holds the detached jobs, oldest first.
+const jobsFile = "jobs.json"
+
+// GetJobs loads the detached jobs, oldest first.
+func (s *Store) GetJobs() ([]Job, error) {
+	var jobs []Job
+	if err := s.ReadJSON(jobsFile, &jobs); err != nil {
+		if err == ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// AddJobs records jobs left running.
+func (s *Store) AddJobs(jobs ...Job) error {
+	existing, err := s.GetJobs()
+	if err != nil {
+		return err
+	}
+	return s.WriteJSON(jobsFile, append(existing, jobs...))
+}
+
+// RemoveJob forgets the job writing to log, and deletes the log if it is
+// one of gdev's.
+func (s *Store) RemoveJob(log string) error {
+	jobs, err := s.GetJobs()
+	if err != nil {
+		return err
+	}
+
+	kept := jobs[:0]
+	for _, j := range jobs {
+		if j.Log != log {
+			kept = append(kept, j)
+		}
+	}
+	if err := s.WriteJSON(jobsFile, kept); err != nil {
+		return err
+	}
+
+	logs, err := s.SubDir("logs")
+	if err != nil {
+		return err
+	}
+	if filepath.Dir(log) != logs.path {
+		return nil
+	}
+	if err := logs.Delete(filepath.Base(log)); err != nil && err != ErrNotFound {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package store
+
+import (
+	"os"
+	"time"
+)
+
+// CreateLog creates a file in ~/.gdev/logs for the output of a command.
+// The file is named after the time and name, e.g. 20240102-150405-commit-123.log.
+func (s *Store) CreateLog(name string) (*os.File, error) {
+	if s.ReadOnly() {
+		return nil, ErrReadOnly
+	}
+	logs, err := s.SubDir("logs")
+	if err != nil {
+		return nil, err
+	}
+	pattern := time.Now().Format("20060102-150405") + "-" + unsafeFileChars.ReplaceAllString(name, "_") + "-*.log"
+	return os.CreateTemp(logs.path, pattern)
+}
@@ -0,0 +1,18 @@
+package store
+
+import "errors"
+
+// ErrReadOnly is returned by writes refused in read-only mode.
+var ErrReadOnly = errors.New("gdev is in read-only mode")
+
+// SetReadOnly enables or disables read-only mode. While enabled, every
+// write and delete in the store, gdev's own files included, returns
+// ErrReadOnly, and gdev runs no commands that change a repository.
+func (s *Store) SetReadOnly(enabled bool) {
+	s.ro.Store(enabled)
+}
+
+// ReadOnly reports whether read-only mode is enabled.
+func (s *Store) ReadOnly() bool {
+	return s.ro.Load()
+}
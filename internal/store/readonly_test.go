@@ -0,0 +1,90 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ihatemodels/gdev/internal/todo"
+)
+
+func TestReadOnly_RefusesWrites(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	s, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	repo := "/src/project"
+	if err := s.SaveRepoState(&RepoState{Path: repo, Name: "project"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveTodos(&todo.TodoList{RepoPath: repo}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Write("settings.json", []byte("{}")); err != nil {
+		t.Fatal(err)
+	}
+	before := snapshot(t, s.Path())
+
+	s.SetReadOnly(true)
+	logs, err := s.SubDir("logs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writes := map[string]func() error{
+		"Write":  func() error { return s.Write("settings.json", []byte(`{"read_only": false}`)) },
+		"Append": func() error { return s.Append("settings.json", []byte("\n")) },
+		"Delete": func() error { return s.Delete("settings.json") },
+		"SubDir": func() error { return logs.Write("run.log", nil) },
+		"SaveTodos": func() error {
+			return s.SaveTodos(&todo.TodoList{RepoPath: repo, Todos: []todo.Todo{*todo.NewTodo("main", "x", "", nil)}})
+		},
+		"ForgetRepo": func() error { return s.ForgetRepo(repo) },
+		"Remove":     func() error { return s.Remove(filepath.Join(s.Path(), "settings.json")) },
+		"Cleanup": func() error {
+			_, err := s.Cleanup("repos", Policy{MaxSize: 1}, nil)
+			return err
+		},
+		"CreateLog": func() error {
+			_, err := s.CreateLog("commit")
+			return err
+		},
+	}
+	for name, write := range writes {
+		if err := write(); !errors.Is(err, ErrReadOnly) {
+			t.Errorf("%s() error = %v, want ErrReadOnly", name, err)
+		}
+	}
+
+	if after := snapshot(t, s.Path()); after != before {
+		t.Errorf("files changed in read-only mode:\nbefore %s\nafter  %s", before, after)
+	}
+	if _, err := s.TouchRepo(repo, "project"); err != nil {
+		t.Errorf("TouchRepo() error = %v, want the state as last saved", err)
+	}
+}
+
+// snapshot lists the files and directories under dir with their sizes.
+func snapshot(t *testing.T, dir string) string {
+	t.Helper()
+	var out strings.Builder
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&out, "%s %d; ", path[len(dir):], info.Size())
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out.String()
+}
@@ -3,6 +3,11 @@ package store
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -11,6 +16,70 @@ type RepoState struct {
 	Path         string    `json:"path"`
 	Name         string    `json:"name"`
 	LastOpenedAt time.Time `json:"last_opened_at"`
+
+	// Positions are the remembered list positions, keyed by view name.
+	Positions map[string]Position `json:"positions,omitempty"`
+
+	// TodoView is how the todo list was last sorted and filtered.
+	TodoView TodoView `json:"todo_view"`
+
+	// Trust is whether the user trusts the repository's own gdev config,
+	// TrustAllowed or TrustDenied; "" until they are asked.
+	Trust string `json:"trust,omitempty"`
+
+	// TrustHash is the hash of the config Trust answers for. Once the file
+	// changes the answer no longer holds.
+	TrustHash string `json:"trust_hash,omitempty"`
+
+	// ApprovedCommands are the command lines of the repository's tasks the
+	// user confirmed running; new ones are confirmed before they first run.
+	ApprovedCommands []string `json:"approved_commands,omitempty"`
+}
+
+// The answers to whether a repository is trusted.
+const (
+	TrustAllowed = "trusted"
+	TrustDenied  = "untrusted"
+)
+
+// Trusted reports whether the user trusts the repository's own gdev config
+// with the given hash.
+func (r *RepoState) Trusted(hash string) bool {
+	return r != nil && r.Trust == TrustAllowed && r.TrustHash == hash
+}
+
+// TrustAnswered reports whether the user answered if they trust the
+// repository's own gdev config with the given hash.
+func (r *RepoState) TrustAnswered(hash string) bool {
+	return r != nil && r.Trust != "" && r.TrustHash == hash
+}
+
+// Approved reports whether the user confirmed running command, exactly as
+// written, in the repository.
+func (r *RepoState) Approved(command string) bool {
+	return r != nil && slices.Contains(r.ApprovedCommands, command)
+}
+
+// Approve records that the user confirmed running command. Save the state
+// to remember it.
+func (r *RepoState) Approve(command string) {
+	if !r.Approved(command) {
+		r.ApprovedCommands = append(r.ApprovedCommands, command)
+	}
+}
+
+// TodoView is a sort order and filters of the todo list. Empty fields are
+// the defaults: creation order, and every todo.
+type TodoView struct {
+	Sort    string   `json:"sort,omitempty"`
+	Filters []string `json:"filters,omitempty"`
+	Status  string   `json:"status,omitempty"` // the statuses shown
+}
+
+// Position is a cursor and scroll offset in a list view.
+type Position struct {
+	Cursor int `json:"cursor"`
+	Scroll int `json:"scroll"`
 }
 
 // repoID generates a unique ID for a repo based on its path.
@@ -58,8 +127,66 @@ func (s *Store) TouchRepo(repoPath, repoName string) (*RepoState, error) {
 	}
 
 	state.LastOpenedAt = time.Now()
-	if err := s.SaveRepoState(state); err != nil {
+	// In read-only mode the repository is opened as it was last left
+	if err := s.SaveRepoState(state); err != nil && err != ErrReadOnly {
 		return nil, err
 	}
 	return state, nil
 }
+
+// ListRepos loads the state of every repository gdev has been opened in,
+// most recently opened first.
+func (s *Store) ListRepos() ([]RepoState, error) {
+	repos, err := s.SubDir("repos")
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(repos.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var states []RepoState
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		var state RepoState
+		// Skip unreadable files; the storage check reports them
+		if err := repos.ReadJSON(e.Name(), &state); err != nil || state.Path == "" {
+			continue
+		}
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].LastOpenedAt.After(states[j].LastOpenedAt)
+	})
+	return states, nil
+}
+
+// ForgetRepo deletes everything gdev keeps for a repository: its state and
+// remembered positions, todo list, audit log and command history.
+func (s *Store) ForgetRepo(repoPath string) error {
+	id := repoID(repoPath)
+	for _, name := range []string{
+		filepath.Join("repos", id+".json"),
+		filepath.Join("todos", todoRepoID(repoPath)+".json"),
+		filepath.Join("audit", id+".jsonl"),
+		filepath.Join("commands", id+".json"),
+	} {
+		if err := s.Delete(name); err != nil && err != ErrNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// InstanceSocket returns the socket the gdev running in a repository
+// listens on, see package instance.
+func (s *Store) InstanceSocket(repoPath string) (string, error) {
+	instances, err := s.SubDir("instances")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(instances.path, repoID(repoPath)+".sock"), nil
+}
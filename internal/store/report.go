@@ -0,0 +1,176 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Usage is the space a category of files takes in the store. Categories
+// are the subdirectories of ~/.gdev; files directly in it are "config".
+type Usage struct {
+	Category string
+	Files    int
+	Bytes    int64
+}
+
+// Problem is a file in the store and what is wrong with it.
+type Problem struct {
+	Path   string
+	Reason string
+}
+
+// Report describes the contents of the store.
+type Report struct {
+	Usage []Usage // largest category first
+
+	// Unreadable are JSON files that don't parse
+	Unreadable []Problem
+
+	// Orphaned are todo lists, repository state, audit logs and command
+	// histories of repositories that no longer exist
+	Orphaned []Problem
+}
+
+// Total returns the number of files and bytes in the store.
+func (r Report) Total() (files int, bytes int64) {
+	for _, u := range r.Usage {
+		files += u.Files
+		bytes += u.Bytes
+	}
+	return files, bytes
+}
+
+// orphanDirs are the directories holding a file per repository, with the
+// function reading the repository path from one of their files.
+var orphanDirs = map[string]func(data []byte) string{
+	"todos": func(data []byte) string {
+		var list struct {
+			RepoPath string `json:"repo_path"`
+		}
+		json.Unmarshal(data, &list)
+		return list.RepoPath
+	},
+	"repos": func(data []byte) string {
+		var state RepoState
+		json.Unmarshal(data, &state)
+		return state.Path
+	},
+	"commands": func(data []byte) string {
+		var list commandList
+		json.Unmarshal(data, &list)
+		return list.RepoPath
+	},
+	"audit": func(data []byte) string {
+		line, _, _ := bytes.Cut(data, []byte("\n"))
+		var e AuditEntry
+		json.Unmarshal(line, &e)
+		return e.Repo
+	},
+}
+
+// Check measures the store and looks for unreadable and orphaned files.
+func (s *Store) Check() (Report, error) {
+	var r Report
+	usage := make(map[string]*Usage)
+
+	err := filepath.WalkDir(s.path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(s.path, path)
+		category, name, nested := strings.Cut(rel, string(filepath.Separator))
+		if !nested {
+			category, name = "config", rel
+		}
+		u, ok := usage[category]
+		if !ok {
+			u = &Usage{Category: category}
+			usage[category] = u
+		}
+		u.Files++
+		u.Bytes += info.Size()
+
+		if !strings.HasSuffix(name, ".json") && !strings.HasSuffix(name, ".jsonl") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			r.Unreadable = append(r.Unreadable, Problem{Path: path, Reason: err.Error()})
+			return nil
+		}
+		if reason := checkJSON(name, data); reason != "" {
+			r.Unreadable = append(r.Unreadable, Problem{Path: path, Reason: reason})
+			return nil
+		}
+		if repoPath, ok := orphanDirs[category]; ok && nested {
+			if repo := repoPath(data); repo != "" {
+				if _, err := os.Stat(repo); errors.Is(err, fs.ErrNotExist) {
+					r.Orphaned = append(r.Orphaned, Problem{Path: path, Reason: repo + " no longer exists"})
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return r, err
+	}
+
+	for _, u := range usage {
+		r.Usage = append(r.Usage, *u)
+	}
+	sort.Slice(r.Usage, func(i, j int) bool {
+		return r.Usage[i].Bytes > r.Usage[j].Bytes
+	})
+	return r, nil
+}
+
+// checkJSON returns why the JSON or JSON Lines file name can't be read,
+// or "" if it can.
+func checkJSON(name string, data []byte) string {
+	if strings.HasSuffix(name, ".json") {
+		if !json.Valid(data) {
+			return "invalid JSON"
+		}
+		return ""
+	}
+
+	bad := 0
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) > 0 && !json.Valid(scanner.Bytes()) {
+			bad++
+		}
+	}
+	if bad > 0 {
+		return fmt.Sprintf("%d invalid lines", bad)
+	}
+	return ""
+}
+
+// Remove deletes files by their full path, which must be in the store.
+func (s *Store) Remove(paths ...string) error {
+	for _, path := range paths {
+		rel, err := filepath.Rel(s.path, path)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			return fmt.Errorf("%s is not in %s", path, s.path)
+		}
+		if err := s.Delete(rel); err != nil && err != ErrNotFound {
+			return err
+		}
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 )
 
 const DirName = ".gdev"
@@ -13,6 +14,8 @@ var ErrNotFound = errors.New("not found")
 
 type Store struct {
 	path string
+	dry  *dryRun
+	ro   *atomic.Bool // read-only mode, shared like dry
 }
 
 // New creates a new Store instance in ~/.gdev,
@@ -25,6 +28,8 @@ func New() (*Store, error) {
 
 	s := &Store{
 		path: filepath.Join(home, DirName),
+		dry:  &dryRun{},
+		ro:   &atomic.Bool{},
 	}
 
 	if err := s.init(); err != nil {
@@ -47,9 +52,38 @@ func (s *Store) Path() string {
 // Write writes raw bytes to a file in the ~/.gdev directory.
 func (s *Store) Write(name string, data []byte) error {
 	filePath := filepath.Join(s.path, name)
+	if s.ReadOnly() {
+		return ErrReadOnly
+	}
+	if s.DryRun() {
+		s.Plan("write %s (%d bytes)", filePath, len(data))
+		return nil
+	}
 	return os.WriteFile(filePath, data, 0644)
 }
 
+// Append appends raw bytes to a file in the ~/.gdev directory,
+// creating it if needed.
+func (s *Store) Append(name string, data []byte) error {
+	filePath := filepath.Join(s.path, name)
+	if s.ReadOnly() {
+		return ErrReadOnly
+	}
+	if s.DryRun() {
+		s.Plan("append to %s (%d bytes)", filePath, len(data))
+		return nil
+	}
+	f, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
 // Read reads raw bytes from a file in the ~/.gdev directory.
 func (s *Store) Read(name string) ([]byte, error) {
 	filePath := filepath.Join(s.path, name)
@@ -81,6 +115,16 @@ func (s *Store) ReadJSON(name string, v any) error {
 // Delete removes a file from the ~/.gdev directory.
 func (s *Store) Delete(name string) error {
 	filePath := filepath.Join(s.path, name)
+	if s.ReadOnly() {
+		return ErrReadOnly
+	}
+	if s.DryRun() {
+		if !s.Exists(name) {
+			return ErrNotFound
+		}
+		s.Plan("delete %s", filePath)
+		return nil
+	}
 	err := os.Remove(filePath)
 	if errors.Is(err, os.ErrNotExist) {
 		return ErrNotFound
@@ -111,10 +155,16 @@ func (s *Store) List() ([]string, error) {
 	return files, nil
 }
 
-// SubDir returns a new Store scoped to a subdirectory within ~/.gdev.
+// SubDir returns a new Store scoped to a subdirectory within ~/.gdev,
+// creating it unless in read-only mode.
 func (s *Store) SubDir(name string) (*Store, error) {
 	sub := &Store{
 		path: filepath.Join(s.path, name),
+		dry:  s.dry,
+		ro:   s.ro,
+	}
+	if s.ReadOnly() {
+		return sub, nil
 	}
 	if err := sub.init(); err != nil {
 		return nil, err
@@ -0,0 +1,99 @@
+package store
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// The kinds of timeline events.
+const (
+	EventCommand = "command" // a mutating command, from the audit log
+	EventRun     = "run"     // a command the terminal ran, from the command history
+	EventOutput  = "output"  // saved command output
+	EventTodo    = "todo"    // todo activity
+)
+
+// Event is something done in a repository, as the timeline lists it.
+type Event struct {
+	Time   time.Time
+	Repo   string // path of the repository, or the directory of saved output outside any
+	Kind   string // one of the Event kinds
+	Text   string
+	Failed bool // a command that failed
+}
+
+// Timeline gathers what was done in every repository gdev has been opened
+// in into one list, newest first: the audited commands, the other commands
+// the terminal ran, the saved output and the activity of todos. Commands
+// run again are only listed the last time, as the history keeps them.
+func (s *Store) Timeline() ([]Event, error) {
+	repos, err := s.ListRepos()
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	for _, r := range repos {
+		audit, err := s.GetAudit(r.Path)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range audit {
+			events = append(events, Event{Time: e.Time, Repo: r.Path, Kind: EventCommand, Text: e.Command, Failed: e.ExitCode != 0})
+		}
+
+		commands, err := s.GetCommands(r.Path)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range commands {
+			// Mutating ones are in the audit log, with how they exited
+			if !c.Mutating {
+				events = append(events, Event{Time: c.Ran, Repo: r.Path, Kind: EventRun, Text: strings.Join(c.Args, " ")})
+			}
+		}
+
+		list, err := s.GetTodos(r.Path)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range list.Todos {
+			events = append(events, Event{Time: t.CreatedAt, Repo: r.Path, Kind: EventTodo, Text: "Created " + t.Name})
+			for _, a := range t.Activity {
+				events = append(events, Event{Time: a.At, Repo: r.Path, Kind: EventTodo, Text: t.Name + ": " + a.Title})
+			}
+			if t.UpdatedAt.Sub(t.CreatedAt) > time.Minute {
+				events = append(events, Event{Time: t.UpdatedAt, Repo: r.Path, Kind: EventTodo, Text: "Last updated " + t.Name + " (" + t.Status.String() + ")"})
+			}
+		}
+	}
+
+	runs, err := s.GetHistory()
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range runs {
+		events = append(events, Event{Time: r.Saved, Repo: repoOf(repos, r.Dir), Kind: EventOutput, Text: r.Title + ": " + r.Command, Failed: r.Err != ""})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.After(events[j].Time) })
+	return events, nil
+}
+
+// repoOf returns the repository of repos dir is in, the deepest if they
+// nest, or dir itself if it is in none.
+func repoOf(repos []RepoState, dir string) string {
+	found := dir
+	for _, r := range repos {
+		rel, err := filepath.Rel(r.Path, dir)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if found == dir || len(r.Path) > len(found) {
+			found = r.Path
+		}
+	}
+	return found
+}
@@ -3,6 +3,8 @@ package store
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"path/filepath"
+	"time"
 
 	"github.com/ihatemodels/gdev/internal/todo"
 )
@@ -90,3 +92,22 @@ func (s *Store) DeleteTodo(repoPath string, todoID string) error {
 
 	return ErrNotFound
 }
+
+// ExportTodos writes a copy of a repository's todo list to ~/.gdev/exports
+// and returns its path.
+func (s *Store) ExportTodos(repoPath string) (string, error) {
+	list, err := s.GetTodos(repoPath)
+	if err != nil {
+		return "", err
+	}
+	exports, err := s.SubDir("exports")
+	if err != nil {
+		return "", err
+	}
+
+	name := "todos-" + unsafeFileChars.ReplaceAllString(filepath.Base(repoPath), "_") + "-" + time.Now().Format("20060102-150405") + ".json"
+	if err := exports.WriteJSON(name, list); err != nil {
+		return "", err
+	}
+	return filepath.Join(exports.path, name), nil
+}
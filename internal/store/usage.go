@@ -0,0 +1,66 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// UsageEntry records what a request to an AI provider used.
+type UsageEntry struct {
+	Time     time.Time `json:"time"`
+	Provider string    `json:"provider"` // backend, e.g. "claude" or "ollama"
+	Model    string    `json:"model,omitempty"`
+	Tokens   int       `json:"tokens"`
+	Cost     float64   `json:"cost"` // estimated, in USD
+}
+
+// usageFile returns the name of the usage log of t's month, as the logs
+// are kept one per month.
+func usageFile(t time.Time) string {
+	return t.Local().Format("2006-01") + ".jsonl"
+}
+
+// AppendUsage adds an entry to the usage log of its month.
+func (s *Store) AppendUsage(e UsageEntry) error {
+	usage, err := s.SubDir("usage")
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return usage.Append(usageFile(e.Time), append(data, '\n'))
+}
+
+// MonthUsage loads the usage log of t's month, oldest entry first.
+func (s *Store) MonthUsage(t time.Time) ([]UsageEntry, error) {
+	usage, err := s.SubDir("usage")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := usage.Read(usageFile(t))
+	if err == ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []UsageEntry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		var e UsageEntry
+		// Skip lines torn by an interrupted write
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
@@ -0,0 +1,137 @@
+// Package timesheet buckets the timeline of what was done into the time
+// spent per repository and day, for timesheet and invoicing tools.
+package timesheet
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ihatemodels/gdev/internal/store"
+)
+
+// Options are how events are grouped into sessions of work and how their
+// time is counted.
+type Options struct {
+	Gap        time.Duration // longest break between two events of a session
+	MinSession time.Duration // least a session counts for, so a single event isn't worth nothing
+	Rounding   time.Duration // each entry is rounded up to a multiple of it, 0 for whole minutes
+}
+
+// Entry is the time spent in a repository on a day.
+type Entry struct {
+	Date     string // local date, as 2006-01-02
+	Repo     string // path of the repository
+	Minutes  int
+	Sessions int
+	Events   int
+}
+
+// Hours returns the time of the entry in hours.
+func (e Entry) Hours() float64 {
+	return float64(e.Minutes) / 60
+}
+
+// Build groups the events of each repository into sessions, events at
+// most Gap apart, and adds up the sessions of each repository and day. A
+// session lasts from its first event to its last, at least MinSession, and
+// counts for the day it started on. Entries are by date, then repository.
+func Build(events []store.Event, o Options) []Entry {
+	byRepo := make(map[string][]time.Time)
+	for _, e := range events {
+		byRepo[e.Repo] = append(byRepo[e.Repo], e.Time)
+	}
+
+	type key struct{ date, repo string }
+	spent := make(map[key]time.Duration)
+	entries := make(map[key]*Entry)
+	add := func(repo string, start, end time.Time, n int) {
+		k := key{start.Local().Format(time.DateOnly), repo}
+		e, ok := entries[k]
+		if !ok {
+			e = &Entry{Date: k.date, Repo: repo}
+			entries[k] = e
+		}
+		e.Sessions++
+		e.Events += n
+		spent[k] += max(end.Sub(start), o.MinSession)
+	}
+	for repo, times := range byRepo {
+		sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+		start, n := 0, 1
+		for i := 1; i <= len(times); i++ {
+			if i < len(times) && times[i].Sub(times[i-1]) <= o.Gap {
+				n++
+				continue
+			}
+			add(repo, times[start], times[i-1], n)
+			start, n = i, 1
+		}
+	}
+
+	list := make([]Entry, 0, len(entries))
+	for k, e := range entries {
+		e.Minutes = minutes(spent[k], o.Rounding)
+		list = append(list, *e)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Date != list[j].Date {
+			return list[i].Date < list[j].Date
+		}
+		return list[i].Repo < list[j].Repo
+	})
+	return list
+}
+
+// minutes returns d in whole minutes, rounded up to a multiple of
+// rounding if it is set, or to the nearest minute.
+func minutes(d, rounding time.Duration) int {
+	if rounding > 0 {
+		d = (d + rounding - 1) / rounding * rounding
+	}
+	return int(d.Round(time.Minute) / time.Minute)
+}
+
+// WriteCSV writes the entries as CSV with a header row: the date, the name
+// and path of the repository, the time in hours and minutes, and the
+// sessions and events it adds up.
+func WriteCSV(w io.Writer, entries []Entry) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"date", "repo", "path", "hours", "minutes", "sessions", "events"})
+	for _, e := range entries {
+		cw.Write([]string{
+			e.Date, filepath.Base(e.Repo), e.Repo,
+			fmt.Sprintf("%.2f", e.Hours()), strconv.Itoa(e.Minutes),
+			strconv.Itoa(e.Sessions), strconv.Itoa(e.Events),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the entries as a JSON array, with the fields of
+// WriteCSV's columns.
+func WriteJSON(w io.Writer, entries []Entry) error {
+	type row struct {
+		Date     string  `json:"date"`
+		Repo     string  `json:"repo"`
+		Path     string  `json:"path"`
+		Hours    float64 `json:"hours"`
+		Minutes  int     `json:"minutes"`
+		Sessions int     `json:"sessions"`
+		Events   int     `json:"events"`
+	}
+	rows := make([]row, 0, len(entries))
+	for _, e := range entries {
+		hours, _ := strconv.ParseFloat(fmt.Sprintf("%.2f", e.Hours()), 64)
+		rows = append(rows, row{e.Date, filepath.Base(e.Repo), e.Repo, hours, e.Minutes, e.Sessions, e.Events})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rows)
+}
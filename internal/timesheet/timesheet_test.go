@@ -0,0 +1,80 @@
+package timesheet
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ihatemodels/gdev/internal/store"
+)
+
+func TestBuild_Sessions(t *testing.T) {
+	day := time.Date(2026, 10, 13, 9, 0, 0, 0, time.Local)
+	at := func(minutes int) time.Time { return day.Add(time.Duration(minutes) * time.Minute) }
+	events := []store.Event{
+		// A session of 50 minutes, in any order
+		{Time: at(50), Repo: "/src/api"},
+		{Time: at(0), Repo: "/src/api"},
+		{Time: at(25), Repo: "/src/api"},
+		// A single event, counted as the minimum session
+		{Time: at(180), Repo: "/src/api"},
+		// Another repository, and another day
+		{Time: at(10), Repo: "/src/web"},
+		{Time: at(24 * 60), Repo: "/src/api"},
+	}
+
+	got := Build(events, Options{Gap: 30 * time.Minute, MinSession: 15 * time.Minute})
+	want := []Entry{
+		{Date: "2026-10-13", Repo: "/src/api", Minutes: 65, Sessions: 2, Events: 4},
+		{Date: "2026-10-13", Repo: "/src/web", Minutes: 15, Sessions: 1, Events: 1},
+		{Date: "2026-10-14", Repo: "/src/api", Minutes: 15, Sessions: 1, Events: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Build() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestMinutes_Rounding(t *testing.T) {
+	tests := []struct {
+		d, rounding time.Duration
+		want        int
+	}{
+		{65 * time.Minute, 15 * time.Minute, 75},
+		{60 * time.Minute, 15 * time.Minute, 60},
+		{61 * time.Minute, 0, 61},
+		{61*time.Minute + 40*time.Second, 0, 62},
+		{time.Second, 6 * time.Minute, 6},
+	}
+	for _, tt := range tests {
+		if got := minutes(tt.d, tt.rounding); got != tt.want {
+			t.Errorf("minutes(%v, %v) = %d, want %d", tt.d, tt.rounding, got, tt.want)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteCSV(&b, []Entry{{Date: "2026-10-13", Repo: "/src/api", Minutes: 75, Sessions: 2, Events: 4}}); err != nil {
+		t.Fatal(err)
+	}
+	want := "date,repo,path,hours,minutes,sessions,events\n2026-10-13,api,/src/api,1.25,75,2,4\n"
+	if b.String() != want {
+		t.Errorf("WriteCSV() = %q, want %q", b.String(), want)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteJSON(&b, []Entry{{Date: "2026-10-13", Repo: "/src/api", Minutes: 20, Sessions: 1, Events: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), `"hours": 0.33`) || !strings.Contains(b.String(), `"repo": "api"`) {
+		t.Errorf("WriteJSON() = %s", b.String())
+	}
+}
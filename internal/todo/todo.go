@@ -3,6 +3,8 @@ package todo
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -13,10 +15,122 @@ type Todo struct {
 	Name        string    `json:"name"`
 	Description string    `json:"description"` // supports markdown
 	Prompts     []string  `json:"prompts"`     // markdown prompts for Claude Code
+	Subtasks    []Subtask `json:"subtasks,omitempty"`
+	Model       string    `json:"model,omitempty"` // claude model the prompts run with, "" for claude's default
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+
+	Status   Status   `json:"status,omitempty"`
+	Priority Priority `json:"priority,omitempty"`
+
+	DueDate      *time.Time `json:"due_date,omitempty"`
+	SnoozedUntil *time.Time `json:"snoozed_until,omitempty"` // hidden from attention until this date
+
+	Activity   []Activity  `json:"activity,omitempty"`   // oldest first
+	Executions []Execution `json:"executions,omitempty"` // of its prompts, oldest first
+	Usage      *Usage      `json:"usage,omitempty"`      // of the runs of its prompts
+}
+
+// Status is how far along a todo is.
+type Status string
+
+const (
+	StatusOpen       Status = "" // not started, as are todos saved before statuses
+	StatusInProgress Status = "in_progress"
+	StatusDone       Status = "done"
+)
+
+// Statuses are the statuses in the order they are cycled through.
+var Statuses = []Status{StatusOpen, StatusInProgress, StatusDone}
+
+// String returns the status for display, e.g. "in progress".
+func (s Status) String() string {
+	switch s {
+	case StatusInProgress:
+		return "in progress"
+	case StatusDone:
+		return "done"
+	}
+	return "open"
+}
+
+// Next returns the status after s; done goes back to open.
+func (s Status) Next() Status {
+	i := slices.Index(Statuses, s)
+	return Statuses[(i+1)%len(Statuses)]
+}
+
+// Priority is how urgent a todo is.
+type Priority string
+
+const (
+	PriorityNone   Priority = "" // not set, as for todos saved before priorities
+	PriorityLow    Priority = "low"
+	PriorityMedium Priority = "medium"
+	PriorityHigh   Priority = "high"
+)
+
+// Priorities are the priorities from least to most urgent.
+var Priorities = []Priority{PriorityNone, PriorityLow, PriorityMedium, PriorityHigh}
+
+// String returns the priority for display, e.g. "high".
+func (p Priority) String() string {
+	if p == PriorityNone {
+		return "none"
+	}
+	return string(p)
+}
+
+// Rank orders the priorities, higher for more urgent; unknown ones rank
+// with none.
+func (p Priority) Rank() int {
+	return max(slices.Index(Priorities, p), 0)
+}
+
+// ParsePriority returns the priority named s, as String returns it.
+func ParsePriority(s string) Priority {
+	if p := Priority(s); slices.Contains(Priorities, p) {
+		return p
+	}
+	return PriorityNone
+}
+
+// Usage is what the runs of a todo's prompts used, summed over all of them.
+type Usage struct {
+	Runs   int     `json:"runs"`
+	Tokens int     `json:"tokens"`
+	Cost   float64 `json:"cost"` // estimated, in USD
 }
 
+// Subtask is an item of a todo's checklist.
+type Subtask struct {
+	Text string `json:"text"`
+	Done bool   `json:"done,omitempty"`
+}
+
+// Activity is an entry of a todo's activity log, such as the summary of a
+// run of its prompts.
+type Activity struct {
+	At    time.Time `json:"at"`
+	Title string    `json:"title"`
+	Text  string    `json:"text"` // markdown
+}
+
+// Execution is a run of one of a todo's prompts through claude.
+type Execution struct {
+	Prompt string    `json:"prompt"` // as it was run
+	At     time.Time `json:"at"`     // when it ended
+	Status string    `json:"status"` // how it ended, e.g. "done" or "failed"
+	Output string    `json:"output,omitempty"`
+}
+
+// Limits on what is kept of executions, so runs don't grow the todo list
+// without bound.
+const (
+	MaxExecutions      = 100
+	MaxExecutionOutput = 8 << 10 // bytes at the end of the output
+)
+
 // TodoList holds all TODOs for a repository.
 type TodoList struct {
 	RepoPath string `json:"repo_path"`
@@ -64,6 +178,109 @@ func (t *Todo) RemovePrompt(index int) {
 	t.Update()
 }
 
+// SetSubtasks replaces the checklist with an item for each of texts that
+// isn't blank. Items already on it keep whether they are done.
+func (t *Todo) SetSubtasks(texts []string) {
+	done := make(map[string]bool)
+	for _, s := range t.Subtasks {
+		done[s.Text] = done[s.Text] || s.Done
+	}
+	var subtasks []Subtask
+	for _, text := range texts {
+		if text = strings.TrimSpace(text); text != "" {
+			subtasks = append(subtasks, Subtask{Text: text, Done: done[text]})
+		}
+	}
+	t.Subtasks = subtasks
+}
+
+// ToggleSubtask marks the subtask at index done, or not done if it was.
+func (t *Todo) ToggleSubtask(index int) {
+	if index < 0 || index >= len(t.Subtasks) {
+		return
+	}
+	t.Subtasks = slices.Clone(t.Subtasks)
+	t.Subtasks[index].Done = !t.Subtasks[index].Done
+	t.Update()
+}
+
+// Progress returns how many subtasks are done, of how many.
+func (t *Todo) Progress() (done, total int) {
+	for _, s := range t.Subtasks {
+		if s.Done {
+			done++
+		}
+	}
+	return done, len(t.Subtasks)
+}
+
+// SetStatus sets the status of the todo.
+func (t *Todo) SetStatus(s Status) {
+	t.Status = s
+	t.Update()
+}
+
+// AddActivity adds an entry to the activity log.
+func (t *Todo) AddActivity(title, text string) {
+	t.Activity = append(t.Activity, Activity{At: time.Now(), Title: title, Text: text})
+	t.Update()
+}
+
+// AddExecution records a run of prompt that ended with status, keeping the
+// end of its output. The oldest are forgotten beyond MaxExecutions.
+func (t *Todo) AddExecution(prompt, status, output string) {
+	if len(output) > MaxExecutionOutput {
+		output = output[len(output)-MaxExecutionOutput:]
+		// Start at a whole line
+		if i := strings.IndexByte(output, '\n'); i >= 0 {
+			output = output[i+1:]
+		}
+	}
+	t.Executions = append(t.Executions, Execution{Prompt: prompt, At: time.Now(), Status: status, Output: output})
+	if len(t.Executions) > MaxExecutions {
+		t.Executions = t.Executions[len(t.Executions)-MaxExecutions:]
+	}
+	t.Update()
+}
+
+// LastExecution returns the last run of prompt, nil if it never ran as it
+// is now written, and how many times it ran.
+func (t *Todo) LastExecution(prompt string) (*Execution, int) {
+	var last *Execution
+	n := 0
+	for i := range t.Executions {
+		if t.Executions[i].Prompt == prompt {
+			last = &t.Executions[i]
+			n++
+		}
+	}
+	return last, n
+}
+
+// AddUsage records the tokens and estimated cost of a run of the prompts.
+func (t *Todo) AddUsage(tokens int, cost float64) {
+	if t.Usage == nil {
+		t.Usage = &Usage{}
+	}
+	t.Usage.Runs++
+	t.Usage.Tokens += tokens
+	t.Usage.Cost += cost
+}
+
+// IsOverdue reports whether the due date is before the start of now's day.
+func (t *Todo) IsOverdue(now time.Time) bool {
+	if t.DueDate == nil {
+		return false
+	}
+	y, m, d := now.Date()
+	return t.DueDate.Before(time.Date(y, m, d, 0, 0, 0, 0, now.Location()))
+}
+
+// IsSnoozed reports whether the todo is snoozed at now.
+func (t *Todo) IsSnoozed(now time.Time) bool {
+	return t.SnoozedUntil != nil && now.Before(*t.SnoozedUntil)
+}
+
 // Validate checks that required fields are set.
 func (t *Todo) Validate() bool {
 	return t.Name != "" && t.Branch != ""
@@ -8,10 +8,21 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/ihatemodels/gdev/internal/claude"
 	"github.com/ihatemodels/gdev/internal/config"
 	"github.com/ihatemodels/gdev/internal/git"
+	"github.com/ihatemodels/gdev/internal/scaffold"
+	"github.com/ihatemodels/gdev/internal/skill"
 	"github.com/ihatemodels/gdev/internal/store"
 	"github.com/ihatemodels/gdev/internal/ui/commit"
+	"github.com/ihatemodels/gdev/internal/ui/confirm"
+	"github.com/ihatemodels/gdev/internal/ui/failure"
+	"github.com/ihatemodels/gdev/internal/ui/filefinder"
+	"github.com/ihatemodels/gdev/internal/ui/form"
+	"github.com/ihatemodels/gdev/internal/ui/nav"
+	"github.com/ihatemodels/gdev/internal/ui/pr"
+	"github.com/ihatemodels/gdev/internal/ui/sessions"
+	"github.com/ihatemodels/gdev/internal/ui/settings"
 	"github.com/ihatemodels/gdev/internal/ui/styles"
 	"github.com/ihatemodels/gdev/internal/ui/terminal"
 	"github.com/ihatemodels/gdev/internal/ui/todo"
@@ -33,6 +44,25 @@ const (
 	TodosView
 	TerminalTestView
 	CommitView
+	PRsView
+	SessionsView
+	SettingsView
+	AuditView
+	JobsView
+	JobLogView
+	HistoryView
+	RunLogView
+	BranchesView
+	ReflogView
+	GitCommandView // a git command run from the Branches, Reflog or Patches view
+	PatchesView
+	NewProjectView
+	TimelineView
+	SkillsView
+	SkillRunView // a skill running in the terminal modal
+	TasksView
+	TaskRunView // a task of the repository's own config running in the terminal modal
+	FilesView   // the file finder picking a file to open in the editor
 )
 
 // RepoInfo holds information about the current git repository.
@@ -44,6 +74,15 @@ type RepoInfo struct {
 	HasChanges bool
 }
 
+// Refresh re-reads the branch and sync status of the repository.
+func (ri *RepoInfo) Refresh() {
+	if err := ri.Repo.Refresh(); err != nil {
+		return
+	}
+	ri.Ahead, ri.Behind, _ = ri.Repo.GetAheadBehind()
+	ri.HasChanges, _ = ri.Repo.HasLocalChanges()
+}
+
 // Model is the main application model.
 type Model struct {
 	store    *store.Store
@@ -55,20 +94,154 @@ type Model struct {
 	width    int
 	height   int
 
-	currentView View
-	todoModel   *todo.Model
-	commitModel *commit.Model
-	terminal    terminal.Model
+	views         nav.Stack[View] // open views, MainMenuView at the bottom
+	todoModel     *todo.Model
+	commitModel   *commit.Model
+	prModel       *pr.Model
+	sessionsModel *sessions.Model
+	settingsModel *settings.Model
+	terminal      terminal.Model
+
+	// Remembered list positions, keyed by view
+	positions map[string]store.Position
+
+	// Claude usage across the repository's sessions, nil until loaded
+	usage *claude.Usage
+
+	// Mutating commands gdev ran in the repository, oldest first
+	audit []store.AuditEntry
+
+	// Commands left running by earlier runs, and the one selected
+	jobs      []store.Job
+	jobCursor int
+
+	// Saved command output, newest first, and the run selected
+	history       []store.Run
+	historyCursor int
+
+	// Local branches and the one selected, the form editing its notes, and
+	// the confirmation before a push that may replace remote commits
+	branches       []branchNote
+	branchCursor   int
+	noteForm       form.Model
+	editingNote    bool
+	branchErr      string
+	plannedPush    git.ForcePush
+	pushConfirm    confirm.Model
+	confirmingPush bool
+
+	// Commits of a branch to export as patches and those picked, and the
+	// form asking where to export them or which patches to apply
+	patchBranch     string
+	commits         []git.Commit
+	commitCursor    int
+	picked          map[string]bool
+	patchForm       form.Model
+	namingPatchDir  bool
+	applyingPatches bool
+
+	// Templates of new projects and the one selected, and the form naming
+	// the project
+	templates      []scaffold.Template
+	templateCursor int
+	projectForm    form.Model
+	namingProject  bool
+	projectErr     string
+
+	// Claude commands that run as skills and the one selected, and the
+	// form filling in its parameters
+	skills       []skill.Skill
+	skillCursor  int
+	skillForm    form.Model
+	fillingSkill bool
+
+	// Asking before running a skill past claude's budget, the skill and
+	// its parameters, and the claude session of the skill running
+	skillConfirm    confirm.Model
+	confirmingSkill bool
+	skillValues     map[string]string
+	skillSession    string
+
+	// Tasks of the repository's own config, the hash of the file they were
+	// read from and the one selected, the confirmation before a new command
+	// first runs, and why the selected one can't run
+	tasks          []config.Task
+	taskHash       string
+	taskCursor     int
+	taskConfirm    confirm.Model
+	confirmingTask bool
+	taskErr        string
+
+	// Where HEAD has been and the entry selected, and the form naming a
+	// branch to rescue its commit
+	reflog       []git.ReflogEntry
+	reflogCursor int
+	rescueForm   form.Model
+	namingRescue bool
+	reflogErr    string
+
+	// Picking a file of the repository to open in the editor
+	fileFinder filefinder.Model
+
+	// What was done across repositories, and the repository, day and
+	// query the timeline is narrowed to
+	timeline          []store.Event
+	timelineScroll    int
+	timelineRepo      string    // "" for every repository
+	timelineDay       time.Time // midnight of the day shown, zero for every day
+	timelineQuery     string
+	timelineSearching bool
+
+	// The form asking where to export the timeline as a timesheet, and
+	// where the last export went
+	timesheetForm      form.Model
+	exportingTimesheet bool
+	timelineNote       string
+
+	// Error screen for a failed command, shown over any view
+	errScreen  failure.Model
+	showingErr bool
+
+	// Store writes skipped in dry-run mode, shown over any view
+	dryRunPlan    terminal.Model
+	showingDryRun bool
+
+	// Update and View timings, only in debug mode
+	diag *diagnostics
+
+	// Asked before reading the repository's own config, see askTrust
+	trustPrompt confirm.Model
+	askingTrust bool
+	trustHash   string
+
+	// Quitting with commands still running: what to do with them, and
+	// whether gdev waits for them or leaves them running
+	quitChoice     quitChoice
+	quitting       bool
+	waitingForJobs bool
+	detached       bool
 }
 
+// Message types
+type (
+	// usageLoadedMsg carries the Claude usage of the repository.
+	usageLoadedMsg struct {
+		Usage claude.Usage
+	}
+
+	// auditLoadedMsg carries the audit log of the repository.
+	auditLoadedMsg struct {
+		Entries []store.AuditEntry
+	}
+)
+
 // New creates a new application model.
 func New(s *store.Store, cfg *config.Config, ri *RepoInfo, version string, startView View) Model {
 	m := Model{
-		store:       s,
-		config:      cfg,
-		repoInfo:    ri,
-		version:     version,
-		currentView: startView,
+		store:    s,
+		config:   cfg,
+		repoInfo: ri,
+		version:  version,
 		choices: []string{
 			"󰘬  Branches",
 			"  Pull Requests",
@@ -76,44 +249,184 @@ func New(s *store.Store, cfg *config.Config, ri *RepoInfo, version string, start
 			"  TODOs",
 			"  Smart Commit",
 			"  Terminal Test",
+			"  New Project",
+			"  Skills",
+			"  Tasks",
 			"  Settings",
 			"  Quit",
 		},
 	}
 
+	m.views = nav.NewStack(MainMenuView)
+	if startView != MainMenuView {
+		m.views.Push(startView)
+	}
+
+	m.positions = m.loadPositions()
+	m.cursor = min(m.positions[posMenu].Cursor, len(m.choices)-1)
+
 	if ri != nil && ri.Repo != nil {
 		tm := todo.New(s, cfg, ri.Repo.Root, ri.Repo.Branch)
+		if ri.State != nil {
+			tm.SetView(ri.State.TodoView)
+		}
+		tm.Cursor = m.positions[posTodos].Cursor
+		tm.ListScroll = m.positions[posTodos].Scroll
 		m.todoModel = &tm
 	}
+	if cfg.Debug() {
+		m.diag = &diagnostics{}
+	}
 
 	return m
 }
 
 // Init implements tea.Model.
 func (m Model) Init() tea.Cmd {
-	if m.currentView == TodosView && m.todoModel != nil {
-		return m.todoModel.Init()
+	cmds := []tea.Cmd{m.loadUsage, m.loadAudit, m.loadJobs}
+	if m.views.Is(TodosView) && m.todoModel != nil {
+		cmds = append(cmds, m.todoModel.Init())
 	}
-	return nil
+	return tea.Batch(cmds...)
+}
+
+// loadUsage sums the token usage of the repository's Claude sessions.
+func (m Model) loadUsage() tea.Msg {
+	if m.repoInfo == nil || m.repoInfo.Repo == nil {
+		return nil
+	}
+	u, err := claude.RepoUsage(m.repoInfo.Repo.Root)
+	if err != nil {
+		return nil
+	}
+	return usageLoadedMsg{Usage: u}
+}
+
+// loadAudit reads the audit log of the repository.
+func (m Model) loadAudit() tea.Msg {
+	if m.repoInfo == nil || m.repoInfo.Repo == nil {
+		return nil
+	}
+	entries, err := m.store.GetAudit(m.repoInfo.Repo.Root)
+	if err != nil {
+		return nil
+	}
+	return auditLoadedMsg{Entries: entries}
 }
 
 // Update implements tea.Model.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	// Handle terminal test view
-	if m.currentView == TerminalTestView {
+	if m.diag == nil {
+		return m.update(msg)
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok && config.Matches(msg.String(), m.config.Keys().Global.Diagnostics) {
+		m.diag.visible = !m.diag.visible
+		return m, nil
+	}
+	start := time.Now()
+	m.diag.received(start)
+	model, cmd := m.update(msg)
+	m.diag.updates.add(time.Since(start))
+	return model, m.diag.track(cmd)
+}
+
+// update handles a message.
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Usage loads in the background and may arrive in any view
+	if msg, ok := msg.(usageLoadedMsg); ok {
+		m.usage = &msg.Usage
+		return m, nil
+	}
+	if msg, ok := msg.(auditLoadedMsg); ok {
+		m.audit = msg.Entries
+		return m, nil
+	}
+	if msg, ok := msg.(jobsLoadedMsg); ok {
+		m.jobs = msg.Jobs
+		return m, nil
+	}
+	if msg, ok := msg.(ForwardedMsg); ok {
+		return m.updateForwarded(msg.Request)
+	}
+
+	// File writes skipped by dry-run mode are reported with the next message
+	if msg, ok := msg.(tea.KeyMsg); ok && m.showingDryRun {
+		if m.dryRunPlan.ShouldClose(msg) {
+			m.showingDryRun = false
+			return m, nil
+		}
+		m.dryRunPlan, _ = m.dryRunPlan.Update(msg)
+		return m, nil
+	}
+	if msg, ok := msg.(tea.MouseMsg); ok && m.showingDryRun {
+		m.dryRunPlan, _ = m.dryRunPlan.Update(msg)
+		return m, nil
+	}
+	if plan := m.store.TakePlan(); len(plan) > 0 {
+		m.dryRunPlan = terminal.New(m.config, "Dry run: file changes")
+		m.dryRunPlan.SetSize(m.width, m.height)
+		m.dryRunPlan.Print(plan...)
+		m.showingDryRun = true
+	}
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.dryRunPlan.SetSize(msg.Width, msg.Height)
+	}
+
+	// Error boundary: a failed command in any view opens the error screen.
+	// The failure still reaches the active view so it can stop loading.
+	if msg, ok := msg.(failure.Msg); ok {
+		m.errScreen = failure.New(m.config, msg)
+		m.showingErr = true
+	}
+	if msg, ok := msg.(tea.KeyMsg); ok && m.showingErr {
+		var res failure.Result
+		m.errScreen, res = m.errScreen.Update(msg)
+		switch res {
+		case failure.Retry:
+			m.showingErr = false
+			return m, m.errScreen.Failure.Retry
+		case failure.Back:
+			m.showingErr = false
+		}
+		return m, nil
+	}
+
+	// Whether the repository is trusted is answered before anything else
+	if msg, ok := msg.(tea.KeyMsg); ok && m.askingTrust {
+		return m.updateTrust(msg)
+	}
+
+	// Asking what to do with running commands before quitting
+	if m.quitting {
+		switch msg.(type) {
+		case tea.KeyMsg, jobsTickMsg:
+			return m.updateQuit(msg)
+		}
+	}
+
+	// Handle terminal test, audit log, job output, saved output, git command, skill and task views
+	if m.views.Is(TerminalTestView) || m.views.Is(AuditView) || m.views.Is(JobLogView) || m.views.Is(RunLogView) || m.views.Is(GitCommandView) || m.views.Is(SkillRunView) || m.views.Is(TaskRunView) {
 		switch msg := msg.(type) {
 		case tea.WindowSizeMsg:
 			m.width = msg.Width
 			m.height = msg.Height
 			m.terminal.SetSize(msg.Width, msg.Height)
 			return m, nil
-		case terminal.TickMsg:
+		case terminal.TickMsg, tea.MouseMsg:
 			var cmd tea.Cmd
 			m.terminal, cmd = m.terminal.Update(msg)
 			return m, cmd
 		case tea.KeyMsg:
 			if m.terminal.ShouldClose(msg) {
-				m.currentView = MainMenuView
+				m.terminal.Cancel()
+				if m.views.Is(SkillRunView) {
+					m.recordSkillUsage()
+				}
+				m.views.Pop()
+				if m.views.Is(ReflogView) {
+					return m.reloadReflog()
+				}
 				return m, nil
 			}
 			var cmd tea.Cmd
@@ -123,9 +436,129 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	if m.currentView == TodosView {
+	if m.views.Is(JobsView) {
+		switch msg := msg.(type) {
+		case tea.WindowSizeMsg:
+			m.width = msg.Width
+			m.height = msg.Height
+		case tea.KeyMsg:
+			return m.updateJobs(msg)
+		}
+		return m, nil
+	}
+
+	if m.views.Is(HistoryView) {
+		switch msg := msg.(type) {
+		case tea.WindowSizeMsg:
+			m.width = msg.Width
+			m.height = msg.Height
+		case tea.KeyMsg:
+			return m.updateHistory(msg)
+		}
+		return m, nil
+	}
+
+	if m.views.Is(BranchesView) {
+		switch msg := msg.(type) {
+		case tea.WindowSizeMsg:
+			m.width = msg.Width
+			m.height = msg.Height
+		case noteSavedMsg:
+			setNote(m.branches, msg)
+		case tea.KeyMsg:
+			return m.updateBranches(msg)
+		}
+		return m, nil
+	}
+
+	if m.views.Is(ReflogView) {
+		switch msg := msg.(type) {
+		case tea.WindowSizeMsg:
+			m.width = msg.Width
+			m.height = msg.Height
+		case tea.KeyMsg:
+			return m.updateReflog(msg)
+		}
+		return m, nil
+	}
+
+	if m.views.Is(SkillsView) {
+		switch msg := msg.(type) {
+		case tea.WindowSizeMsg:
+			m.width = msg.Width
+			m.height = msg.Height
+		case tea.KeyMsg:
+			return m.updateSkills(msg)
+		}
+		return m, nil
+	}
+
+	if m.views.Is(TasksView) {
+		switch msg := msg.(type) {
+		case tea.WindowSizeMsg:
+			m.width = msg.Width
+			m.height = msg.Height
+		case tea.KeyMsg:
+			return m.updateTasks(msg)
+		}
+		return m, nil
+	}
+
+	if m.views.Is(TimelineView) {
+		switch msg := msg.(type) {
+		case tea.WindowSizeMsg:
+			m.width = msg.Width
+			m.height = msg.Height
+		case timesheetExportedMsg:
+			m.timelineNote = fmt.Sprintf("Exported %d rows to %s", msg.Entries, msg.Path)
+		case tea.KeyMsg:
+			return m.updateTimeline(msg)
+		}
+		return m, nil
+	}
+
+	if m.views.Is(FilesView) {
+		if msg, ok := msg.(tea.WindowSizeMsg); ok {
+			m.width = msg.Width
+			m.height = msg.Height
+			m.fileFinder.SetSize(msg.Width, msg.Height)
+			return m, nil
+		}
+		return m.updateFileFinder(msg)
+	}
+
+	if m.views.Is(PatchesView) {
+		switch msg := msg.(type) {
+		case tea.WindowSizeMsg:
+			m.width = msg.Width
+			m.height = msg.Height
+		case tea.KeyMsg:
+			return m.updatePatches(msg)
+		}
+		return m, nil
+	}
+
+	if m.views.Is(NewProjectView) {
+		switch msg := msg.(type) {
+		case tea.WindowSizeMsg:
+			m.width = msg.Width
+			m.height = msg.Height
+		case projectCreatedMsg:
+			return m.initProject(msg.Dir)
+		case tea.KeyMsg:
+			return m.updateNewProject(msg)
+		}
+		return m, nil
+	}
+
+	if m.views.Is(TodosView) {
 		if _, ok := msg.(todo.BackToMenuMsg); ok {
-			m.currentView = MainMenuView
+			m.savePosition(posTodos, m.todoModel.Cursor, m.todoModel.ListScroll)
+			m.views.Pop()
+			return m, nil
+		}
+		if vc, ok := msg.(todo.ViewChangedMsg); ok {
+			m.saveTodoView(vc.View)
 			return m, nil
 		}
 
@@ -141,10 +574,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	}
 
-	if m.currentView == CommitView && m.commitModel != nil {
+	if m.views.Is(CommitView) && m.commitModel != nil {
 		if _, ok := msg.(commit.BackToMenuMsg); ok {
-			m.currentView = MainMenuView
-			return m, nil
+			m.views.Pop()
+			return m, m.loadAudit
 		}
 
 		if wsm, ok := msg.(tea.WindowSizeMsg); ok {
@@ -159,6 +592,70 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	}
 
+	if m.views.Is(PRsView) && m.prModel != nil {
+		switch msg := msg.(type) {
+		case pr.BackToMenuMsg:
+			m.savePosition(posPRs, m.prModel.Cursor, m.prModel.ListScroll)
+			m.views.Pop()
+			return m, m.loadAudit
+		case pr.CheckedOutMsg:
+			m.savePosition(posPRs, m.prModel.Cursor, m.prModel.ListScroll)
+			m.views.Pop()
+			m.repoInfo.Refresh()
+			if m.todoModel != nil {
+				m.todoModel.Branch = m.repoInfo.Repo.Branch
+			}
+			return m, m.loadAudit
+		case tea.WindowSizeMsg:
+			m.width = msg.Width
+			m.height = msg.Height
+		}
+
+		updatedModel, cmd := m.prModel.Update(msg)
+		if pm, ok := updatedModel.(pr.Model); ok {
+			m.prModel = &pm
+		}
+		return m, cmd
+	}
+
+	if m.views.Is(SessionsView) && m.sessionsModel != nil {
+		if _, ok := msg.(sessions.BackToMenuMsg); ok {
+			m.savePosition(posSessions, m.sessionsModel.Cursor, m.sessionsModel.ListScroll)
+			m.views.Pop()
+			// Sessions may have been resumed, so the totals can be stale
+			return m, m.loadUsage
+		}
+
+		if wsm, ok := msg.(tea.WindowSizeMsg); ok {
+			m.width = wsm.Width
+			m.height = wsm.Height
+		}
+
+		updatedModel, cmd := m.sessionsModel.Update(msg)
+		if sm, ok := updatedModel.(sessions.Model); ok {
+			m.sessionsModel = &sm
+		}
+		return m, cmd
+	}
+
+	if m.views.Is(SettingsView) && m.settingsModel != nil {
+		if _, ok := msg.(settings.BackToMenuMsg); ok {
+			m.views.Pop()
+			return m, nil
+		}
+
+		if wsm, ok := msg.(tea.WindowSizeMsg); ok {
+			m.width = wsm.Width
+			m.height = wsm.Height
+		}
+
+		updatedModel, cmd := m.settingsModel.Update(msg)
+		if sm, ok := updatedModel.(settings.Model); ok {
+			m.settingsModel = &sm
+		}
+		return m, cmd
+	}
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -170,7 +667,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		switch {
 		case key == "ctrl+c" || config.MatchesAny(key, kb.Global.Quit, kb.Global.QuitAlt):
-			return m, tea.Quit
+			return m.quit()
 		case config.MatchesAny(key, kb.Global.MoveUp, kb.Global.MoveUpAlt):
 			if m.cursor > 0 {
 				m.cursor--
@@ -181,16 +678,72 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		case config.MatchesAny(key, kb.List.Select, " "):
 			return m.handleMenuSelection()
+		case config.Matches(key, kb.Global.DryRun):
+			m.config.SetDryRun(!m.config.DryRun())
+		case config.Matches(key, kb.Global.AuditLog):
+			if m.repoInfo != nil && m.repoInfo.Repo != nil {
+				m.terminal = terminal.New(m.config, "Audit log")
+				m.terminal.SetSize(m.width, m.height)
+				m.terminal.Print(m.auditLines()...)
+				m.views.Push(AuditView)
+			}
+		case config.Matches(key, kb.Global.Jobs):
+			if len(m.jobs) > 0 {
+				m.jobCursor = 0
+				m.views.Push(JobsView)
+			}
+		case config.Matches(key, kb.Global.History):
+			m.history = m.loadHistory()
+			if len(m.history) > 0 {
+				m.historyCursor = 0
+				m.views.Push(HistoryView)
+			}
+		case config.Matches(key, kb.Global.Reflog):
+			if m.repoInfo != nil && m.repoInfo.Repo != nil {
+				return m.openReflog()
+			}
+		case config.Matches(key, kb.Global.Timeline):
+			return m.openTimeline()
+		case config.Matches(key, kb.Global.FindFile):
+			if m.repoInfo != nil && m.repoInfo.Repo != nil {
+				return m.openFileFinder()
+			}
 		}
 	}
 	return m, nil
 }
 
 func (m Model) handleMenuSelection() (tea.Model, tea.Cmd) {
+	m.savePosition(posMenu, m.cursor, 0)
+
 	switch m.cursor {
+	case 0: // Branches
+		if m.repoInfo != nil && m.repoInfo.Repo != nil {
+			return m.openBranches()
+		}
+	case 1: // Pull Requests
+		if m.repoInfo != nil && m.repoInfo.Repo != nil {
+			pm := pr.New(m.config, m.repoInfo.Repo.Root)
+			pm.SetSize(m.width, m.height)
+			pm.Cursor = m.positions[posPRs].Cursor
+			pm.ListScroll = m.positions[posPRs].Scroll
+			m.prModel = &pm
+			m.views.Push(PRsView)
+			return m, m.prModel.Init()
+		}
+	case 2: // Claude Sessions
+		if m.repoInfo != nil && m.repoInfo.Repo != nil {
+			sm := sessions.New(m.store, m.config, m.repoInfo.Repo.Root)
+			sm.SetSize(m.width, m.height)
+			sm.Cursor = m.positions[posSessions].Cursor
+			sm.ListScroll = m.positions[posSessions].Scroll
+			m.sessionsModel = &sm
+			m.views.Push(SessionsView)
+			return m, m.sessionsModel.Init()
+		}
 	case 3: // TODOs
 		if m.repoInfo != nil && m.repoInfo.Repo != nil && m.todoModel != nil {
-			m.currentView = TodosView
+			m.views.Push(TodosView)
 			m.todoModel.SetSize(m.width, m.height)
 			return m, m.todoModel.Init()
 		}
@@ -199,7 +752,7 @@ func (m Model) handleMenuSelection() (tea.Model, tea.Cmd) {
 			cm := commit.New(m.config, m.repoInfo.Repo.Root)
 			cm.SetSize(m.width, m.height)
 			m.commitModel = &cm
-			m.currentView = CommitView
+			m.views.Push(CommitView)
 			return m, m.commitModel.Init()
 		}
 	case 5: // Terminal Test
@@ -207,41 +760,146 @@ func (m Model) handleMenuSelection() (tea.Model, tea.Cmd) {
 			m.terminal = terminal.New(m.config, "Git Status Loop (0.5s)")
 			m.terminal.Dir = m.repoInfo.Repo.Root
 			m.terminal.SetSize(m.width, m.height)
-			m.currentView = TerminalTestView
+			m.views.Push(TerminalTestView)
 			// Run git status in a loop with 0.5s sleep
 			cmd := m.terminal.RunCommand("bash", "-c",
 				`for i in $(seq 1 20); do echo "=== Run $i at $(date +%H:%M:%S) ==="; git status --short; echo ""; sleep 0.5; done; echo "Done!"`)
 			return m, cmd
 		}
-	case 7: // Quit
-		return m, tea.Quit
+	case 6: // New Project
+		return m.openNewProject()
+	case 7: // Skills
+		if m.repoInfo != nil && m.repoInfo.Repo != nil {
+			return m.openSkills()
+		}
+	case 8: // Tasks
+		if m.repoInfo != nil && m.repoInfo.Repo != nil {
+			return m.openTasks()
+		}
+	case 9: // Settings
+		sm := settings.New(m.config)
+		sm.SetSize(m.width, m.height)
+		m.settingsModel = &sm
+		m.views.Push(SettingsView)
+		return m, m.settingsModel.Init()
+	case 10: // Quit
+		return m.quit()
 	}
 	return m, nil
 }
 
 // View implements tea.Model.
 func (m Model) View() string {
+	if m.diag == nil {
+		return m.view()
+	}
+
+	start := time.Now()
+	screen := m.view()
+	m.diag.views.add(time.Since(start))
+	if !m.diag.visible {
+		return screen
+	}
+	return overlay(screen, m.diag.view(), m.width)
+}
+
+// view renders the active view.
+func (m Model) view() string {
 	if m.width == 0 {
 		return "Loading..."
 	}
 
-	if m.currentView == TerminalTestView {
+	if m.showingErr {
+		return m.errScreen.ViewCentered(m.width, m.height)
+	}
+
+	if m.showingDryRun {
+		return m.dryRunPlan.ViewCentered(m.width, m.height)
+	}
+
+	if m.askingTrust {
+		return m.trustPrompt.ViewCentered(m.width, m.height)
+	}
+
+	if m.quitting {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.viewQuit())
+	}
+
+	if m.views.Is(TerminalTestView) || m.views.Is(AuditView) || m.views.Is(JobLogView) || m.views.Is(RunLogView) || m.views.Is(GitCommandView) || m.views.Is(SkillRunView) || m.views.Is(TaskRunView) {
 		return m.terminal.ViewCentered(m.width, m.height)
 	}
 
-	if m.currentView == TodosView {
+	if m.views.Is(JobsView) {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.viewJobs())
+	}
+
+	if m.views.Is(HistoryView) {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.viewHistory())
+	}
+
+	if m.views.Is(BranchesView) {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.viewBranches())
+	}
+
+	if m.views.Is(ReflogView) {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.viewReflog())
+	}
+
+	if m.views.Is(SkillsView) {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.viewSkills())
+	}
+
+	if m.views.Is(TasksView) {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.viewTasks())
+	}
+
+	if m.views.Is(TimelineView) {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.viewTimeline())
+	}
+
+	if m.views.Is(FilesView) {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.fileFinder.View())
+	}
+
+	if m.views.Is(PatchesView) {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.viewPatches())
+	}
+
+	if m.views.Is(NewProjectView) {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.viewNewProject())
+	}
+
+	if m.views.Is(TodosView) {
 		return m.todoModel.View()
 	}
 
-	if m.currentView == CommitView && m.commitModel != nil {
+	if m.views.Is(CommitView) && m.commitModel != nil {
 		return m.commitModel.View()
 	}
 
+	if m.views.Is(PRsView) && m.prModel != nil {
+		return m.prModel.View()
+	}
+
+	if m.views.Is(SessionsView) && m.sessionsModel != nil {
+		return m.sessionsModel.View()
+	}
+
+	if m.views.Is(SettingsView) && m.settingsModel != nil {
+		return m.settingsModel.View()
+	}
+
 	var content strings.Builder
 
 	content.WriteString(styles.Banner.Render(banner))
 	content.WriteString("\n")
 	content.WriteString(styles.Version.Render(fmt.Sprintf("v%s", m.version)))
+	if m.config.DryRun() {
+		content.WriteString(styles.Status.Render("  DRY RUN"))
+	}
+	if m.config.ReadOnly() {
+		content.WriteString(styles.Status.Render("  READ ONLY"))
+	}
 	content.WriteString("\n\n")
 
 	if m.repoInfo != nil {
@@ -252,6 +910,14 @@ func (m Model) View() string {
 		content.WriteString("\n\n")
 	}
 
+	kb := m.config.Keys()
+	if len(m.jobs) > 0 {
+		running, finished := m.jobCounts()
+		content.WriteString(styles.Dim.Render(fmt.Sprintf("  Background jobs: %d running, %d finished • %s to show",
+			running, finished, kb.Global.Jobs)))
+		content.WriteString("\n\n")
+	}
+
 	content.WriteString(styles.Title.Render("What would you like to do?"))
 	content.WriteString("\n\n")
 
@@ -266,9 +932,8 @@ func (m Model) View() string {
 	}
 
 	content.WriteString("\n")
-	kb := m.config.Keys()
-	content.WriteString(styles.Help.Render(fmt.Sprintf("↑/%s up • ↓/%s down • %s select • %s quit",
-		kb.Global.MoveUp, kb.Global.MoveDown, kb.List.Select, kb.Global.QuitAlt)))
+	content.WriteString(styles.Help.Render(fmt.Sprintf("↑/%s up • ↓/%s down • %s select • %s dry run • %s audit log • %s saved output • %s reflog • %s timeline • %s open file • %s quit",
+		kb.Global.MoveUp, kb.Global.MoveDown, kb.List.Select, kb.Global.DryRun, kb.Global.AuditLog, kb.Global.History, kb.Global.Reflog, kb.Global.Timeline, kb.Global.FindFile, kb.Global.QuitAlt)))
 
 	return lipgloss.NewStyle().
 		Width(m.width).
@@ -304,6 +969,17 @@ func (m Model) renderRepoInfo() string {
 		parts = append(parts, styles.Dim.Render(fmt.Sprintf("  Last opened: %s", lastOpened)))
 	}
 
+	if m.usage != nil && m.usage.Total() > 0 {
+		parts = append(parts, styles.Dim.Render(fmt.Sprintf("  Claude: %s tokens • %s",
+			claude.FormatTokens(m.usage.Total()), claude.FormatCost(m.usage.Cost))))
+	}
+
+	if len(m.audit) > 0 {
+		last := m.audit[len(m.audit)-1]
+		parts = append(parts, styles.Dim.Render(fmt.Sprintf("  Last command: %s %s • %s",
+			exitMark(last.ExitCode), truncate(last.Command, 50), formatTimeAgo(last.Time))))
+	}
+
 	return strings.Join(parts, "\n") + "\n"
 }
 
@@ -0,0 +1,41 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+	"github.com/mattn/go-runewidth"
+)
+
+// auditLines renders the audit log for the terminal modal, newest first.
+func (m Model) auditLines() []string {
+	if len(m.audit) == 0 {
+		return []string{styles.Help.Render("No commands recorded for this repository yet")}
+	}
+
+	lines := make([]string, 0, len(m.audit))
+	for i := len(m.audit) - 1; i >= 0; i-- {
+		e := m.audit[i]
+		line := fmt.Sprintf("%s %s  %s",
+			styles.Dim.Render(e.Time.Local().Format(time.DateTime)),
+			exitMark(e.ExitCode),
+			strings.Join(strings.Fields(e.Command), " "))
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// exitMark renders a command's exit code as a check or a cross with the code.
+func exitMark(code int) string {
+	if code == 0 {
+		return styles.Selected.Render("✓")
+	}
+	return styles.Error.Render(fmt.Sprintf("✗ %d", code))
+}
+
+// truncate shortens s to at most n columns, adding an ellipsis.
+func truncate(s string, n int) string {
+	return runewidth.Truncate(strings.Join(strings.Fields(s), " "), n, "...")
+}
@@ -0,0 +1,266 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/git"
+	"github.com/ihatemodels/gdev/internal/ui/confirm"
+	"github.com/ihatemodels/gdev/internal/ui/failure"
+	"github.com/ihatemodels/gdev/internal/ui/form"
+	"github.com/ihatemodels/gdev/internal/ui/markdown"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+)
+
+// branchNote is a local branch and its notes, see git.BranchNotes.
+type branchNote struct {
+	Name string
+	Note string
+}
+
+// noteSavedMsg is sent once the notes of a branch are saved.
+type noteSavedMsg struct {
+	Branch string
+	Note   string
+}
+
+// loadBranches lists the local branches, the most used first, with their
+// notes.
+func (m Model) loadBranches() ([]branchNote, error) {
+	root := m.repoInfo.Repo.Root
+	refs, err := git.ListRefs(root)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := git.BranchNotes(root)
+	if err != nil {
+		return nil, err
+	}
+	var branches []branchNote
+	for _, r := range refs {
+		if r.Kind == git.RefBranch {
+			branches = append(branches, branchNote{Name: r.Name, Note: notes[r.Name]})
+		}
+	}
+	return branches, nil
+}
+
+// openBranches shows the local branches and their notes.
+func (m Model) openBranches() (tea.Model, tea.Cmd) {
+	branches, err := m.loadBranches()
+	if err != nil {
+		return m, func() tea.Msg { return failure.Msg{Op: "List branches", Err: err} }
+	}
+	m.branches = branches
+	m.branchCursor = 0
+	m.editingNote = false
+	m.applyingPatches = false
+	m.views.Push(BranchesView)
+	return m, nil
+}
+
+// updateBranches handles input in the list of branches and the form
+// editing the notes of one.
+func (m Model) updateBranches(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.editingNote {
+		var res form.Result
+		m.noteForm, res = m.noteForm.Update(msg)
+		switch res {
+		case form.Submitted:
+			m.editingNote = false
+			return m, m.saveNote(m.branches[m.branchCursor].Name, m.noteForm.Value("notes"))
+		case form.Canceled:
+			m.editingNote = false
+		}
+		return m, nil
+	}
+	if m.confirmingPush {
+		var res confirm.Result
+		m.pushConfirm, res = m.pushConfirm.Update(msg)
+		switch res {
+		case confirm.Confirmed:
+			m.confirmingPush = false
+			return m.runPush(m.plannedPush)
+		case confirm.Canceled:
+			m.confirmingPush = false
+		}
+		return m, nil
+	}
+	if m.applyingPatches {
+		var res form.Result
+		m.patchForm, res = m.patchForm.Update(msg)
+		switch res {
+		case form.Submitted:
+			m.applyingPatches = false
+			return m.applyPatches(config.ExpandHome(strings.TrimSpace(m.patchForm.Value("path"))))
+		case form.Canceled:
+			m.applyingPatches = false
+		}
+		return m, nil
+	}
+
+	key := msg.String()
+	kb := m.config.Keys()
+
+	switch {
+	case config.MatchesAny(key, kb.Global.Quit, kb.Global.QuitAlt):
+		m.views.Pop()
+
+	case config.MatchesAny(key, kb.Global.MoveUp, kb.Global.MoveUpAlt):
+		if m.branchCursor > 0 {
+			m.branchCursor--
+		}
+
+	case config.MatchesAny(key, kb.Global.MoveDown, kb.Global.MoveDownAlt):
+		if m.branchCursor < len(m.branches)-1 {
+			m.branchCursor++
+		}
+
+	case config.Matches(key, kb.List.Select):
+		if len(m.branches) == 0 {
+			break
+		}
+		if m.config.ReadOnly() {
+			m.branchErr = "Notes can't be edited in read-only mode"
+			break
+		}
+		b := m.branches[m.branchCursor]
+		m.noteForm = form.New(m.config, "Notes for "+b.Name,
+			form.Multiline("notes", "Notes (markdown), e.g. what the branch is for", b.Note))
+		m.noteForm.Fields[0].StartEdit(m.config)
+		m.noteForm.Editing = true
+		m.editingNote = true
+		m.branchErr = ""
+
+	case config.Matches(key, kb.Branch.Push):
+		if len(m.branches) == 0 {
+			break
+		}
+		if m.config.ReadOnly() {
+			m.branchErr = "Branches can't be pushed in read-only mode"
+			break
+		}
+		return m.openPush(m.branches[m.branchCursor].Name)
+
+	case config.Matches(key, kb.Patch.Export):
+		if len(m.branches) > 0 {
+			return m.openPatchExport(m.branches[m.branchCursor].Name)
+		}
+
+	case config.Matches(key, kb.Patch.Apply):
+		if m.config.ReadOnly() {
+			m.branchErr = "Patches can't be applied in read-only mode"
+			break
+		}
+		return m.openPatchApply(), nil
+	}
+	return m, nil
+}
+
+// saveNote stores the notes of branch in the repository's git config. In
+// dry-run mode the change is only planned.
+func (m Model) saveNote(branch, note string) tea.Cmd {
+	root := m.repoInfo.Repo.Root
+	if m.config.DryRun() {
+		m.store.Plan("git config branch.%s.description in %s", branch, root)
+		return func() tea.Msg { return noteSavedMsg{Branch: branch, Note: strings.TrimRight(note, " \t\n")} }
+	}
+	return failure.Cmd("Save branch notes", func() (tea.Msg, error) {
+		if err := git.SetBranchNote(root, branch, note); err != nil {
+			return nil, err
+		}
+		saved, err := git.BranchNote(root, branch)
+		if err != nil {
+			return nil, err
+		}
+		return noteSavedMsg{Branch: branch, Note: saved}, nil
+	})
+}
+
+// viewBranches renders the branches with the first line of their notes,
+// and all the notes of the selected one.
+func (m Model) viewBranches() string {
+	if m.editingNote {
+		return m.noteForm.View()
+	}
+	if m.applyingPatches {
+		return m.patchForm.View()
+	}
+	if m.confirmingPush {
+		return m.pushConfirm.View()
+	}
+
+	var b strings.Builder
+	b.WriteString(styles.Title.Render("Branches"))
+	b.WriteString("\n\n")
+
+	if len(m.branches) == 0 {
+		b.WriteString(styles.Dim.Render("  No local branches"))
+		b.WriteString("\n")
+	}
+
+	// Keep the cursor in view, leaving room for the notes below
+	visible := max(m.height/2-4, 5)
+	start := max(m.branchCursor-visible+1, 0)
+	end := min(start+visible, len(m.branches))
+	if start > 0 {
+		b.WriteString(styles.Help.Render(fmt.Sprintf("  ↑ %d more", start)))
+		b.WriteString("\n")
+	}
+	for i := start; i < end; i++ {
+		br := m.branches[i]
+		name := br.Name
+		if m.repoInfo.Repo.Branch == br.Name {
+			name += " ●"
+		}
+		if i == m.branchCursor {
+			b.WriteString(styles.Cursor.Render("▸ "))
+			b.WriteString(styles.Selected.Render(name))
+		} else {
+			b.WriteString("  ")
+			b.WriteString(styles.Item.Render(name))
+		}
+		if first, _, _ := strings.Cut(br.Note, "\n"); first != "" {
+			b.WriteString(styles.Dim.Render(" • " + truncate(first, 50)))
+		}
+		b.WriteString("\n")
+	}
+	if end < len(m.branches) {
+		b.WriteString(styles.Help.Render(fmt.Sprintf("  ↓ %d more", len(m.branches)-end)))
+		b.WriteString("\n")
+	}
+
+	if len(m.branches) > 0 {
+		b.WriteString("\n")
+		if note := m.branches[m.branchCursor].Note; note != "" {
+			for _, line := range markdown.Render(note, min(m.width-8, 80)) {
+				b.WriteString("  " + line + "\n")
+			}
+		} else {
+			b.WriteString(styles.Dim.Render("  No notes"))
+			b.WriteString("\n")
+		}
+	}
+	if m.branchErr != "" {
+		b.WriteString("\n")
+		b.WriteString(styles.Error.Render(m.branchErr))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	kb := m.config.Keys()
+	b.WriteString(styles.Help.Render(fmt.Sprintf("↑/%s ↓/%s choose • %s edit notes • %s push • %s export patches • %s apply patches • %s back",
+		kb.Global.MoveUp, kb.Global.MoveDown, kb.List.Select, kb.Branch.Push, kb.Patch.Export, kb.Patch.Apply, kb.Global.Quit)))
+	return b.String()
+}
+
+// setNote shows the saved notes of a branch.
+func setNote(branches []branchNote, msg noteSavedMsg) {
+	for i := range branches {
+		if branches[i].Name == msg.Branch {
+			branches[i].Note = msg.Note
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package app
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+)
+
+// diagnostics measures how long the app takes to handle messages and render,
+// for the overlay shown in debug mode. It is shared by the copies of the
+// model, which is why the model holds a pointer to it.
+type diagnostics struct {
+	visible bool
+
+	updates timings
+	views   timings
+
+	// Arrival times of the messages of the last second
+	arrivals []time.Time
+
+	// Commands returned by Update that haven't finished running. Bubble Tea
+	// doesn't expose its message queue; a backlog of commands is the closest
+	// sign of messages piling up.
+	pending atomic.Int64
+}
+
+// timings keeps the last durations of something done often.
+type timings struct {
+	samples [100]time.Duration
+	next    int
+	count   int
+}
+
+// add records a duration, replacing the oldest once full.
+func (t *timings) add(d time.Duration) {
+	t.samples[t.next] = d
+	t.next = (t.next + 1) % len(t.samples)
+	t.count = min(t.count+1, len(t.samples))
+}
+
+// last returns the duration recorded last.
+func (t *timings) last() time.Duration {
+	if t.count == 0 {
+		return 0
+	}
+	return t.samples[(t.next+len(t.samples)-1)%len(t.samples)]
+}
+
+// stats returns the average and the longest of the recorded durations.
+func (t *timings) stats() (avg, longest time.Duration) {
+	if t.count == 0 {
+		return 0, 0
+	}
+	var total time.Duration
+	for _, d := range t.samples[:t.count] {
+		total += d
+		longest = max(longest, d)
+	}
+	return total / time.Duration(t.count), longest
+}
+
+// received records a message arriving at now.
+func (d *diagnostics) received(now time.Time) {
+	d.arrivals = append(d.arrivals, now)
+	i := 0
+	for i < len(d.arrivals) && now.Sub(d.arrivals[i]) > time.Second {
+		i++
+	}
+	d.arrivals = d.arrivals[i:]
+}
+
+// track counts cmd as pending until it has run.
+func (d *diagnostics) track(cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	d.pending.Add(1)
+	return func() tea.Msg {
+		defer d.pending.Add(-1)
+		return cmd()
+	}
+}
+
+// view renders the overlay.
+func (d *diagnostics) view() string {
+	row := func(label string, t *timings) string {
+		avg, longest := t.stats()
+		return fmt.Sprintf("%s %s last %s avg %s max",
+			styles.Label.Render(fmt.Sprintf("%-8s", label)),
+			styles.Value.Render(fmt.Sprintf("%8s", round(t.last()))),
+			styles.Value.Render(fmt.Sprintf("%8s", round(avg))),
+			styles.Value.Render(fmt.Sprintf("%8s", round(longest))))
+	}
+	count := func(label string, n int64) string {
+		return styles.Label.Render(fmt.Sprintf("%-8s", label)) + " " + styles.Value.Render(fmt.Sprintf("%8d", n))
+	}
+
+	lines := []string{
+		styles.Title.Render("Diagnostics"),
+		row("Update", &d.updates),
+		row("View", &d.views),
+		count("Msgs/s", int64(len(d.arrivals))),
+		count("Pending", d.pending.Load()) + styles.Help.Render(" commands"),
+		count("Routines", int64(runtime.NumGoroutine())),
+	}
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.Purple).
+		Padding(0, 1).
+		Render(strings.Join(lines, "\n"))
+}
+
+// round shortens a duration for display, e.g. 1.234ms.
+func round(d time.Duration) time.Duration {
+	switch {
+	case d >= time.Millisecond:
+		return d.Round(time.Microsecond)
+	case d >= time.Microsecond:
+		return d.Round(100 * time.Nanosecond)
+	}
+	return d
+}
+
+// overlay draws box at the top right of screen, which is width wide, in
+// place of the lines it covers.
+func overlay(screen, box string, width int) string {
+	lines := strings.Split(screen, "\n")
+	for i, l := range strings.Split(box, "\n") {
+		if i >= len(lines) {
+			break
+		}
+		lines[i] = lipgloss.PlaceHorizontal(width, lipgloss.Right, l)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,40 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/ui/failure"
+	"github.com/ihatemodels/gdev/internal/ui/filefinder"
+	"github.com/ihatemodels/gdev/internal/ui/terminal"
+)
+
+// openFileFinder lists the repository's files to pick one to open in the
+// editor.
+func (m Model) openFileFinder() (tea.Model, tea.Cmd) {
+	m.fileFinder = filefinder.New(m.config, "Open File", m.repoInfo.Repo.Root)
+	m.fileFinder.SetSize(m.width, m.height)
+	m.views.Push(FilesView)
+	return m, m.fileFinder.Init()
+}
+
+// updateFileFinder handles the streamed files and input in the file
+// finder, opening the file picked in $VISUAL or $EDITOR.
+func (m Model) updateFileFinder(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var (
+		cmd tea.Cmd
+		res filefinder.Result
+	)
+	m.fileFinder, cmd, res = m.fileFinder.Update(msg)
+	switch res {
+	case filefinder.Selected:
+		m.views.Pop()
+		path := m.fileFinder.Value()
+		edit, err := terminal.Edit(m.repoInfo.Repo.Root, path, 1, 0)
+		if err != nil {
+			return m, func() tea.Msg { return failure.Msg{Op: "Open " + path, Err: err} }
+		}
+		return m, edit
+	case filefinder.Canceled:
+		m.views.Pop()
+	}
+	return m, cmd
+}
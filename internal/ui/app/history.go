@@ -0,0 +1,115 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/store"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+	"github.com/ihatemodels/gdev/internal/ui/terminal"
+)
+
+// loadHistory reads the saved runs, newest first, forgetting those whose
+// log is gone, e.g. deleted by retention cleanup.
+func (m Model) loadHistory() []store.Run {
+	runs, err := m.store.GetHistory()
+	if err != nil {
+		return nil
+	}
+	var kept []store.Run
+	for i := len(runs) - 1; i >= 0; i-- {
+		if fileExists(runs[i].Log) {
+			kept = append(kept, runs[i])
+		} else {
+			_ = m.store.RemoveRun(runs[i].Log)
+		}
+	}
+	return kept
+}
+
+// updateHistory handles input in the list of saved runs.
+func (m Model) updateHistory(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	kb := m.config.Keys()
+
+	switch {
+	case config.MatchesAny(key, kb.Global.Quit, kb.Global.QuitAlt):
+		m.views.Pop()
+
+	case config.MatchesAny(key, kb.Global.MoveUp, kb.Global.MoveUpAlt):
+		if m.historyCursor > 0 {
+			m.historyCursor--
+		}
+
+	case config.MatchesAny(key, kb.Global.MoveDown, kb.Global.MoveDownAlt):
+		if m.historyCursor < len(m.history)-1 {
+			m.historyCursor++
+		}
+
+	case config.Matches(key, kb.List.Select):
+		r := m.history[m.historyCursor]
+		data, err := os.ReadFile(r.Log)
+		if err != nil {
+			return m, nil
+		}
+		lines := []string{styles.Help.Render("$ " + r.Command), ""}
+		lines = append(lines, strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")...)
+		if r.Err != "" {
+			lines = append(lines, "", styles.Error.Render("Error: "+r.Err))
+		}
+		m.terminal = terminal.New(m.config, r.Title)
+		m.terminal.Dir = r.Dir
+		m.terminal.SetSize(m.width, m.height)
+		m.terminal.Print(lines...)
+		m.views.Push(RunLogView)
+
+	case config.Matches(key, kb.List.Delete):
+		if err := m.store.RemoveRun(m.history[m.historyCursor].Log); err != nil {
+			return m, nil
+		}
+		m.history = append(m.history[:m.historyCursor:m.historyCursor], m.history[m.historyCursor+1:]...)
+		if m.historyCursor >= len(m.history) {
+			m.historyCursor = max(len(m.history)-1, 0)
+		}
+		if len(m.history) == 0 {
+			m.views.Pop()
+		}
+	}
+	return m, nil
+}
+
+// viewHistory renders the saved runs, newest first.
+func (m Model) viewHistory() string {
+	var b strings.Builder
+
+	b.WriteString(styles.Title.Render("Saved output"))
+	b.WriteString("\n\n")
+
+	for i, r := range m.history {
+		if i == m.historyCursor {
+			b.WriteString(styles.Cursor.Render("▸ "))
+			b.WriteString(styles.Selected.Render(r.Title))
+		} else {
+			b.WriteString("  ")
+			b.WriteString(styles.Item.Render(r.Title))
+		}
+		if r.Err != "" {
+			b.WriteString("  " + styles.Error.Render("✗ failed"))
+		} else {
+			b.WriteString("  " + styles.Selected.Render("✓"))
+		}
+		b.WriteString(styles.Dim.Render(" • saved " + formatTimeAgo(r.Saved)))
+		b.WriteString("\n")
+		b.WriteString(styles.Help.Render("    " + truncate(r.Command, 70)))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	kb := m.config.Keys()
+	b.WriteString(styles.Help.Render(fmt.Sprintf("↑/%s ↓/%s choose • %s show output • %s delete • %s back",
+		kb.Global.MoveUp, kb.Global.MoveDown, kb.List.Select, kb.List.Delete, kb.Global.Quit)))
+	return b.String()
+}
@@ -0,0 +1,49 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/instance"
+)
+
+// ForwardedMsg is the command line of gdev started again in the repository,
+// with the single_instance setting, see package instance.
+type ForwardedMsg struct {
+	Request *instance.Request
+}
+
+// updateForwarded runs a forwarded command line where it makes sense while
+// gdev is running, and replies to the gdev that sent it.
+func (m Model) updateForwarded(r *instance.Request) (tea.Model, tea.Cmd) {
+	var command string
+	if len(r.Args) > 0 {
+		command = r.Args[0]
+	}
+
+	switch command {
+	case "capture":
+		name := strings.TrimSpace(strings.Join(r.Args[1:], " "))
+		if name == "" || m.todoModel == nil {
+			r.Reply("", errors.New("usage: gdev capture <name>"))
+			return m, nil
+		}
+		return m, m.todoModel.Capture(name, func(err error) {
+			r.Reply(fmt.Sprintf("Added TODO %q", name), err)
+		})
+
+	case "todo", "todos":
+		// Only from the main menu, so nothing the user is doing is left
+		if m.views.Is(MainMenuView) && m.todoModel != nil {
+			m.views.Push(TodosView)
+			m.todoModel.SetSize(m.width, m.height)
+			r.Reply("gdev is already running for this repository; it now shows the TODOs", nil)
+			return m, m.todoModel.Init()
+		}
+	}
+
+	r.Reply("gdev is already running for this repository", nil)
+	return m, nil
+}
@@ -0,0 +1,135 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/store"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+	"github.com/ihatemodels/gdev/internal/ui/terminal"
+)
+
+// jobsLoadedMsg carries the commands left running by earlier runs of gdev.
+type jobsLoadedMsg struct {
+	Jobs []store.Job
+}
+
+// loadJobs reads the detached jobs, dropping those whose log is gone since
+// there is nothing left to show for them.
+func (m Model) loadJobs() tea.Msg {
+	jobs, err := m.store.GetJobs()
+	if err != nil {
+		return nil
+	}
+	var kept []store.Job
+	for _, j := range jobs {
+		if fileExists(j.Log) || terminal.ProcessAlive(j.Pid) {
+			kept = append(kept, j)
+		} else {
+			_ = m.store.RemoveJob(j.Log)
+		}
+	}
+	return jobsLoadedMsg{Jobs: kept}
+}
+
+// jobCounts returns how many detached jobs are still running and how many
+// have finished.
+func (m Model) jobCounts() (running, finished int) {
+	for _, j := range m.jobs {
+		if terminal.ProcessAlive(j.Pid) {
+			running++
+		} else {
+			finished++
+		}
+	}
+	return running, finished
+}
+
+// updateJobs handles input in the list of detached jobs.
+func (m Model) updateJobs(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	kb := m.config.Keys()
+
+	switch {
+	case config.MatchesAny(key, kb.Global.Quit, kb.Global.QuitAlt):
+		m.views.Pop()
+
+	case config.MatchesAny(key, kb.Global.MoveUp, kb.Global.MoveUpAlt):
+		if m.jobCursor > 0 {
+			m.jobCursor--
+		}
+
+	case config.MatchesAny(key, kb.Global.MoveDown, kb.Global.MoveDownAlt):
+		if m.jobCursor < len(m.jobs)-1 {
+			m.jobCursor++
+		}
+
+	case config.Matches(key, kb.List.Select):
+		j := m.jobs[m.jobCursor]
+		m.terminal = terminal.New(m.config, j.Title)
+		m.terminal.Dir = j.Dir
+		m.terminal.SetSize(m.width, m.height)
+		m.views.Push(JobLogView)
+		return m, m.terminal.Follow(j.Log, j.Pid)
+
+	case config.Matches(key, kb.List.Delete):
+		// Running jobs are left alone; they can be removed once they finish
+		j := m.jobs[m.jobCursor]
+		if terminal.ProcessAlive(j.Pid) {
+			return m, nil
+		}
+		if err := m.store.RemoveJob(j.Log); err != nil {
+			return m, nil
+		}
+		m.jobs = append(m.jobs[:m.jobCursor:m.jobCursor], m.jobs[m.jobCursor+1:]...)
+		if m.jobCursor >= len(m.jobs) {
+			m.jobCursor = max(len(m.jobs)-1, 0)
+		}
+		if len(m.jobs) == 0 {
+			m.views.Pop()
+		}
+	}
+	return m, nil
+}
+
+// viewJobs renders the detached jobs with whether they are still running.
+func (m Model) viewJobs() string {
+	var b strings.Builder
+
+	b.WriteString(styles.Title.Render("Background jobs"))
+	b.WriteString("\n\n")
+
+	for i, j := range m.jobs {
+		status := styles.Selected.Render("finished")
+		if terminal.ProcessAlive(j.Pid) {
+			status = styles.Confirm.Render("running")
+		}
+		if i == m.jobCursor {
+			b.WriteString(styles.Cursor.Render("▸ "))
+			b.WriteString(styles.Selected.Render(j.Title))
+		} else {
+			b.WriteString("  ")
+			b.WriteString(styles.Item.Render(j.Title))
+		}
+		b.WriteString("  " + status)
+		b.WriteString(styles.Dim.Render(fmt.Sprintf(" • started %s • pid %d", formatTimeAgo(j.Started), j.Pid)))
+		b.WriteString("\n")
+		b.WriteString(styles.Help.Render("    " + truncate(j.Command, 70)))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	kb := m.config.Keys()
+	b.WriteString(styles.Help.Render(fmt.Sprintf("↑/%s ↓/%s choose • %s show output • %s remove finished • %s back",
+		kb.Global.MoveUp, kb.Global.MoveDown, kb.List.Select, kb.List.Delete, kb.Global.Quit)))
+	return b.String()
+}
+
+// fileExists reports whether a file exists at path.
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,207 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/scaffold"
+	"github.com/ihatemodels/gdev/internal/ui/failure"
+	"github.com/ihatemodels/gdev/internal/ui/form"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+	"github.com/ihatemodels/gdev/internal/ui/terminal"
+)
+
+// templatesDir is the store subdirectory of the user's project templates.
+const templatesDir = "templates"
+
+// projectCreatedMsg is sent once the files of a new project are written.
+type projectCreatedMsg struct {
+	Dir string
+}
+
+// openNewProject lists the templates a project can start from.
+func (m Model) openNewProject() (tea.Model, tea.Cmd) {
+	templates, err := scaffold.List(filepath.Join(m.store.Path(), templatesDir))
+	if err != nil {
+		return m, func() tea.Msg { return failure.Msg{Op: "List project templates", Err: err} }
+	}
+	m.templates = templates
+	m.templateCursor = 0
+	m.namingProject = false
+	m.projectErr = ""
+	m.views.Push(NewProjectView)
+	return m, nil
+}
+
+// updateNewProject handles input in the list of templates and the form
+// naming the project.
+func (m Model) updateNewProject(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.namingProject {
+		var res form.Result
+		m.projectForm, res = m.projectForm.Update(msg)
+		switch res {
+		case form.Submitted:
+			m.namingProject = false
+			name := strings.TrimSpace(m.projectForm.Value("name"))
+			dir := filepath.Join(config.ExpandHome(strings.TrimSpace(m.projectForm.Value("parent"))), name)
+			return m, m.createProject(m.templates[m.templateCursor], dir, name, strings.TrimSpace(m.projectForm.Value("module")))
+		case form.Canceled:
+			m.namingProject = false
+		}
+		return m, nil
+	}
+
+	key := msg.String()
+	kb := m.config.Keys()
+
+	switch {
+	case config.MatchesAny(key, kb.Global.Quit, kb.Global.QuitAlt):
+		m.views.Pop()
+
+	case config.MatchesAny(key, kb.Global.MoveUp, kb.Global.MoveUpAlt):
+		if m.templateCursor > 0 {
+			m.templateCursor--
+		}
+
+	case config.MatchesAny(key, kb.Global.MoveDown, kb.Global.MoveDownAlt):
+		if m.templateCursor < len(m.templates)-1 {
+			m.templateCursor++
+		}
+
+	case config.Matches(key, kb.List.Select):
+		if len(m.templates) == 0 {
+			break
+		}
+		if m.config.ReadOnly() {
+			m.projectErr = "Projects can't be created in read-only mode"
+			break
+		}
+		m.projectForm = m.newProjectForm()
+		m.namingProject = true
+		m.projectErr = ""
+	}
+	return m, nil
+}
+
+// newProjectForm asks for the name of the project, where to create it and
+// its Go module path.
+func (m Model) newProjectForm() form.Model {
+	// Next to the repository gdev runs in, or in the working directory
+	parent, _ := os.Getwd()
+	if m.repoInfo != nil && m.repoInfo.Repo != nil {
+		parent = filepath.Dir(m.repoInfo.Repo.Root)
+	}
+
+	name := form.Text("name", "Name", "")
+	name.Required = true
+	name.Validate = func(value string) error {
+		value = strings.TrimSpace(value)
+		if strings.ContainsAny(value, `/\`) || value == "." || value == ".." {
+			return fmt.Errorf("%q can't be the name of a directory", value)
+		}
+		return nil
+	}
+	dir := form.Text("parent", "Create in", parent)
+	dir.Required = true
+	module := form.Text("module", "Go module path (default: the name), e.g. github.com/you/name", "")
+
+	f := form.New(m.config, "New "+m.templates[m.templateCursor].Name+" project", name, dir, module)
+	f.Fields[0].StartEdit(m.config)
+	f.Editing = true
+	return f
+}
+
+// createProject writes the files of t to dir and registers it with gdev
+// like a repository gdev was opened in. In dry-run mode the files are only
+// planned.
+func (m Model) createProject(t scaffold.Template, dir, name, module string) tea.Cmd {
+	s := m.store
+	return failure.Cmd("Create the project", func() (tea.Msg, error) {
+		if err := scaffold.CheckTarget(dir); err != nil {
+			return nil, err
+		}
+		files, err := t.Render(scaffold.Vars(name, module))
+		if err != nil {
+			return nil, err
+		}
+		if s.DryRun() {
+			for _, f := range files {
+				s.Plan("write %s (%d bytes)", filepath.Join(dir, f.Path), len(f.Data))
+			}
+		} else if err := scaffold.Write(dir, files); err != nil {
+			return nil, err
+		}
+		if _, err := s.TouchRepo(dir, name); err != nil {
+			return nil, err
+		}
+		return projectCreatedMsg{Dir: dir}, nil
+	})
+}
+
+// initProject makes the new project a repository with its files as the
+// initial commit, in the terminal modal. Closing it goes back to the main
+// menu.
+func (m Model) initProject(dir string) (tea.Model, tea.Cmd) {
+	m.views.Pop()
+	m.terminal = terminal.New(m.config, "New project "+filepath.Base(dir))
+	m.terminal.Dir = dir
+	m.terminal.SetSize(m.width, m.height)
+	m.views.Push(GitCommandView)
+	return m, m.terminal.RunMutatingCommands(nil, "Run gdev in "+dir+" to work on the project",
+		[]string{"git", "init"},
+		[]string{"git", "add", "--all"},
+		[]string{"git", "commit", "--message", "Initial commit"},
+	)
+}
+
+// viewNewProject renders the templates, gdev's and the user's.
+func (m Model) viewNewProject() string {
+	if m.namingProject {
+		return m.projectForm.View()
+	}
+
+	var b strings.Builder
+	b.WriteString(styles.Title.Render("New project"))
+	b.WriteString("\n\n")
+
+	if len(m.templates) == 0 {
+		b.WriteString(styles.Dim.Render("  No templates"))
+		b.WriteString("\n")
+	}
+	for i, t := range m.templates {
+		if i == m.templateCursor {
+			b.WriteString(styles.Cursor.Render("▸ "))
+			b.WriteString(styles.Selected.Render(padRight(t.Name, 16)))
+		} else {
+			b.WriteString("  ")
+			b.WriteString(styles.Item.Render(padRight(t.Name, 16)))
+		}
+		if t.User {
+			b.WriteString(styles.Label.Render(" yours"))
+		}
+		if t.Description != "" {
+			b.WriteString(styles.Dim.Render(" • " + truncate(t.Description, 60)))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(styles.Help.Render(fmt.Sprintf("  Your own templates are directories in %s",
+		filepath.Join(m.store.Path(), templatesDir))))
+	b.WriteString("\n")
+
+	if m.projectErr != "" {
+		b.WriteString("\n")
+		b.WriteString(styles.Error.Render(m.projectErr))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	kb := m.config.Keys()
+	b.WriteString(styles.Help.Render(fmt.Sprintf("↑/%s ↓/%s choose • %s create • %s back",
+		kb.Global.MoveUp, kb.Global.MoveDown, kb.List.Select, kb.Global.Quit)))
+	return b.String()
+}
@@ -0,0 +1,218 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/git"
+	"github.com/ihatemodels/gdev/internal/ui/failure"
+	"github.com/ihatemodels/gdev/internal/ui/form"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+	"github.com/ihatemodels/gdev/internal/ui/terminal"
+)
+
+// patchLogLimit is how many commits of a branch can be picked for export.
+const patchLogLimit = 100
+
+// openPatchExport lists the commits of branch to pick those to export as
+// patches.
+func (m Model) openPatchExport(branch string) (tea.Model, tea.Cmd) {
+	commits, err := git.Log(m.repoInfo.Repo.Root, "refs/heads/"+branch, patchLogLimit)
+	if err != nil {
+		return m, func() tea.Msg { return failure.Msg{Op: "List commits", Err: err} }
+	}
+	m.patchBranch = branch
+	m.commits = commits
+	m.commitCursor = 0
+	m.picked = make(map[string]bool)
+	m.namingPatchDir = false
+	m.views.Push(PatchesView)
+	return m, nil
+}
+
+// pickedHashes returns the picked commits, newest first as git log lists
+// them, or the one under the cursor if none is picked.
+func (m Model) pickedHashes() []string {
+	var hashes []string
+	for _, c := range m.commits {
+		if m.picked[c.Hash] {
+			hashes = append(hashes, c.Hash)
+		}
+	}
+	if len(hashes) == 0 && len(m.commits) > 0 {
+		hashes = []string{m.commits[m.commitCursor].Hash}
+	}
+	return hashes
+}
+
+// updatePatches handles input in the list of commits to export and the
+// form asking where to.
+func (m Model) updatePatches(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.namingPatchDir {
+		var res form.Result
+		m.patchForm, res = m.patchForm.Update(msg)
+		switch res {
+		case form.Submitted:
+			m.namingPatchDir = false
+			return m.exportPatches(config.ExpandHome(strings.TrimSpace(m.patchForm.Value("dir"))), m.pickedHashes())
+		case form.Canceled:
+			m.namingPatchDir = false
+		}
+		return m, nil
+	}
+
+	key := msg.String()
+	kb := m.config.Keys()
+
+	switch {
+	case config.MatchesAny(key, kb.Global.Quit, kb.Global.QuitAlt):
+		m.views.Pop()
+
+	case config.MatchesAny(key, kb.Global.MoveUp, kb.Global.MoveUpAlt):
+		if m.commitCursor > 0 {
+			m.commitCursor--
+		}
+
+	case config.MatchesAny(key, kb.Global.MoveDown, kb.Global.MoveDownAlt):
+		if m.commitCursor < len(m.commits)-1 {
+			m.commitCursor++
+		}
+
+	case config.Matches(key, kb.Patch.Toggle):
+		if len(m.commits) > 0 {
+			hash := m.commits[m.commitCursor].Hash
+			m.picked[hash] = !m.picked[hash]
+		}
+
+	case config.Matches(key, kb.List.Select):
+		if len(m.commits) == 0 {
+			break
+		}
+		// The patches go to gdev's exports by default, out of the work tree
+		name := fmt.Sprintf("patches-%s-%s-%s", filepath.Base(m.repoInfo.Repo.Root),
+			strings.ReplaceAll(m.patchBranch, "/", "-"), time.Now().Format("20060102-150405"))
+		dir := form.Text("dir", "Directory, created if missing", filepath.Join(m.store.Path(), "exports", name))
+		dir.Required = true
+		m.patchForm = form.New(m.config, fmt.Sprintf("Export %s", commitCount(len(m.pickedHashes()))), dir)
+		m.patchForm.Fields[0].StartEdit(m.config)
+		m.patchForm.Editing = true
+		m.namingPatchDir = true
+	}
+	return m, nil
+}
+
+// exportPatches writes the commits to dir with git format-patch, in the
+// terminal modal. It only writes files, so read-only mode allows it; in
+// dry-run mode it is planned.
+func (m Model) exportPatches(dir string, hashes []string) (tea.Model, tea.Cmd) {
+	args := git.FormatPatchArgs(dir, hashes)
+	if m.config.DryRun() {
+		m.store.Plan("%s (%s)", terminal.ShellQuote("git", args...), commitCount(len(hashes)))
+		return m, nil
+	}
+	m.terminal = terminal.New(m.config, "Export patches from "+m.patchBranch)
+	m.terminal.Dir = m.repoInfo.Repo.Root
+	m.terminal.SetSize(m.width, m.height)
+	m.views.Push(GitCommandView)
+	return m, m.terminal.RunCommand("git", args...)
+}
+
+// openPatchApply asks for the patches to apply to the current branch.
+func (m Model) openPatchApply() Model {
+	path := form.Text("path", "Patch file, or directory of .patch files", "")
+	path.Required = true
+	path.Validate = func(value string) error {
+		_, err := git.PatchFiles(config.ExpandHome(strings.TrimSpace(value)))
+		return err
+	}
+	m.patchForm = form.New(m.config, "Apply patches to "+m.repoInfo.Repo.Branch, path)
+	m.patchForm.Fields[0].StartEdit(m.config)
+	m.patchForm.Editing = true
+	m.applyingPatches = true
+	m.branchErr = ""
+	return m
+}
+
+// applyPatches applies the patches at path to the current branch with
+// git am, in the terminal modal. If one doesn't apply, git am stops and
+// Smart Commit offers to continue or abort it.
+func (m Model) applyPatches(path string) (tea.Model, tea.Cmd) {
+	files, err := git.PatchFiles(path)
+	if err != nil {
+		return m, func() tea.Msg { return failure.Msg{Op: "Apply patches", Err: err} }
+	}
+	m.terminal = terminal.New(m.config, "Apply patches to "+m.repoInfo.Repo.Branch)
+	m.terminal.Dir = m.repoInfo.Repo.Root
+	m.terminal.SetSize(m.width, m.height)
+	m.views.Push(GitCommandView)
+	return m, m.terminal.RunMutatingCommand("git", append([]string{"am", "--3way"}, files...)...)
+}
+
+// viewPatches renders the commits of the branch, marking those picked for
+// export.
+func (m Model) viewPatches() string {
+	if m.namingPatchDir {
+		return m.patchForm.View()
+	}
+
+	var b strings.Builder
+	b.WriteString(styles.Title.Render("Export patches from " + m.patchBranch))
+	b.WriteString("\n\n")
+
+	if len(m.commits) == 0 {
+		b.WriteString(styles.Dim.Render("  No commits"))
+		b.WriteString("\n")
+	}
+
+	visible := max(m.height-10, 5)
+	start := max(m.commitCursor-visible+1, 0)
+	end := min(start+visible, len(m.commits))
+	if start > 0 {
+		b.WriteString(styles.Help.Render(fmt.Sprintf("  ↑ %d more", start)))
+		b.WriteString("\n")
+	}
+	for i := start; i < end; i++ {
+		c := m.commits[i]
+		check := "[ ]"
+		if m.picked[c.Hash] {
+			check = "[x]"
+		}
+		line := check + " " + shortHash(c.Hash) + " " + truncate(c.Subject, 60)
+		if i == m.commitCursor {
+			b.WriteString(styles.Cursor.Render("▸ "))
+			b.WriteString(styles.Selected.Render(line))
+		} else {
+			b.WriteString("  ")
+			b.WriteString(styles.Item.Render(line))
+		}
+		b.WriteString(styles.Dim.Render(" • " + truncate(c.Author, 20) + " • " + formatTimeAgo(c.Time)))
+		b.WriteString("\n")
+	}
+	if end < len(m.commits) {
+		b.WriteString(styles.Help.Render(fmt.Sprintf("  ↓ %d more", len(m.commits)-end)))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	if n := len(m.pickedHashes()); len(m.commits) > 0 {
+		b.WriteString(styles.Dim.Render(fmt.Sprintf("  %s to export, numbered oldest first", commitCount(n))))
+		b.WriteString("\n\n")
+	}
+
+	kb := m.config.Keys()
+	b.WriteString(styles.Help.Render(fmt.Sprintf("↑/%s ↓/%s choose • %s pick • %s export • %s back",
+		kb.Global.MoveUp, kb.Global.MoveDown, kb.Patch.Toggle, kb.List.Select, kb.Global.Quit)))
+	return b.String()
+}
+
+// commitCount returns n commits, in words.
+func commitCount(n int) string {
+	if n == 1 {
+		return "1 commit"
+	}
+	return fmt.Sprintf("%d commits", n)
+}
@@ -0,0 +1,54 @@
+package app
+
+import "github.com/ihatemodels/gdev/internal/store"
+
+// Keys for remembered list positions.
+const (
+	posMenu     = "menu"
+	posTodos    = "todos"
+	posPRs      = "prs"
+	posSessions = "sessions"
+)
+
+// loadPositions returns the positions persisted for the repository,
+// or an empty set if remembering positions across runs is disabled.
+func (m Model) loadPositions() map[string]store.Position {
+	positions := make(map[string]store.Position)
+	if !m.config.Settings.RememberPositions || m.repoInfo == nil || m.repoInfo.State == nil {
+		return positions
+	}
+	for k, p := range m.repoInfo.State.Positions {
+		positions[k] = p
+	}
+	return positions
+}
+
+// savePosition remembers the cursor and scroll of a list view so it can be
+// restored when the view is reentered. Positions are persisted per repository
+// when enabled in settings.
+func (m Model) savePosition(key string, cursor, scroll int) {
+	p := store.Position{Cursor: cursor, Scroll: scroll}
+	m.positions[key] = p
+
+	if !m.config.Settings.RememberPositions || m.repoInfo == nil || m.repoInfo.State == nil {
+		return
+	}
+	state := m.repoInfo.State
+	if state.Positions == nil {
+		state.Positions = make(map[string]store.Position)
+	}
+	state.Positions[key] = p
+	// Best effort: a failed write only loses the position
+	_ = m.store.SaveRepoState(state)
+}
+
+// saveTodoView persists the sort order and filter of the todo list for the
+// repository, so the list is shown the same way next time.
+func (m Model) saveTodoView(v store.TodoView) {
+	if m.repoInfo == nil || m.repoInfo.State == nil {
+		return
+	}
+	m.repoInfo.State.TodoView = v
+	// Best effort: a failed write only loses the view
+	_ = m.store.SaveRepoState(m.repoInfo.State)
+}
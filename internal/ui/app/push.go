@@ -0,0 +1,42 @@
+package app
+
+import (
+	"errors"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/git"
+	"github.com/ihatemodels/gdev/internal/ui/confirm"
+	"github.com/ihatemodels/gdev/internal/ui/failure"
+	"github.com/ihatemodels/gdev/internal/ui/terminal"
+)
+
+// openPush pushes branch to its upstream. A push that may replace commits
+// of the remote branch, because the branch was rewritten or never fetched,
+// is confirmed first.
+func (m Model) openPush(branch string) (tea.Model, tea.Cmd) {
+	p, err := git.PlanForcePush(m.repoInfo.Repo.Root, branch)
+	if errors.Is(err, git.ErrNoUpstream) {
+		m.branchErr = branch + " has no upstream; push it once with git push -u"
+		return m, nil
+	}
+	if err != nil {
+		return m, func() tea.Msg { return failure.Msg{Op: "Plan push", Err: err} }
+	}
+	m.branchErr = ""
+	if !p.Forced() {
+		return m.runPush(p)
+	}
+	m.plannedPush = p
+	m.pushConfirm = confirm.ForcePush(m.config, p)
+	m.confirmingPush = true
+	return m, nil
+}
+
+// runPush runs the push p in the terminal modal.
+func (m Model) runPush(p git.ForcePush) (tea.Model, tea.Cmd) {
+	m.terminal = terminal.New(m.config, "Push "+p.Branch+" to "+p.Remote)
+	m.terminal.Dir = m.repoInfo.Repo.Root
+	m.terminal.SetSize(m.width, m.height)
+	m.views.Push(GitCommandView)
+	return m, m.terminal.RunMutatingCommand("git", p.Args()...)
+}
@@ -0,0 +1,171 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+	"github.com/ihatemodels/gdev/internal/ui/terminal"
+	"github.com/mattn/go-runewidth"
+)
+
+// quitChoice is what to do with the commands still running when quitting.
+type quitChoice int
+
+const (
+	quitWait quitChoice = iota
+	quitDetach
+	quitKill
+)
+
+// quitChoices lists the choices in display order, with their descriptions.
+var quitChoices = []struct {
+	Name, Description string
+}{
+	quitWait:   {"wait", "Quit once they finish"},
+	quitDetach: {"detach", "Quit now and let them finish; their output goes to their log files"},
+	quitKill:   {"kill", "Stop them and quit"},
+}
+
+// jobsTickMsg checks again whether the running commands have finished.
+type jobsTickMsg struct{}
+
+// quit exits gdev, first asking what to do with the commands still running.
+func (m Model) quit() (Model, tea.Cmd) {
+	m.savePosition(posMenu, m.cursor, 0)
+	if len(terminal.Jobs()) == 0 {
+		return m, tea.Quit
+	}
+
+	m.quitChoice = quitWait
+	m.quitting = true
+	return m, nil
+}
+
+// updateQuit handles input while asking what to do with the running
+// commands, or while waiting for them.
+func (m Model) updateQuit(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case jobsTickMsg:
+		if !m.waitingForJobs {
+			return m, nil
+		}
+		if len(terminal.Jobs()) == 0 {
+			return m, tea.Quit
+		}
+		return m, tickJobs()
+
+	case tea.KeyMsg:
+		key := msg.String()
+		kb := m.config.Keys()
+
+		if m.waitingForJobs {
+			// Stop waiting; the commands keep running
+			if config.MatchesAny(key, kb.Global.Quit, kb.Global.QuitAlt) {
+				m.waitingForJobs = false
+				m.quitting = false
+			}
+			return m, nil
+		}
+
+		switch {
+		case config.MatchesAny(key, kb.Global.Quit, kb.Global.QuitAlt):
+			m.quitting = false
+
+		case config.MatchesAny(key, kb.Global.MoveUp, kb.Global.MoveUpAlt):
+			if m.quitChoice > 0 {
+				m.quitChoice--
+			}
+
+		case config.MatchesAny(key, kb.Global.MoveDown, kb.Global.MoveDownAlt):
+			if int(m.quitChoice) < len(quitChoices)-1 {
+				m.quitChoice++
+			}
+
+		case config.Matches(key, kb.List.Select):
+			switch m.quitChoice {
+			case quitWait:
+				m.waitingForJobs = true
+				return m, tickJobs()
+			case quitDetach:
+				m.detached = true
+				return m, tea.Quit
+			case quitKill:
+				return m, tea.Quit
+			}
+		}
+	}
+	return m, nil
+}
+
+func tickJobs() tea.Cmd {
+	return tea.Tick(500*time.Millisecond, func(time.Time) tea.Msg {
+		return jobsTickMsg{}
+	})
+}
+
+// Detached reports whether gdev was quit leaving its running commands to
+// finish on their own.
+func (m Model) Detached() bool {
+	return m.detached
+}
+
+// viewQuit renders the running commands and the choice of what to do with them.
+func (m Model) viewQuit() string {
+	var b strings.Builder
+	jobs := terminal.Jobs()
+
+	if m.waitingForJobs {
+		b.WriteString(styles.Title.Render(fmt.Sprintf("Waiting for %s...", commands(len(jobs)))))
+	} else {
+		b.WriteString(styles.Confirm.Render(fmt.Sprintf("⚠ %s still running", commands(len(jobs)))))
+	}
+	b.WriteString("\n\n")
+
+	for _, j := range jobs {
+		b.WriteString(styles.Label.Render("  " + j.Title))
+		b.WriteString(styles.Dim.Render(fmt.Sprintf("  %s • pid %d", time.Since(j.Started).Round(time.Second), j.Pid)))
+		b.WriteString("\n")
+		b.WriteString(styles.Help.Render("    " + truncate(j.Command, 70)))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	kb := m.config.Keys()
+	if m.waitingForJobs {
+		b.WriteString(styles.Help.Render(fmt.Sprintf("gdev quits once they finish • %s stop waiting", kb.Global.Quit)))
+		return b.String()
+	}
+
+	for i, c := range quitChoices {
+		if quitChoice(i) == m.quitChoice {
+			b.WriteString(styles.Cursor.Render("▸ "))
+			b.WriteString(styles.Selected.Render(padRight(c.Name, 7)))
+		} else {
+			b.WriteString("  ")
+			b.WriteString(styles.Item.Render(padRight(c.Name, 7)))
+		}
+		b.WriteString(styles.Help.Render("  " + c.Description))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(styles.Help.Render(fmt.Sprintf("↑/%s ↓/%s choose • %s select • %s back",
+		kb.Global.MoveUp, kb.Global.MoveDown, kb.List.Select, kb.Global.Quit)))
+	return b.String()
+}
+
+// commands formats a count of commands, e.g. "2 commands".
+func commands(n int) string {
+	if n == 1 {
+		return "1 command"
+	}
+	return fmt.Sprintf("%d commands", n)
+}
+
+// padRight pads s with spaces to length columns.
+func padRight(s string, length int) string {
+	return runewidth.FillRight(s, length)
+}
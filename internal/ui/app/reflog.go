@@ -0,0 +1,199 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/git"
+	"github.com/ihatemodels/gdev/internal/ui/failure"
+	"github.com/ihatemodels/gdev/internal/ui/form"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+	"github.com/ihatemodels/gdev/internal/ui/terminal"
+)
+
+// reflogLimit is how many movements of HEAD the reflog view lists.
+const reflogLimit = 200
+
+// openReflog shows where HEAD has been, newest first.
+func (m Model) openReflog() (tea.Model, tea.Cmd) {
+	entries, err := git.Reflog(m.repoInfo.Repo.Root, reflogLimit)
+	if err != nil {
+		return m, func() tea.Msg { return failure.Msg{Op: "Read the reflog", Err: err} }
+	}
+	m.reflog = entries
+	m.reflogCursor = 0
+	m.namingRescue = false
+	m.reflogErr = ""
+	m.views.Push(ReflogView)
+	return m, nil
+}
+
+// reloadReflog reads the reflog again once a rescue branch was created, so
+// the commits it holds are no longer shown as lost.
+func (m Model) reloadReflog() (tea.Model, tea.Cmd) {
+	entries, err := git.Reflog(m.repoInfo.Repo.Root, reflogLimit)
+	if err != nil {
+		return m, func() tea.Msg { return failure.Msg{Op: "Read the reflog", Err: err} }
+	}
+	m.reflog = entries
+	m.reflogCursor = min(m.reflogCursor, max(len(entries)-1, 0))
+	return m, nil
+}
+
+// updateReflog handles input in the reflog and the form naming a rescue
+// branch.
+func (m Model) updateReflog(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.namingRescue {
+		var res form.Result
+		m.rescueForm, res = m.rescueForm.Update(msg)
+		switch res {
+		case form.Submitted:
+			m.namingRescue = false
+			return m.createRescueBranch(strings.TrimSpace(m.rescueForm.Value("branch")), m.reflog[m.reflogCursor])
+		case form.Canceled:
+			m.namingRescue = false
+		}
+		return m, nil
+	}
+
+	key := msg.String()
+	kb := m.config.Keys()
+
+	switch {
+	case config.MatchesAny(key, kb.Global.Quit, kb.Global.QuitAlt):
+		m.views.Pop()
+
+	case config.MatchesAny(key, kb.Global.MoveUp, kb.Global.MoveUpAlt):
+		if m.reflogCursor > 0 {
+			m.reflogCursor--
+		}
+
+	case config.MatchesAny(key, kb.Global.MoveDown, kb.Global.MoveDownAlt):
+		if m.reflogCursor < len(m.reflog)-1 {
+			m.reflogCursor++
+		}
+
+	case config.Matches(key, kb.List.Select):
+		if len(m.reflog) == 0 {
+			break
+		}
+		if m.config.ReadOnly() {
+			m.reflogErr = "Branches can't be created in read-only mode"
+			break
+		}
+		root := m.repoInfo.Repo.Root
+		e := m.reflog[m.reflogCursor]
+		field := form.Text("branch", "Branch at "+e.Selector, "rescue-"+shortHash(e.Hash))
+		field.Required = true
+		field.Validate = func(name string) error {
+			return git.CheckBranchName(root, strings.TrimSpace(name))
+		}
+		m.rescueForm = form.New(m.config, "Rescue "+shortHash(e.Hash), field)
+		m.rescueForm.Fields[0].StartEdit(m.config)
+		m.rescueForm.Editing = true
+		m.namingRescue = true
+		m.reflogErr = ""
+	}
+	return m, nil
+}
+
+// createRescueBranch creates branch at the commit of e, without checking it
+// out, in the terminal modal.
+func (m Model) createRescueBranch(branch string, e git.ReflogEntry) (tea.Model, tea.Cmd) {
+	m.terminal = terminal.New(m.config, "Rescue "+e.Selector)
+	m.terminal.Dir = m.repoInfo.Repo.Root
+	m.terminal.SetSize(m.width, m.height)
+	m.views.Push(GitCommandView)
+	return m, m.terminal.RunMutatingCommand("git", "branch", branch, e.Hash)
+}
+
+// viewReflog renders the movements of HEAD, marking those to commits only
+// the reflog still holds, and the commit of the selected one.
+func (m Model) viewReflog() string {
+	if m.namingRescue {
+		return m.rescueForm.View()
+	}
+
+	var b strings.Builder
+	b.WriteString(styles.Title.Render("Reflog"))
+	b.WriteString("\n\n")
+
+	if len(m.reflog) == 0 {
+		b.WriteString(styles.Dim.Render("  HEAD has not moved yet"))
+		b.WriteString("\n")
+	}
+
+	// Keep the cursor in view, leaving room for the commit below
+	visible := max(m.height-14, 5)
+	start := max(m.reflogCursor-visible+1, 0)
+	end := min(start+visible, len(m.reflog))
+	if start > 0 {
+		b.WriteString(styles.Help.Render(fmt.Sprintf("  ↑ %d more", start)))
+		b.WriteString("\n")
+	}
+	lost := 0
+	for _, e := range m.reflog {
+		if e.Lost {
+			lost++
+		}
+	}
+	for i := start; i < end; i++ {
+		e := m.reflog[i]
+		line := padRight(e.Selector, 10) + " " + shortHash(e.Hash) + " " + truncate(e.Action, 50)
+		if i == m.reflogCursor {
+			b.WriteString(styles.Cursor.Render("▸ "))
+			b.WriteString(styles.Selected.Render(line))
+		} else {
+			b.WriteString("  ")
+			b.WriteString(styles.Item.Render(line))
+		}
+		if e.Lost {
+			b.WriteString(styles.Confirm.Render(" ◆ lost"))
+		}
+		if !e.Time.IsZero() {
+			b.WriteString(styles.Dim.Render(" • " + formatTimeAgo(e.Time)))
+		}
+		b.WriteString("\n")
+	}
+	if end < len(m.reflog) {
+		b.WriteString(styles.Help.Render(fmt.Sprintf("  ↓ %d more", len(m.reflog)-end)))
+		b.WriteString("\n")
+	}
+
+	if len(m.reflog) > 0 {
+		e := m.reflog[m.reflogCursor]
+		b.WriteString("\n")
+		b.WriteString(styles.Item.Render("  " + shortHash(e.Hash) + " " + truncate(e.Subject, 70)))
+		b.WriteString("\n")
+		if e.Lost {
+			b.WriteString(styles.Confirm.Render("  No branch or tag has this commit: git drops it once the reflog entry expires"))
+			b.WriteString("\n")
+		}
+	}
+	if lost > 0 {
+		b.WriteString("\n")
+		b.WriteString(styles.Dim.Render(fmt.Sprintf("  %d of %d entries are at lost commits, e.g. left behind by a reset", lost, len(m.reflog))))
+		b.WriteString("\n")
+	}
+	if m.reflogErr != "" {
+		b.WriteString("\n")
+		b.WriteString(styles.Error.Render(m.reflogErr))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	kb := m.config.Keys()
+	b.WriteString(styles.Help.Render(fmt.Sprintf("↑/%s ↓/%s choose • %s create branch here • %s back",
+		kb.Global.MoveUp, kb.Global.MoveDown, kb.List.Select, kb.Global.Quit)))
+	return b.String()
+}
+
+// shortHash returns the abbreviated form of a commit hash.
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
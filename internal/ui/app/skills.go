@@ -0,0 +1,219 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/budget"
+	"github.com/ihatemodels/gdev/internal/claude"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/skill"
+	"github.com/ihatemodels/gdev/internal/ui/confirm"
+	"github.com/ihatemodels/gdev/internal/ui/failure"
+	"github.com/ihatemodels/gdev/internal/ui/form"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+	"github.com/ihatemodels/gdev/internal/ui/terminal"
+)
+
+// skillsDir is the store subdirectory of the user's claude commands.
+var skillsDir = filepath.Join("claude", "commands")
+
+// openSkills lists the claude commands that can be run as skills.
+func (m Model) openSkills() (tea.Model, tea.Cmd) {
+	skills, err := skill.List(filepath.Join(m.store.Path(), skillsDir))
+	if err != nil {
+		return m, func() tea.Msg { return failure.Msg{Op: "List skills", Err: err} }
+	}
+	m.skills = skills
+	m.skillCursor = 0
+	m.fillingSkill = false
+	m.views.Push(SkillsView)
+	return m, nil
+}
+
+// updateSkills handles input in the list of skills and the form filling in
+// the parameters of the one to run.
+func (m Model) updateSkills(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.confirmingSkill {
+		var res confirm.Result
+		m.skillConfirm, res = m.skillConfirm.Update(msg)
+		switch res {
+		case confirm.Confirmed:
+			m.confirmingSkill = false
+			return m.startSkill(m.skills[m.skillCursor], m.skillValues)
+		case confirm.Canceled:
+			m.confirmingSkill = false
+		}
+		return m, nil
+	}
+
+	if m.fillingSkill {
+		var res form.Result
+		m.skillForm, res = m.skillForm.Update(msg)
+		switch res {
+		case form.Submitted:
+			m.fillingSkill = false
+			s := m.skills[m.skillCursor]
+			values := make(map[string]string, len(s.Params))
+			for _, p := range s.Params {
+				values[p.Name] = strings.TrimSpace(m.skillForm.Value(p.Name))
+			}
+			return m.runSkill(s, values)
+		case form.Canceled:
+			m.fillingSkill = false
+		}
+		return m, nil
+	}
+
+	key := msg.String()
+	kb := m.config.Keys()
+
+	switch {
+	case config.MatchesAny(key, kb.Global.Quit, kb.Global.QuitAlt):
+		m.views.Pop()
+
+	case config.MatchesAny(key, kb.Global.MoveUp, kb.Global.MoveUpAlt):
+		if m.skillCursor > 0 {
+			m.skillCursor--
+		}
+
+	case config.MatchesAny(key, kb.Global.MoveDown, kb.Global.MoveDownAlt):
+		if m.skillCursor < len(m.skills)-1 {
+			m.skillCursor++
+		}
+
+	case config.Matches(key, kb.List.Select):
+		if len(m.skills) == 0 {
+			break
+		}
+		s := m.skills[m.skillCursor]
+		if len(s.Params) == 0 {
+			return m.runSkill(s, nil)
+		}
+		fields := make([]form.Field, len(s.Params))
+		for i, p := range s.Params {
+			label := p.Name
+			if p.Description != "" {
+				label = p.Description
+			}
+			fields[i] = form.Text(p.Name, label, p.Default)
+			fields[i].Required = p.Required
+		}
+		m.skillForm = form.New(m.config, "Run "+s.Name, fields...)
+		m.skillForm.Fields[0].StartEdit(m.config)
+		m.skillForm.Editing = true
+		m.fillingSkill = true
+	}
+	return m, nil
+}
+
+// runSkill runs claude with the prompt of s in the repository, in the
+// terminal modal. Closing it goes back to the skills. Past claude's budget
+// it asks first.
+func (m Model) runSkill(s skill.Skill, values map[string]string) (tea.Model, tea.Cmd) {
+	// An unreadable usage log doesn't stop the skill
+	if status, err := budget.For(m.config, "claude"); err == nil && status.Level == budget.Over {
+		m.skillConfirm = confirm.OverBudget(m.config, status)
+		m.confirmingSkill = true
+		m.skillValues = values
+		return m, nil
+	}
+	return m.startSkill(s, values)
+}
+
+// startSkill runs s in the terminal modal, in a claude session of its own
+// whose usage is recorded once the modal is closed.
+func (m Model) startSkill(s skill.Skill, values map[string]string) (tea.Model, tea.Cmd) {
+	m.skillSession = claude.NewSessionID()
+	// Before --allowedTools, which takes every argument after it
+	args := slices.Insert(s.Args(values), 2, "--session-id", m.skillSession)
+
+	m.terminal = terminal.New(m.config, "Skill "+s.Name)
+	m.terminal.Dir = m.repoInfo.Repo.Root
+	m.terminal.SetSize(m.width, m.height)
+	m.views.Push(SkillRunView)
+	return m, m.terminal.RunCommand("claude", args...)
+}
+
+// recordSkillUsage adds what the session of the skill that ran used to the
+// usage log.
+func (m *Model) recordSkillUsage() {
+	id := m.skillSession
+	m.skillSession = ""
+	if id == "" {
+		return
+	}
+	u, err := claude.SessionUsage(m.repoInfo.Repo.Root, id)
+	if err != nil || u.Total() == 0 {
+		// claude failed before starting the session
+		return
+	}
+	m.config.RecordUsage("claude", m.skills[m.skillCursor].Model, u.Total(), u.Cost)
+}
+
+// viewSkills renders the skills, gdev's and the user's.
+func (m Model) viewSkills() string {
+	if m.confirmingSkill {
+		return m.skillConfirm.View()
+	}
+	if m.fillingSkill {
+		return m.skillForm.View()
+	}
+
+	var b strings.Builder
+	b.WriteString(styles.Title.Render("Skills"))
+	b.WriteString("\n\n")
+
+	if len(m.skills) == 0 {
+		b.WriteString(styles.Dim.Render("  No skills"))
+		b.WriteString("\n")
+	}
+	for i, s := range m.skills {
+		if i == m.skillCursor {
+			b.WriteString(styles.Cursor.Render("▸ "))
+			b.WriteString(styles.Selected.Render(padRight(s.Name, 24)))
+		} else {
+			b.WriteString("  ")
+			b.WriteString(styles.Item.Render(padRight(s.Name, 24)))
+		}
+		if s.User {
+			b.WriteString(styles.Label.Render(" yours"))
+		}
+		if s.Description != "" {
+			b.WriteString(styles.Dim.Render(" • " + truncate(s.Description, 60)))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	if len(m.skills) > 0 {
+		s := m.skills[m.skillCursor]
+		if len(s.Params) > 0 {
+			names := make([]string, len(s.Params))
+			for i, p := range s.Params {
+				names[i] = p.Name
+			}
+			b.WriteString(styles.Help.Render("  Asks for " + strings.Join(names, ", ")))
+			b.WriteString("\n")
+		}
+		if s.Model != "" {
+			b.WriteString(styles.Help.Render("  Runs with " + s.Model))
+			b.WriteString("\n")
+		}
+		if len(s.AllowedTools) > 0 {
+			b.WriteString(styles.Help.Render("  May use " + truncate(strings.Join(s.AllowedTools, ", "), 70)))
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString(styles.Help.Render(fmt.Sprintf("  Your own skills are .md files in %s",
+		filepath.Join(m.store.Path(), skillsDir))))
+	b.WriteString("\n\n")
+
+	kb := m.config.Keys()
+	b.WriteString(styles.Help.Render(fmt.Sprintf("↑/%s ↓/%s choose • %s run • %s back",
+		kb.Global.MoveUp, kb.Global.MoveDown, kb.List.Select, kb.Global.Quit)))
+	return b.String()
+}
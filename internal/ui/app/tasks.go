@@ -0,0 +1,161 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/ui/confirm"
+	"github.com/ihatemodels/gdev/internal/ui/failure"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+	"github.com/ihatemodels/gdev/internal/ui/terminal"
+)
+
+// openTasks lists the tasks of the repository's own config, if the user
+// trusts the file as it is now. Asks first if they haven't answered for it.
+func (m Model) openTasks() (tea.Model, tea.Cmd) {
+	m.tasks = nil
+	m.taskCursor = 0
+	m.taskErr = ""
+	m.confirmingTask = false
+
+	// An error with a hash is one in the file's contents, which only
+	// matters once the file is trusted.
+	tasks, hash, err := config.LoadTasks(m.repoInfo.Repo.Root)
+	switch {
+	case hash == "" && err != nil:
+		return m, func() tea.Msg { return failure.Msg{Op: "Load tasks", Err: err} }
+	case hash != "" && !m.repoInfo.State.TrustAnswered(hash):
+		m.askTrust(hash)
+		return m, nil
+	case hash != "" && m.repoInfo.State.Trusted(hash):
+		if err != nil {
+			return m, func() tea.Msg { return failure.Msg{Op: "Load tasks", Err: err} }
+		}
+		m.tasks = tasks
+	}
+	m.taskHash = hash
+	m.views.Push(TasksView)
+	return m, nil
+}
+
+// updateTasks handles input in the list of tasks and the confirmation
+// before a task's command first runs.
+func (m Model) updateTasks(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.confirmingTask {
+		var res confirm.Result
+		m.taskConfirm, res = m.taskConfirm.Update(msg)
+		switch res {
+		case confirm.Confirmed:
+			m.confirmingTask = false
+			t := m.tasks[m.taskCursor]
+			m.repoInfo.State.Approve(t.Command)
+			if err := m.store.SaveRepoState(m.repoInfo.State); err != nil {
+				return m, func() tea.Msg { return failure.Msg{Op: "Save approved task", Err: err} }
+			}
+			return m.runTask(t)
+		case confirm.Canceled:
+			m.confirmingTask = false
+		}
+		return m, nil
+	}
+
+	key := msg.String()
+	kb := m.config.Keys()
+	m.taskErr = ""
+
+	switch {
+	case config.MatchesAny(key, kb.Global.Quit, kb.Global.QuitAlt):
+		m.views.Pop()
+
+	case config.MatchesAny(key, kb.Global.MoveUp, kb.Global.MoveUpAlt):
+		if m.taskCursor > 0 {
+			m.taskCursor--
+		}
+
+	case config.MatchesAny(key, kb.Global.MoveDown, kb.Global.MoveDownAlt):
+		if m.taskCursor < len(m.tasks)-1 {
+			m.taskCursor++
+		}
+
+	case config.Matches(key, kb.List.Select):
+		if len(m.tasks) == 0 {
+			break
+		}
+		t := m.tasks[m.taskCursor]
+		if err := m.config.Settings.TaskCommands.Check(t.Command); err != nil {
+			m.taskErr = err.Error()
+			break
+		}
+		if !m.repoInfo.State.Approved(t.Command) {
+			m.taskConfirm = confirm.New(m.config, "Run "+t.Name+" for the first time?",
+				"$ "+t.Command+"\n"+config.RepoFile+" defines this command, and it runs on your machine.\n"+
+					"You are asked again whenever the command changes.")
+			m.taskConfirm.Destructive = true
+			m.confirmingTask = true
+			break
+		}
+		return m.runTask(t)
+	}
+	return m, nil
+}
+
+// runTask runs the command of t with sh in the repository, in the terminal
+// modal. Closing it goes back to the tasks.
+func (m Model) runTask(t config.Task) (tea.Model, tea.Cmd) {
+	m.terminal = terminal.New(m.config, "Task "+t.Name)
+	m.terminal.Dir = m.repoInfo.Repo.Root
+	m.terminal.SetSize(m.width, m.height)
+	m.views.Push(TaskRunView)
+	return m, m.terminal.RunMutatingCommand("sh", "-c", t.Command)
+}
+
+// viewTasks renders the tasks of the repository's own config.
+func (m Model) viewTasks() string {
+	if m.confirmingTask {
+		return m.taskConfirm.View()
+	}
+
+	var b strings.Builder
+	b.WriteString(styles.Title.Render("Tasks"))
+	b.WriteString("\n\n")
+
+	switch {
+	case m.taskHash != "" && !m.repoInfo.State.Trusted(m.taskHash):
+		b.WriteString(styles.Dim.Render("  The repository isn't trusted, so its " + config.RepoFile + " is ignored"))
+		b.WriteString("\n")
+	case len(m.tasks) == 0:
+		b.WriteString(styles.Dim.Render("  No tasks in " + config.RepoFile))
+		b.WriteString("\n")
+	}
+	for i, t := range m.tasks {
+		if i == m.taskCursor {
+			b.WriteString(styles.Cursor.Render("▸ "))
+			b.WriteString(styles.Selected.Render(padRight(t.Name, 24)))
+		} else {
+			b.WriteString("  ")
+			b.WriteString(styles.Item.Render(padRight(t.Name, 24)))
+		}
+		if t.Description != "" {
+			b.WriteString(styles.Dim.Render(" • " + truncate(t.Description, 60)))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	if len(m.tasks) > 0 {
+		b.WriteString(styles.Help.Render("  $ " + truncate(m.tasks[m.taskCursor].Command, 70)))
+		b.WriteString("\n")
+	}
+	if m.taskErr != "" {
+		b.WriteString(styles.Error.Render("  " + m.taskErr))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	kb := m.config.Keys()
+	b.WriteString(styles.Help.Render(fmt.Sprintf("↑/%s ↓/%s choose • %s run • %s back",
+		kb.Global.MoveUp, kb.Global.MoveDown, kb.List.Select, kb.Global.Quit)))
+	return b.String()
+}
@@ -0,0 +1,334 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/store"
+	"github.com/ihatemodels/gdev/internal/timesheet"
+	"github.com/ihatemodels/gdev/internal/ui/failure"
+	"github.com/ihatemodels/gdev/internal/ui/form"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+)
+
+// openTimeline shows what was done today, in every repository.
+func (m Model) openTimeline() (tea.Model, tea.Cmd) {
+	events, err := m.store.Timeline()
+	if err != nil {
+		return m, func() tea.Msg { return failure.Msg{Op: "Read the timeline", Err: err} }
+	}
+	m.timeline = events
+	m.timelineScroll = 0
+	m.timelineRepo = ""
+	m.timelineDay = startOfDay(time.Now())
+	m.timelineQuery = ""
+	m.timelineSearching = false
+	m.exportingTimesheet = false
+	m.timelineNote = ""
+	m.views.Push(TimelineView)
+	return m, nil
+}
+
+// startOfDay returns midnight of t's day, in local time.
+func startOfDay(t time.Time) time.Time {
+	y, mo, d := t.Local().Date()
+	return time.Date(y, mo, d, 0, 0, 0, 0, time.Local)
+}
+
+// timelineRepos returns the repositories of the timeline, by name.
+func (m Model) timelineRepos() []string {
+	var repos []string
+	for _, e := range m.timeline {
+		if !slices.Contains(repos, e.Repo) {
+			repos = append(repos, e.Repo)
+		}
+	}
+	slices.SortFunc(repos, func(a, b string) int { return strings.Compare(filepath.Base(a), filepath.Base(b)) })
+	return repos
+}
+
+// filteredTimeline returns the events of the repository and day shown
+// that match the query, each of whose words must be in the event or the
+// name of its repository.
+func (m Model) filteredTimeline() []store.Event {
+	words := strings.Fields(strings.ToLower(m.timelineQuery))
+	var events []store.Event
+	for _, e := range m.timeline {
+		if m.timelineRepo != "" && e.Repo != m.timelineRepo {
+			continue
+		}
+		if !m.timelineDay.IsZero() && !startOfDay(e.Time).Equal(m.timelineDay) {
+			continue
+		}
+		text := strings.ToLower(e.Text + " " + filepath.Base(e.Repo))
+		if slices.ContainsFunc(words, func(w string) bool { return !strings.Contains(text, w) }) {
+			continue
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+// updateTimeline handles input in the timeline, while typing the query
+// and in the form asking where to export the timesheet.
+func (m Model) updateTimeline(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.exportingTimesheet {
+		var res form.Result
+		m.timesheetForm, res = m.timesheetForm.Update(msg)
+		switch res {
+		case form.Submitted:
+			m.exportingTimesheet = false
+			return m, m.exportTimesheet(config.ExpandHome(strings.TrimSpace(m.timesheetForm.Value("path"))))
+		case form.Canceled:
+			m.exportingTimesheet = false
+		}
+		return m, nil
+	}
+
+	key := msg.String()
+	kb := m.config.Keys()
+
+	if m.timelineSearching {
+		switch {
+		case config.Matches(key, kb.Global.Quit):
+			m.timelineSearching = false
+			m.timelineQuery = ""
+		case key == "enter":
+			m.timelineSearching = false
+		case key == "backspace":
+			if r := []rune(m.timelineQuery); len(r) > 0 {
+				m.timelineQuery = string(r[:len(r)-1])
+			}
+		case msg.Type == tea.KeySpace:
+			m.timelineQuery += " "
+		case msg.Type == tea.KeyRunes:
+			m.timelineQuery += string(msg.Runes)
+		default:
+			return m, nil
+		}
+		m.timelineScroll = 0
+		return m, nil
+	}
+
+	switch {
+	case config.MatchesAny(key, kb.Global.Quit, kb.Global.QuitAlt):
+		if m.timelineQuery != "" {
+			m.timelineQuery = ""
+			m.timelineScroll = 0
+			break
+		}
+		m.views.Pop()
+
+	case config.MatchesAny(key, kb.Global.MoveUp, kb.Global.MoveUpAlt):
+		if m.timelineScroll > 0 {
+			m.timelineScroll--
+		}
+
+	case config.MatchesAny(key, kb.Global.MoveDown, kb.Global.MoveDownAlt):
+		if m.timelineScroll < len(m.filteredTimeline())-1 {
+			m.timelineScroll++
+		}
+
+	case config.Matches(key, kb.List.Search):
+		m.timelineSearching = true
+
+	case config.Matches(key, kb.Timeline.Repo):
+		repos := append([]string{""}, m.timelineRepos()...)
+		m.timelineRepo = repos[(slices.Index(repos, m.timelineRepo)+1)%len(repos)]
+		m.timelineScroll = 0
+
+	case config.Matches(key, kb.Timeline.Earlier):
+		if m.timelineDay.IsZero() {
+			m.timelineDay = startOfDay(time.Now())
+		} else {
+			m.timelineDay = m.timelineDay.AddDate(0, 0, -1)
+		}
+		m.timelineScroll = 0
+
+	case config.Matches(key, kb.Timeline.Later):
+		// Nothing was done tomorrow yet
+		if next := m.timelineDay.AddDate(0, 0, 1); !m.timelineDay.IsZero() && !next.After(time.Now()) {
+			m.timelineDay = next
+			m.timelineScroll = 0
+		}
+
+	case config.Matches(key, kb.Timeline.Export):
+		format := m.config.Settings.Timesheet.Format
+		if format != "json" {
+			format = "csv"
+		}
+		name := fmt.Sprintf("timesheet-%s.%s", time.Now().Format("20060102-150405"), format)
+		path := form.Text("path", "File, .json for JSON, otherwise CSV", filepath.Join(m.store.Path(), "exports", name))
+		path.Required = true
+		m.timesheetForm = form.New(m.config, "Export timesheet", path)
+		m.timesheetForm.Fields[0].StartEdit(m.config)
+		m.timesheetForm.Editing = true
+		m.exportingTimesheet = true
+		m.timelineNote = ""
+
+	case config.Matches(key, kb.Timeline.AllDays):
+		if m.timelineDay.IsZero() {
+			m.timelineDay = startOfDay(time.Now())
+		} else {
+			m.timelineDay = time.Time{}
+		}
+		m.timelineScroll = 0
+	}
+	return m, nil
+}
+
+// timesheetExportedMsg is sent once the timesheet is written.
+type timesheetExportedMsg struct {
+	Path    string
+	Entries int
+}
+
+// exportTimesheet writes the time spent in the events shown, per repository
+// and day, to path: JSON if it ends in .json, otherwise CSV. It only writes
+// a file, so read-only mode allows it; in dry-run mode it is planned.
+func (m Model) exportTimesheet(path string) tea.Cmd {
+	gap, minSession, rounding := m.config.Settings.Timesheet.Durations()
+	entries := timesheet.Build(m.filteredTimeline(), timesheet.Options{Gap: gap, MinSession: minSession, Rounding: rounding})
+	s := m.store
+	return failure.Cmd("Export the timesheet", func() (tea.Msg, error) {
+		var b bytes.Buffer
+		write := timesheet.WriteCSV
+		if strings.EqualFold(filepath.Ext(path), ".json") {
+			write = timesheet.WriteJSON
+		}
+		if err := write(&b, entries); err != nil {
+			return nil, err
+		}
+		if s.DryRun() {
+			s.Plan("write %s (%d bytes)", path, b.Len())
+		} else {
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return nil, err
+			}
+			if err := os.WriteFile(path, b.Bytes(), 0644); err != nil {
+				return nil, err
+			}
+		}
+		return timesheetExportedMsg{Path: path, Entries: len(entries)}, nil
+	})
+}
+
+// describeDay returns a day of the timeline for display, e.g. "Tuesday,
+// Oct 13", or "Today".
+func describeDay(day time.Time) string {
+	today := startOfDay(time.Now())
+	switch {
+	case day.Equal(today):
+		return "Today"
+	case day.Equal(today.AddDate(0, 0, -1)):
+		return "Yesterday"
+	case day.Year() != today.Year():
+		return day.Format("Monday, Jan 2 2006")
+	}
+	return day.Format("Monday, Jan 2")
+}
+
+// eventMark renders the kind of an event, and whether a command failed.
+func eventMark(e store.Event) string {
+	if e.Failed {
+		return styles.Error.Render("✗")
+	}
+	switch e.Kind {
+	case store.EventCommand:
+		return styles.Confirm.Render("$")
+	case store.EventRun:
+		return styles.Help.Render("$")
+	case store.EventOutput:
+		return styles.Prompt.Render("▤")
+	}
+	return styles.Selected.Render("●")
+}
+
+// viewTimeline renders the events shown, newest first, under the day they
+// happened on.
+func (m Model) viewTimeline() string {
+	if m.exportingTimesheet {
+		return m.timesheetForm.View()
+	}
+
+	var b strings.Builder
+	b.WriteString(styles.Title.Render("Timeline"))
+	b.WriteString("\n\n")
+
+	day, repo := "All days", "all repositories"
+	if !m.timelineDay.IsZero() {
+		day = describeDay(m.timelineDay)
+	}
+	if m.timelineRepo != "" {
+		repo = filepath.Base(m.timelineRepo)
+	}
+	b.WriteString(styles.Label.Render("  "+day) + styles.Dim.Render(" • "+repo))
+	b.WriteString("\n")
+	switch {
+	case m.timelineSearching:
+		b.WriteString("  " + styles.Prompt.Render("/") + styles.Input.Render(m.timelineQuery) + styles.Cursor.Render("█"))
+		b.WriteString("\n")
+	case m.timelineQuery != "":
+		b.WriteString(styles.Help.Render("  /" + m.timelineQuery))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	events := m.filteredTimeline()
+	if len(events) == 0 {
+		b.WriteString(styles.Dim.Render("  Nothing done"))
+		b.WriteString("\n")
+	}
+
+	visible := max(m.height-12, 5)
+	start := min(m.timelineScroll, max(len(events)-1, 0))
+	end := min(start+visible, len(events))
+	if start > 0 {
+		b.WriteString(styles.Help.Render(fmt.Sprintf("  ↑ %d more", start)))
+		b.WriteString("\n")
+	}
+	var lastDay time.Time
+	for _, e := range events[start:end] {
+		// Every day shown starts with a heading
+		if d := startOfDay(e.Time); m.timelineDay.IsZero() && !d.Equal(lastDay) {
+			b.WriteString(styles.Label.Render("  " + describeDay(d)))
+			b.WriteString("\n")
+			lastDay = d
+		}
+		b.WriteString("  ")
+		b.WriteString(styles.Dim.Render(e.Time.Local().Format("15:04")))
+		b.WriteString(" " + eventMark(e) + " ")
+		if m.timelineRepo == "" {
+			b.WriteString(styles.Branch.Render(padRight(truncate(filepath.Base(e.Repo), 16), 16)) + " ")
+		}
+		b.WriteString(styles.Item.Render(truncate(e.Text, 60)))
+		b.WriteString("\n")
+	}
+	if end < len(events) {
+		b.WriteString(styles.Help.Render(fmt.Sprintf("  ↓ %d more", len(events)-end)))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	if m.timelineNote != "" {
+		b.WriteString(styles.Dim.Render("  " + m.timelineNote))
+		b.WriteString("\n\n")
+	}
+
+	kb := m.config.Keys()
+	if m.timelineSearching {
+		b.WriteString(styles.Help.Render(fmt.Sprintf("enter keep • %s clear", kb.Global.Quit)))
+		return b.String()
+	}
+	b.WriteString(styles.Help.Render(fmt.Sprintf("↑/%s ↓/%s scroll • %s/%s day • %s all days • %s repository • %s search • %s export • %s back",
+		kb.Global.MoveUp, kb.Global.MoveDown, kb.Timeline.Earlier, kb.Timeline.Later, kb.Timeline.AllDays,
+		kb.Timeline.Repo, kb.List.Search, kb.Timeline.Export, kb.Global.Quit)))
+	return b.String()
+}
@@ -0,0 +1,48 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/store"
+	"github.com/ihatemodels/gdev/internal/ui/confirm"
+	"github.com/ihatemodels/gdev/internal/ui/failure"
+)
+
+// askTrust opens the question whether the repository's config with the
+// given hash is trusted. It is asked before anything reads the file.
+func (m *Model) askTrust(hash string) {
+	m.trustPrompt = confirm.New(m.config, "Trust "+m.repoInfo.Repo.Name+"?",
+		"Its "+config.RepoFile+" defines tasks that run commands on your machine.\n"+
+			"Only trust repositories whose authors you trust. Untrusted, gdev ignores\n"+
+			"the file. You are asked again whenever it changes.")
+	m.trustHash = hash
+	m.askingTrust = true
+}
+
+// updateTrust handles input while asking whether the repository is trusted,
+// remembers the answer in its state and goes on to the tasks. In read-only
+// mode the state isn't saved, so the answer only holds for the session.
+func (m Model) updateTrust(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var res confirm.Result
+	m.trustPrompt, res = m.trustPrompt.Update(msg)
+
+	trust := store.TrustAllowed
+	switch res {
+	case confirm.Confirmed:
+	case confirm.Canceled:
+		trust = store.TrustDenied
+	default:
+		return m, nil
+	}
+
+	m.askingTrust = false
+	m.repoInfo.State.Trust = trust
+	m.repoInfo.State.TrustHash = m.trustHash
+	if m.config.ReadOnly() {
+		return m.openTasks()
+	}
+	if err := m.store.SaveRepoState(m.repoInfo.State); err != nil {
+		return m, func() tea.Msg { return failure.Msg{Op: "Save repository trust", Err: err} }
+	}
+	return m.openTasks()
+}
@@ -0,0 +1,44 @@
+package commit
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/ai"
+	"github.com/ihatemodels/gdev/internal/budget"
+	"github.com/ihatemodels/gdev/internal/store"
+	"github.com/ihatemodels/gdev/internal/ui/confirm"
+)
+
+// budgetAllows reports whether a request to backend, cached under key,
+// may go ahead. Past the budget of a provider backend may ask, its own or
+// its fallback's, it asks first (confirm.OverBudget), and once confirmed
+// calls retry, whose request then goes ahead; near it the request goes
+// ahead with a warning in BudgetNote. A cached response costs nothing, so
+// unless fresh is set it always goes ahead.
+func (m Model) budgetAllows(backend ai.Backend, key store.CacheKey, fresh bool, retry func(Model) (Model, tea.Cmd)) (Model, bool) {
+	m.BudgetNote = ""
+	if m.BudgetConfirmed {
+		m.BudgetConfirmed = false
+		return m, true
+	}
+	if _, cached := m.Config.CachedResponse(key); cached && !fresh {
+		return m, true
+	}
+
+	status, err := budget.ForBackend(m.Config, backend)
+	switch {
+	case err != nil:
+		// An unreadable usage log doesn't stop the work
+		return m, true
+	case status.Level == budget.Over:
+		m.Confirm = confirm.OverBudget(m.Config, status)
+		m.Confirming = true
+		m.OnConfirm = func(m Model) (Model, tea.Cmd) {
+			m.BudgetConfirmed = true
+			return retry(m)
+		}
+		return m, false
+	case status.Level == budget.Warn:
+		m.BudgetNote = status.String()
+	}
+	return m, true
+}
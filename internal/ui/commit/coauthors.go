@@ -0,0 +1,114 @@
+package commit
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/git"
+	"github.com/ihatemodels/gdev/internal/ui/selector"
+)
+
+// coAuthorTrailer is the trailer GitHub credits co-authors from.
+const coAuthorTrailer = "Co-authored-by: "
+
+// nameAndEmail matches an author typed as "Name <email>".
+var nameAndEmail = regexp.MustCompile(`^[^<>]+ <[^<>\s]+@[^<>\s]+>$`)
+
+// AuthorsLoadedMsg carries the authors of recent commits.
+type AuthorsLoadedMsg struct {
+	Authors []git.Author
+}
+
+// loadAuthors reads the authors of recent commits, offered as co-authors.
+func (m Model) loadAuthors() tea.Cmd {
+	repoPath := m.RepoPath
+	return func() tea.Msg {
+		// Without history there's no one to suggest; names can still be typed
+		authors, _ := git.RecentAuthors(repoPath, 500)
+		return AuthorsLoadedMsg{Authors: authors}
+	}
+}
+
+// pickCoAuthor opens the list of recent committers. Picking someone adds
+// them as a co-author, or removes them if they already are one.
+func (m Model) pickCoAuthor() (Model, tea.Cmd) {
+	items := make([]selector.Item, len(m.Authors))
+	for i, a := range m.Authors {
+		detail := fmt.Sprintf("%d commits", a.Commits)
+		if m.hasCoAuthor(a.String()) {
+			detail = "✓ co-author • " + detail
+		}
+		items[i] = selector.Item{Value: a.String(), Detail: detail}
+	}
+
+	m.CoAuthorPicker = selector.New(m.Config, "Co-authors", items)
+	m.CoAuthorPicker.AllowCustom = true
+	m.PickingCoAuthor = true
+	m.ErrMsg = ""
+	return m, nil
+}
+
+// updateCoAuthor handles input for the co-author picker.
+func (m Model) updateCoAuthor(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var res selector.Result
+	m.CoAuthorPicker, res = m.CoAuthorPicker.Update(msg)
+
+	switch res {
+	case selector.Selected:
+		m.PickingCoAuthor = false
+		author := m.CoAuthorPicker.Value()
+		if !nameAndEmail.MatchString(author) {
+			m.ErrMsg = "Co-authors are written as: Name <email>"
+			return m, nil
+		}
+		m.toggleCoAuthor(author)
+	case selector.Canceled:
+		m.PickingCoAuthor = false
+	}
+	return m, nil
+}
+
+// hasCoAuthor reports whether author is a co-author of the commit.
+func (m Model) hasCoAuthor(author string) bool {
+	for _, a := range m.CoAuthors {
+		if strings.EqualFold(a, author) {
+			return true
+		}
+	}
+	return false
+}
+
+// toggleCoAuthor adds author as a co-author, or removes them if they are one.
+func (m *Model) toggleCoAuthor(author string) {
+	for i, a := range m.CoAuthors {
+		if strings.EqualFold(a, author) {
+			m.CoAuthors = append(m.CoAuthors[:i:i], m.CoAuthors[i+1:]...)
+			return
+		}
+	}
+	m.CoAuthors = append(m.CoAuthors, author)
+}
+
+// appendCoAuthors adds a Co-authored-by trailer to message for each co-author
+// it doesn't credit yet. Trailers go in a paragraph of their own at the end.
+func appendCoAuthors(message string, coAuthors []string) string {
+	var trailers []string
+	for _, a := range coAuthors {
+		trailer := coAuthorTrailer + a
+		if !strings.Contains(message, trailer) {
+			trailers = append(trailers, trailer)
+		}
+	}
+	if len(trailers) == 0 {
+		return message
+	}
+
+	message = strings.TrimRight(message, "\n")
+	lines := strings.Split(message, "\n")
+	if last := lines[len(lines)-1]; len(lines) == 1 || !strings.HasPrefix(last, coAuthorTrailer) {
+		message += "\n"
+	}
+	return message + "\n" + strings.Join(trailers, "\n")
+}
@@ -0,0 +1,20 @@
+package commit
+
+import "testing"
+
+func TestAppendCoAuthors(t *testing.T) {
+	jane := "Jane Doe <jane@example.com>"
+	tests := []struct {
+		message, want string
+	}{
+		{"fix: wrap long lines", "fix: wrap long lines\n\nCo-authored-by: " + jane},
+		{"fix: wrap long lines\n\nKeeps words whole.\n", "fix: wrap long lines\n\nKeeps words whole.\n\nCo-authored-by: " + jane},
+		{"fix: x\n\nCo-authored-by: Bob <bob@example.com>", "fix: x\n\nCo-authored-by: Bob <bob@example.com>\nCo-authored-by: " + jane},
+		{"fix: x\n\nCo-authored-by: " + jane, "fix: x\n\nCo-authored-by: " + jane},
+	}
+	for _, tt := range tests {
+		if got := appendCoAuthors(tt.message, []string{jane}); got != tt.want {
+			t.Errorf("appendCoAuthors(%q) = %q; want %q", tt.message, got, tt.want)
+		}
+	}
+}
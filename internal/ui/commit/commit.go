@@ -1,19 +1,32 @@
-// Package commit provides a TUI component for smart commits using Claude.
+// Package commit provides a TUI component for smart commits with an AI
+// written message.
 package commit
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
-	"runtime"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/ihatemodels/gdev/internal/ai"
+	"github.com/ihatemodels/gdev/internal/budget"
 	"github.com/ihatemodels/gdev/internal/config"
 	"github.com/ihatemodels/gdev/internal/embedded"
+	"github.com/ihatemodels/gdev/internal/git"
+	"github.com/ihatemodels/gdev/internal/sshagent"
+	"github.com/ihatemodels/gdev/internal/ui/confirm"
+	"github.com/ihatemodels/gdev/internal/ui/diffview"
+	"github.com/ihatemodels/gdev/internal/ui/failure"
+	"github.com/ihatemodels/gdev/internal/ui/form"
+	"github.com/ihatemodels/gdev/internal/ui/selector"
 	"github.com/ihatemodels/gdev/internal/ui/styles"
 	"github.com/ihatemodels/gdev/internal/ui/terminal"
+	"github.com/ihatemodels/gdev/internal/ui/textedit"
+	"github.com/mattn/go-runewidth"
 )
 
 // State represents the current state of the commit flow.
@@ -22,8 +35,13 @@ type State int
 const (
 	StateChecking State = iota
 	StateNoChanges
+	StateInProgress // a merge or rebase stopped partway
+	StateResolving  // continuing or aborting it
+	StateSelecting  // choosing the files to commit
 	StateGenerating
+	StateSplit // reviewing a proposed split into several commits
 	StateEditing
+	StateReviewing // confirming the message, files and signing
 	StateCommitting
 	StateDone
 	StateError
@@ -37,11 +55,21 @@ type CommitDoneMsg struct {
 	Err error
 }
 
+// EditorDoneMsg signals that a commit written in the user's editor finished.
+type EditorDoneMsg struct {
+	Err     error
+	Subject string // subject of the new commit
+}
+
 // CheckDoneMsg signals that the check for changes completed.
 type CheckDoneMsg struct {
 	HasChanges bool
 	Diff       string
-	Err        error
+	Files      []git.StatusFile
+
+	// An interrupted merge or rebase, and the files still in conflict
+	InProgress git.Operation
+	Conflicts  []string
 }
 
 // Model represents the commit view state.
@@ -51,18 +79,95 @@ type Model struct {
 
 	State    State
 	ErrMsg   string
+	Fallback string // why the message was written in the git editor instead
+	Offline  string // why the message was made from the diffstat instead
+	FellBack string // which backend wrote the message instead, and why (see ai.FallbackNote)
 	Diff     string // git diff output for context
 
+	// LintWarned is set after submitting a message that breaks the lint
+	// rules, so submitting again commits it anyway
+	LintWarned bool
+
+	// How the commit will be signed, shown when reviewing it
+	Signing git.Signing
+
 	// Commit message editing
-	Subject       string // first line
+	Type          string // conventional commit type, "" for none
+	Emoji         string // gitmoji picked instead of the type's, "" for none
+	EmojiPicked   bool
+	Scope         string
+	Subject       string // first line, after the type and scope
 	Body          string // rest of the message
-	EditingField  int    // 0 = subject, 1 = body
+	EditingField  int    // one of the field constants
 	CursorPos     int    // cursor position within current field
 	BodyScrollPos int    // scroll position in body
 
 	// Terminal for running commands
 	Terminal terminal.Model
 
+	// Diff viewer for reviewing changes while editing
+	DiffView diffview.Model
+	ShowDiff bool
+
+	// Diff shown beside the message editor, nil until loaded
+	Preview       []string
+	ShowPreview   bool
+	PreviewScroll int
+
+	// Scopes of recent commits, suggested in the scope field
+	Scopes []string
+
+	// Recent committers, and those picked as co-authors as "Name <email>"
+	Authors         []git.Author
+	CoAuthors       []string
+	CoAuthorPicker  selector.Model
+	PickingCoAuthor bool
+
+	// Changed files and which of them to stage and commit. With StagedOnly
+	// set, Files are the staged ones of Changes and the index is committed
+	// as it is.
+	Changes    []git.StatusFile
+	StagedOnly bool
+	Files      []git.StatusFile
+	Selected   []bool
+	FileCursor int
+	FileScroll int
+
+	// Interrupted merge or rebase, offered to continue or abort
+	Operation git.Operation
+	Conflicts []string
+
+	// Dialog confirming an action, and the action to take once confirmed
+	Confirm    confirm.Model
+	Confirming bool
+	OnConfirm  func(m Model) (Model, tea.Cmd)
+
+	// Regenerating the message while editing: the hint asked for, whether
+	// to skip the cache, and the new message waiting to replace the current one
+	Regenerating bool
+	BypassCache  bool
+	AskingHint   bool
+	HintForm     form.Model
+	NewSubject   string
+	NewBody      string
+	NewFellBack  string
+
+	// Budget of the AI provider: a warning when it is nearly used up, and
+	// whether going past it was confirmed for the next request
+	BudgetNote      string
+	BudgetConfirmed bool
+
+	// Temporary files holding the messages being committed
+	MessageFiles []string
+
+	// Commits the selected files are split into, as proposed by the AI
+	// backend, and the form editing the subject of one of them
+	Splitting    bool // the proposal is being generated
+	Split        []splitCommit
+	SplitCursor  int
+	SplitForm    form.Model
+	EditingSplit bool
+
 	Width  int
 	Height int
 }
@@ -70,9 +175,10 @@ type Model struct {
 // New creates a new commit model.
 func New(cfg *config.Config, repoPath string) Model {
 	return Model{
-		Config:   cfg,
-		RepoPath: repoPath,
-		State:    StateChecking,
+		Config:     cfg,
+		RepoPath:   repoPath,
+		State:      StateChecking,
+		StagedOnly: cfg.Settings.CommitStagedOnly,
 	}
 }
 
@@ -84,23 +190,35 @@ func (m *Model) SetSize(width, height int) {
 
 // Init implements tea.Model.
 func (m Model) Init() tea.Cmd {
-	return m.checkForChanges()
+	return tea.Batch(m.checkForChanges(), m.loadScopes(), m.loadAuthors())
 }
 
 func (m Model) checkForChanges() tea.Cmd {
 	repoPath := m.RepoPath
-	return func() tea.Msg {
+	return failure.Cmd("Check for changes", func() (tea.Msg, error) {
+		// Committing everything in the middle of a merge or rebase would
+		// conclude it with whatever is in the tree, conflict markers included
+		op, err := git.InProgress(repoPath)
+		if err != nil {
+			return nil, err
+		}
+		if op != git.OpNone {
+			conflicts, err := git.ConflictedFiles(repoPath)
+			if err != nil {
+				return nil, err
+			}
+			return CheckDoneMsg{HasChanges: true, InProgress: op, Conflicts: conflicts}, nil
+		}
+
 		// Check if there are any changes
-		cmd := exec.Command("git", "status", "--porcelain")
-		cmd.Dir = repoPath
-		out, err := cmd.Output()
+		files, err := git.Status(repoPath)
 		if err != nil {
-			return CheckDoneMsg{Err: err}
+			return nil, err
 		}
 
-		hasChanges := len(strings.TrimSpace(string(out))) > 0
+		hasChanges := len(files) > 0
 		if !hasChanges {
-			return CheckDoneMsg{HasChanges: false}
+			return CheckDoneMsg{HasChanges: false}, nil
 		}
 
 		// Get the diff for context
@@ -108,8 +226,8 @@ func (m Model) checkForChanges() tea.Cmd {
 		diffCmd.Dir = repoPath
 		diffOut, _ := diffCmd.Output()
 
-		return CheckDoneMsg{HasChanges: true, Diff: string(diffOut)}
-	}
+		return CheckDoneMsg{HasChanges: true, Diff: string(diffOut), Files: files}, nil
+	})
 }
 
 // Update implements tea.Model.
@@ -119,12 +237,48 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.Width = msg.Width
 		m.Height = msg.Height
 		m.Terminal.SetSize(msg.Width, msg.Height)
+		m.DiffView.SetSize(msg.Width-4, msg.Height-2)
 		return m, nil
 
-	case CheckDoneMsg:
+	case ScopesLoadedMsg:
+		m.Scopes = msg.Scopes
+		return m, nil
+
+	case AuthorsLoadedMsg:
+		m.Authors = msg.Authors
+		return m, nil
+
+	case PreviewLoadedMsg:
+		m.Preview = msg.Lines
+		m.PreviewScroll = 0
+		return m, nil
+
+	case diffview.FilesLoadedMsg, diffview.FileDiffLoadedMsg:
+		var cmd tea.Cmd
+		m.DiffView, cmd, _ = m.DiffView.Update(msg)
+		return m, cmd
+
+	case failure.Msg:
+		// The app shows the error screen; a retry leaves this state
+		m.State = StateError
+		m.ErrMsg = msg.Error()
+		return m, nil
+
+	case EditorDoneMsg:
 		if msg.Err != nil {
 			m.State = StateError
-			m.ErrMsg = msg.Err.Error()
+			m.ErrMsg = "Commit failed: " + msg.Err.Error()
+			return m, nil
+		}
+		m.Type, m.Scope, m.Subject = "", "", msg.Subject
+		m.State = StateDone
+		return m, nil
+
+	case CheckDoneMsg:
+		if msg.InProgress != git.OpNone {
+			m.State = StateInProgress
+			m.Operation = msg.InProgress
+			m.Conflicts = msg.Conflicts
 			return m, nil
 		}
 		if !msg.HasChanges {
@@ -132,25 +286,33 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		m.Diff = msg.Diff
-		return m.startGenerating()
+		m.Changes = msg.Files
+		m.setFiles()
+		m.State = StateSelecting
+		return m, nil
 
 	case terminal.TickMsg:
-		if m.State == StateGenerating || m.State == StateCommitting {
+		if m.State == StateGenerating || m.State == StateCommitting || m.State == StateResolving {
 			var cmd tea.Cmd
 			m.Terminal, cmd = m.Terminal.Update(msg)
-
-			// Check if done
-			if !m.Terminal.Running {
-				if m.State == StateGenerating {
-					return m.handleGenerateDone()
-				} else if m.State == StateCommitting {
-					return m.handleCommitDone()
-				}
-			}
 			return m, cmd
 		}
 		return m, nil
 
+	case terminal.CommandDoneMsg:
+		if msg.ID != m.Terminal.ID {
+			return m, nil
+		}
+		switch m.State {
+		case StateGenerating:
+			return m.handleGenerateDone(msg)
+		case StateCommitting:
+			return m.handleCommitDone(msg)
+		case StateResolving:
+			return m.handleResolveDone(msg)
+		}
+		return m, nil
+
 	case tea.KeyMsg:
 		return m.handleKey(msg)
 	}
@@ -158,32 +320,82 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m Model) startGenerating() (Model, tea.Cmd) {
+// backend returns the AI backend chosen in settings, or why it can't be used.
+func (m Model) backend() (ai.Backend, error) {
+	b, err := budget.Backend(m.Config)
+	if err != nil {
+		return nil, err
+	}
+	return b, b.Check()
+}
+
+// startGenerating asks the AI backend to write the message for the selected
+// files, following hint if it is set. A cached message for the same request
+// is reused unless fresh is set.
+func (m Model) startGenerating(hint string, fresh bool) (Model, tea.Cmd) {
+	backend, err := m.backend()
+	if err != nil {
+		return m.writeOffline(err.Error())
+	}
+
+	// Build the prompt with git context
+	prompt := m.buildCommitPrompt(hint)
+
+	regenerating := m.Regenerating
+	m, ok := m.budgetAllows(backend, backend.CacheKey(prompt), fresh, func(m Model) (Model, tea.Cmd) {
+		m.Regenerating = regenerating
+		return m.startGenerating(hint, fresh)
+	})
+	if !ok {
+		m.Regenerating = false
+		return m, nil
+	}
+
 	m.State = StateGenerating
 	m.Terminal = terminal.New(m.Config, "Generating commit message...")
 	m.Terminal.Dir = m.RepoPath
 	m.Terminal.SetSize(m.Width, m.Height)
 
-	// Build the prompt with git context
-	prompt := m.buildCommitPrompt()
-
-	// Run claude with the embedded prompt
-	cmd := m.Terminal.RunCommand("claude", "-p", prompt)
+	cmd := m.Terminal.RunCachedFunc(backend.CacheKey(prompt), fresh, ai.Describe(backend), func(ctx context.Context, w io.Writer) error {
+		return backend.Generate(ctx, prompt, w)
+	})
 	return m, cmd
 }
 
-// buildCommitPrompt constructs the commit message prompt with git context.
-func (m Model) buildCommitPrompt() string {
-	// Get git context
-	gitDiff := runGitCommand(m.RepoPath, "diff", "HEAD")
-	gitStatus := runGitCommand(m.RepoPath, "status", "--short")
+// buildCommitPrompt constructs the commit message prompt with git context
+// and the user's hint, if any. A template in prompts/commit.md replaces the
+// built-in prompt; if it uses placeholders such as {{diff}}, the context goes
+// only where they are.
+func (m Model) buildCommitPrompt(hint string) string {
+	// Get git context for the selected files only
+	paths := m.selectedPaths()
+	gitDiff := runGitCommand(m.RepoPath, append([]string{"diff", m.diffBase(), "--"}, paths...)...)
+	gitStatus := runGitCommand(m.RepoPath, append([]string{"status", "--short", "--"}, paths...)...)
 	gitLog := runGitCommand(m.RepoPath, "log", "--oneline", "-5")
 
-	// Get the embedded prompt template
-	promptTemplate, err := embedded.GetCommandPrompt("generate-commit-msg")
-	if err != nil {
-		// Fallback to a simple prompt if embedded fails
-		promptTemplate = "Generate a commit message for these changes."
+	promptTemplate, err := m.Config.PromptOverride("commit")
+	if err == nil && promptTemplate != "" {
+		vars := map[string]string{
+			"diff":   gitDiff,
+			"status": gitStatus,
+			"log":    gitLog,
+			"branch": runGitCommand(m.RepoPath, "rev-parse", "--abbrev-ref", "HEAD"),
+			"files":  strings.Join(paths, "\n"),
+			"hint":   hint,
+		}
+		if prompt, used := embedded.Expand(promptTemplate, vars); used {
+			if _, usesHint := embedded.Expand(promptTemplate, map[string]string{"hint": ""}); hint != "" && !usesHint {
+				prompt += "\n\nAdditional instructions from the user:\n" + hint
+			}
+			return prompt
+		}
+	} else {
+		// Get the embedded prompt template
+		promptTemplate, err = embedded.GetCommandPrompt("generate-commit-msg")
+		if err != nil {
+			// Fallback to a simple prompt if embedded fails
+			promptTemplate = "Generate a commit message for these changes."
+		}
 	}
 
 	// Build context section
@@ -199,6 +411,9 @@ func (m Model) buildCommitPrompt() string {
 %s
 
 `, gitDiff, gitStatus, gitLog)
+	if hint != "" {
+		context += fmt.Sprintf("- Additional instructions from the user:\n%s\n\n", hint)
+	}
 
 	return context + promptTemplate
 }
@@ -214,128 +429,148 @@ func runGitCommand(dir string, args ...string) string {
 	return strings.TrimSpace(string(out))
 }
 
-func (m Model) handleGenerateDone() (Model, tea.Cmd) {
-	if m.Terminal.Err != nil {
-		m.State = StateError
-		m.ErrMsg = "Failed to generate commit message: " + m.Terminal.Err.Error()
-		return m, nil
+func (m Model) handleGenerateDone(msg terminal.CommandDoneMsg) (Model, tea.Cmd) {
+	if m.Splitting {
+		return m.handleSplitDone(msg)
+	}
+	if m.Regenerating {
+		return m.handleRegenerateDone(msg)
+	}
+	output, err := generated(msg)
+	if err != nil {
+		return m.writeOffline("generating it failed: " + err.Error())
 	}
 
-	// Parse the output into subject and body
-	output := strings.TrimSpace(m.Terminal.GetRawOutput())
-
-	// Extract the actual commit message from Claude's response
 	subject, body := parseCommitMessage(output)
+	m.FellBack = ai.FallbackNote(output)
 
-	m.Subject = subject
+	m.Type, m.Scope, m.Subject = splitSubject(subject)
 	m.Body = body
 
 	m.State = StateEditing
-	m.EditingField = 0
+	m.EditingField = fieldSubject
 	m.CursorPos = len(m.Subject)
 
 	return m, nil
 }
 
-// parseCommitMessage extracts a commit message from Claude's output.
-// It handles markdown code blocks and preamble text.
+// parseCommitMessage reads a commit message from the backend's output: the
+// result the prompt asks for (see ai.Result), its first line the subject and
+// the rest the body.
 func parseCommitMessage(output string) (subject, body string) {
-	lines := strings.Split(output, "\n")
-
-	// Commit type prefixes to look for
-	prefixes := []string{"feat:", "fix:", "refactor:", "docs:", "style:", "test:", "chore:"}
-
-	// Find the line that starts with a commit type
-	startIdx := -1
-	for i, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		// Skip code block delimiters
-		if strings.HasPrefix(trimmed, "```") {
-			continue
-		}
-		// Check if line starts with a commit type
-		for _, prefix := range prefixes {
-			if strings.HasPrefix(strings.ToLower(trimmed), prefix) {
-				startIdx = i
-				break
-			}
-		}
-		if startIdx != -1 {
-			break
-		}
-	}
-
-	// If no commit type found, fall back to stripping code blocks and taking first line
-	if startIdx == -1 {
-		cleaned := stripCodeBlocks(output)
-		parts := strings.SplitN(cleaned, "\n", 2)
-		subject = strings.TrimSpace(parts[0])
-		if len(parts) > 1 {
-			body = strings.TrimSpace(parts[1])
-		}
-		return
-	}
+	subject, body, _ = strings.Cut(ai.Result(output), "\n")
+	return strings.TrimSpace(subject), strings.TrimSpace(body)
+}
 
-	// Extract from the commit type line onwards
-	var resultLines []string
-	for i := startIdx; i < len(lines); i++ {
-		trimmed := strings.TrimSpace(lines[i])
-		// Stop at code block end or obvious non-commit content
-		if strings.HasPrefix(trimmed, "```") {
-			continue
+func (m Model) handleCommitDone(msg terminal.CommandDoneMsg) (Model, tea.Cmd) {
+	m.removeMessageFiles()
+	if msg.ExitCode != 0 {
+		m.State = StateError
+		m.ErrMsg = "Commit failed: " + msg.Err.Error()
+		if len(m.Split) > 0 {
+			m.ErrMsg += "; the commits before the failed one were made"
 		}
-		resultLines = append(resultLines, lines[i])
-	}
-
-	result := strings.TrimSpace(strings.Join(resultLines, "\n"))
-	parts := strings.SplitN(result, "\n", 2)
-
-	subject = strings.TrimSpace(parts[0])
-	if len(parts) > 1 {
-		body = strings.TrimSpace(parts[1])
+		return m, nil
 	}
 
-	return
+	m.State = StateDone
+	return m, nil
 }
 
-// stripCodeBlocks removes markdown code block delimiters from the output.
-func stripCodeBlocks(s string) string {
-	lines := strings.Split(s, "\n")
-	var result []string
-
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		// Skip lines that are just code block delimiters
-		if strings.HasPrefix(trimmed, "```") {
-			continue
-		}
-		result = append(result, line)
-	}
+// resolve continues or aborts the interrupted operation with git args.
+func (m Model) resolve(title string, args ...string) (Model, tea.Cmd) {
+	m.State = StateResolving
+	m.ErrMsg = ""
+	m.Terminal = terminal.New(m.Config, title)
+	m.Terminal.Dir = m.RepoPath
+	m.Terminal.SetSize(m.Width, m.Height)
 
-	return strings.TrimSpace(strings.Join(result, "\n"))
+	cmd := m.Terminal.RunMutatingCommand("git", args...)
+	return m, cmd
 }
 
-func (m Model) handleCommitDone() (Model, tea.Cmd) {
-	if m.Terminal.Err != nil {
+func (m Model) handleResolveDone(msg terminal.CommandDoneMsg) (Model, tea.Cmd) {
+	if msg.ExitCode != 0 {
 		m.State = StateError
-		m.ErrMsg = "Commit failed: " + m.Terminal.Err.Error()
+		m.ErrMsg = fmt.Sprintf("git %s failed: %s", m.Operation, msg.Err.Error())
 		return m, nil
 	}
 
-	m.State = StateDone
-	return m, nil
+	// A rebase may stop again at the next commit; otherwise carry on as usual
+	m.State = StateChecking
+	return m, m.checkForChanges()
 }
 
 func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	key := msg.String()
 	kb := m.Config.Keys()
 
+	// Answers to credential prompts are typed into the terminal, as are
+	// keys while selecting its output
+	if m.Terminal.Prompting() || (m.Terminal.Selecting() && m.showsTerminal()) {
+		var cmd tea.Cmd
+		m.Terminal, cmd = m.Terminal.Update(msg)
+		return m, cmd
+	}
+
+	if m.Confirming {
+		var res confirm.Result
+		m.Confirm, res = m.Confirm.Update(msg)
+		switch res {
+		case confirm.Confirmed:
+			m.Confirming = false
+			onConfirm := m.OnConfirm
+			m.OnConfirm = nil
+			return onConfirm(m)
+		case confirm.Canceled:
+			m.Confirming = false
+			m.OnConfirm = nil
+		}
+		return m, nil
+	}
+
+	if m.AskingHint {
+		return m.updateHint(msg)
+	}
+
+	if m.PickingCoAuthor {
+		return m.updateCoAuthor(msg)
+	}
+
+	if m.EditingSplit {
+		return m.updateSplitForm(msg)
+	}
+
+	if m.ShowDiff {
+		var cmd tea.Cmd
+		var res diffview.Result
+		m.DiffView, cmd, res = m.DiffView.Update(msg)
+		if res == diffview.Closed {
+			m.ShowDiff = false
+		}
+		return m, cmd
+	}
+
 	// Global: escape to go back
 	if config.MatchesAny(key, kb.Global.Quit, kb.Global.QuitAlt) {
+		if m.State == StateSplit {
+			m.State = StateSelecting
+			m.Split = nil
+			return m, nil
+		}
+		if m.State == StateReviewing {
+			m.State = StateEditing
+			return m, nil
+		}
 		if m.State == StateEditing {
 			// Confirm cancel?
 			return m, func() tea.Msg { return BackToMenuMsg{} }
 		}
+		// Nothing would see a message still being generated; a commit or
+		// rebase is left to finish, and gdev asks about it before quitting
+		if m.State == StateGenerating {
+			m.Terminal.Cancel()
+		}
 		return m, func() tea.Msg { return BackToMenuMsg{} }
 	}
 
@@ -346,7 +581,34 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, func() tea.Msg { return BackToMenuMsg{} }
 		}
 
-	case StateGenerating, StateCommitting:
+	case StateSelecting:
+		return m.handleSelectKey(msg)
+
+	case StateSplit:
+		return m.handleSplitKey(msg)
+
+	case StateInProgress:
+		switch {
+		case config.Matches(key, kb.Commit.Continue):
+			if len(m.Conflicts) > 0 {
+				m.ErrMsg = "Resolve and stage the conflicted files first"
+				return m, nil
+			}
+			return m.resolve(fmt.Sprintf("Continuing %s...", m.Operation), m.Operation.ContinueArgs()...)
+		case config.Matches(key, kb.Commit.Abort):
+			m.Confirm = confirm.New(m.Config, fmt.Sprintf("Abort the %s?", m.Operation),
+				"The repository goes back to where it was before it started.")
+			m.Confirm.Destructive = true
+			m.Confirming = true
+			m.OnConfirm = func(m Model) (Model, tea.Cmd) {
+				return m.resolve(fmt.Sprintf("Aborting %s...", m.Operation), m.Operation.AbortArgs()...)
+			}
+		}
+
+	case StateGenerating, StateCommitting, StateResolving:
+		if m.streaming() {
+			return m.handleStreamKey(msg)
+		}
 		// Handle terminal scrolling
 		var cmd tea.Cmd
 		m.Terminal, cmd = m.Terminal.Update(msg)
@@ -354,6 +616,9 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	case StateEditing:
 		return m.handleEditKey(msg)
+
+	case StateReviewing:
+		return m.handleReviewKey(msg)
 	}
 
 	return m, nil
@@ -363,90 +628,131 @@ func (m Model) handleEditKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	key := msg.String()
 	kb := m.Config.Keys()
 
+	// Submitting twice in a row commits despite the lint problems
+	lintWarned := m.LintWarned
+	m.LintWarned = false
+
+	// Review the changes being committed
+	if config.Matches(key, kb.Diff.Open) {
+		m.DiffView = diffview.New(m.Config, "Changes", m.RepoPath, m.diffBase())
+		m.DiffView.SetSize(m.Width-4, m.Height-2)
+		m.ShowDiff = true
+		return m, m.DiffView.Init()
+	}
+
+	if config.Matches(key, kb.Commit.TogglePreview) {
+		return m.togglePreview()
+	}
+	if config.Matches(key, kb.Commit.Regenerate) {
+		return m.askHint(false)
+	}
+	if config.Matches(key, kb.Commit.RegenerateFresh) {
+		return m.askHint(true)
+	}
+	if config.Matches(key, kb.Commit.CoAuthor) {
+		return m.pickCoAuthor()
+	}
+	if m.ShowPreview {
+		switch {
+		case config.Matches(key, kb.List.PageUp):
+			m.scrollPreview(-m.previewHeight() / 2)
+			return m, nil
+		case config.Matches(key, kb.List.PageDown):
+			m.scrollPreview(m.previewHeight() / 2)
+			return m, nil
+		}
+	}
+
 	// Submit commit
 	if config.Matches(key, kb.Form.Submit) {
 		if m.Subject == "" {
 			m.ErrMsg = "Subject is required"
 			return m, nil
 		}
-		return m.doCommit()
+		if len(lintMessage(m.message(), m.Config.Settings.CommitLint)) > 0 && !lintWarned {
+			m.ErrMsg = fmt.Sprintf("Fix the message, or press %s again to commit it anyway", kb.Form.Submit)
+			m.LintWarned = true
+			return m, nil
+		}
+		return m.review()
 	}
 
 	// Navigate between fields
 	if config.Matches(key, kb.Form.NextField) || key == "down" {
-		if m.EditingField == 0 {
-			m.EditingField = 1
-			m.CursorPos = len(m.Body)
-		}
+		m.moveField(1)
 		return m, nil
 	}
 
 	if config.Matches(key, kb.Form.PrevField) || key == "up" {
-		if m.EditingField == 1 {
-			m.EditingField = 0
-			m.CursorPos = len(m.Subject)
-		}
+		m.moveField(-1)
 		return m, nil
 	}
 
 	// Handle text input
-	if m.EditingField == 0 {
+	switch m.EditingField {
+	case fieldEmoji:
+		return m.handleEmojiKey(msg)
+	case fieldType, fieldScope:
+		return m.handlePrefixKey(msg)
+	case fieldSubject:
 		m.Subject, m.CursorPos = handleTextEdit(m.Subject, m.CursorPos, msg)
-		// Limit subject to 72 chars
-		if len(m.Subject) > 72 {
-			m.Subject = m.Subject[:72]
-			if m.CursorPos > 72 {
-				m.CursorPos = 72
-			}
-		}
-	} else {
+		// Limit the subject line to 72 chars
+		m.clampSubject()
+	default:
 		m.Body, m.CursorPos = handleTextEdit(m.Body, m.CursorPos, msg)
 	}
 
 	return m, nil
 }
 
+// moveField moves to the next field of the editor, or the previous with
+// delta -1, skipping the gitmoji picker when it is off.
+func (m *Model) moveField(delta int) {
+	field := m.EditingField + delta
+	if field == fieldEmoji && !m.Config.Settings.Gitmoji.Enabled {
+		field += delta
+	}
+	if field < fieldType || field > fieldBody {
+		return
+	}
+	m.EditingField = field
+	m.CursorPos = len(m.fieldText())
+}
+
+// fieldText returns the text of the field being edited.
+func (m Model) fieldText() string {
+	switch m.EditingField {
+	case fieldScope:
+		return m.Scope
+	case fieldSubject:
+		return m.Subject
+	case fieldBody:
+		return m.Body
+	}
+	return ""
+}
+
 func handleTextEdit(text string, cursor int, msg tea.KeyMsg) (string, int) {
 	key := msg.String()
 
 	switch key {
 	case "backspace":
-		if cursor > 0 {
-			text = text[:cursor-1] + text[cursor:]
-			cursor--
-		}
+		text, cursor = textedit.Backspace(text, cursor)
 	case "delete":
-		if cursor < len(text) {
-			text = text[:cursor] + text[cursor+1:]
-		}
+		text = textedit.Delete(text, cursor)
 	case "left":
-		if cursor > 0 {
-			cursor--
-		}
+		cursor = textedit.Left(text, cursor)
 	case "right":
-		if cursor < len(text) {
-			cursor++
-		}
+		cursor = textedit.Right(text, cursor)
 	case "home", "ctrl+a":
-		// Go to start of current line
-		for cursor > 0 && text[cursor-1] != '\n' {
-			cursor--
-		}
+		cursor = textedit.LineStart(text, cursor)
 	case "end", "ctrl+e":
-		// Go to end of current line
-		for cursor < len(text) && text[cursor] != '\n' {
-			cursor++
-		}
+		cursor = textedit.LineEnd(text, cursor)
 	case "enter":
-		text = text[:cursor] + "\n" + text[cursor:]
-		cursor++
-	case "space":
-		text = text[:cursor] + " " + text[cursor:]
-		cursor++
+		text, cursor = textedit.Insert(text, cursor, "\n")
 	default:
-		if len(key) == 1 && key[0] >= 32 && key[0] < 127 {
-			text = text[:cursor] + key + text[cursor:]
-			cursor++
+		if typed, ok := textedit.Typed(msg); ok {
+			text, cursor = textedit.Insert(text, cursor, typed)
 		}
 	}
 
@@ -454,62 +760,114 @@ func handleTextEdit(text string, cursor int, msg tea.KeyMsg) (string, int) {
 }
 
 func (m Model) doCommit() (Model, tea.Cmd) {
+	file, err := m.writeMessageFile(m.message())
+	if err != nil {
+		m.State = StateError
+		m.ErrMsg = "Commit failed: " + err.Error()
+		return m, nil
+	}
+	m.MessageFiles = []string{file}
+
 	m.State = StateCommitting
 	m.Terminal = terminal.New(m.Config, "Committing changes...")
 	m.Terminal.Dir = m.RepoPath
 	m.Terminal.SetSize(m.Width, m.Height)
 
-	// Build commit message
-	commitMsg := m.Subject
+	agent := m.sshAgent()
+	cmd := m.Terminal.RunMutatingCommands(agent.Env(os.Environ()), agent.String(), m.commitCommands("-F", file)...)
+	return m, cmd
+}
+
+// writeMessageFile writes a commit message to a temporary file for git
+// commit -F, which keeps it exactly as written. The file is removed once
+// the commit is done.
+func (m Model) writeMessageFile(message string) (string, error) {
+	f, err := os.CreateTemp("", "gdev-commit-*.txt")
+	if err != nil {
+		return "", fmt.Errorf("writing the message: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(message + "\n"); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("writing the message: %w", err)
+	}
+	return f.Name(), nil
+}
+
+// removeMessageFiles removes the message files of the commits made.
+func (m *Model) removeMessageFiles() {
+	for _, f := range m.MessageFiles {
+		// Best effort: the temporary directory is cleaned up eventually
+		_ = os.Remove(f)
+	}
+	m.MessageFiles = nil
+}
+
+// message returns the commit message being edited, with co-author trailers.
+func (m Model) message() string {
+	msg := m.fullSubject()
 	if m.Body != "" {
-		commitMsg += "\n\n" + m.Body
+		msg += "\n\n" + m.Body
 	}
+	return appendCoAuthors(msg, m.CoAuthors)
+}
 
-	// Build the git command using HEREDOC to preserve newlines
-	gitCmd := fmt.Sprintf(`git add -A && git commit -m "$(cat <<'COMMITMSG'
-%s
-COMMITMSG
-)"`, commitMsg)
-
-	// On Linux, ensure ssh-agent is available for commit signing
-	var cmd tea.Cmd
-	if runtime.GOOS == "linux" && os.Getenv("SSH_AUTH_SOCK") == "" {
-		// Try to find existing ssh-agent socket or start new one
-		sshSetup := findOrStartSSHAgent()
-		cmd = m.Terminal.RunCommand("bash", "-c", sshSetup+gitCmd)
-	} else {
-		cmd = m.Terminal.RunCommand("bash", "-c", gitCmd)
+// commitWithEditor commits the selected files with the user's git editor
+// (core.editor) when no message could be generated. git fills in
+// commit.template, and the diff unless commit.verbose is set to false.
+func (m Model) commitWithEditor(reason string) (Model, tea.Cmd) {
+	m.Fallback = reason
+
+	var flags []string
+	if runGitCommand(m.RepoPath, "config", "commit.verbose") == "" {
+		flags = append(flags, "--verbose")
+	}
+	commands := m.commitCommands(flags...)
+	agent := m.sshAgent()
+
+	if m.Config.DryRun() {
+		m.State = StateCommitting
+		m.Terminal = terminal.New(m.Config, "Committing with editor...")
+		m.Terminal.Dir = m.RepoPath
+		m.Terminal.SetSize(m.Width, m.Height)
+		cmd := m.Terminal.RunMutatingCommands(agent.Env(os.Environ()), agent.String(), commands...)
+		return m, cmd
 	}
 
-	return m, cmd
+	repoPath := m.RepoPath
+	m.State = StateCommitting
+	return m, terminal.ExecMutatingCommands(m.Config, repoPath, agent.Env(os.Environ()), func(err error) tea.Msg {
+		return EditorDoneMsg{Err: err, Subject: runGitCommand(repoPath, "log", "-1", "--format=%s")}
+	}, commands...)
+}
+
+// commitCommands returns the git commands that stage and commit only the
+// selected files, passing flags to git commit. In staged-only mode they
+// commit the index as it is.
+func (m Model) commitCommands(flags ...string) [][]string {
+	if m.StagedOnly {
+		return [][]string{append([]string{"git", "commit"}, flags...)}
+	}
+	return stageAndCommit(m.selectedFiles(), flags...)
+}
+
+// stageAndCommit returns the git commands that stage and commit only files,
+// passing flags to git commit.
+func stageAndCommit(files []git.StatusFile, flags ...string) [][]string {
+	var commands [][]string
+	// Without paths, git add -A would stage everything
+	if paths := addPaths(files); len(paths) > 0 {
+		commands = append(commands, append([]string{"git", "add", "-A", "--"}, paths...))
+	}
+	commit := append(append([]string{"git", "commit"}, flags...), "--")
+	return append(commands, append(commit, commitPaths(files)...))
 }
 
-// findOrStartSSHAgent returns a bash snippet that ensures ssh-agent is available.
-// It tries common socket locations before starting a new agent.
-func findOrStartSSHAgent() string {
-	return `
-# Try to find existing ssh-agent socket
-if [ -z "$SSH_AUTH_SOCK" ]; then
-    # Check common socket locations
-    for sock in \
-        "$XDG_RUNTIME_DIR/ssh-agent.socket" \
-        "$XDG_RUNTIME_DIR/keyring/ssh" \
-        "$XDG_RUNTIME_DIR/gcr/ssh" \
-        /tmp/ssh-*/agent.*; do
-        if [ -S "$sock" ]; then
-            export SSH_AUTH_SOCK="$sock"
-            break
-        fi
-    done
-fi
-
-# If still no agent, start one and add keys
-if [ -z "$SSH_AUTH_SOCK" ]; then
-    eval $(ssh-agent -s) > /dev/null
-    ssh-add 2>/dev/null
-fi
-
-`
+// sshAgent finds an ssh-agent for commit signing, as configured by the
+// ssh_agent setting. The commit output starts with the socket it found.
+func (m Model) sshAgent() sshagent.Agent {
+	st := m.Config.Settings
+	return sshagent.Find(st.SSHAgent, st.SSHAgentCommand)
 }
 
 // View implements tea.Model.
@@ -518,15 +876,50 @@ func (m Model) View() string {
 		return "Loading..."
 	}
 
+	if m.ShowDiff {
+		return lipgloss.NewStyle().Padding(1, 2).Render(m.DiffView.View())
+	}
+
+	if m.Confirming {
+		return m.Confirm.ViewCentered(m.Width, m.Height)
+	}
+
 	switch m.State {
 	case StateChecking:
 		return m.viewCentered(m.viewChecking())
 	case StateNoChanges:
 		return m.viewCentered(m.viewNoChanges())
+	case StateInProgress:
+		return m.viewCentered(m.viewInProgress())
+	case StateSelecting:
+		return m.viewCentered(m.viewSelecting())
+	case StateResolving:
+		return m.Terminal.ViewCentered(m.Width, m.Height)
 	case StateGenerating:
+		if m.streaming() {
+			return m.viewCentered(m.viewGenerating())
+		}
 		return m.Terminal.ViewCentered(m.Width, m.Height)
+	case StateSplit:
+		if m.EditingSplit {
+			return m.viewCentered(m.SplitForm.View())
+		}
+		return m.viewCentered(m.viewSplit())
 	case StateEditing:
+		if m.AskingHint {
+			return m.viewCentered(m.HintForm.View())
+		}
+		if m.PickingCoAuthor {
+			return m.viewCentered(m.CoAuthorPicker.View())
+		}
+		if m.ShowPreview {
+			editor := lipgloss.NewStyle().Width(editorWidth).Render(m.viewEditing())
+			return lipgloss.NewStyle().Padding(1, 1).Render(
+				lipgloss.JoinHorizontal(lipgloss.Top, editor, m.viewPreview()))
+		}
 		return m.viewCentered(m.viewEditing())
+	case StateReviewing:
+		return m.viewCentered(m.viewReview())
 	case StateCommitting:
 		return m.Terminal.ViewCentered(m.Width, m.Height)
 	case StateDone:
@@ -538,6 +931,15 @@ func (m Model) View() string {
 	return ""
 }
 
+// showsTerminal reports whether the view is the terminal modal.
+func (m Model) showsTerminal() bool {
+	switch m.State {
+	case StateGenerating, StateCommitting, StateResolving:
+		return !m.ShowDiff && !m.Confirming && !m.streaming()
+	}
+	return false
+}
+
 func (m Model) viewCentered(content string) string {
 	return lipgloss.Place(
 		m.Width,
@@ -562,6 +964,38 @@ func (m Model) viewNoChanges() string {
 	return b.String()
 }
 
+func (m Model) viewInProgress() string {
+	var b strings.Builder
+	kb := m.Config.Keys()
+
+	b.WriteString(styles.Confirm.Render(fmt.Sprintf("  ⚠ git %s in progress", m.Operation)))
+	b.WriteString("\n\n")
+	b.WriteString(styles.Help.Render(fmt.Sprintf("Smart Commit is disabled until the %s is continued or aborted.", m.Operation)))
+	b.WriteString("\n\n")
+
+	if len(m.Conflicts) > 0 {
+		b.WriteString(styles.Label.Render("  Conflicted files:"))
+		b.WriteString("\n")
+		for _, f := range m.Conflicts {
+			b.WriteString(styles.Error.Render("    " + f))
+			b.WriteString("\n")
+		}
+	} else {
+		b.WriteString(styles.Selected.Render("  ✓ All conflicts resolved"))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	if m.ErrMsg != "" {
+		b.WriteString(styles.Error.Render("  " + m.ErrMsg))
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString(styles.Help.Render(fmt.Sprintf("%s continue • %s abort • %s back",
+		kb.Commit.Continue, kb.Commit.Abort, kb.Global.Quit)))
+	return b.String()
+}
+
 func (m Model) viewEditing() string {
 	var b strings.Builder
 	kb := m.Config.Keys()
@@ -569,9 +1003,12 @@ func (m Model) viewEditing() string {
 	b.WriteString(styles.Title.Render("  Smart Commit"))
 	b.WriteString("\n\n")
 
+	// Type and scope
+	b.WriteString(m.viewPrefix())
+
 	// Subject field
 	subjectLabel := "Subject:"
-	if m.EditingField == 0 {
+	if m.EditingField == fieldSubject {
 		subjectLabel = styles.Selected.Render("▸ Subject:")
 	} else {
 		subjectLabel = styles.Label.Render("  Subject:")
@@ -582,7 +1019,7 @@ func (m Model) viewEditing() string {
 	// Subject input box
 	boxWidth := 72
 	subjectDisplay := m.Subject
-	if m.EditingField == 0 {
+	if m.EditingField == fieldSubject {
 		// Show cursor
 		if m.CursorPos <= len(subjectDisplay) {
 			subjectDisplay = subjectDisplay[:m.CursorPos] + "█" + subjectDisplay[m.CursorPos:]
@@ -597,9 +1034,14 @@ func (m Model) viewEditing() string {
 	b.WriteString(styles.Help.Render("  └" + strings.Repeat("─", boxWidth) + "┘"))
 	b.WriteString("\n")
 
-	// Character count for subject
-	charCount := fmt.Sprintf("  %d/72 characters", len(m.Subject))
-	if len(m.Subject) > 50 {
+	// Character count for the whole subject line
+	subject := m.fullSubject()
+	length := subjectLength(subject)
+	charCount := fmt.Sprintf("  %d/%d characters", length, maxSubject)
+	if prefix := m.subjectPrefix(); prefix != "" {
+		charCount = fmt.Sprintf("  %s… %d/%d characters", prefix, length, maxSubject)
+	}
+	if length > 50 {
 		charCount = styles.Confirm.Render(charCount)
 	} else {
 		charCount = styles.Help.Render(charCount)
@@ -609,7 +1051,7 @@ func (m Model) viewEditing() string {
 
 	// Body field
 	bodyLabel := "Body (optional):"
-	if m.EditingField == 1 {
+	if m.EditingField == fieldBody {
 		bodyLabel = styles.Selected.Render("▸ Body (optional):")
 	} else {
 		bodyLabel = styles.Label.Render("  Body (optional):")
@@ -620,7 +1062,7 @@ func (m Model) viewEditing() string {
 	// Body input box (multi-line)
 	bodyHeight := 8
 	bodyDisplay := m.Body
-	if m.EditingField == 1 {
+	if m.EditingField == fieldBody {
 		// Show cursor
 		if m.CursorPos <= len(bodyDisplay) {
 			bodyDisplay = bodyDisplay[:m.CursorPos] + "█" + bodyDisplay[m.CursorPos:]
@@ -634,37 +1076,96 @@ func (m Model) viewEditing() string {
 	b.WriteString(styles.Help.Render("  ┌" + strings.Repeat("─", boxWidth) + "┐"))
 	b.WriteString("\n")
 	for i := 0; i < bodyHeight && i < len(bodyLines); i++ {
-		line := bodyLines[i]
-		if len(line) > boxWidth-2 {
-			line = line[:boxWidth-2]
-		}
 		b.WriteString(styles.Help.Render("  │ "))
-		b.WriteString(styles.Input.Render(padRight(line, boxWidth-2)))
+		b.WriteString(styles.Input.Render(padRight(bodyLines[i], boxWidth-2)))
 		b.WriteString(styles.Help.Render(" │"))
 		b.WriteString("\n")
 	}
 	b.WriteString(styles.Help.Render("  └" + strings.Repeat("─", boxWidth) + "┘"))
 	b.WriteString("\n\n")
 
+	// Co-authors, added as trailers
+	if len(m.CoAuthors) > 0 {
+		b.WriteString(styles.Label.Render("  Co-authors:"))
+		b.WriteString("\n")
+		for _, a := range m.CoAuthors {
+			b.WriteString(styles.Value.Render("    " + a))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
+	// Problems with the message, checked as it is typed
+	if problems := lintMessage(m.message(), m.Config.Settings.CommitLint); len(problems) > 0 {
+		for _, p := range problems {
+			b.WriteString(styles.Confirm.Render("  ⚠ " + p))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
 	// Error message
 	if m.ErrMsg != "" {
 		b.WriteString(styles.Error.Render("  " + m.ErrMsg))
 		b.WriteString("\n\n")
+	} else if m.Offline != "" {
+		b.WriteString(styles.Status.Render("  Guessed from the changed files, since " + m.Offline))
+		b.WriteString("\n\n")
+	} else if m.FellBack != "" {
+		b.WriteString(styles.Status.Render("  Written by " + m.FellBack))
+		b.WriteString("\n\n")
+	}
+	if m.BudgetNote != "" {
+		b.WriteString(styles.Confirm.Render("  ⚠ " + m.BudgetNote))
+		b.WriteString("\n\n")
 	}
 
 	// Help
-	b.WriteString(styles.Help.Render(fmt.Sprintf("↑/↓ or %s/%s switch fields • %s commit • %s cancel",
-		kb.Form.PrevField, kb.Form.NextField, kb.Form.Submit, kb.Global.Quit)))
+	switch m.EditingField {
+	case fieldType:
+		b.WriteString(styles.Help.Render("←/→ change type"))
+		b.WriteString("\n")
+	case fieldEmoji:
+		b.WriteString(styles.Help.Render("←/→ change emoji"))
+		b.WriteString("\n")
+	case fieldScope:
+		b.WriteString(styles.Help.Render("enter complete a recent scope"))
+		b.WriteString("\n")
+	}
+	b.WriteString(styles.Help.Render(fmt.Sprintf("↑/↓ or %s/%s switch fields • %s view diff • %s co-authors • %s review • %s cancel",
+		kb.Form.PrevField, kb.Form.NextField, kb.Diff.Open, kb.Commit.CoAuthor, kb.Form.Submit, kb.Global.Quit)))
+	b.WriteString("\n")
+	if m.ShowPreview {
+		b.WriteString(styles.Help.Render(fmt.Sprintf("%s hide changes • %s/%s scroll changes • %s regenerate (%s uncached)",
+			kb.Commit.TogglePreview, kb.List.PageUp, kb.List.PageDown, kb.Commit.Regenerate, kb.Commit.RegenerateFresh)))
+	} else {
+		b.WriteString(styles.Help.Render(fmt.Sprintf("%s show changes beside the message • %s regenerate (%s uncached)",
+			kb.Commit.TogglePreview, kb.Commit.Regenerate, kb.Commit.RegenerateFresh)))
+	}
 
 	return b.String()
 }
 
 func (m Model) viewDone() string {
 	var b strings.Builder
-	b.WriteString(styles.Selected.Render("  ✓ Commit Created"))
-	b.WriteString("\n\n")
-	b.WriteString(styles.Label.Render("  " + m.Subject))
-	b.WriteString("\n\n")
+	if len(m.Split) > 0 {
+		b.WriteString(styles.Selected.Render(fmt.Sprintf("  ✓ %d Commits Created", len(m.Split))))
+		b.WriteString("\n\n")
+		for _, c := range m.Split {
+			b.WriteString(styles.Label.Render("  " + c.subject()))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	} else {
+		b.WriteString(styles.Selected.Render("  ✓ Commit Created"))
+		b.WriteString("\n\n")
+		b.WriteString(styles.Label.Render("  " + m.fullSubject()))
+		b.WriteString("\n\n")
+	}
+	if m.Fallback != "" {
+		b.WriteString(styles.Help.Render("  Message written in your git editor: " + m.Fallback))
+		b.WriteString("\n\n")
+	}
 	b.WriteString(styles.Help.Render("Press Enter to go back"))
 	return b.String()
 }
@@ -679,9 +1180,8 @@ func (m Model) viewError() string {
 	return b.String()
 }
 
+// padRight pads s with spaces to length columns, cutting it if it is wider.
+// Wide characters, such as CJK and most emoji, take two columns.
 func padRight(s string, length int) string {
-	if len(s) >= length {
-		return s[:length]
-	}
-	return s + strings.Repeat(" ", length-len(s))
+	return runewidth.FillRight(runewidth.Truncate(s, length, ""), length)
 }
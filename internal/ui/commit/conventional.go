@@ -0,0 +1,198 @@
+package commit
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+)
+
+// Fields of the message editor, in navigation order.
+const (
+	fieldType  = iota
+	fieldEmoji // only with the gitmoji setting on
+	fieldScope
+	fieldSubject
+	fieldBody
+)
+
+// maxSubject is the longest subject line allowed, prefix included.
+const maxSubject = 72
+
+// commitTypes are the conventional commit types offered by the type picker.
+// The empty type writes the subject without a prefix.
+var commitTypes = []string{"", "feat", "fix", "refactor", "perf", "docs", "style", "test", "build", "ci", "chore", "revert"}
+
+// conventionalSubject matches "type(scope): description" and "type: description".
+var conventionalSubject = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^()\s]*)\))?: (.*)$`)
+
+// ScopesLoadedMsg carries the scopes used in recent commits.
+type ScopesLoadedMsg struct {
+	Scopes []string
+}
+
+// splitSubject splits a conventional commit subject into its type, scope and
+// description, dropping an emoji before the type. Subjects with an unknown
+// type are returned as the description.
+func splitSubject(subject string) (typ, scope, desc string) {
+	match := conventionalSubject.FindStringSubmatch(stripEmoji(strings.TrimSpace(subject)))
+	if match == nil {
+		return "", "", subject
+	}
+	typ = strings.ToLower(match[1])
+	for _, t := range commitTypes[1:] {
+		if t == typ {
+			return typ, match[2], match[3]
+		}
+	}
+	return "", "", subject
+}
+
+// subjectPrefix returns the "type(scope): " prefix the type and scope make,
+// or "" without a type, after the gitmoji if there is one.
+func (m Model) subjectPrefix() string {
+	prefix := ""
+	if emoji := m.emoji(); emoji != "" {
+		prefix = emoji + " "
+	}
+	switch {
+	case m.Type == "":
+		return prefix
+	case m.Scope == "":
+		return prefix + m.Type + ": "
+	}
+	return prefix + fmt.Sprintf("%s(%s): ", m.Type, m.Scope)
+}
+
+// fullSubject returns the subject line the commit is made with.
+func (m Model) fullSubject() string {
+	return m.subjectPrefix() + m.Subject
+}
+
+// loadScopes reads the scopes of recent conventional commits, most recent first.
+func (m Model) loadScopes() tea.Cmd {
+	repoPath := m.RepoPath
+	return func() tea.Msg {
+		var scopes []string
+		seen := make(map[string]bool)
+		for _, subject := range strings.Split(runGitCommand(repoPath, "log", "-n", "500", "--format=%s"), "\n") {
+			if _, scope, _ := splitSubject(subject); scope != "" && !seen[scope] {
+				seen[scope] = true
+				scopes = append(scopes, scope)
+			}
+		}
+		return ScopesLoadedMsg{Scopes: scopes}
+	}
+}
+
+// scopeSuggestions returns the recent scopes starting with what has been typed.
+func (m Model) scopeSuggestions() []string {
+	var matches []string
+	for _, s := range m.Scopes {
+		if s != m.Scope && strings.HasPrefix(s, m.Scope) {
+			matches = append(matches, s)
+			if len(matches) == 5 {
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// cycleType selects the type delta places away in commitTypes.
+func (m *Model) cycleType(delta int) {
+	idx := 0
+	for i, t := range commitTypes {
+		if t == m.Type {
+			idx = i
+		}
+	}
+	m.Type = commitTypes[(idx+delta+len(commitTypes))%len(commitTypes)]
+	// The emoji follows the type again
+	m.EmojiPicked = false
+	m.clampSubject()
+}
+
+// clampSubject shortens the description so the subject line fits maxSubject.
+func (m *Model) clampSubject() {
+	limit := max(maxSubject-subjectLength(m.subjectPrefix()), 0)
+	m.Subject = truncateSubject(m.Subject, limit)
+	if m.EditingField == fieldSubject && m.CursorPos > len(m.Subject) {
+		m.CursorPos = len(m.Subject)
+	}
+}
+
+// handlePrefixKey handles input for the type picker and the scope field.
+func (m Model) handlePrefixKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+
+	if m.EditingField == fieldType {
+		switch key {
+		case "left":
+			m.cycleType(-1)
+		case "right", " ":
+			m.cycleType(1)
+		}
+		return m, nil
+	}
+
+	switch key {
+	case "enter":
+		// Complete to the first recent scope that matches
+		if s := m.scopeSuggestions(); len(s) > 0 {
+			m.Scope = s[0]
+			m.CursorPos = len(m.Scope)
+		}
+	case " ", "(", ")", ":":
+		// Not allowed in a scope
+	default:
+		m.Scope, m.CursorPos = handleTextEdit(m.Scope, m.CursorPos, msg)
+	}
+	m.clampSubject()
+	return m, nil
+}
+
+// viewPrefix renders the type picker and the scope field.
+func (m Model) viewPrefix() string {
+	var b strings.Builder
+
+	typ := m.Type
+	if typ == "" {
+		typ = "none"
+	}
+	if m.EditingField == fieldType {
+		b.WriteString(styles.Selected.Render("▸ Type:   "))
+		b.WriteString(styles.Cursor.Render("◂ ") + styles.Value.Render(typ) + styles.Cursor.Render(" ▸"))
+	} else {
+		b.WriteString(styles.Label.Render("  Type:   "))
+		b.WriteString(styles.Value.Render(typ))
+	}
+	b.WriteString("\n")
+	if m.Config.Settings.Gitmoji.Enabled {
+		b.WriteString(m.viewEmoji())
+	}
+
+	scope := m.Scope
+	if m.EditingField == fieldScope {
+		if m.CursorPos <= len(scope) {
+			scope = scope[:m.CursorPos] + "█" + scope[m.CursorPos:]
+		}
+		b.WriteString(styles.Selected.Render("▸ Scope:  "))
+	} else {
+		b.WriteString(styles.Label.Render("  Scope:  "))
+	}
+	if scope == "" {
+		b.WriteString(styles.Help.Render("(none)"))
+	} else {
+		b.WriteString(styles.Input.Render(scope))
+	}
+	if m.EditingField == fieldScope {
+		if s := m.scopeSuggestions(); len(s) > 0 {
+			b.WriteString(styles.Help.Render("   recent: " + strings.Join(s, ", ")))
+		}
+	}
+	b.WriteString("\n\n")
+	return b.String()
+}
@@ -0,0 +1,100 @@
+package commit
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSplitSubject(t *testing.T) {
+	tests := []struct {
+		subject, typ, scope, desc string
+	}{
+		{"feat(store): add audit log", "feat", "store", "add audit log"},
+		{"Fix: handle empty diff", "fix", "", "handle empty diff"},
+		{"chore(): bump deps", "chore", "", "bump deps"},
+		{"wip(ui): not a known type", "", "", "wip(ui): not a known type"},
+		{"Add config export", "", "", "Add config export"},
+		{"✨ feat(ui): add gitmoji", "feat", "ui", "add gitmoji"},
+		{"✨ add gitmoji", "", "", "✨ add gitmoji"},
+	}
+	for _, tt := range tests {
+		typ, scope, desc := splitSubject(tt.subject)
+		if typ != tt.typ || scope != tt.scope || desc != tt.desc {
+			t.Errorf("splitSubject(%q) = %q, %q, %q; want %q, %q, %q",
+				tt.subject, typ, scope, desc, tt.typ, tt.scope, tt.desc)
+		}
+	}
+
+	m := Model{Type: "fix", Scope: "ui", Subject: "wrap long lines"}
+	if got := m.fullSubject(); got != "fix(ui): wrap long lines" {
+		t.Errorf("fullSubject() = %q", got)
+	}
+}
+
+func TestSubjectLength(t *testing.T) {
+	for subject, want := range map[string]int{
+		"fix: wrap":      9,
+		"✨ feat: add":    11,
+		"♻️ refactor: x": 13,
+	} {
+		if got := subjectLength(subject); got != want {
+			t.Errorf("subjectLength(%q) = %d; want %d", subject, got, want)
+		}
+	}
+}
+
+func TestClampSubject(t *testing.T) {
+	m := Model{Type: "feat", Subject: strings.Repeat("漢", maxSubject)}
+	m.clampSubject()
+	if !utf8.ValidString(m.Subject) {
+		t.Fatalf("clampSubject() cut a character: %q", m.Subject)
+	}
+	if got := subjectLength(m.fullSubject()); got != maxSubject {
+		t.Errorf("subject length = %d; want %d", got, maxSubject)
+	}
+
+	// Variation selectors don't count, so more runes than characters fit
+	m = Model{Type: "feat", Subject: strings.Repeat("♻️", maxSubject)}
+	m.clampSubject()
+	if got := subjectLength(m.fullSubject()); got != maxSubject {
+		t.Errorf("subject length = %d; want %d", got, maxSubject)
+	}
+	if !strings.HasSuffix(m.Subject, "♻️") {
+		t.Errorf("clampSubject() split an emoji from its selector: %q", m.Subject)
+	}
+}
+
+func TestHandleTextEdit_Multibyte(t *testing.T) {
+	text, cursor := "", 0
+	for _, r := range "añ漢" {
+		text, cursor = handleTextEdit(text, cursor, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	text, cursor = handleTextEdit(text, cursor, tea.KeyMsg{Type: tea.KeyLeft})
+	text, cursor = handleTextEdit(text, cursor, tea.KeyMsg{Type: tea.KeyBackspace})
+	if text != "a漢" || cursor != 1 {
+		t.Fatalf("after backspace = %q at %d; want \"a漢\" at 1", text, cursor)
+	}
+	text, cursor = handleTextEdit(text, cursor, tea.KeyMsg{Type: tea.KeyDelete})
+	if text != "a" || cursor != 1 {
+		t.Errorf("after delete = %q at %d; want \"a\" at 1", text, cursor)
+	}
+}
+
+func TestPadRight(t *testing.T) {
+	for _, tt := range []struct {
+		s    string
+		want string
+	}{
+		{"fix", "fix   "},
+		{"漢字", "漢字  "},
+		{"✨ add", "✨ add"},
+		{"漢字漢字", "漢字漢"},
+	} {
+		if got := padRight(tt.s, 6); got != tt.want {
+			t.Errorf("padRight(%q, 6) = %q; want %q", tt.s, got, tt.want)
+		}
+	}
+}
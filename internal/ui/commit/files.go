@@ -0,0 +1,244 @@
+package commit
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/git"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+)
+
+// setFiles lists the files to commit for the mode: all changed files, or
+// only the staged ones in staged-only mode. All of them start selected.
+func (m *Model) setFiles() {
+	m.Files = m.Changes
+	if m.StagedOnly {
+		m.Files = nil
+		for _, f := range m.Changes {
+			if f.Status[0] != ' ' && !f.Untracked() {
+				m.Files = append(m.Files, f)
+			}
+		}
+	}
+	m.Selected = make([]bool, len(m.Files))
+	for i := range m.Selected {
+		m.Selected[i] = true
+	}
+	m.FileCursor = 0
+	m.FileScroll = 0
+}
+
+// diffBase returns what git diff compares the files to commit with:
+// HEAD, or the index in staged-only mode.
+func (m Model) diffBase() string {
+	if m.StagedOnly {
+		return "--cached"
+	}
+	return "HEAD"
+}
+
+// selectedFiles returns the files chosen to commit.
+func (m Model) selectedFiles() []git.StatusFile {
+	var files []git.StatusFile
+	for i, f := range m.Files {
+		if m.Selected[i] {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// selectedPaths returns the paths to stage and commit.
+func (m Model) selectedPaths() []string {
+	return commitPaths(m.selectedFiles())
+}
+
+// commitPaths returns the paths of files to stage and commit. Renames
+// include the old path so its removal is committed too.
+func commitPaths(files []git.StatusFile) []string {
+	var paths []string
+	for _, f := range files {
+		if f.OldPath != "" {
+			paths = append(paths, f.OldPath)
+		}
+		paths = append(paths, f.Path)
+	}
+	return paths
+}
+
+// addPaths returns the paths of files to stage. Paths already removed from
+// the index are left out, since git add rejects paths it can't find.
+func addPaths(files []git.StatusFile) []string {
+	var paths []string
+	for _, f := range files {
+		if f.Status[0] != 'D' {
+			paths = append(paths, f.Path)
+		}
+	}
+	return paths
+}
+
+// visibleFiles returns how many files fit on screen.
+func (m Model) visibleFiles() int {
+	visible := m.Height - 12
+	if visible < 1 {
+		visible = 1
+	}
+	return visible
+}
+
+func (m Model) handleSelectKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	kb := m.Config.Keys()
+	visible := m.visibleFiles()
+
+	switch {
+	case config.MatchesAny(key, kb.Global.MoveUp, kb.Global.MoveUpAlt):
+		if m.FileCursor > 0 {
+			m.FileCursor--
+			if m.FileCursor < m.FileScroll {
+				m.FileScroll = m.FileCursor
+			}
+		}
+
+	case config.MatchesAny(key, kb.Global.MoveDown, kb.Global.MoveDownAlt):
+		if m.FileCursor < len(m.Files)-1 {
+			m.FileCursor++
+			if m.FileCursor >= m.FileScroll+visible {
+				m.FileScroll = m.FileCursor - visible + 1
+			}
+		}
+
+	case config.Matches(key, kb.Commit.StagedOnly):
+		m.StagedOnly = !m.StagedOnly
+		m.setFiles()
+		m.ErrMsg = ""
+
+	case m.StagedOnly && config.MatchesAny(key, kb.Commit.ToggleFile, kb.Commit.ToggleAll):
+		// git commit with paths would take them from the working tree
+		m.ErrMsg = "The whole index is committed; stage or unstage files with git to change it"
+
+	case config.Matches(key, kb.Commit.ToggleFile):
+		m.Selected[m.FileCursor] = !m.Selected[m.FileCursor]
+		m.ErrMsg = ""
+
+	case config.Matches(key, kb.Commit.ToggleAll):
+		// Select all unless everything is already selected
+		all := true
+		for _, s := range m.Selected {
+			all = all && s
+		}
+		for i := range m.Selected {
+			m.Selected[i] = !all
+		}
+		m.ErrMsg = ""
+
+	case config.Matches(key, kb.List.Select):
+		if m.StagedOnly && len(m.Files) == 0 {
+			m.ErrMsg = "Nothing is staged"
+			return m, nil
+		}
+		if len(m.selectedPaths()) == 0 {
+			m.ErrMsg = "Select at least one file"
+			return m, nil
+		}
+		m.ErrMsg = ""
+		return m.startGenerating("", false)
+
+	case config.Matches(key, kb.Commit.Split):
+		if m.StagedOnly {
+			m.ErrMsg = "Splitting stages each commit's files; switch off staged-only mode first"
+			return m, nil
+		}
+		if len(m.selectedFiles()) < 2 {
+			m.ErrMsg = "Select at least two files to split"
+			return m, nil
+		}
+		m.ErrMsg = ""
+		m.Split = nil
+		return m.startSplitting()
+	}
+
+	return m, nil
+}
+
+func (m Model) viewSelecting() string {
+	var b strings.Builder
+	kb := m.Config.Keys()
+
+	count := 0
+	for _, s := range m.Selected {
+		if s {
+			count++
+		}
+	}
+
+	b.WriteString(styles.Title.Render("  Smart Commit"))
+	if m.StagedOnly {
+		b.WriteString(styles.Help.Render(fmt.Sprintf(" (staged only, %d of %d files)", len(m.Files), len(m.Changes))))
+	} else {
+		b.WriteString(styles.Help.Render(fmt.Sprintf(" (%d of %d files)", count, len(m.Files))))
+	}
+	b.WriteString("\n\n")
+
+	visible := m.visibleFiles()
+	if m.FileScroll > 0 {
+		b.WriteString(styles.Help.Render("  ↑ more above"))
+	}
+	b.WriteString("\n")
+
+	if len(m.Files) == 0 {
+		b.WriteString(styles.Dim.Render("  Nothing is staged"))
+		b.WriteString("\n")
+	}
+
+	end := min(m.FileScroll+visible, len(m.Files))
+	for i := m.FileScroll; i < end; i++ {
+		f := m.Files[i]
+
+		check := "[ ]"
+		if m.Selected[i] {
+			check = "[x]"
+		}
+		path := f.Path
+		if f.OldPath != "" {
+			path = f.OldPath + " → " + f.Path
+		}
+		status := styles.Status.Render(f.Status)
+		if f.Untracked() {
+			status = styles.Dim.Render(f.Status)
+		}
+
+		if i == m.FileCursor {
+			b.WriteString(styles.Cursor.Render("▸ "))
+			b.WriteString(styles.Selected.Render(check))
+		} else {
+			b.WriteString("  ")
+			b.WriteString(styles.Item.Render(check))
+		}
+		b.WriteString(" " + status + " ")
+		b.WriteString(styles.Value.Render(path))
+		b.WriteString("\n")
+	}
+
+	if end < len(m.Files) {
+		b.WriteString(styles.Help.Render("  ↓ more below"))
+	}
+	b.WriteString("\n\n")
+
+	if m.ErrMsg != "" {
+		b.WriteString(styles.Error.Render("  " + m.ErrMsg))
+		b.WriteString("\n\n")
+	}
+
+	if m.StagedOnly {
+		b.WriteString(styles.Help.Render(fmt.Sprintf("%s choose files • %s generate message • %s cancel",
+			kb.Commit.StagedOnly, kb.List.Select, kb.Global.Quit)))
+	} else {
+		b.WriteString(styles.Help.Render(fmt.Sprintf("%s toggle • %s toggle all • %s staged only • %s generate message • %s split into commits • %s cancel",
+			kb.Commit.ToggleFile, kb.Commit.ToggleAll, kb.Commit.StagedOnly, kb.List.Select, kb.Commit.Split, kb.Global.Quit)))
+	}
+	return b.String()
+}
@@ -0,0 +1,41 @@
+package commit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ihatemodels/gdev/internal/git"
+	"github.com/ihatemodels/gdev/internal/ui/terminal"
+)
+
+// quoteCommands formats commands as a shell would run them in turn.
+func quoteCommands(commands [][]string) string {
+	quoted := make([]string, len(commands))
+	for i, c := range commands {
+		quoted[i] = terminal.ShellQuote(c[0], c[1:]...)
+	}
+	return strings.Join(quoted, " && ")
+}
+
+func TestCommitCommandsStagedOnly(t *testing.T) {
+	m := Model{Changes: []git.StatusFile{
+		{Path: "staged.go", Status: "M "},
+		{Path: "both.go", Status: "MM"},
+		{Path: "unstaged.go", Status: " M"},
+		{Path: "new.go", Status: "??"},
+	}}
+
+	m.setFiles()
+	if got, want := quoteCommands(m.commitCommands("-F", "-")), "git add -A -- staged.go both.go unstaged.go new.go && git commit -F - -- staged.go both.go unstaged.go new.go"; got != want {
+		t.Errorf("commitCommands() = %q; want %q", got, want)
+	}
+
+	m.StagedOnly = true
+	m.setFiles()
+	if len(m.Files) != 2 || m.Files[0].Path != "staged.go" || m.Files[1].Path != "both.go" {
+		t.Errorf("staged files = %v; want staged.go and both.go", m.Files)
+	}
+	if got, want := quoteCommands(m.commitCommands("-F", "-")), "git commit -F -"; got != want {
+		t.Errorf("commitCommands() = %q; want %q", got, want)
+	}
+}
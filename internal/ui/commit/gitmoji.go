@@ -0,0 +1,142 @@
+package commit
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+)
+
+// gitmoji is an emoji offered by the picker, with what it stands for.
+type gitmoji struct {
+	Emoji string
+	Name  string
+}
+
+// gitmojis are the emoji offered by the picker. The empty one writes the
+// subject without an emoji.
+var gitmojis = []gitmoji{
+	{"", "none"},
+	{"✨", "new feature"},
+	{"🐛", "bug fix"},
+	{"🚑️", "critical hotfix"},
+	{"♻️", "refactor"},
+	{"⚡️", "performance"},
+	{"📝", "documentation"},
+	{"🎨", "structure or format"},
+	{"✅", "tests"},
+	{"📦️", "build or packages"},
+	{"👷", "CI"},
+	{"🔧", "configuration"},
+	{"⏪️", "revert"},
+	{"🔥", "remove code or files"},
+	{"💄", "UI and styles"},
+	{"🔒️", "security"},
+	{"⬆️", "upgrade dependencies"},
+	{"🏷️", "types"},
+	{"🌐", "internationalization"},
+	{"🚧", "work in progress"},
+}
+
+// emoji returns the emoji the subject starts with: the one picked, or the
+// one configured for the type. It is "" unless the gitmoji setting is on.
+func (m Model) emoji() string {
+	if m.Config == nil || !m.Config.Settings.Gitmoji.Enabled {
+		return ""
+	}
+	if m.EmojiPicked {
+		return m.Emoji
+	}
+	return m.Config.Settings.Gitmoji.Types[m.Type]
+}
+
+// cycleEmoji picks the emoji delta places away in gitmojis.
+func (m *Model) cycleEmoji(delta int) {
+	idx := 0
+	current := m.emoji()
+	for i, g := range gitmojis {
+		if g.Emoji == current {
+			idx = i
+		}
+	}
+	m.Emoji = gitmojis[(idx+delta+len(gitmojis))%len(gitmojis)].Emoji
+	m.EmojiPicked = true
+	m.clampSubject()
+}
+
+// handleEmojiKey handles input for the gitmoji picker.
+func (m Model) handleEmojiKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "left":
+		m.cycleEmoji(-1)
+	case "right", " ":
+		m.cycleEmoji(1)
+	}
+	return m, nil
+}
+
+// viewEmoji renders the gitmoji picker.
+func (m Model) viewEmoji() string {
+	emoji := m.emoji()
+	name := "none"
+	for _, g := range gitmojis {
+		if g.Emoji == emoji {
+			name = g.Name
+		}
+	}
+	if emoji != "" {
+		name = emoji + " " + name
+	}
+
+	if m.EditingField == fieldEmoji {
+		return styles.Selected.Render("▸ Emoji:  ") +
+			styles.Cursor.Render("◂ ") + styles.Value.Render(name) + styles.Cursor.Render(" ▸") + "\n"
+	}
+	return styles.Label.Render("  Emoji:  ") + styles.Value.Render(name) + "\n"
+}
+
+// stripEmoji removes a leading emoji and the space after it from subject.
+func stripEmoji(subject string) string {
+	first, rest, ok := strings.Cut(subject, " ")
+	if !ok || first == "" {
+		return subject
+	}
+	for _, r := range first {
+		if r < utf8.RuneSelf || unicode.IsLetter(r) {
+			return subject
+		}
+	}
+	return rest
+}
+
+// subjectLength counts the characters of a line of a commit message, as
+// git hosts do: a CJK character or an emoji is one. Variation selectors,
+// which only choose how an emoji is drawn, don't count.
+func subjectLength(subject string) int {
+	n := 0
+	for _, r := range subject {
+		if !unicode.Is(unicode.Variation_Selector, r) {
+			n++
+		}
+	}
+	return n
+}
+
+// truncateSubject returns the start of subject that is limit characters
+// long, counted as subjectLength does. A variation selector stays with the
+// character it draws.
+func truncateSubject(subject string, limit int) string {
+	n := 0
+	for i, r := range subject {
+		if unicode.Is(unicode.Variation_Selector, r) {
+			continue
+		}
+		if n == limit {
+			return subject[:i]
+		}
+		n++
+	}
+	return subject
+}
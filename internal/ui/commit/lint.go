@@ -0,0 +1,73 @@
+package commit
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ihatemodels/gdev/internal/config"
+)
+
+// notImperative are words ending like a past tense, gerund or third person
+// verb that are imperative anyway.
+var notImperative = map[string]bool{
+	"embed": true, "feed": true, "need": true, "seed": true, "shed": true, "speed": true,
+	"bring": true, "ping": true, "ring": true, "sing": true, "string": true,
+	"access": true, "address": true, "alias": true, "bypass": true, "compress": true,
+	"focus": true, "pass": true, "process": true, "press": true, "redis": true, "suppress": true,
+}
+
+// lintMessage returns how message breaks the rules, in the order of the lines
+// they concern. A rule's zero value turns it off.
+func lintMessage(message string, rules config.CommitLint) []string {
+	var problems []string
+	lines := strings.Split(strings.TrimRight(message, "\n"), "\n")
+	subject := lines[0]
+	typ, _, desc := splitSubject(subject)
+
+	if n := subjectLength(subject); rules.MaxSubject > 0 && n > rules.MaxSubject {
+		problems = append(problems, fmt.Sprintf("The subject is %d characters, over %d", n, rules.MaxSubject))
+	}
+	if len(rules.Types) > 0 && !slices.Contains(rules.Types, typ) {
+		problems = append(problems, "The subject needs a type: "+strings.Join(rules.Types, ", "))
+	}
+	if rules.Imperative {
+		word, _, _ := strings.Cut(strings.TrimSpace(desc), " ")
+		if !imperative(word) {
+			problems = append(problems, fmt.Sprintf("Start the subject with a verb like \"add\", not %q", word))
+		}
+	}
+	if rules.BlankLine && len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		problems = append(problems, "Leave a blank line between the subject and the body")
+	}
+	if rules.BodyWrap > 0 {
+		long := 0
+		for _, line := range lines[1:] {
+			if subjectLength(line) > rules.BodyWrap {
+				long++
+			}
+		}
+		if long == 1 {
+			problems = append(problems, fmt.Sprintf("A line of the body is longer than %d characters", rules.BodyWrap))
+		} else if long > 1 {
+			problems = append(problems, fmt.Sprintf("%d lines of the body are longer than %d characters", long, rules.BodyWrap))
+		}
+	}
+	return problems
+}
+
+// imperative guesses whether word is a verb in the imperative mood, by
+// whether it ends like "added", "adding" or "adds".
+func imperative(word string) bool {
+	word = strings.ToLower(strings.Trim(word, ".,:;!?"))
+	if word == "" || notImperative[word] {
+		return true
+	}
+	switch {
+	case strings.HasSuffix(word, "ed"), strings.HasSuffix(word, "ing"):
+		return false
+	case strings.HasSuffix(word, "s"):
+		return strings.HasSuffix(word, "ss") || strings.HasSuffix(word, "us") || strings.HasSuffix(word, "is")
+	}
+	return true
+}
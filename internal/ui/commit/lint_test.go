@@ -0,0 +1,34 @@
+package commit
+
+import (
+	"testing"
+
+	"github.com/ihatemodels/gdev/internal/config"
+)
+
+func TestLintMessage(t *testing.T) {
+	rules := config.CommitLint{MaxSubject: 50, Imperative: true, Types: []string{"feat", "fix"}, BlankLine: true, BodyWrap: 20}
+
+	tests := []struct {
+		message  string
+		problems int
+	}{
+		{"feat: add export\n\n* keeps backups", 0},
+		{"fix(store): process jobs in order", 0},
+		{"feat: added export", 1},
+		{"feat: adds export", 1},
+		{"add export", 1},
+		{"feat: add export\n* no blank line", 1},
+		{"feat: add export\n\nthis line is much longer than twenty", 1},
+		{"docs: updating a subject that is well over the fifty characters allowed", 3},
+	}
+	for _, tt := range tests {
+		if got := lintMessage(tt.message, rules); len(got) != tt.problems {
+			t.Errorf("lintMessage(%q) = %q; want %d problems", tt.message, got, tt.problems)
+		}
+	}
+
+	if got := lintMessage("Updated things", config.CommitLint{}); len(got) != 0 {
+		t.Errorf("lintMessage() without rules = %q; want none", got)
+	}
+}
@@ -0,0 +1,160 @@
+package commit
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fileChange is a file to commit with its line counts from git diff --numstat.
+type fileChange struct {
+	Path    string
+	Status  string // two-letter porcelain status
+	Added   int
+	Deleted int
+}
+
+// diffStat returns the selected files with how many lines they change.
+// Untracked files aren't in the diff, so they count no lines.
+func (m Model) diffStat() []fileChange {
+	counts := make(map[string][2]int)
+	numstat := runGitCommand(m.RepoPath, append([]string{"diff", "--numstat", m.diffBase(), "--"}, m.selectedPaths()...)...)
+	for _, line := range strings.Split(numstat, "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		// Binary files count "-"
+		added, _ := strconv.Atoi(fields[0])
+		deleted, _ := strconv.Atoi(fields[1])
+		counts[fields[2]] = [2]int{added, deleted}
+	}
+
+	var changes []fileChange
+	for i, f := range m.Files {
+		if m.Selected[i] {
+			c := counts[f.Path]
+			changes = append(changes, fileChange{Path: f.Path, Status: f.Status, Added: c[0], Deleted: c[1]})
+		}
+	}
+	return changes
+}
+
+// writeOffline fills in a message made from the diffstat when none could be
+// generated, explaining why in reason, and opens it in the message editor.
+// Without a diffstat to go on, the message is written in the git editor.
+func (m Model) writeOffline(reason string) (Model, tea.Cmd) {
+	typ, subject, body := offlineMessage(m.diffStat())
+	if subject == "" {
+		return m.commitWithEditor(reason)
+	}
+
+	m.Offline = reason
+	m.Type, m.Scope, m.Subject = typ, "", subject
+	m.Body = body
+	m.State = StateEditing
+	m.EditingField = fieldSubject
+	m.CursorPos = len(m.Subject)
+	return m, nil
+}
+
+// offlineMessage guesses a commit message from the files changed: a type
+// when all files are tests, docs or new, a subject naming the parts of the
+// tree with the most changed lines, and the diffstat as the body.
+func offlineMessage(changes []fileChange) (typ, subject, body string) {
+	if len(changes) == 0 {
+		return "", "", ""
+	}
+
+	added, deleted, tests, docs := 0, 0, 0, 0
+	lines := make(map[string]int)
+	var areas []string
+	for _, c := range changes {
+		switch {
+		case c.Status == "??" || c.Status[0] == 'A':
+			added++
+		case c.Status[0] == 'D' || c.Status[1] == 'D':
+			deleted++
+		}
+		if strings.HasSuffix(c.Path, "_test.go") || strings.HasPrefix(c.Path, "test/") || strings.Contains(c.Path, "/testdata/") {
+			tests++
+		}
+		if strings.HasSuffix(c.Path, ".md") || strings.HasPrefix(c.Path, "docs/") {
+			docs++
+		}
+
+		area := changeArea(c.Path, len(changes) == 1)
+		if _, ok := lines[area]; !ok {
+			areas = append(areas, area)
+		}
+		lines[area] += c.Added + c.Deleted
+	}
+	sort.SliceStable(areas, func(i, j int) bool {
+		return lines[areas[i]] > lines[areas[j]]
+	})
+
+	verb := "update"
+	switch {
+	case added == len(changes):
+		verb = "add"
+	case deleted == len(changes):
+		verb = "remove"
+	}
+	switch {
+	case tests == len(changes):
+		typ = "test"
+	case docs == len(changes):
+		typ = "docs"
+	case added == len(changes):
+		typ = "feat"
+	}
+
+	// Name fewer parts until the subject fits
+	prefix := subjectLength(typ + ": ")
+	for named := min(len(areas), 3); named > 0; named-- {
+		subject = verb + " " + joinAreas(areas, named)
+		if prefix+subjectLength(subject) <= maxSubject {
+			break
+		}
+	}
+
+	stat := make([]string, len(changes))
+	for i, c := range changes {
+		if c.Status == "??" {
+			stat[i] = c.Path + " (new)"
+		} else {
+			stat[i] = fmt.Sprintf("%s (+%d -%d)", c.Path, c.Added, c.Deleted)
+		}
+	}
+	return typ, subject, strings.Join(stat, "\n")
+}
+
+// changeArea names the part of the tree path is in: its directory, or the
+// file itself at the root. With file set, the file name is added, e.g.
+// "todo form" for internal/ui/todo/form.go.
+func changeArea(p string, file bool) string {
+	name := strings.TrimSuffix(path.Base(p), path.Ext(p))
+	dir := path.Dir(p)
+	if dir == "." {
+		return name
+	}
+	if file && name != path.Base(dir) {
+		return path.Base(dir) + " " + name
+	}
+	return path.Base(dir)
+}
+
+// joinAreas lists the first named areas, e.g. "store, todo and 2 more".
+func joinAreas(areas []string, named int) string {
+	if rest := len(areas) - named; rest > 0 {
+		return strings.Join(areas[:named], ", ") + fmt.Sprintf(" and %d more", rest)
+	}
+	if named == 1 {
+		return areas[0]
+	}
+	return strings.Join(areas[:named-1], ", ") + " and " + areas[named-1]
+}
@@ -0,0 +1,40 @@
+package commit
+
+import "testing"
+
+func TestOfflineMessage(t *testing.T) {
+	tests := []struct {
+		changes      []fileChange
+		typ, subject string
+	}{
+		{
+			[]fileChange{
+				{Path: "internal/ui/todo/form.go", Status: " M", Added: 3, Deleted: 1},
+				{Path: "internal/store/todo.go", Status: "M ", Added: 40, Deleted: 2},
+			},
+			"", "update store and todo",
+		},
+		{
+			[]fileChange{{Path: "internal/ui/todo/form.go", Status: " M", Added: 3}},
+			"", "update todo form",
+		},
+		{
+			[]fileChange{{Path: "internal/ai/ai_test.go", Status: "??"}},
+			"test", "add ai ai_test",
+		},
+		{
+			[]fileChange{{Path: "README.md", Status: " M"}, {Path: "docs/usage.md", Status: "D "}},
+			"docs", "update README and docs",
+		},
+		{
+			[]fileChange{{Path: "a/x.go", Status: "A "}, {Path: "b/x.go", Status: "A "}, {Path: "c/x.go", Status: "A "}, {Path: "d/x.go", Status: "A "}},
+			"feat", "add a, b, c and 1 more",
+		},
+	}
+	for _, tt := range tests {
+		typ, subject, _ := offlineMessage(tt.changes)
+		if typ != tt.typ || subject != tt.subject {
+			t.Errorf("offlineMessage(%v) = %q, %q; want %q, %q", tt.changes, typ, subject, tt.typ, tt.subject)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package commit
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ihatemodels/gdev/internal/ui/diffview"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+	"github.com/mattn/go-runewidth"
+)
+
+// editorWidth is the width of the message editor and its help, beside which
+// the preview is shown.
+const editorWidth = 88
+
+// PreviewLoadedMsg carries the diff of the files being committed.
+type PreviewLoadedMsg struct {
+	Lines []string
+}
+
+// loadPreview loads the diff of the selected files against HEAD, or of the
+// index in staged-only mode. Untracked files are shown as added, since they
+// are staged with the commit.
+func (m Model) loadPreview() tea.Cmd {
+	repoPath, base := m.RepoPath, m.diffBase()
+	paths := m.selectedPaths()
+	var untracked []string
+	for i, f := range m.Files {
+		if m.Selected[i] && f.Untracked() {
+			untracked = append(untracked, f.Path)
+		}
+	}
+
+	return func() tea.Msg {
+		var b strings.Builder
+		b.WriteString(runGitCommand(repoPath, append([]string{"diff", base, "--"}, paths...)...))
+		for _, path := range untracked {
+			// Exits 1 when the files differ, which they always do
+			cmd := exec.Command("git", "diff", "--no-index", "--", "/dev/null", path)
+			cmd.Dir = repoPath
+			out, _ := cmd.Output()
+			b.WriteString("\n")
+			b.WriteString(strings.TrimSpace(string(out)))
+		}
+
+		diff := strings.TrimSpace(b.String())
+		if diff == "" {
+			return PreviewLoadedMsg{Lines: []string{}}
+		}
+		return PreviewLoadedMsg{Lines: strings.Split(diff, "\n")}
+	}
+}
+
+// togglePreview shows or hides the diff beside the message editor,
+// loading it the first time.
+func (m Model) togglePreview() (tea.Model, tea.Cmd) {
+	m.ShowPreview = !m.ShowPreview
+	if m.ShowPreview && m.Preview == nil {
+		return m, m.loadPreview()
+	}
+	return m, nil
+}
+
+// scrollPreview scrolls the preview by delta lines.
+func (m *Model) scrollPreview(delta int) {
+	maxScroll := max(len(m.Preview)-m.previewHeight(), 0)
+	m.PreviewScroll = min(max(m.PreviewScroll+delta, 0), maxScroll)
+}
+
+// previewHeight returns how many diff lines fit in the preview.
+func (m Model) previewHeight() int {
+	return max(m.Height-8, 5)
+}
+
+// previewWidth returns the width of the preview, including its border.
+func (m Model) previewWidth() int {
+	return max(m.Width-editorWidth-4, 30)
+}
+
+// viewPreview renders the scrollable diff pane.
+func (m Model) viewPreview() string {
+	var b strings.Builder
+	width := m.previewWidth() - 3
+	height := m.previewHeight()
+
+	b.WriteString(styles.Label.Render("Changes to commit"))
+	b.WriteString("\n\n")
+
+	switch {
+	case m.Preview == nil:
+		b.WriteString(styles.Help.Render("Loading diff..."))
+	case len(m.Preview) == 0:
+		b.WriteString(styles.Help.Render("No diff to show"))
+	default:
+		end := min(m.PreviewScroll+height, len(m.Preview))
+		for i := m.PreviewScroll; i < end; i++ {
+			line := runewidth.Truncate(strings.ReplaceAll(m.Preview[i], "\t", "    "), width, "")
+			b.WriteString(diffview.RenderLine(line))
+			b.WriteString("\n")
+		}
+		b.WriteString(styles.Help.Render(fmt.Sprintf("lines %d-%d of %d", m.PreviewScroll+1, end, len(m.Preview))))
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder(), false, false, false, true).
+		BorderForeground(styles.Subtle).
+		PaddingLeft(1).
+		Width(m.previewWidth()).
+		Render(b.String())
+}
@@ -0,0 +1,78 @@
+package commit
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/ai"
+	"github.com/ihatemodels/gdev/internal/ui/confirm"
+	"github.com/ihatemodels/gdev/internal/ui/form"
+	"github.com/ihatemodels/gdev/internal/ui/terminal"
+)
+
+// askHint opens the form for an optional hint before generating the message
+// again. With fresh set, the cache is skipped so a new message is generated
+// even for the same request.
+func (m Model) askHint(fresh bool) (Model, tea.Cmd) {
+	if _, err := m.backend(); err != nil {
+		m.ErrMsg = "Can't regenerate: " + err.Error()
+		return m, nil
+	}
+
+	m.HintForm = form.New(m.Config, "Regenerate Message",
+		form.Text("hint", "Hint (optional), e.g. mention the store refactor", ""))
+	m.HintForm.Fields[0].StartEdit(m.Config)
+	m.HintForm.Editing = true
+	m.AskingHint = true
+	m.BypassCache = fresh
+	m.ErrMsg = ""
+	return m, nil
+}
+
+// updateHint handles input for the hint form and starts generating once submitted.
+func (m Model) updateHint(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var res form.Result
+	m.HintForm, res = m.HintForm.Update(msg)
+
+	switch res {
+	case form.Submitted:
+		m.AskingHint = false
+		m.Regenerating = true
+		return m.startGenerating(m.HintForm.Value("hint"), m.BypassCache)
+	case form.Canceled:
+		m.AskingHint = false
+	}
+	return m, nil
+}
+
+// handleRegenerateDone asks before replacing the message being edited with
+// the one just generated.
+func (m Model) handleRegenerateDone(msg terminal.CommandDoneMsg) (Model, tea.Cmd) {
+	m.Regenerating = false
+	m.State = StateEditing
+
+	output, err := generated(msg)
+	if err != nil {
+		m.ErrMsg = "Regenerating failed: " + err.Error()
+		return m, nil
+	}
+	subject, body := parseCommitMessage(output)
+	if subject == "" {
+		m.ErrMsg = "Regenerating failed: no message was generated"
+		return m, nil
+	}
+
+	m.NewSubject, m.NewBody, m.NewFellBack = subject, body, ai.FallbackNote(output)
+	m.Confirm = confirm.New(m.Config, "Replace the commit message?", subject)
+	m.Confirming = true
+	m.OnConfirm = Model.replaceMessage
+	return m, nil
+}
+
+// replaceMessage replaces the message being edited with the regenerated one.
+func (m Model) replaceMessage() (Model, tea.Cmd) {
+	m.Type, m.Scope, m.Subject = splitSubject(m.NewSubject)
+	m.Body, m.FellBack = m.NewBody, m.NewFellBack
+	m.NewSubject, m.NewBody, m.NewFellBack = "", "", ""
+	m.EditingField = fieldSubject
+	m.CursorPos = len(m.Subject)
+	return m, nil
+}
@@ -0,0 +1,82 @@
+package commit
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/git"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+)
+
+// review shows the commit about to be made, to be confirmed before it is.
+func (m Model) review() (Model, tea.Cmd) {
+	m.Signing = git.CommitSigning(m.RepoPath)
+	m.State = StateReviewing
+	m.ErrMsg = ""
+	return m, nil
+}
+
+// handleReviewKey commits on confirming the review. Quitting goes back to
+// editing, handled with the other quit keys.
+func (m Model) handleReviewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	kb := m.Config.Keys()
+	if config.MatchesAny(key, kb.Form.Submit, kb.List.Select) {
+		return m.doCommit()
+	}
+	return m, nil
+}
+
+// viewReview renders the final message, the files it commits and how it
+// is signed.
+func (m Model) viewReview() string {
+	var b strings.Builder
+	kb := m.Config.Keys()
+
+	b.WriteString(styles.Title.Render("  Smart Commit"))
+	b.WriteString(styles.Help.Render(" (review)"))
+	b.WriteString("\n\n")
+
+	b.WriteString(styles.Label.Render("  Message:"))
+	b.WriteString("\n")
+	for i, line := range strings.Split(m.message(), "\n") {
+		if i == 0 {
+			b.WriteString(styles.Selected.Render("    " + line))
+		} else {
+			b.WriteString(styles.Value.Render("    " + line))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	files := m.selectedFiles()
+	b.WriteString(styles.Label.Render(fmt.Sprintf("  Files (%d):", len(files))))
+	if m.StagedOnly {
+		b.WriteString(styles.Help.Render(" staged changes only"))
+	}
+	b.WriteString("\n")
+	shown := min(len(files), max(m.Height-20, 5))
+	for _, f := range files[:shown] {
+		b.WriteString(styles.Dim.Render("    " + f.Status + " " + f.Path))
+		b.WriteString("\n")
+	}
+	if rest := len(files) - shown; rest > 0 {
+		b.WriteString(styles.Help.Render(fmt.Sprintf("    and %d more", rest)))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString(styles.Label.Render("  Signing: "))
+	if m.Signing.Enabled {
+		b.WriteString(styles.Selected.Render(m.Signing.String()))
+	} else {
+		b.WriteString(styles.Confirm.Render(m.Signing.String()))
+	}
+	b.WriteString("\n\n")
+
+	b.WriteString(styles.Help.Render(fmt.Sprintf("%s/%s commit • %s back to the message",
+		kb.List.Select, kb.Form.Submit, kb.Global.Quit)))
+	return b.String()
+}
@@ -0,0 +1,292 @@
+package commit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/ai"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/embedded"
+	"github.com/ihatemodels/gdev/internal/git"
+	"github.com/ihatemodels/gdev/internal/ui/confirm"
+	"github.com/ihatemodels/gdev/internal/ui/form"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+	"github.com/ihatemodels/gdev/internal/ui/terminal"
+)
+
+// splitCommit is one of the commits the selected files are split into.
+type splitCommit struct {
+	Files   []git.StatusFile
+	Message string
+}
+
+// subject returns the first line of the commit's message.
+func (c splitCommit) subject() string {
+	subject, _, _ := strings.Cut(c.Message, "\n")
+	return subject
+}
+
+// startSplitting asks the AI backend how to split the selected files into
+// several commits.
+func (m Model) startSplitting() (Model, tea.Cmd) {
+	backend, err := m.backend()
+	if err != nil {
+		m.ErrMsg = "Can't split: " + err.Error()
+		return m, nil
+	}
+
+	prompt := m.buildSplitPrompt()
+	m, ok := m.budgetAllows(backend, backend.CacheKey(prompt), false, Model.startSplitting)
+	if !ok {
+		return m, nil
+	}
+
+	m.Splitting = true
+	m.State = StateGenerating
+	m.Terminal = terminal.New(m.Config, "Proposing commits...")
+	m.Terminal.Dir = m.RepoPath
+	m.Terminal.SetSize(m.Width, m.Height)
+
+	cmd := m.Terminal.RunCachedFunc(backend.CacheKey(prompt), false, ai.Describe(backend), func(ctx context.Context, w io.Writer) error {
+		return backend.Generate(ctx, prompt, w)
+	})
+	return m, cmd
+}
+
+// buildSplitPrompt constructs the prompt asking for a split of the selected
+// files, with the same git context as the commit message prompt.
+func (m Model) buildSplitPrompt() string {
+	paths := m.selectedPaths()
+	gitDiff := runGitCommand(m.RepoPath, append([]string{"diff", m.diffBase(), "--"}, paths...)...)
+	gitStatus := runGitCommand(m.RepoPath, append([]string{"status", "--short", "--untracked-files=all", "--"}, paths...)...)
+	gitLog := runGitCommand(m.RepoPath, "log", "--oneline", "-5")
+
+	promptTemplate, err := embedded.GetCommandPrompt("split-commits")
+	if err != nil {
+		promptTemplate = "Split these changes into logical commits. Answer with a JSON array of {\"files\": [...], \"message\": \"...\"}."
+	}
+
+	return fmt.Sprintf(`## Context
+
+- Current git diff:
+%s
+
+- Current git status:
+%s
+
+- Recent commits for style reference:
+%s
+
+`, gitDiff, gitStatus, gitLog) + promptTemplate
+}
+
+// handleSplitDone shows the proposed commits, or goes back to the file list
+// if there is no usable proposal.
+func (m Model) handleSplitDone(msg terminal.CommandDoneMsg) (Model, tea.Cmd) {
+	m.Splitting = false
+	m.State = StateSelecting
+
+	if msg.Err != nil {
+		m.ErrMsg = "Splitting failed: " + msg.Err.Error()
+		return m, nil
+	}
+	output := strings.Join(msg.Output, "\n")
+	split, err := parseSplit(output, m.selectedFiles())
+	if err != nil {
+		m.ErrMsg = "Splitting failed: " + err.Error()
+		return m, nil
+	}
+
+	m.Split = split
+	m.FellBack = ai.FallbackNote(output)
+	m.SplitCursor = 0
+	m.State = StateSplit
+	m.ErrMsg = ""
+	return m, nil
+}
+
+// parseSplit reads the proposed commits from the backend's output. Paths
+// that aren't among files are dropped, as are repeated ones; files left out
+// of the proposal are put in a last commit with a message guessed from them.
+func parseSplit(output string, files []git.StatusFile) ([]splitCommit, error) {
+	output = ai.Result(output)
+	start, end := strings.Index(output, "["), strings.LastIndex(output, "]")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no list of commits in the response")
+	}
+	var proposal []struct {
+		Files   []string `json:"files"`
+		Message string   `json:"message"`
+	}
+	if err := json.Unmarshal([]byte(output[start:end+1]), &proposal); err != nil {
+		return nil, fmt.Errorf("reading the proposed commits: %w", err)
+	}
+
+	byPath := make(map[string]git.StatusFile, len(files))
+	for _, f := range files {
+		byPath[f.Path] = f
+	}
+	assigned := make(map[string]bool)
+
+	var split []splitCommit
+	for _, p := range proposal {
+		var c splitCommit
+		for _, path := range p.Files {
+			if f, ok := byPath[path]; ok && !assigned[path] {
+				assigned[path] = true
+				c.Files = append(c.Files, f)
+			}
+		}
+		c.Message = strings.TrimSpace(p.Message)
+		if len(c.Files) > 0 && c.Message != "" {
+			split = append(split, c)
+		} else {
+			for _, f := range c.Files {
+				delete(assigned, f.Path)
+			}
+		}
+	}
+
+	var rest []fileChange
+	var restFiles []git.StatusFile
+	for _, f := range files {
+		if !assigned[f.Path] {
+			rest = append(rest, fileChange{Path: f.Path, Status: f.Status})
+			restFiles = append(restFiles, f)
+		}
+	}
+	if len(rest) > 0 {
+		typ, subject, _ := offlineMessage(rest)
+		if typ != "" {
+			subject = typ + ": " + subject
+		}
+		split = append(split, splitCommit{Files: restFiles, Message: subject})
+	}
+	return split, nil
+}
+
+// handleSplitKey handles input while reviewing the proposed commits.
+func (m Model) handleSplitKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	kb := m.Config.Keys()
+
+	switch {
+	case config.MatchesAny(key, kb.Global.MoveUp, kb.Global.MoveUpAlt):
+		if m.SplitCursor > 0 {
+			m.SplitCursor--
+		}
+
+	case config.MatchesAny(key, kb.Global.MoveDown, kb.Global.MoveDownAlt):
+		if m.SplitCursor < len(m.Split)-1 {
+			m.SplitCursor++
+		}
+
+	case config.Matches(key, kb.List.Edit):
+		m.SplitForm = form.New(m.Config, fmt.Sprintf("Commit %d of %d", m.SplitCursor+1, len(m.Split)),
+			form.Text("subject", "Subject", m.Split[m.SplitCursor].subject()))
+		m.SplitForm.Fields[0].StartEdit(m.Config)
+		m.SplitForm.Editing = true
+		m.EditingSplit = true
+
+	case config.MatchesAny(key, kb.List.Select, kb.Form.Submit):
+		m.Confirm = confirm.New(m.Config, fmt.Sprintf("Make %d commits?", len(m.Split)),
+			"Each is staged and committed in turn, in the order shown.")
+		m.Confirming = true
+		m.OnConfirm = Model.commitSplit
+	}
+	return m, nil
+}
+
+// updateSplitForm handles input for the form editing a proposed subject.
+func (m Model) updateSplitForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var res form.Result
+	m.SplitForm, res = m.SplitForm.Update(msg)
+
+	switch res {
+	case form.Submitted:
+		m.EditingSplit = false
+		if subject := strings.TrimSpace(m.SplitForm.Value("subject")); subject != "" {
+			c := &m.Split[m.SplitCursor]
+			_, body, _ := strings.Cut(c.Message, "\n")
+			c.Message = strings.TrimRight(subject+"\n"+body, "\n")
+		}
+	case form.Canceled:
+		m.EditingSplit = false
+	}
+	return m, nil
+}
+
+// commitSplit stages and commits each proposed commit in turn, stopping at
+// the first that fails.
+func (m Model) commitSplit() (Model, tea.Cmd) {
+	var commands [][]string
+	m.MessageFiles = nil
+	for _, c := range m.Split {
+		file, err := m.writeMessageFile(c.Message)
+		if err != nil {
+			m.removeMessageFiles()
+			m.State = StateError
+			m.ErrMsg = "Commit failed: " + err.Error()
+			return m, nil
+		}
+		m.MessageFiles = append(m.MessageFiles, file)
+		commands = append(commands, stageAndCommit(c.Files, "-F", file)...)
+	}
+
+	m.State = StateCommitting
+	m.Terminal = terminal.New(m.Config, fmt.Sprintf("Making %d commits...", len(m.Split)))
+	m.Terminal.Dir = m.RepoPath
+	m.Terminal.SetSize(m.Width, m.Height)
+
+	agent := m.sshAgent()
+	cmd := m.Terminal.RunMutatingCommands(agent.Env(os.Environ()), agent.String(), commands...)
+	return m, cmd
+}
+
+// viewSplit renders the proposed commits with their files.
+func (m Model) viewSplit() string {
+	var b strings.Builder
+	kb := m.Config.Keys()
+
+	b.WriteString(styles.Title.Render("  Smart Commit"))
+	b.WriteString(styles.Help.Render(fmt.Sprintf(" (%d commits proposed)", len(m.Split))))
+	b.WriteString("\n\n")
+
+	for i, c := range m.Split {
+		subject := fmt.Sprintf("%d. %s", i+1, c.subject())
+		if i == m.SplitCursor {
+			b.WriteString(styles.Cursor.Render("▸ "))
+			b.WriteString(styles.Selected.Render(subject))
+		} else {
+			b.WriteString("  ")
+			b.WriteString(styles.Item.Render(subject))
+		}
+		b.WriteString("\n")
+		for _, p := range lintMessage(c.Message, m.Config.Settings.CommitLint) {
+			b.WriteString(styles.Confirm.Render("     ⚠ " + p))
+			b.WriteString("\n")
+		}
+		for _, f := range c.Files {
+			b.WriteString(styles.Dim.Render("     " + f.Status + " " + f.Path))
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString("\n")
+	if m.FellBack != "" {
+		b.WriteString(styles.Status.Render("  Proposed by " + m.FellBack))
+		b.WriteString("\n\n")
+	}
+	if m.BudgetNote != "" {
+		b.WriteString(styles.Confirm.Render("  ⚠ " + m.BudgetNote))
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString(styles.Help.Render(fmt.Sprintf("↑/%s ↓/%s choose • %s edit subject • %s make the commits • %s back to files",
+		kb.Global.MoveUp, kb.Global.MoveDown, kb.List.Edit, kb.List.Select, kb.Global.Quit)))
+	return b.String()
+}
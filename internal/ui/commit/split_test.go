@@ -0,0 +1,46 @@
+package commit
+
+import (
+	"testing"
+
+	"github.com/ihatemodels/gdev/internal/git"
+)
+
+func TestParseSplit(t *testing.T) {
+	files := []git.StatusFile{
+		{Path: "internal/store/todo.go", Status: " M"},
+		{Path: "internal/store/todo_test.go", Status: "??"},
+		{Path: "README.md", Status: " M"},
+		{Path: "Makefile", Status: " M"},
+	}
+	output := "Here you go:\n" + `[
+  {"files": ["internal/store/todo.go", "internal/store/todo_test.go"], "message": "feat: add todo export\n\n* for backups"},
+  {"files": ["README.md", "internal/store/todo.go", "missing.go"], "message": "docs: document export"},
+  {"files": ["Makefile"], "message": ""}
+]`
+
+	split, err := parseSplit(output, files)
+	if err != nil {
+		t.Fatalf("parseSplit() error = %v", err)
+	}
+	want := []struct {
+		subject string
+		files   int
+	}{
+		{"feat: add todo export", 2},
+		{"docs: document export", 1},
+		{"update Makefile", 1},
+	}
+	if len(split) != len(want) {
+		t.Fatalf("parseSplit() = %d commits; want %d", len(split), len(want))
+	}
+	for i, w := range want {
+		if split[i].subject() != w.subject || len(split[i].Files) != w.files {
+			t.Errorf("commit %d = %q with %d files; want %q with %d", i, split[i].subject(), len(split[i].Files), w.subject, w.files)
+		}
+	}
+
+	if _, err := parseSplit("no idea", files); err == nil {
+		t.Error("parseSplit() without JSON succeeded")
+	}
+}
@@ -0,0 +1,81 @@
+package commit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+	"github.com/ihatemodels/gdev/internal/ui/terminal"
+)
+
+// streaming reports whether a commit message is being generated, which is
+// shown as it is written rather than in the terminal modal.
+func (m Model) streaming() bool {
+	return m.State == StateGenerating && !m.Splitting
+}
+
+// generated returns the message the backend wrote, as the generation that
+// ended with msg left it. A generation stopped early keeps what was
+// written so far.
+func generated(msg terminal.CommandDoneMsg) (string, error) {
+	output := strings.TrimSpace(strings.Join(msg.Output, "\n"))
+	if err := msg.Err; err != nil && (!errors.Is(err, terminal.ErrCanceled) || output == "") {
+		return "", err
+	}
+	return output, nil
+}
+
+// handleStreamKey handles input while the message is being generated.
+func (m Model) handleStreamKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if config.Matches(msg.String(), m.Config.Keys().Global.Cancel) {
+		m.Terminal.Cancel()
+	}
+	return m, nil
+}
+
+// viewGenerating renders the message as the backend writes it, keeping the
+// last lines in view.
+func (m Model) viewGenerating() string {
+	var b strings.Builder
+	width := min(m.Width-8, editorWidth)
+
+	title := "Generating commit message"
+	if m.Regenerating {
+		title = "Regenerating commit message"
+	}
+	b.WriteString(styles.Title.Render(title))
+	b.WriteString(styles.Help.Render(" • " + m.Terminal.Command))
+	b.WriteString("\n\n")
+
+	text := strings.Join(append(m.Terminal.GetRawOutputLines(), m.Terminal.Partial()), "\n")
+	var lines []string
+	if strings.TrimSpace(text) == "" {
+		lines = []string{styles.Help.Render("Waiting for the first words...")}
+	} else {
+		wrapped := lipgloss.NewStyle().Width(width - 2).Render(strings.TrimLeft(text, "\n"))
+		lines = strings.Split(wrapped, "\n")
+		if height := max(m.Height-12, 5); len(lines) > height {
+			lines = lines[len(lines)-height:]
+		}
+		for i, line := range lines {
+			lines[i] = styles.Value.Render(strings.TrimRight(line, " "))
+		}
+		lines[len(lines)-1] += styles.Cursor.Render("▌")
+	}
+	b.WriteString(lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.Subtle).
+		Padding(0, 1).
+		Width(width).
+		Render(strings.Join(lines, "\n")))
+	b.WriteString("\n\n")
+
+	kb := m.Config.Keys()
+	b.WriteString(styles.Help.Render(fmt.Sprintf("%s stop and edit what was written • %s back",
+		kb.Global.Cancel, kb.Global.Quit)))
+	return b.String()
+}
@@ -0,0 +1,17 @@
+package confirm
+
+import (
+	"github.com/ihatemodels/gdev/internal/budget"
+	"github.com/ihatemodels/gdev/internal/config"
+)
+
+// OverBudget returns the dialog asking before a request past the budget s
+// is over. The confirm key alone doesn't send it: the provider's name must
+// be typed, so going past a budget is never an accident.
+func OverBudget(cfg *config.Config, s budget.Status) Model {
+	m := New(cfg, "Over the "+s.Provider+" budget",
+		s.String()+". Going past it costs more than you set out to spend.")
+	m.Destructive = true
+	m.TypeToConfirm = s.Provider
+	return m
+}
@@ -0,0 +1,130 @@
+// Package confirm provides a reusable modal confirmation dialog.
+package confirm
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+	"github.com/ihatemodels/gdev/internal/ui/textedit"
+)
+
+// Result is the outcome of a key press in the dialog.
+type Result int
+
+const (
+	Pending Result = iota
+	Confirmed
+	Canceled
+)
+
+// Model represents a confirmation dialog.
+type Model struct {
+	Title   string // question shown in the header
+	Message string // details about what will happen
+
+	// Destructive renders the dialog in error colors.
+	Destructive bool
+
+	// TypeToConfirm, when set, requires the user to type this text
+	// and press enter instead of a single confirm key.
+	TypeToConfirm string
+
+	Config *config.Config
+
+	input string // typed text for TypeToConfirm
+}
+
+// New creates a new confirmation dialog.
+func New(cfg *config.Config, title, message string) Model {
+	return Model{
+		Title:   title,
+		Message: message,
+		Config:  cfg,
+	}
+}
+
+// Update handles a key press and reports whether the dialog was answered.
+func (m Model) Update(msg tea.KeyMsg) (Model, Result) {
+	key := msg.String()
+	kb := m.Config.Keys()
+
+	if m.TypeToConfirm != "" {
+		switch {
+		case config.Matches(key, kb.Global.Quit):
+			return m, Canceled
+		case key == "enter":
+			if m.input == m.TypeToConfirm {
+				return m, Confirmed
+			}
+		case key == "backspace":
+			m.input, _ = textedit.Backspace(m.input, len(m.input))
+		default:
+			if typed, ok := textedit.Typed(msg); ok {
+				m.input += typed
+			}
+		}
+		return m, Pending
+	}
+
+	switch {
+	case config.Matches(key, kb.Confirm.Yes):
+		return m, Confirmed
+	case config.MatchesAny(key, kb.Confirm.No, kb.Global.Quit):
+		return m, Canceled
+	}
+	return m, Pending
+}
+
+// View renders the dialog box.
+func (m Model) View() string {
+	kb := m.Config.Keys()
+
+	titleStyle := styles.Confirm
+	borderColor := styles.Yellow
+	if m.Destructive {
+		titleStyle = styles.Error
+		borderColor = styles.Red
+	}
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(m.Title))
+	if m.Message != "" {
+		b.WriteString("\n\n")
+		b.WriteString(styles.Value.Render(m.Message))
+	}
+	b.WriteString("\n\n")
+
+	if m.TypeToConfirm != "" {
+		b.WriteString(styles.Help.Render("Type "))
+		b.WriteString(styles.Input.Render(m.TypeToConfirm))
+		b.WriteString(styles.Help.Render(" to confirm:"))
+		b.WriteString("\n")
+		b.WriteString(styles.Input.Render(m.input))
+		b.WriteString(styles.Cursor.Render("█"))
+		b.WriteString("\n\n")
+		b.WriteString(styles.Help.Render(fmt.Sprintf("enter confirm • %s cancel", kb.Global.Quit)))
+	} else {
+		b.WriteString(styles.Help.Render(fmt.Sprintf("%s confirm • %s cancel", kb.Confirm.Yes, kb.Confirm.No)))
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(borderColor).
+		Padding(1, 2).
+		Render(b.String())
+}
+
+// ViewCentered renders the dialog centered on screen.
+func (m Model) ViewCentered(screenWidth, screenHeight int) string {
+	return lipgloss.Place(
+		screenWidth,
+		screenHeight,
+		lipgloss.Center,
+		lipgloss.Center,
+		m.View(),
+	)
+}
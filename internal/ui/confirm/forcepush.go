@@ -0,0 +1,41 @@
+package confirm
+
+import (
+	"fmt"
+
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/git"
+)
+
+// ForcePush returns the dialog asking before p is pushed, saying how many
+// commits of the remote branch it discards. Discarding commits, or pushing
+// without a lease, which can't tell what it discards, needs the branch name
+// typed.
+func ForcePush(cfg *config.Config, p git.ForcePush) Model {
+	target := p.Remote + "/" + p.RemoteBranch
+	var message string
+	switch {
+	case !p.Leased():
+		message = fmt.Sprintf("%s was never fetched, so the push can't tell what it overwrites. "+
+			"It only goes through if the branch doesn't exist on %s; fetch first to replace it.", target, p.Remote)
+	case p.Discarded == 0:
+		message = fmt.Sprintf("No commits of %s are discarded. The push fails if it moved since the last fetch.", target)
+	default:
+		message = fmt.Sprintf("%s of %s will be discarded. The push fails if it moved since the last fetch.",
+			commitCount(p.Discarded), target)
+	}
+
+	m := New(cfg, "Force-push "+p.Branch+"?", message)
+	m.Destructive = p.Discarded > 0 || !p.Leased()
+	if m.Destructive {
+		m.TypeToConfirm = p.Branch
+	}
+	return m
+}
+
+func commitCount(n int) string {
+	if n == 1 {
+		return "1 commit"
+	}
+	return fmt.Sprintf("%d commits", n)
+}
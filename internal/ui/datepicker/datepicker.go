@@ -0,0 +1,203 @@
+// Package datepicker provides a calendar date picker with natural-language input.
+package datepicker
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+)
+
+// Result is the outcome of a key press in the picker.
+type Result int
+
+const (
+	Pending Result = iota
+	Picked
+	Cleared
+	Canceled
+)
+
+// Model represents a date picker.
+// Arrow keys move through the calendar, typed text is parsed with Parse
+// ("next tue", "in 3 days") and enter picks the highlighted date.
+type Model struct {
+	Title  string
+	Cursor time.Time // highlighted date (midnight)
+	Input  string    // natural-language input
+	Err    string
+
+	Config *config.Config
+
+	now func() time.Time
+}
+
+// New creates a date picker starting at initial, or today if initial is zero.
+func New(cfg *config.Config, title string, initial time.Time) Model {
+	m := Model{
+		Title:  title,
+		Config: cfg,
+		now:    time.Now,
+	}
+	if initial.IsZero() {
+		initial = m.now()
+	}
+	m.Cursor = Truncate(initial)
+	return m
+}
+
+// Value returns the picked date.
+func (m Model) Value() time.Time {
+	return m.Cursor
+}
+
+// Update handles a key press and reports whether a date was picked.
+func (m Model) Update(msg tea.KeyMsg) (Model, Result) {
+	key := msg.String()
+	kb := m.Config.Keys()
+
+	if config.Matches(key, kb.Global.Quit) {
+		return m, Canceled
+	}
+
+	switch msg.Type {
+	case tea.KeyLeft:
+		m.move(0, -1)
+		return m, Pending
+	case tea.KeyRight:
+		m.move(0, 1)
+		return m, Pending
+	case tea.KeyUp:
+		m.move(0, -7)
+		return m, Pending
+	case tea.KeyDown:
+		m.move(0, 7)
+		return m, Pending
+	case tea.KeyPgUp:
+		m.move(-1, 0)
+		return m, Pending
+	case tea.KeyPgDown:
+		m.move(1, 0)
+		return m, Pending
+	case tea.KeyHome:
+		m.Cursor = Truncate(m.now())
+		m.Input = ""
+		return m, Pending
+	}
+
+	switch key {
+	case "enter":
+		switch strings.ToLower(strings.TrimSpace(m.Input)) {
+		case "":
+			return m, Picked
+		case "none", "clear":
+			return m, Cleared
+		}
+		t, err := Parse(m.Input, m.now())
+		if err != nil {
+			m.Err = fmt.Sprintf("Can't understand %q", m.Input)
+			return m, Pending
+		}
+		m.Cursor = t
+		return m, Picked
+
+	case "backspace":
+		if len(m.Input) > 0 {
+			m.Input = m.Input[:len(m.Input)-1]
+			m.preview()
+		}
+
+	case "space":
+		m.Input += " "
+
+	default:
+		if len(key) == 1 && key[0] >= 32 && key[0] < 127 {
+			m.Input += key
+			m.preview()
+		}
+	}
+
+	return m, Pending
+}
+
+// move shifts the cursor and clears any typed input.
+func (m *Model) move(months, days int) {
+	m.Cursor = m.Cursor.AddDate(0, months, days)
+	m.Input = ""
+	m.Err = ""
+}
+
+// preview moves the cursor to the parsed input when it is valid.
+func (m *Model) preview() {
+	m.Err = ""
+	if t, err := Parse(m.Input, m.now()); err == nil {
+		m.Cursor = t
+	}
+}
+
+// View renders the calendar for the cursor's month.
+func (m Model) View() string {
+	var b strings.Builder
+
+	if m.Title != "" {
+		b.WriteString(styles.Title.Render(m.Title))
+		b.WriteString("\n\n")
+	}
+
+	header := m.Cursor.Format("January 2006")
+	b.WriteString(styles.Label.Render(fmt.Sprintf("%s%s", strings.Repeat(" ", (20-len(header))/2), header)))
+	b.WriteString("\n")
+	b.WriteString(styles.Help.Render("Mo Tu We Th Fr Sa Su"))
+	b.WriteString("\n")
+
+	today := Truncate(m.now())
+	first := time.Date(m.Cursor.Year(), m.Cursor.Month(), 1, 0, 0, 0, 0, m.Cursor.Location())
+	offset := (int(first.Weekday()) + 6) % 7 // Monday first
+	b.WriteString(strings.Repeat("   ", offset))
+
+	for d := first; d.Month() == first.Month(); d = d.AddDate(0, 0, 1) {
+		cell := fmt.Sprintf("%2d", d.Day())
+		switch {
+		case d.Equal(m.Cursor):
+			cell = styles.Selected.Reverse(true).Render(cell)
+		case d.Equal(today):
+			cell = styles.Repo.Underline(true).Render(cell)
+		default:
+			cell = styles.Value.Render(cell)
+		}
+		b.WriteString(cell)
+
+		if d.Weekday() == time.Sunday {
+			b.WriteString("\n")
+		} else {
+			b.WriteString(" ")
+		}
+	}
+	b.WriteString("\n\n")
+
+	b.WriteString(styles.Prompt.Render("> "))
+	b.WriteString(styles.Input.Render(m.Input))
+	b.WriteString(styles.Cursor.Render("█"))
+	b.WriteString(styles.Help.Render("  " + m.Cursor.Format("Mon, Jan 2 2006")))
+	b.WriteString("\n")
+	if m.Err != "" {
+		b.WriteString(styles.Error.Render(m.Err))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	kb := m.Config.Keys()
+	b.WriteString(styles.Help.Render("←/→ day • ↑/↓ week • pgup/pgdn month • type \"next tue\""))
+	b.WriteString("\n")
+	b.WriteString(styles.Help.Render(fmt.Sprintf("enter pick • \"none\" clear • %s cancel", kb.Global.Quit)))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.Purple).
+		Padding(0, 1).
+		Render(b.String())
+}
@@ -0,0 +1,127 @@
+package datepicker
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnknownDate is returned when text cannot be parsed as a date.
+var ErrUnknownDate = errors.New("unrecognized date")
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// absoluteLayouts are the accepted formats for explicit dates.
+var absoluteLayouts = []string{"2006-01-02", "Jan 2 2006", "2 Jan 2006", "Jan 2", "2 Jan", "01/02"}
+
+// Parse interprets natural-language date text relative to now.
+// It returns the date at midnight in now's location.
+//
+// Supported forms include "today", "tomorrow", weekday names ("tue",
+// "next friday" - both mean the upcoming one), "next week", "next month",
+// "eom", relative offsets ("in 3 days", "+2w", "5d") and absolute dates
+// ("2025-01-31", "jan 31").
+func Parse(text string, now time.Time) (time.Time, error) {
+	text = strings.ToLower(strings.Join(strings.Fields(text), " "))
+	today := Truncate(now)
+
+	switch text {
+	case "":
+		return time.Time{}, ErrUnknownDate
+	case "today", "now":
+		return today, nil
+	case "tomorrow", "tmr", "tom":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "next week":
+		return today.AddDate(0, 0, 7), nil
+	case "next month":
+		return today.AddDate(0, 1, 0), nil
+	case "eom", "end of month":
+		return time.Date(today.Year(), today.Month()+1, 0, 0, 0, 0, 0, today.Location()), nil
+	case "eow", "end of week":
+		return nextWeekday(today, time.Sunday), nil
+	}
+
+	if day, ok := weekdays[strings.TrimPrefix(text, "next ")]; ok {
+		return nextWeekday(today, day), nil
+	}
+
+	if t, ok := parseOffset(text, today); ok {
+		return t, nil
+	}
+
+	for _, layout := range absoluteLayouts {
+		t, err := time.ParseInLocation(layout, text, now.Location())
+		if err != nil {
+			continue
+		}
+		if !strings.Contains(layout, "2006") {
+			// No year given: use the next occurrence of that date
+			t = t.AddDate(today.Year()-t.Year(), 0, 0)
+			if t.Before(today) {
+				t = t.AddDate(1, 0, 0)
+			}
+		}
+		return t, nil
+	}
+
+	return time.Time{}, ErrUnknownDate
+}
+
+// parseOffset handles "in 3 days", "+2w", "5d" and similar relative offsets.
+func parseOffset(text string, today time.Time) (time.Time, bool) {
+	text = strings.TrimPrefix(text, "in ")
+	text = strings.TrimPrefix(text, "+")
+	text = strings.ReplaceAll(text, " ", "")
+
+	i := 0
+	for i < len(text) && text[i] >= '0' && text[i] <= '9' {
+		i++
+	}
+	if i == 0 || i == len(text) {
+		return time.Time{}, false
+	}
+
+	n, err := strconv.Atoi(text[:i])
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	switch strings.TrimSuffix(text[i:], "s") {
+	case "d", "day":
+		return today.AddDate(0, 0, n), true
+	case "w", "wk", "week":
+		return today.AddDate(0, 0, 7*n), true
+	case "m", "mo", "month":
+		return today.AddDate(0, n, 0), true
+	case "y", "yr", "year":
+		return today.AddDate(n, 0, 0), true
+	}
+	return time.Time{}, false
+}
+
+// nextWeekday returns the first date after today that falls on day.
+func nextWeekday(today time.Time, day time.Weekday) time.Time {
+	diff := (int(day) - int(today.Weekday()) + 7) % 7
+	if diff == 0 {
+		diff = 7
+	}
+	return today.AddDate(0, 0, diff)
+}
+
+// Truncate returns t at midnight in its location.
+func Truncate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
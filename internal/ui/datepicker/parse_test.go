@@ -0,0 +1,55 @@
+package datepicker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	// Wednesday, 15 January 2025
+	now := time.Date(2025, 1, 15, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"today", "2025-01-15"},
+		{"Tomorrow", "2025-01-16"},
+		{"yesterday", "2025-01-14"},
+		{"fri", "2025-01-17"},
+		{"next tue", "2025-01-21"},
+		{"wednesday", "2025-01-22"}, // same weekday means next week
+		{"next week", "2025-01-22"},
+		{"next month", "2025-02-15"},
+		{"eom", "2025-01-31"},
+		{"in 3 days", "2025-01-18"},
+		{"+2w", "2025-01-29"},
+		{"5d", "2025-01-20"},
+		{"in 1 month", "2025-02-15"},
+		{"2025-03-01", "2025-03-01"},
+		{"jan 20", "2025-01-20"},
+		{"jan 2", "2026-01-02"}, // already passed this year
+		{"3 feb", "2025-02-03"},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.text, now)
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %v", tt.text, err)
+			continue
+		}
+		if got.Format("2006-01-02") != tt.expected {
+			t.Errorf("Parse(%q) = %s, want %s", tt.text, got.Format("2006-01-02"), tt.expected)
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	now := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	for _, text := range []string{"", "someday", "in x days", "3 fortnights", "2025-13-01"} {
+		if _, err := Parse(text, now); err == nil {
+			t.Errorf("Parse(%q) should fail", text)
+		}
+	}
+}
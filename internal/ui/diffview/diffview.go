@@ -0,0 +1,417 @@
+// Package diffview provides a diff viewer that loads file diffs lazily,
+// so large diffs stay responsive.
+package diffview
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/git"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+)
+
+// cacheSize is the number of file diffs kept in memory.
+const cacheSize = 32
+
+// Result is the outcome of a message in the viewer.
+type Result int
+
+const (
+	Pending Result = iota
+	Closed
+)
+
+// Pane is the focused side of the viewer.
+type Pane int
+
+const (
+	FilesPane Pane = iota
+	ContentPane
+)
+
+type (
+	// FilesLoadedMsg carries the list of changed files.
+	FilesLoadedMsg struct {
+		Files []git.FileChange
+		Err   error
+	}
+
+	// FileDiffLoadedMsg carries the diff of a single file.
+	FileDiffLoadedMsg struct {
+		Path  string
+		Lines []string
+		Err   error
+	}
+)
+
+// Model represents the diff viewer.
+// Only the file list is loaded up front; a file's diff is loaded when it is
+// selected and the most recent ones are cached.
+type Model struct {
+	Title    string
+	RepoRoot string
+	Revs     []string // arguments passed to git diff, e.g. "HEAD" or "main...feature"
+
+	Files      []git.FileChange
+	FileCursor int
+	FileScroll int
+	Focus      Pane
+
+	Scroll  int // first visible line of the current file
+	Loading bool
+	Err     string
+
+	Width  int
+	Height int
+
+	Config *config.Config
+
+	cache   map[string][]string
+	order   []string // cached paths, least recently loaded first
+	pending string   // path being loaded
+}
+
+// New creates a diff viewer for `git diff <revs>` in repoRoot.
+func New(cfg *config.Config, title, repoRoot string, revs ...string) Model {
+	return Model{
+		Title:    title,
+		RepoRoot: repoRoot,
+		Revs:     revs,
+		Loading:  true,
+		Config:   cfg,
+		cache:    make(map[string][]string),
+	}
+}
+
+// SetSize updates the available space.
+func (m *Model) SetSize(width, height int) {
+	m.Width = width
+	m.Height = height
+}
+
+// Init loads the list of changed files.
+func (m Model) Init() tea.Cmd {
+	root, revs := m.RepoRoot, m.Revs
+	return func() tea.Msg {
+		files, err := git.ChangedFiles(root, revs...)
+		return FilesLoadedMsg{Files: files, Err: err}
+	}
+}
+
+// loadFile returns a command loading the selected file's diff, or nil if it is cached.
+func (m *Model) loadFile() tea.Cmd {
+	if len(m.Files) == 0 {
+		return nil
+	}
+	fc := m.Files[m.FileCursor]
+	if _, ok := m.cache[fc.Path]; ok {
+		m.pending = ""
+		return nil
+	}
+
+	m.pending = fc.Path
+	root, revs := m.RepoRoot, m.Revs
+	return func() tea.Msg {
+		diff, err := git.FileDiff(root, fc, revs...)
+		return FileDiffLoadedMsg{Path: fc.Path, Lines: strings.Split(strings.TrimRight(diff, "\n"), "\n"), Err: err}
+	}
+}
+
+// store caches a file diff, evicting the oldest entry when full.
+func (m *Model) store(path string, lines []string) {
+	if _, ok := m.cache[path]; !ok {
+		m.order = append(m.order, path)
+	}
+	m.cache[path] = lines
+	if len(m.order) > cacheSize {
+		delete(m.cache, m.order[0])
+		m.order = m.order[1:]
+	}
+}
+
+// lines returns the diff of the selected file, or nil if it is not loaded.
+func (m Model) lines() []string {
+	if len(m.Files) == 0 {
+		return nil
+	}
+	return m.cache[m.Files[m.FileCursor].Path]
+}
+
+// Update handles loaded diffs and key presses and reports whether the viewer was closed.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd, Result) {
+	switch msg := msg.(type) {
+	case FilesLoadedMsg:
+		m.Loading = false
+		if msg.Err != nil {
+			m.Err = msg.Err.Error()
+			return m, nil, Pending
+		}
+		m.Files = msg.Files
+		return m, m.loadFile(), Pending
+
+	case FileDiffLoadedMsg:
+		if msg.Err != nil {
+			m.Err = msg.Err.Error()
+		}
+		m.store(msg.Path, msg.Lines)
+		if msg.Path == m.pending {
+			m.pending = ""
+		}
+		return m, nil, Pending
+
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+	return m, nil, Pending
+}
+
+func (m Model) handleKey(msg tea.KeyMsg) (Model, tea.Cmd, Result) {
+	key := msg.String()
+	kb := m.Config.Keys()
+
+	switch {
+	case config.MatchesAny(key, kb.Global.Quit, kb.Global.QuitAlt):
+		return m, nil, Closed
+
+	case config.Matches(key, kb.Diff.SwitchPane):
+		if m.Focus == FilesPane {
+			m.Focus = ContentPane
+		} else {
+			m.Focus = FilesPane
+		}
+
+	case config.Matches(key, kb.Diff.NextFile):
+		return m, m.selectFile(m.FileCursor + 1), Pending
+
+	case config.Matches(key, kb.Diff.PrevFile):
+		return m, m.selectFile(m.FileCursor - 1), Pending
+
+	case m.Focus == FilesPane:
+		switch {
+		case config.MatchesAny(key, kb.Global.MoveUp, kb.Global.MoveUpAlt):
+			return m, m.selectFile(m.FileCursor - 1), Pending
+		case config.MatchesAny(key, kb.Global.MoveDown, kb.Global.MoveDownAlt):
+			return m, m.selectFile(m.FileCursor + 1), Pending
+		case config.Matches(key, kb.List.Top):
+			return m, m.selectFile(0), Pending
+		case config.Matches(key, kb.List.Bottom):
+			return m, m.selectFile(len(m.Files) - 1), Pending
+		case config.Matches(key, kb.List.Select):
+			m.Focus = ContentPane
+		}
+
+	default:
+		page := m.contentHeight()
+		switch {
+		case config.MatchesAny(key, kb.Global.MoveUp, kb.Global.MoveUpAlt):
+			m.scrollBy(-1)
+		case config.MatchesAny(key, kb.Global.MoveDown, kb.Global.MoveDownAlt):
+			m.scrollBy(1)
+		case config.MatchesAny(key, kb.List.PageUp, "pgup"):
+			m.scrollBy(-page)
+		case config.MatchesAny(key, kb.List.PageDown, "pgdown"):
+			m.scrollBy(page)
+		case config.Matches(key, kb.List.Top):
+			m.Scroll = 0
+		case config.Matches(key, kb.List.Bottom):
+			m.scrollBy(len(m.lines()))
+		}
+	}
+
+	return m, nil, Pending
+}
+
+// selectFile moves the file cursor to idx and loads its diff.
+func (m *Model) selectFile(idx int) tea.Cmd {
+	if idx < 0 || idx >= len(m.Files) || idx == m.FileCursor {
+		return nil
+	}
+	m.FileCursor = idx
+	m.Scroll = 0
+
+	visible := m.contentHeight()
+	if m.FileCursor < m.FileScroll {
+		m.FileScroll = m.FileCursor
+	} else if m.FileCursor >= m.FileScroll+visible {
+		m.FileScroll = m.FileCursor - visible + 1
+	}
+	return m.loadFile()
+}
+
+// scrollBy scrolls the content, keeping the last page in view.
+func (m *Model) scrollBy(delta int) {
+	m.Scroll += delta
+	maxScroll := len(m.lines()) - m.contentHeight()
+	if m.Scroll > maxScroll {
+		m.Scroll = maxScroll
+	}
+	if m.Scroll < 0 {
+		m.Scroll = 0
+	}
+}
+
+func (m Model) contentHeight() int {
+	h := m.Height - 8
+	if h < 5 {
+		h = 5
+	}
+	return h
+}
+
+func (m Model) sidebarWidth() int {
+	w := m.Width / 3
+	if w > 50 {
+		w = 50
+	}
+	if w < 24 {
+		w = 24
+	}
+	return w
+}
+
+// View renders the file list, the selected file's diff and a progress line.
+func (m Model) View() string {
+	var b strings.Builder
+
+	b.WriteString(styles.Title.Render("  " + m.Title))
+	b.WriteString("\n")
+	b.WriteString(styles.Help.Render(strings.Repeat("─", max(m.Width-4, 10))))
+	b.WriteString("\n")
+
+	switch {
+	case m.Loading:
+		b.WriteString(styles.Help.Render("  Loading changed files..."))
+		b.WriteString("\n")
+	case m.Err != "" && len(m.Files) == 0:
+		b.WriteString(styles.Error.Render("  " + m.Err))
+		b.WriteString("\n")
+	case len(m.Files) == 0:
+		b.WriteString(styles.Help.Render("  No changes"))
+		b.WriteString("\n")
+	default:
+		sidebar := lipgloss.NewStyle().Width(m.sidebarWidth()).Render(m.viewFiles())
+		content := lipgloss.NewStyle().
+			Border(lipgloss.NormalBorder(), false, false, false, true).
+			BorderForeground(styles.Subtle).
+			PaddingLeft(1).
+			Render(m.viewContent())
+		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, sidebar, content))
+		b.WriteString("\n")
+		b.WriteString(m.viewProgress())
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	kb := m.Config.Keys()
+	b.WriteString(styles.Help.Render(fmt.Sprintf("↑/%s ↓/%s navigate • %s switch pane • %s/%s prev/next file • %s/%s page • %s back",
+		kb.Global.MoveUp, kb.Global.MoveDown, kb.Diff.SwitchPane, kb.Diff.PrevFile, kb.Diff.NextFile,
+		kb.List.PageUp, kb.List.PageDown, kb.Global.Quit)))
+
+	return b.String()
+}
+
+func (m Model) viewFiles() string {
+	var b strings.Builder
+	width := m.sidebarWidth() - 2
+	visible := m.contentHeight()
+
+	end := min(m.FileScroll+visible, len(m.Files))
+	for i := m.FileScroll; i < end; i++ {
+		fc := m.Files[i]
+
+		stat := "bin"
+		if !fc.Binary {
+			stat = fmt.Sprintf("+%d -%d", fc.Additions, fc.Deletions)
+		}
+		name := fc.Path
+		if avail := width - len(stat) - 3; len(name) > avail && avail > 3 {
+			name = "…" + name[len(name)-avail+1:]
+		}
+
+		if i == m.FileCursor {
+			style := styles.Selected
+			if m.Focus != FilesPane {
+				style = styles.Label
+			}
+			b.WriteString(styles.Cursor.Render("▸ "))
+			b.WriteString(style.Render(name))
+		} else {
+			b.WriteString("  ")
+			b.WriteString(styles.Item.Render(name))
+		}
+		b.WriteString(" ")
+		b.WriteString(styles.Help.Render(stat))
+		if i < end-1 {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+func (m Model) viewContent() string {
+	lines := m.lines()
+	if lines == nil {
+		return styles.Help.Render("Loading diff...")
+	}
+
+	width := m.Width - m.sidebarWidth() - 6
+	end := min(m.Scroll+m.contentHeight(), len(lines))
+
+	var b strings.Builder
+	for i := m.Scroll; i < end; i++ {
+		line := strings.ReplaceAll(lines[i], "\t", "    ")
+		if width > 0 && len(line) > width {
+			line = line[:width]
+		}
+		b.WriteString(RenderLine(line))
+		if i < end-1 {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// RenderLine colors a diff line by its prefix.
+func RenderLine(line string) string {
+	switch {
+	case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"),
+		strings.HasPrefix(line, "diff "), strings.HasPrefix(line, "index "):
+		return styles.Dim.Render(line)
+	case strings.HasPrefix(line, "@@"):
+		return styles.Prompt.Render(line)
+	case strings.HasPrefix(line, "+"):
+		return lipgloss.NewStyle().Foreground(styles.Green).Render(line)
+	case strings.HasPrefix(line, "-"):
+		return lipgloss.NewStyle().Foreground(styles.Red).Render(line)
+	}
+	return styles.Value.Render(line)
+}
+
+// viewProgress renders the position within the file list and the current file.
+func (m Model) viewProgress() string {
+	var adds, dels int
+	for _, fc := range m.Files {
+		adds += fc.Additions
+		dels += fc.Deletions
+	}
+	parts := []string{
+		fmt.Sprintf("file %d/%d", m.FileCursor+1, len(m.Files)),
+		fmt.Sprintf("+%d -%d total", adds, dels),
+	}
+
+	if lines := m.lines(); lines != nil {
+		end := min(m.Scroll+m.contentHeight(), len(lines))
+		pct := 100
+		if len(lines) > m.contentHeight() {
+			pct = end * 100 / len(lines)
+		}
+		parts = append(parts, fmt.Sprintf("lines %d-%d/%d (%d%%)", m.Scroll+1, end, len(lines), pct))
+	} else if m.pending != "" {
+		parts = append(parts, "loading…")
+	}
+
+	return styles.Help.Render("  " + strings.Join(parts, "  •  "))
+}
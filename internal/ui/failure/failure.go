@@ -0,0 +1,164 @@
+// Package failure provides a shared result for failed commands and a
+// standard error screen offering retry, details and back.
+//
+// Commands built with Cmd report errors as a Msg carrying a command to
+// retry. The app shows the error screen for any Msg, and also forwards it
+// to the active component so it can leave its loading state.
+package failure
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+)
+
+// Msg reports a failed command.
+type Msg struct {
+	Op    string  // what was being done, e.g. "Load TODOs"
+	Err   error   // the failure
+	Retry tea.Cmd // runs the command again; nil if it can't be retried
+}
+
+// Error implements error.
+func (m Msg) Error() string {
+	return m.Op + ": " + m.Err.Error()
+}
+
+// Cmd returns a command that runs fn and returns its message.
+// If fn fails, the command returns a Msg that retries it.
+func Cmd(op string, fn func() (tea.Msg, error)) tea.Cmd {
+	var cmd tea.Cmd
+	cmd = func() tea.Msg {
+		msg, err := fn()
+		if err != nil {
+			return Msg{Op: op, Err: err, Retry: cmd}
+		}
+		return msg
+	}
+	return cmd
+}
+
+// Result is the outcome of a key press on the error screen.
+type Result int
+
+const (
+	Pending Result = iota
+	Retry
+	Back
+)
+
+// Model is the error screen for a failed command.
+type Model struct {
+	Failure     Msg
+	ShowDetails bool
+
+	Config *config.Config
+}
+
+// New creates an error screen for f.
+func New(cfg *config.Config, f Msg) Model {
+	return Model{
+		Failure: f,
+		Config:  cfg,
+	}
+}
+
+// Update handles a key press and reports whether to retry or go back.
+func (m Model) Update(msg tea.KeyMsg) (Model, Result) {
+	key := msg.String()
+	kb := m.Config.Keys()
+
+	switch {
+	case config.Matches(key, kb.Error.Retry) && m.Failure.Retry != nil:
+		return m, Retry
+	case config.Matches(key, kb.Error.Details):
+		m.ShowDetails = !m.ShowDetails
+	case config.MatchesAny(key, kb.Global.Quit, kb.Global.QuitAlt):
+		return m, Back
+	}
+	return m, Pending
+}
+
+// View renders the error box.
+func (m Model) View() string {
+	kb := m.Config.Keys()
+
+	var b strings.Builder
+	b.WriteString(styles.Error.Render(m.Failure.Op + " failed"))
+	b.WriteString("\n\n")
+
+	// Command errors often end with multi-line stderr; the first line is the summary
+	summary, _, _ := strings.Cut(m.Failure.Err.Error(), "\n")
+	b.WriteString(render(styles.Value, summary))
+	b.WriteString("\n\n")
+
+	if m.ShowDetails {
+		b.WriteString(styles.Label.Render("Details"))
+		b.WriteString("\n")
+		for _, line := range details(m.Failure.Err) {
+			b.WriteString(render(styles.Dim, line))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
+	var help []string
+	if m.Failure.Retry != nil {
+		help = append(help, fmt.Sprintf("%s retry", kb.Error.Retry))
+	}
+	if m.ShowDetails {
+		help = append(help, fmt.Sprintf("%s hide details", kb.Error.Details))
+	} else {
+		help = append(help, fmt.Sprintf("%s details", kb.Error.Details))
+	}
+	help = append(help, fmt.Sprintf("%s back", kb.Global.Quit))
+	b.WriteString(styles.Help.Render(strings.Join(help, " • ")))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.Red).
+		Padding(1, 2).
+		Render(b.String())
+}
+
+// ViewCentered renders the error box centered on screen.
+func (m Model) ViewCentered(screenWidth, screenHeight int) string {
+	return lipgloss.Place(
+		screenWidth,
+		screenHeight,
+		lipgloss.Center,
+		lipgloss.Center,
+		m.View(),
+	)
+}
+
+// maxTextWidth is the width error text wraps at.
+const maxTextWidth = 76
+
+// render renders text with style, wrapping long lines.
+func render(style lipgloss.Style, text string) string {
+	if lipgloss.Width(text) > maxTextWidth {
+		style = style.Width(maxTextWidth)
+	}
+	return style.Render(text)
+}
+
+// details lists the full error text, the stderr of a failed command,
+// and the type of each wrapped error.
+func details(err error) []string {
+	lines := strings.Split(strings.TrimSpace(err.Error()), "\n")
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		lines = append(lines, strings.Split(strings.TrimSpace(string(exitErr.Stderr)), "\n")...)
+	}
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		lines = append(lines, fmt.Sprintf("  %T", e))
+	}
+	return lines
+}
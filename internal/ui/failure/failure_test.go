@@ -0,0 +1,33 @@
+package failure
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCmd_Retry(t *testing.T) {
+	calls := 0
+	cmd := Cmd("Load", func() (tea.Msg, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("boom")
+		}
+		return "loaded", nil
+	})
+
+	f, ok := cmd().(Msg)
+	if !ok {
+		t.Fatal("first call did not return a failure")
+	}
+	if f.Op != "Load" || f.Err.Error() != "boom" {
+		t.Errorf("failure = %q, want Load: boom", f.Error())
+	}
+	if f.Retry == nil {
+		t.Fatal("failure has no retry command")
+	}
+	if got := f.Retry(); got != "loaded" {
+		t.Errorf("retry returned %v, want loaded", got)
+	}
+}
@@ -0,0 +1,402 @@
+// Package filefinder provides a fuzzy file finder over the files of a git repository.
+package filefinder
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/git"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+)
+
+const (
+	batchSize    = 500
+	previewLines = 20
+	previewBytes = 8 * 1024
+)
+
+// Result is the outcome of a message in the finder.
+type Result int
+
+const (
+	Pending Result = iota
+	Selected
+	Canceled
+)
+
+type (
+	// FilesMsg carries a batch of paths streamed from git ls-files.
+	FilesMsg struct {
+		Paths []string
+		Done  bool
+		Err   error
+
+		stream <-chan FilesMsg
+	}
+)
+
+// Model represents a fuzzy file finder.
+// Files are streamed in as git lists them, so results appear before the
+// listing finishes in large repositories.
+type Model struct {
+	Title    string
+	RepoRoot string
+
+	Files   []string
+	Matches []Match
+	Input   string
+	Cursor  int
+	Scroll  int
+	Loading bool
+	Err     string
+
+	// Preview holds the first lines of the highlighted file.
+	Preview     []string
+	previewPath string
+
+	Width  int
+	Height int
+
+	Config *config.Config
+
+	value string
+	done  chan struct{} // closed to stop streaming once the finder is closed
+}
+
+// New creates a new file finder for the repository at repoRoot.
+func New(cfg *config.Config, title, repoRoot string) Model {
+	return Model{
+		Title:    title,
+		RepoRoot: repoRoot,
+		Loading:  true,
+		Config:   cfg,
+		done:     make(chan struct{}),
+	}
+}
+
+// SetSize updates the available space.
+func (m *Model) SetSize(width, height int) {
+	m.Width = width
+	m.Height = height
+}
+
+// Init starts streaming the repository's files.
+func (m Model) Init() tea.Cmd {
+	stream := make(chan FilesMsg)
+	done := m.done
+	go func() {
+		defer close(stream)
+		err := git.StreamFiles(m.RepoRoot, batchSize, func(paths []string) bool {
+			select {
+			case stream <- FilesMsg{Paths: paths}:
+				return true
+			case <-done:
+				return false
+			}
+		})
+		select {
+		case stream <- FilesMsg{Done: true, Err: err}:
+		case <-done:
+		}
+	}()
+	return waitForFiles(stream)
+}
+
+// waitForFiles returns a command that receives the next batch from stream.
+func waitForFiles(stream <-chan FilesMsg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-stream
+		if !ok {
+			return nil
+		}
+		msg.stream = stream
+		return msg
+	}
+}
+
+// Value returns the selected path, relative to the repository root.
+func (m Model) Value() string {
+	return m.value
+}
+
+// Update handles streamed files and key presses and reports whether a file was selected.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd, Result) {
+	switch msg := msg.(type) {
+	case FilesMsg:
+		m.Files = append(m.Files, msg.Paths...)
+		if msg.Err != nil {
+			m.Err = msg.Err.Error()
+		}
+		m.refresh(false)
+		if msg.Done {
+			m.Loading = false
+			return m, nil, Pending
+		}
+		return m, waitForFiles(msg.stream), Pending
+
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+	return m, nil, Pending
+}
+
+func (m Model) handleKey(msg tea.KeyMsg) (Model, tea.Cmd, Result) {
+	key := msg.String()
+	kb := m.Config.Keys()
+
+	switch {
+	case config.Matches(key, kb.Global.Quit):
+		m.stop()
+		return m, nil, Canceled
+
+	case key == "enter":
+		if len(m.Matches) > 0 {
+			m.value = m.Matches[m.Cursor].Path
+			m.stop()
+			return m, nil, Selected
+		}
+
+	case config.MatchesAny(key, kb.Global.MoveUpAlt, "ctrl+p"):
+		if m.Cursor > 0 {
+			m.Cursor--
+			if m.Cursor < m.Scroll {
+				m.Scroll = m.Cursor
+			}
+			m.loadPreview()
+		}
+
+	case config.MatchesAny(key, kb.Global.MoveDownAlt, "ctrl+n"):
+		if m.Cursor < len(m.Matches)-1 {
+			m.Cursor++
+			if m.Cursor >= m.Scroll+m.visibleItems() {
+				m.Scroll = m.Cursor - m.visibleItems() + 1
+			}
+			m.loadPreview()
+		}
+
+	case key == "backspace":
+		if len(m.Input) > 0 {
+			m.Input = m.Input[:len(m.Input)-1]
+			m.refresh(true)
+		}
+
+	case key == "space":
+		m.Input += " "
+		m.refresh(true)
+
+	default:
+		if len(key) == 1 && key[0] >= 32 && key[0] < 127 {
+			m.Input += key
+			m.refresh(true)
+		}
+	}
+
+	return m, nil, Pending
+}
+
+// stop ends file streaming if it is still running.
+func (m *Model) stop() {
+	if m.done != nil {
+		close(m.done)
+		m.done = nil
+	}
+}
+
+// refresh re-runs the query. The cursor is reset when the query changed,
+// otherwise it stays on the same file while new batches arrive.
+func (m *Model) refresh(queryChanged bool) {
+	current := ""
+	if !queryChanged && m.Cursor < len(m.Matches) {
+		current = m.Matches[m.Cursor].Path
+	}
+
+	m.Matches = Find(m.Files, m.Input)
+	m.Cursor = 0
+	m.Scroll = 0
+	if current != "" {
+		for i, match := range m.Matches {
+			if match.Path == current {
+				m.Cursor = i
+				if m.Cursor >= m.visibleItems() {
+					m.Scroll = m.Cursor - m.visibleItems() + 1
+				}
+				break
+			}
+		}
+	}
+	m.loadPreview()
+}
+
+// loadPreview reads the head of the highlighted file.
+func (m *Model) loadPreview() {
+	if len(m.Matches) == 0 {
+		m.Preview = nil
+		m.previewPath = ""
+		return
+	}
+	p := m.Matches[m.Cursor].Path
+	if p == m.previewPath {
+		return
+	}
+	m.previewPath = p
+	m.Preview = readHead(filepath.Join(m.RepoRoot, p))
+}
+
+// readHead returns the first lines of a file, or a placeholder for
+// unreadable and binary files.
+func readHead(path string) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return []string{"(cannot read file)"}
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, previewBytes))
+	if err != nil {
+		return []string{"(cannot read file)"}
+	}
+	if bytes.IndexByte(data, 0) >= 0 {
+		return []string{"(binary file)"}
+	}
+	if len(data) == 0 {
+		return []string{"(empty file)"}
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() && len(lines) < previewLines {
+		lines = append(lines, strings.ReplaceAll(scanner.Text(), "\t", "    "))
+	}
+	return lines
+}
+
+func (m Model) visibleItems() int {
+	n := m.Height - 12
+	if n < 5 {
+		n = 5
+	}
+	return n
+}
+
+// View renders the finder with the result list and a preview of the highlighted file.
+func (m Model) View() string {
+	var b strings.Builder
+
+	if m.Title != "" {
+		b.WriteString(styles.Title.Render(m.Title))
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString(styles.Prompt.Render("> "))
+	b.WriteString(styles.Input.Render(m.Input))
+	b.WriteString(styles.Cursor.Render("█"))
+	status := fmt.Sprintf("  %d/%d", len(m.Matches), len(m.Files))
+	if m.Loading {
+		status += " (loading…)"
+	}
+	b.WriteString(styles.Help.Render(status))
+	b.WriteString("\n\n")
+
+	listWidth := m.Width/2 - 4
+	if listWidth < 30 {
+		listWidth = 30
+	}
+	list := m.viewList(listWidth)
+	preview := m.viewPreview(m.Width - listWidth - 10)
+
+	if m.Width >= 100 {
+		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top,
+			lipgloss.NewStyle().Width(listWidth).Render(list),
+			"  ",
+			preview))
+	} else {
+		b.WriteString(list)
+	}
+	b.WriteString("\n")
+
+	if m.Err != "" {
+		b.WriteString(styles.Error.Render(m.Err))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	kb := m.Config.Keys()
+	b.WriteString(styles.Help.Render(fmt.Sprintf("type to search • ↑/↓ navigate • enter select • %s cancel",
+		kb.Global.Quit)))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.Purple).
+		Padding(0, 1).
+		Render(b.String())
+}
+
+func (m Model) viewList(width int) string {
+	var b strings.Builder
+
+	if len(m.Matches) == 0 {
+		if m.Loading {
+			b.WriteString(styles.Help.Render("Loading files..."))
+		} else {
+			b.WriteString(styles.Help.Render("no matches"))
+		}
+		return b.String()
+	}
+
+	end := m.Scroll + m.visibleItems()
+	if end > len(m.Matches) {
+		end = len(m.Matches)
+	}
+	for i := m.Scroll; i < end; i++ {
+		p := m.Matches[i].Path
+		if len(p) > width-2 {
+			p = "…" + p[len(p)-(width-3):]
+		}
+		if i == m.Cursor {
+			b.WriteString(styles.Cursor.Render("▸ "))
+			b.WriteString(styles.Selected.Render(p))
+		} else {
+			b.WriteString("  ")
+			b.WriteString(styles.Item.Render(p))
+		}
+		if i < end-1 {
+			b.WriteString("\n")
+		}
+	}
+	if end < len(m.Matches) {
+		b.WriteString("\n")
+		b.WriteString(styles.Help.Render(fmt.Sprintf("  ↓ %d more", len(m.Matches)-end)))
+	}
+
+	return b.String()
+}
+
+func (m Model) viewPreview(width int) string {
+	if len(m.Preview) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(styles.Label.Render(m.previewPath))
+	for _, line := range m.Preview {
+		if width > 0 && len(line) > width {
+			line = line[:width]
+		}
+		b.WriteString("\n")
+		b.WriteString(styles.Dim.Render(line))
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder(), false, false, false, true).
+		BorderForeground(styles.Subtle).
+		PaddingLeft(1).
+		Render(b.String())
+}
@@ -0,0 +1,74 @@
+package filefinder
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// Match is a file path matching the query.
+type Match struct {
+	Path  string
+	Score int
+}
+
+// Score rates how well query fuzzy-matches a file path.
+// Every character of query must appear in path in order (case-insensitive).
+// Consecutive characters, characters at the start of a path segment or word,
+// and matches inside the file name score higher; longer paths score lower.
+func Score(query, filePath string) (int, bool) {
+	if query == "" {
+		return 0, true
+	}
+
+	q := strings.ToLower(query)
+	p := strings.ToLower(filePath)
+	baseStart := len(p) - len(path.Base(p))
+
+	score := 0
+	qi := 0
+	prev := -2
+	for pi := 0; pi < len(p) && qi < len(q); pi++ {
+		if p[pi] != q[qi] {
+			continue
+		}
+
+		score++
+		if pi == prev+1 {
+			score += 5 // consecutive
+		}
+		if pi == 0 || strings.ContainsRune("/_-. ", rune(p[pi-1])) {
+			score += 3 // start of segment or word
+		}
+		if pi >= baseStart {
+			score += 2 // in the file name
+		}
+
+		prev = pi
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+
+	return score*10 - len(p), true
+}
+
+// Find returns the paths matching query, best matches first.
+// With an empty query, paths are returned in their original order.
+func Find(paths []string, query string) []Match {
+	query = strings.TrimSpace(query)
+
+	var matches []Match
+	for _, p := range paths {
+		if score, ok := Score(query, p); ok {
+			matches = append(matches, Match{Path: p, Score: score})
+		}
+	}
+	if query != "" {
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i].Score > matches[j].Score
+		})
+	}
+	return matches
+}
@@ -0,0 +1,39 @@
+package filefinder
+
+import "testing"
+
+func TestFind(t *testing.T) {
+	paths := []string{
+		"internal/ui/todo/model.go",
+		"internal/ui/app/app.go",
+		"internal/config/config.go",
+		"main.go",
+		"README.md",
+	}
+
+	tests := []struct {
+		query string
+		best  string
+		count int
+	}{
+		{"", "internal/ui/todo/model.go", 5},
+		{"main", "main.go", 1},
+		{"app", "internal/ui/app/app.go", 1},
+		{"cfg", "internal/config/config.go", 1},
+		{"readme", "README.md", 1},
+		{"model", "internal/ui/todo/model.go", 1},
+		{"go", "main.go", 4}, // shortest path wins among equal matches
+		{"zzz", "", 0},
+	}
+
+	for _, tt := range tests {
+		got := Find(paths, tt.query)
+		if len(got) != tt.count {
+			t.Errorf("Find(%q) returned %d matches, want %d", tt.query, len(got), tt.count)
+			continue
+		}
+		if tt.count > 0 && got[0].Path != tt.best {
+			t.Errorf("Find(%q) best = %s, want %s", tt.query, got[0].Path, tt.best)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package form
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/ui/datepicker"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+	"github.com/ihatemodels/gdev/internal/ui/textedit"
+)
+
+// DateLayout is the format used for date field values.
+const DateLayout = "2006-01-02"
+
+// Kind is the type of input a field accepts.
+type Kind int
+
+const (
+	KindText Kind = iota
+	KindMultiline
+	KindSelect
+	KindToggle
+	KindDate
+)
+
+// Field is a single labeled form input.
+type Field struct {
+	Key   string // identifier used to look up the value
+	Label string
+	Kind  Kind
+
+	Value   string   // text, multiline, select and date value
+	Checked bool     // toggle value
+	Options []string // select options
+
+	Required bool
+	Validate func(value string) error // optional custom validation
+
+	Err string // validation error shown below the field
+
+	picker datepicker.Model // calendar used while editing a date field
+}
+
+// Text creates a single-line text field.
+func Text(key, label, value string) Field {
+	return Field{Key: key, Label: label, Kind: KindText, Value: value}
+}
+
+// Multiline creates a multi-line text field.
+func Multiline(key, label, value string) Field {
+	return Field{Key: key, Label: label, Kind: KindMultiline, Value: value}
+}
+
+// Select creates a field that cycles through a fixed set of options.
+// If value is not one of the options, the first option is selected.
+func Select(key, label string, options []string, value string) Field {
+	f := Field{Key: key, Label: label, Kind: KindSelect, Options: options}
+	f.Value = value
+	if f.optionIndex() == -1 && len(options) > 0 {
+		f.Value = options[0]
+	}
+	return f
+}
+
+// Toggle creates an on/off field.
+func Toggle(key, label string, checked bool) Field {
+	return Field{Key: key, Label: label, Kind: KindToggle, Checked: checked}
+}
+
+// Date creates a date field with values in DateLayout format.
+func Date(key, label, value string) Field {
+	return Field{Key: key, Label: label, Kind: KindDate, Value: value}
+}
+
+// Editable reports whether the field is edited in insert mode.
+// Select and toggle fields change value directly with Activate.
+func (f Field) Editable() bool {
+	return f.Kind == KindText || f.Kind == KindMultiline || f.Kind == KindDate
+}
+
+// StartEdit prepares the field for insert mode.
+// Date fields open a calendar picker at the current value.
+func (f *Field) StartEdit(cfg *config.Config) {
+	if f.Kind == KindDate {
+		initial, _ := f.Time()
+		f.picker = datepicker.New(cfg, "", initial)
+	}
+}
+
+// Activate toggles a toggle field or advances a select field.
+func (f *Field) Activate() {
+	switch f.Kind {
+	case KindToggle:
+		f.Checked = !f.Checked
+	case KindSelect:
+		f.Cycle(1)
+	}
+}
+
+// Cycle moves a select field's value by delta options, wrapping around.
+func (f *Field) Cycle(delta int) {
+	if f.Kind != KindSelect || len(f.Options) == 0 {
+		return
+	}
+	idx := f.optionIndex()
+	if idx == -1 {
+		idx = 0
+	}
+	idx = (idx + delta + len(f.Options)) % len(f.Options)
+	f.Value = f.Options[idx]
+}
+
+func (f Field) optionIndex() int {
+	for i, o := range f.Options {
+		if o == f.Value {
+			return i
+		}
+	}
+	return -1
+}
+
+// HandleKey processes a key press while the field is in insert mode.
+// It returns true when editing is finished.
+func (f *Field) HandleKey(msg tea.KeyMsg, kb *config.Keybindings) bool {
+	key := msg.String()
+
+	if f.Kind == KindDate {
+		return f.handlePickerKey(msg)
+	}
+
+	if config.Matches(key, kb.Form.Cancel) {
+		f.Check()
+		return true
+	}
+
+	if config.Matches(key, kb.Editor.NewLine) {
+		if f.Kind == KindMultiline {
+			f.Value += "\n"
+			return false
+		}
+		f.Check()
+		return true
+	}
+
+	if key == "backspace" {
+		f.Value, _ = textedit.Backspace(f.Value, len(f.Value))
+	} else if typed, ok := textedit.Typed(msg); ok {
+		f.Value += typed
+	}
+	return false
+}
+
+// handlePickerKey forwards a key to the date picker.
+func (f *Field) handlePickerKey(msg tea.KeyMsg) bool {
+	var res datepicker.Result
+	f.picker, res = f.picker.Update(msg)
+
+	switch res {
+	case datepicker.Picked:
+		f.Value = f.picker.Value().Format(DateLayout)
+	case datepicker.Cleared:
+		f.Value = ""
+	case datepicker.Canceled:
+	default:
+		return false
+	}
+	f.Check()
+	return true
+}
+
+// Check validates the field and records any error in Err.
+func (f *Field) Check() bool {
+	f.Err = ""
+	value := strings.TrimSpace(f.Value)
+
+	if f.Required && f.Kind != KindToggle && value == "" {
+		f.Err = f.Label + " is required"
+		return false
+	}
+
+	if f.Kind == KindDate && value != "" {
+		if _, err := time.Parse(DateLayout, value); err != nil {
+			f.Err = "Date must be in YYYY-MM-DD format"
+			return false
+		}
+	}
+
+	if f.Validate != nil {
+		if err := f.Validate(f.Value); err != nil {
+			f.Err = err.Error()
+			return false
+		}
+	}
+	return true
+}
+
+// Time returns the parsed value of a date field.
+func (f Field) Time() (time.Time, error) {
+	if f.Kind != KindDate {
+		return time.Time{}, errors.New("not a date field")
+	}
+	return time.ParseInLocation(DateLayout, strings.TrimSpace(f.Value), time.Local)
+}
+
+// View renders the field with its label and any validation error.
+func (f Field) View(focused, editing bool) string {
+	var b strings.Builder
+
+	if focused {
+		b.WriteString(styles.Selected.Render(fmt.Sprintf("▸ %s: ", f.Label)))
+	} else {
+		b.WriteString(styles.Label.Render(fmt.Sprintf("  %s: ", f.Label)))
+	}
+
+	switch f.Kind {
+	case KindToggle:
+		if f.Checked {
+			b.WriteString(styles.Input.Render("[x]"))
+		} else {
+			b.WriteString(styles.Input.Render("[ ]"))
+		}
+
+	case KindSelect:
+		if focused {
+			b.WriteString(styles.Help.Render("‹ "))
+			b.WriteString(styles.Input.Render(f.Value))
+			b.WriteString(styles.Help.Render(" ›"))
+		} else {
+			b.WriteString(styles.Input.Render(f.Value))
+		}
+
+	case KindMultiline:
+		if editing {
+			lines := strings.Split(f.Value, "\n")
+			for i, line := range lines {
+				if i > 0 {
+					b.WriteString("\n    ")
+				}
+				b.WriteString(styles.Input.Render(line))
+			}
+		} else {
+			first, _, more := strings.Cut(f.Value, "\n")
+			b.WriteString(styles.Input.Render(first))
+			if more {
+				b.WriteString(styles.Help.Render(" …"))
+			}
+		}
+
+	case KindDate:
+		if f.Value == "" {
+			b.WriteString(styles.Help.Render("none"))
+		} else {
+			b.WriteString(styles.Input.Render(f.Value))
+		}
+		if editing {
+			b.WriteString("\n")
+			b.WriteString(lipgloss.NewStyle().MarginLeft(4).Render(f.picker.View()))
+			b.WriteString("\n")
+			return b.String()
+		}
+
+	default:
+		b.WriteString(styles.Input.Render(f.Value))
+	}
+
+	if editing {
+		b.WriteString(styles.Cursor.Render("█"))
+	}
+	b.WriteString("\n")
+
+	if f.Err != "" {
+		b.WriteString(styles.Error.Render("    " + f.Err))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// Validate checks all fields and reports whether every field is valid.
+func Validate(fields []Field) bool {
+	ok := true
+	for i := range fields {
+		if !fields[i].Check() {
+			ok = false
+		}
+	}
+	return ok
+}
@@ -0,0 +1,80 @@
+package form
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/config"
+)
+
+func TestFieldCheck(t *testing.T) {
+	noSpaces := func(v string) error {
+		for _, c := range v {
+			if c == ' ' {
+				return errors.New("no spaces allowed")
+			}
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name  string
+		field Field
+		valid bool
+	}{
+		{"required empty", Field{Label: "Name", Kind: KindText, Required: true}, false},
+		{"required whitespace", Field{Label: "Name", Kind: KindText, Required: true, Value: "  "}, false},
+		{"required set", Field{Label: "Name", Kind: KindText, Required: true, Value: "x"}, true},
+		{"optional empty", Field{Label: "Name", Kind: KindText}, true},
+		{"valid date", Date("due", "Due", "2025-01-31"), true},
+		{"invalid date", Date("due", "Due", "31/01/2025"), false},
+		{"empty optional date", Date("due", "Due", ""), true},
+		{"custom ok", Field{Kind: KindText, Value: "abc", Validate: noSpaces}, true},
+		{"custom fails", Field{Kind: KindText, Value: "a b", Validate: noSpaces}, false},
+		{"required toggle", Field{Kind: KindToggle, Required: true}, true},
+	}
+
+	for _, tt := range tests {
+		f := tt.field
+		if got := f.Check(); got != tt.valid {
+			t.Errorf("%s: Check() = %v, want %v (err %q)", tt.name, got, tt.valid, f.Err)
+		}
+		if !tt.valid && f.Err == "" {
+			t.Errorf("%s: expected an error message", tt.name)
+		}
+	}
+}
+
+func TestSelectCycle(t *testing.T) {
+	f := Select("strategy", "Strategy", []string{"merge", "squash", "rebase"}, "unknown")
+	if f.Value != "merge" {
+		t.Fatalf("Expected unknown value to default to first option, got %q", f.Value)
+	}
+
+	f.Cycle(-1)
+	if f.Value != "rebase" {
+		t.Errorf("Expected Cycle(-1) to wrap to 'rebase', got %q", f.Value)
+	}
+
+	f.Activate()
+	if f.Value != "merge" {
+		t.Errorf("Expected Activate to wrap to 'merge', got %q", f.Value)
+	}
+}
+
+func TestHandleKey_Multibyte(t *testing.T) {
+	kb := config.DefaultKeybindings()
+	f := Text("name", "Name", "")
+	for _, r := range "café漢" {
+		f.HandleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}, kb)
+	}
+	if f.Value != "café漢" {
+		t.Fatalf("Value after typing = %q, want %q", f.Value, "café漢")
+	}
+	f.HandleKey(tea.KeyMsg{Type: tea.KeyBackspace}, kb)
+	f.HandleKey(tea.KeyMsg{Type: tea.KeyBackspace}, kb)
+	if f.Value != "caf" {
+		t.Errorf("Value after two backspaces = %q, want %q", f.Value, "caf")
+	}
+}
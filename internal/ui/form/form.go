@@ -0,0 +1,179 @@
+// Package form provides reusable labeled form fields with focus management and validation.
+package form
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+)
+
+// Result is the outcome of a key press in the form.
+type Result int
+
+const (
+	Pending Result = iota
+	Submitted
+	Canceled
+)
+
+// Model is a complete form with navigation and edit modes.
+// In navigation mode, movement keys change focus and shortcuts work;
+// in edit mode, keys are typed into the focused field.
+type Model struct {
+	Title  string
+	Fields []Field
+	Focus  int
+
+	// Editing is true while typing into the focused field.
+	Editing bool
+
+	Config *config.Config
+}
+
+// New creates a new form with the given fields.
+func New(cfg *config.Config, title string, fields ...Field) Model {
+	return Model{
+		Title:  title,
+		Fields: fields,
+		Config: cfg,
+	}
+}
+
+// Update handles a key press and reports whether the form was submitted or canceled.
+// Submission only succeeds when all fields are valid.
+func (m Model) Update(msg tea.KeyMsg) (Model, Result) {
+	if len(m.Fields) == 0 {
+		return m, Canceled
+	}
+
+	key := msg.String()
+	kb := m.Config.Keys()
+	field := &m.Fields[m.Focus]
+
+	if m.Editing {
+		if field.HandleKey(msg, kb) {
+			m.Editing = false
+		}
+		return m, Pending
+	}
+
+	switch {
+	case config.Matches(key, kb.Form.Cancel):
+		return m, Canceled
+
+	case config.Matches(key, kb.Form.Submit):
+		if m.Validate() {
+			return m, Submitted
+		}
+
+	case config.MatchesAny(key, kb.Global.MoveUp, kb.Global.MoveUpAlt, kb.Form.PrevField):
+		m.Focus = (m.Focus - 1 + len(m.Fields)) % len(m.Fields)
+
+	case config.MatchesAny(key, kb.Global.MoveDown, kb.Global.MoveDownAlt, kb.Form.NextField):
+		m.Focus = (m.Focus + 1) % len(m.Fields)
+
+	case key == "left":
+		field.Cycle(-1)
+
+	case key == "right":
+		field.Cycle(1)
+
+	case config.MatchesAny(key, kb.Form.EditPrompt, kb.Editor.NewLine, " "):
+		if field.Editable() {
+			field.StartEdit(m.Config)
+			m.Editing = true
+		} else {
+			field.Activate()
+		}
+	}
+
+	return m, Pending
+}
+
+// Validate checks all fields, focusing the first invalid one.
+func (m *Model) Validate() bool {
+	ok := Validate(m.Fields)
+	if !ok {
+		for i, f := range m.Fields {
+			if f.Err != "" {
+				m.Focus = i
+				break
+			}
+		}
+	}
+	return ok
+}
+
+// Field returns the field with the given key, or nil if none exists.
+func (m *Model) Field(key string) *Field {
+	for i := range m.Fields {
+		if m.Fields[i].Key == key {
+			return &m.Fields[i]
+		}
+	}
+	return nil
+}
+
+// Value returns the trimmed value of the field with the given key.
+func (m *Model) Value(key string) string {
+	if f := m.Field(key); f != nil {
+		return strings.TrimSpace(f.Value)
+	}
+	return ""
+}
+
+// Checked returns the state of the toggle field with the given key.
+func (m *Model) Checked(key string) bool {
+	if f := m.Field(key); f != nil {
+		return f.Checked
+	}
+	return false
+}
+
+// View renders the form with its title, fields and help text.
+func (m Model) View() string {
+	var b strings.Builder
+
+	b.WriteString(styles.Title.Render("  " + m.Title))
+	if m.Editing {
+		b.WriteString(styles.Confirm.Render("  [EDITING]"))
+	}
+	b.WriteString("\n\n")
+
+	for i, f := range m.Fields {
+		b.WriteString(f.View(i == m.Focus, m.Editing && i == m.Focus))
+	}
+
+	b.WriteString("\n")
+	b.WriteString(styles.Help.Render(m.helpText()))
+
+	return b.String()
+}
+
+func (m Model) helpText() string {
+	kb := m.Config.Keys()
+
+	if m.Editing {
+		if m.Fields[m.Focus].Kind == KindDate {
+			return "pick a date in the calendar above"
+		}
+		if m.Fields[m.Focus].Kind == KindMultiline {
+			return fmt.Sprintf("type to edit • %s new line • %s done", kb.Editor.NewLine, kb.Form.Cancel)
+		}
+		return fmt.Sprintf("type to edit • %s confirm • %s cancel", kb.Editor.NewLine, kb.Form.Cancel)
+	}
+
+	switch m.Fields[m.Focus].Kind {
+	case KindSelect:
+		return fmt.Sprintf("%s/%s navigate • ←/→ change • %s save • %s cancel",
+			kb.Global.MoveUp, kb.Global.MoveDown, kb.Form.Submit, kb.Form.Cancel)
+	case KindToggle:
+		return fmt.Sprintf("%s/%s navigate • %s toggle • %s save • %s cancel",
+			kb.Global.MoveUp, kb.Global.MoveDown, kb.Editor.NewLine, kb.Form.Submit, kb.Form.Cancel)
+	}
+	return fmt.Sprintf("%s/%s navigate • %s edit • %s save • %s cancel",
+		kb.Global.MoveUp, kb.Global.MoveDown, kb.Editor.NewLine, kb.Form.Submit, kb.Form.Cancel)
+}
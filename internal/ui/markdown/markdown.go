@@ -0,0 +1,137 @@
+// Package markdown renders the markdown of todo descriptions and prompts
+// for the terminal: headings, lists, quotes, code and emphasis.
+package markdown
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+	"github.com/mattn/go-runewidth"
+)
+
+var (
+	heading    = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	bullet     = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	quote      = regexp.MustCompile(`^\s*>\s?(.*)$`)
+	rule       = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+	fence      = regexp.MustCompile("^\\s*(```|~~~)")
+	checkbox   = regexp.MustCompile(`^\[([ xX])\]\s+`)
+	inlineSpan = regexp.MustCompile("`([^`]+)`|\\*\\*([^*]+)\\*\\*|__([^_]+)__|\\*([^*\\s][^*]*)\\*|\\b_([^_]+)_\\b")
+)
+
+// Styles of the elements; text is styles.Value
+var (
+	code  = lipgloss.NewStyle().Foreground(styles.Yellow)
+	quiet = styles.Help
+)
+
+// Render renders text as lines at most width columns wide. Lines that are
+// not markdown are shown as they are, so plain text renders unchanged
+// apart from wrapping.
+func Render(text string, width int) []string {
+	width = max(width, 10)
+	var lines []string
+	inCode := false
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		if fence.MatchString(line) {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			lines = append(lines, "  "+code.Render(truncate(line, width-2)))
+			continue
+		}
+
+		switch {
+		case heading.MatchString(line):
+			m := heading.FindStringSubmatch(line)
+			style := styles.Label
+			if len(m[1]) <= 2 {
+				style = styles.Title.Underline(true)
+			}
+			lines = append(lines, wrap(inline(m[2], style), width, "", "")...)
+
+		case rule.MatchString(line):
+			lines = append(lines, quiet.Render(strings.Repeat("─", width)))
+
+		case bullet.MatchString(line):
+			m := bullet.FindStringSubmatch(line)
+			indent := strings.Repeat(" ", len(strings.ReplaceAll(m[1], "\t", "  ")))
+			marker, item := m[2], m[3]
+			if marker == "-" || marker == "*" || marker == "+" {
+				marker = "•"
+				if c := checkbox.FindStringSubmatch(item); c != nil {
+					marker = "☐"
+					if c[1] != " " {
+						marker = "☑"
+					}
+					item = item[len(c[0]):]
+				}
+			}
+			prefix := indent + styles.Prompt.Render(marker) + " "
+			hang := indent + strings.Repeat(" ", lipgloss.Width(marker)+1)
+			lines = append(lines, wrap(inline(item, styles.Value), width, prefix, hang)...)
+
+		case quote.MatchString(line):
+			m := quote.FindStringSubmatch(line)
+			bar := quiet.Render("│ ")
+			lines = append(lines, wrap(inline(m[1], quiet.Italic(true)), width, bar, bar)...)
+
+		default:
+			lines = append(lines, wrap(inline(line, styles.Value), width, "", "")...)
+		}
+	}
+	return lines
+}
+
+// inline renders the code spans and emphasis of text, the rest in base.
+func inline(text string, base lipgloss.Style) string {
+	var b strings.Builder
+	for text != "" {
+		loc := inlineSpan.FindStringSubmatchIndex(text)
+		if loc == nil {
+			b.WriteString(base.Render(text))
+			break
+		}
+		if loc[0] > 0 {
+			b.WriteString(base.Render(text[:loc[0]]))
+		}
+		switch {
+		case loc[2] >= 0:
+			b.WriteString(code.Render(text[loc[2]:loc[3]]))
+		case loc[4] >= 0:
+			b.WriteString(base.Bold(true).Render(text[loc[4]:loc[5]]))
+		case loc[6] >= 0:
+			b.WriteString(base.Bold(true).Render(text[loc[6]:loc[7]]))
+		case loc[8] >= 0:
+			b.WriteString(base.Italic(true).Render(text[loc[8]:loc[9]]))
+		case loc[10] >= 0:
+			b.WriteString(base.Italic(true).Render(text[loc[10]:loc[11]]))
+		}
+		text = text[loc[1]:]
+	}
+	return b.String()
+}
+
+// wrap wraps styled text to width, starting the first line with prefix and
+// the rest with hang, which must be as wide.
+func wrap(text string, width int, prefix, hang string) []string {
+	w := max(width-lipgloss.Width(prefix), 1)
+	wrapped := strings.Split(lipgloss.NewStyle().Width(w).Render(text), "\n")
+	for i, l := range wrapped {
+		l = strings.TrimRight(l, " ")
+		if i == 0 {
+			wrapped[i] = prefix + l
+		} else {
+			wrapped[i] = hang + l
+		}
+	}
+	return wrapped
+}
+
+// truncate shortens line to width columns.
+func truncate(line string, width int) string {
+	return runewidth.Truncate(line, width, "…")
+}
@@ -0,0 +1,37 @@
+package markdown
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	text := "# Fix the login\n" +
+		"\n" +
+		"Use **bold** and `code`.\n" +
+		"- first item that is long enough to wrap\n" +
+		"  1. nested\n" +
+		"- [x] done\n" +
+		"> quoted\n" +
+		"---\n" +
+		"```go\n" +
+		"  x := 1 // *not* emphasis\n" +
+		"```\n"
+
+	got := Render(text, 24)
+	want := []string{
+		"Fix the login",
+		"",
+		"Use bold and code.",
+		"• first item that is",
+		"  long enough to wrap",
+		"  1. nested",
+		"☑ done",
+		"│ quoted",
+		"────────────────────────",
+		"    x := 1 // *not* emp…",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Render() =\n%q\nwant\n%q", got, want)
+	}
+}
@@ -0,0 +1,93 @@
+// Package nav provides a view stack with push/pop navigation and breadcrumbs.
+package nav
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+)
+
+// Stack is a stack of views. The bottom view is the root and is never popped.
+//
+// Models holding a Stack are copied on every update, so operations never
+// write to an array shared with an older copy.
+type Stack[V comparable] struct {
+	views []V
+}
+
+// NewStack creates a stack with root as its only view.
+func NewStack[V comparable](root V) Stack[V] {
+	return Stack[V]{views: []V{root}}
+}
+
+// Current returns the view on top of the stack.
+func (s Stack[V]) Current() V {
+	if len(s.views) == 0 {
+		var zero V
+		return zero
+	}
+	return s.views[len(s.views)-1]
+}
+
+// Is reports whether v is the current view.
+func (s Stack[V]) Is(v V) bool {
+	return s.Current() == v
+}
+
+// Push opens v on top of the current view.
+func (s *Stack[V]) Push(v V) {
+	s.views = append(slices.Clip(s.views), v)
+}
+
+// Pop closes the current view and returns the view below it.
+// The root view is never popped.
+func (s *Stack[V]) Pop() V {
+	if len(s.views) > 1 {
+		s.views = slices.Clip(s.views[:len(s.views)-1])
+	}
+	return s.Current()
+}
+
+// Replace swaps the current view for v.
+func (s *Stack[V]) Replace(v V) {
+	if len(s.views) == 0 {
+		s.views = []V{v}
+		return
+	}
+	s.views = append(slices.Clip(s.views[:len(s.views)-1]), v)
+}
+
+// Reset pops every view above the root.
+func (s *Stack[V]) Reset() {
+	if len(s.views) > 1 {
+		s.views = s.views[:1:1]
+	}
+}
+
+// Depth returns the number of views on the stack.
+func (s Stack[V]) Depth() int {
+	return len(s.views)
+}
+
+// Views returns the views from the root to the current one.
+func (s Stack[V]) Views() []V {
+	return slices.Clone(s.views)
+}
+
+// Breadcrumb renders a navigation trail like "TODOs › Edit TODO › Prompt".
+// The last crumb is highlighted as the current location.
+func Breadcrumb(crumbs ...string) string {
+	if len(crumbs) == 0 {
+		return ""
+	}
+	parts := make([]string, len(crumbs))
+	for i, c := range crumbs {
+		if i == len(crumbs)-1 {
+			parts[i] = styles.Label.Render(c)
+		} else {
+			parts[i] = styles.Dim.Render(c)
+		}
+	}
+	return strings.Join(parts, styles.Dim.Render(" › "))
+}
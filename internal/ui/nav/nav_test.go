@@ -0,0 +1,33 @@
+package nav
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestStack(t *testing.T) {
+	s := NewStack("list")
+	s.Push("form")
+	s.Push("editor")
+
+	// A copy taken before pushing must not see later changes
+	saved := s
+	s.Pop()
+	s.Push("terminal")
+	if got := saved.Views(); !slices.Equal(got, []string{"list", "form", "editor"}) {
+		t.Errorf("saved copy = %v, want [list form editor]", got)
+	}
+
+	if got := s.Pop(); got != "form" {
+		t.Errorf("Pop() = %q, want form", got)
+	}
+	s.Replace("review")
+	if got := s.Views(); !slices.Equal(got, []string{"list", "review"}) {
+		t.Errorf("Views() = %v, want [list review]", got)
+	}
+
+	s.Reset()
+	if got := s.Pop(); got != "list" || s.Depth() != 1 {
+		t.Errorf("Pop() at root = %q (depth %d), want list (depth 1)", got, s.Depth())
+	}
+}
@@ -0,0 +1,41 @@
+package pr
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/ai"
+	"github.com/ihatemodels/gdev/internal/budget"
+	"github.com/ihatemodels/gdev/internal/ui/confirm"
+)
+
+// budgetAllows reports whether a review of diff by backend may start. Past
+// the budget of a provider backend may ask it asks first, and reviews diff
+// once confirmed. A cached review costs nothing and always goes ahead.
+func (m Model) budgetAllows(backend ai.Backend, prompt, diff string) (Model, bool) {
+	if _, cached := m.Config.CachedResponse(backend.CacheKey(prompt)); cached {
+		return m, true
+	}
+	status, err := budget.ForBackend(m.Config, backend)
+	if err != nil || status.Level != budget.Over {
+		// An unreadable usage log doesn't stop the review
+		return m, true
+	}
+	m.Confirm = confirm.OverBudget(m.Config, status)
+	m.BudgetConfirm = true
+	m.ReviewDiff = diff
+	return m, false
+}
+
+// UpdateBudgetConfirm handles input for the over-budget confirmation.
+func (m Model) UpdateBudgetConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var res confirm.Result
+	m.Confirm, res = m.Confirm.Update(msg)
+	switch res {
+	case confirm.Confirmed:
+		diff := m.ReviewDiff
+		m.BudgetConfirm, m.ReviewDiff = false, ""
+		return m.runReview(diff)
+	case confirm.Canceled:
+		m.BudgetConfirm, m.ReviewDiff = false, ""
+	}
+	return m, nil
+}
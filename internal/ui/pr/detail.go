@@ -0,0 +1,171 @@
+package pr
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/gh"
+	"github.com/ihatemodels/gdev/internal/ui/failure"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+)
+
+// openDetail switches to the detail view and loads the full PR details.
+func (m Model) openDetail(pr gh.PR) (tea.Model, tea.Cmd) {
+	m.SelectedPR = &pr
+	m.DetailScroll = 0
+	m.ReviewSections = nil
+	m.CurrentView = DetailView
+	m.Loading = true
+
+	ctx, repoPath := m.Config.Context(), m.RepoPath
+	return m, failure.Cmd(fmt.Sprintf("Load PR #%d", pr.Number), func() (tea.Msg, error) {
+		detail, err := gh.ViewPR(ctx, repoPath, pr.Number)
+		if err != nil {
+			return nil, err
+		}
+		return PRDetailLoadedMsg{PR: detail}, nil
+	})
+}
+
+// UpdateDetailView handles input for the detail view.
+func (m Model) UpdateDetailView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	kb := m.Config.Keys()
+
+	if config.MatchesAny(key, kb.Global.Quit, kb.Global.QuitAlt, kb.Detail.Back) {
+		m.CurrentView = ListView
+		m.SelectedPR = nil
+		return m, nil
+	}
+
+	if config.MatchesAny(key, kb.Detail.ScrollUp, kb.Global.MoveUp, kb.Global.MoveUpAlt) {
+		if m.DetailScroll > 0 {
+			m.DetailScroll--
+		}
+		return m, nil
+	}
+
+	if config.MatchesAny(key, kb.Detail.ScrollDown, kb.Global.MoveDown, kb.Global.MoveDownAlt) {
+		m.DetailScroll++
+		return m, nil
+	}
+
+	if m.SelectedPR == nil {
+		return m, nil
+	}
+
+	switch {
+	case config.Matches(key, kb.PR.Checkout):
+		return m.openCheckoutTerminal(*m.SelectedPR)
+
+	case config.Matches(key, kb.PR.Review):
+		if len(m.ReviewSections) > 0 && !m.ReviewPending {
+			m.CurrentView = ReviewView
+			return m, nil
+		}
+		return m.loadReviewDiff()
+
+	case config.Matches(key, kb.PR.Merge):
+		m.MergeIdx = 0
+		m.MergeConfirm = false
+		m.CurrentView = MergeView
+	}
+
+	return m, nil
+}
+
+// ViewDetail renders the detail view.
+func (m Model) ViewDetail() string {
+	if m.SelectedPR == nil {
+		return ""
+	}
+	pr := m.SelectedPR
+
+	var lines []string
+
+	lines = append(lines, styles.Title.Render(fmt.Sprintf("  #%d %s", pr.Number, pr.Title)))
+	lines = append(lines, styles.Help.Render("─────────────────────────────────────────────────────"))
+	lines = append(lines, "")
+
+	lines = append(lines, styles.Label.Render("Branch: ")+styles.Branch.Render(" "+pr.HeadRefName)+
+		styles.Help.Render(" → "+pr.BaseRefName))
+	lines = append(lines, styles.Label.Render("Author: ")+styles.Value.Render("@"+pr.Author.Login))
+
+	state := pr.State
+	if pr.IsDraft {
+		state += " (draft)"
+	}
+	lines = append(lines, styles.Label.Render("State: ")+styles.Value.Render(state))
+
+	if m.Loading {
+		lines = append(lines, "")
+		lines = append(lines, styles.Help.Render("  Loading details..."))
+	} else {
+		lines = append(lines, styles.Label.Render("Changes: ")+
+			styles.Selected.Render(fmt.Sprintf("+%d", pr.Additions))+" "+
+			styles.Error.Render(fmt.Sprintf("-%d", pr.Deletions))+
+			styles.Help.Render(fmt.Sprintf(" in %d files", pr.ChangedFiles)))
+		if pr.Mergeable != "" {
+			lines = append(lines, styles.Label.Render("Mergeable: ")+styles.Value.Render(pr.Mergeable))
+		}
+		if pr.ReviewDecision != "" {
+			lines = append(lines, styles.Label.Render("Review: ")+styles.Value.Render(pr.ReviewDecision))
+		}
+		lines = append(lines, styles.Label.Render("URL: ")+styles.Help.Render(pr.URL))
+		lines = append(lines, "")
+
+		lines = append(lines, styles.Label.Render("Description:"))
+		if strings.TrimSpace(pr.Body) != "" {
+			for _, bl := range strings.Split(pr.Body, "\n") {
+				lines = append(lines, "  "+styles.Value.Render(strings.TrimRight(bl, "\r")))
+			}
+		} else {
+			lines = append(lines, "  "+styles.Help.Render("(no description)"))
+		}
+	}
+
+	visibleLines := m.Height - 8
+	if visibleLines < 5 {
+		visibleLines = 5
+	}
+
+	maxScroll := len(lines) - visibleLines
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	scroll := m.DetailScroll
+	if scroll > maxScroll {
+		scroll = maxScroll
+	}
+
+	var b strings.Builder
+
+	if scroll > 0 {
+		b.WriteString(styles.Help.Render("  ↑ scroll up for more"))
+		b.WriteString("\n")
+	}
+
+	endIdx := scroll + visibleLines
+	if endIdx > len(lines) {
+		endIdx = len(lines)
+	}
+
+	for i := scroll; i < endIdx; i++ {
+		b.WriteString(lines[i])
+		b.WriteString("\n")
+	}
+
+	if endIdx < len(lines) {
+		b.WriteString(styles.Help.Render("  ↓ scroll down for more"))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	kb := m.Config.Keys()
+	b.WriteString(styles.Help.Render(fmt.Sprintf("↑/%s ↓/%s scroll • %s checkout • %s merge • %s ai review • %s back",
+		kb.Detail.ScrollUp, kb.Detail.ScrollDown, kb.PR.Checkout, kb.PR.Merge, kb.PR.Review, kb.Detail.Back)))
+
+	return b.String()
+}
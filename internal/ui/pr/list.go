@@ -0,0 +1,193 @@
+package pr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/gh"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+	"github.com/ihatemodels/gdev/internal/ui/terminal"
+)
+
+// visibleItems returns how many PR cards fit on screen.
+func (m Model) visibleItems() int {
+	visible := (m.Height - 10) / 4
+	if visible < 1 {
+		visible = 1
+	}
+	return visible
+}
+
+// UpdateListView handles input for the list view.
+func (m Model) UpdateListView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	visibleItems := m.visibleItems()
+
+	key := msg.String()
+	kb := m.Config.Keys()
+
+	// Handle quit/back
+	if config.MatchesAny(key, kb.Global.Quit, kb.Global.QuitAlt) {
+		return m, func() tea.Msg { return BackToMenuMsg{} }
+	}
+
+	// Handle navigation
+	if config.MatchesAny(key, kb.Global.MoveUp, kb.Global.MoveUpAlt) {
+		if m.Cursor > 0 {
+			m.Cursor--
+			if m.Cursor < m.ListScroll {
+				m.ListScroll = m.Cursor
+			}
+		}
+		return m, nil
+	}
+
+	if config.MatchesAny(key, kb.Global.MoveDown, kb.Global.MoveDownAlt) {
+		if m.Cursor < len(m.PRs)-1 {
+			m.Cursor++
+			if m.Cursor >= m.ListScroll+visibleItems {
+				m.ListScroll = m.Cursor - visibleItems + 1
+			}
+		}
+		return m, nil
+	}
+
+	switch {
+	case config.Matches(key, kb.List.Top):
+		m.Cursor = 0
+		m.ListScroll = 0
+
+	case config.Matches(key, kb.List.Bottom):
+		if len(m.PRs) > 0 {
+			m.Cursor = len(m.PRs) - 1
+			if m.Cursor >= visibleItems {
+				m.ListScroll = m.Cursor - visibleItems + 1
+			}
+		}
+
+	case config.Matches(key, kb.PR.Refresh):
+		m.Loading = true
+		return m, m.LoadPRs()
+
+	case config.Matches(key, kb.List.Select):
+		if len(m.PRs) > 0 {
+			return m.openDetail(m.PRs[m.Cursor])
+		}
+
+	case config.Matches(key, kb.PR.Checkout):
+		if len(m.PRs) > 0 {
+			return m.openCheckoutTerminal(m.PRs[m.Cursor])
+		}
+	}
+
+	return m, nil
+}
+
+// openCheckoutTerminal runs `gh pr checkout` for the given PR in the terminal modal.
+func (m Model) openCheckoutTerminal(pr gh.PR) (tea.Model, tea.Cmd) {
+	return m.openTerminal(fmt.Sprintf("Checkout #%d", pr.Number),
+		terminalJob{Kind: jobCheckout, Branch: pr.HeadRefName},
+		"pr", "checkout", strconv.Itoa(pr.Number))
+}
+
+// finishCheckout returns to the menu after a finished checkout, so the new
+// branch is shown.
+func (m Model) finishCheckout(job terminalJob, res terminal.CommandDoneMsg) (tea.Model, tea.Cmd) {
+	if res.Err != nil {
+		return m, nil
+	}
+	return m, func() tea.Msg { return CheckedOutMsg{Branch: job.Branch} }
+}
+
+// ViewList renders the list view.
+func (m Model) ViewList() string {
+	var b strings.Builder
+
+	header := "  Pull Requests"
+	if len(m.PRs) > 0 {
+		header += styles.Help.Render(fmt.Sprintf(" (%d)", len(m.PRs)))
+	}
+	b.WriteString(styles.Title.Render(header))
+	b.WriteString("\n")
+	b.WriteString(styles.Help.Render("─────────────────────────────────────────"))
+	b.WriteString("\n\n")
+
+	switch {
+	case m.Loading:
+		b.WriteString(styles.Help.Render("  Loading pull requests..."))
+		b.WriteString("\n")
+	case len(m.PRs) == 0:
+		b.WriteString(styles.Help.Render("  No open pull requests"))
+		b.WriteString("\n")
+	default:
+		b.WriteString(m.viewPRCards())
+	}
+
+	b.WriteString("\n\n")
+	kb := m.Config.Keys()
+	b.WriteString(styles.Help.Render(fmt.Sprintf("↑/%s ↓/%s navigate • %s/%s top/bottom",
+		kb.Global.MoveUp, kb.Global.MoveDown, kb.List.Top, kb.List.Bottom)))
+	b.WriteString("\n")
+	b.WriteString(styles.Help.Render(fmt.Sprintf("%s view • %s checkout • %s refresh • %s back",
+		kb.List.Select, kb.PR.Checkout, kb.PR.Refresh, kb.Global.Quit)))
+
+	return b.String()
+}
+
+func (m Model) viewPRCards() string {
+	var b strings.Builder
+
+	visibleItems := m.visibleItems()
+
+	if m.ListScroll > 0 {
+		b.WriteString(styles.Help.Render("  ↑ more above"))
+		b.WriteString("\n\n")
+	}
+
+	endIdx := m.ListScroll + visibleItems
+	if endIdx > len(m.PRs) {
+		endIdx = len(m.PRs)
+	}
+
+	for i := m.ListScroll; i < endIdx; i++ {
+		pr := m.PRs[i]
+		title := fmt.Sprintf("#%d %s", pr.Number, pr.Title)
+
+		if i == m.Cursor {
+			b.WriteString(styles.Cursor.Render("▸ "))
+			b.WriteString(styles.Selected.Render("┌─ "))
+			b.WriteString(styles.Selected.Render(title))
+		} else {
+			b.WriteString("  ")
+			b.WriteString(styles.Help.Render("┌─ "))
+			b.WriteString(styles.Item.Render(title))
+		}
+		if pr.IsDraft {
+			b.WriteString(styles.Dim.Render("  draft"))
+		}
+		b.WriteString("\n")
+
+		b.WriteString("  ")
+		b.WriteString(styles.Help.Render("│  "))
+		b.WriteString(styles.Branch.Render(" " + pr.HeadRefName))
+		b.WriteString(styles.Help.Render(fmt.Sprintf(" → %s  •  @%s", pr.BaseRefName, pr.Author.Login)))
+		b.WriteString("\n")
+
+		b.WriteString("  ")
+		b.WriteString(styles.Help.Render("└───"))
+		b.WriteString("\n")
+
+		if i < endIdx-1 {
+			b.WriteString("\n")
+		}
+	}
+
+	if endIdx < len(m.PRs) {
+		b.WriteString("\n")
+		b.WriteString(styles.Help.Render("  ↓ more below"))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,123 @@
+package pr
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/gh"
+	"github.com/ihatemodels/gdev/internal/ui/confirm"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+	"github.com/ihatemodels/gdev/internal/ui/terminal"
+)
+
+// mergeDescriptions explains each merge strategy in the selection list.
+var mergeDescriptions = map[gh.MergeStrategy]string{
+	gh.MergeCommit: "Add all commits to the base branch via a merge commit",
+	gh.MergeSquash: "Combine all commits into one commit on the base branch",
+	gh.MergeRebase: "Rebase all commits onto the base branch",
+}
+
+// UpdateMergeView handles input for merge strategy selection and confirmation.
+func (m Model) UpdateMergeView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	kb := m.Config.Keys()
+
+	if m.MergeConfirm {
+		var res confirm.Result
+		m.Confirm, res = m.Confirm.Update(msg)
+		switch res {
+		case confirm.Confirmed:
+			m.MergeConfirm = false
+			return m.openMergeTerminal()
+		case confirm.Canceled:
+			m.MergeConfirm = false
+		}
+		return m, nil
+	}
+
+	switch {
+	case config.MatchesAny(key, kb.Global.Quit, kb.Global.QuitAlt):
+		m.CurrentView = DetailView
+
+	case config.MatchesAny(key, kb.Global.MoveUp, kb.Global.MoveUpAlt):
+		if m.MergeIdx > 0 {
+			m.MergeIdx--
+		}
+
+	case config.MatchesAny(key, kb.Global.MoveDown, kb.Global.MoveDownAlt):
+		if m.MergeIdx < len(gh.MergeStrategies)-1 {
+			m.MergeIdx++
+		}
+
+	case config.Matches(key, kb.List.Select):
+		pr := m.SelectedPR
+		strategy := gh.MergeStrategies[m.MergeIdx]
+		m.Confirm = confirm.New(m.Config, fmt.Sprintf("Merge #%d using %s?", pr.Number, strategy),
+			fmt.Sprintf("%s → %s", pr.HeadRefName, pr.BaseRefName))
+		m.MergeConfirm = true
+	}
+
+	return m, nil
+}
+
+// openMergeTerminal runs `gh pr merge` with the selected strategy.
+func (m Model) openMergeTerminal() (tea.Model, tea.Cmd) {
+	pr := *m.SelectedPR
+	strategy := gh.MergeStrategies[m.MergeIdx]
+
+	m.CurrentView = DetailView
+	return m.openTerminal(fmt.Sprintf("Merge #%d (%s)", pr.Number, strategy),
+		terminalJob{Kind: jobMerge}, gh.MergeArgs(pr.Number, strategy)...)
+}
+
+// finishMerge returns to the refreshed list after a successful merge.
+func (m Model) finishMerge(res terminal.CommandDoneMsg) (tea.Model, tea.Cmd) {
+	if res.Err != nil {
+		return m, nil
+	}
+	m.CurrentView = ListView
+	m.SelectedPR = nil
+	m.Loading = true
+	return m, m.LoadPRs()
+}
+
+// ViewMerge renders the merge strategy selection.
+func (m Model) ViewMerge() string {
+	if m.SelectedPR == nil {
+		return ""
+	}
+	pr := m.SelectedPR
+
+	var b strings.Builder
+
+	b.WriteString(styles.Title.Render(fmt.Sprintf("  Merge #%d", pr.Number)))
+	b.WriteString("\n")
+	b.WriteString(styles.Help.Render(fmt.Sprintf("  %s → %s", pr.HeadRefName, pr.BaseRefName)))
+	b.WriteString("\n\n")
+
+	for i, strategy := range gh.MergeStrategies {
+		if i == m.MergeIdx {
+			b.WriteString(styles.Cursor.Render("▸ "))
+			b.WriteString(styles.Selected.Render(string(strategy)))
+		} else {
+			b.WriteString("  ")
+			b.WriteString(styles.Item.Render(string(strategy)))
+		}
+		b.WriteString(styles.Help.Render("  " + mergeDescriptions[strategy]))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	if m.MergeConfirm {
+		b.WriteString(m.Confirm.View())
+		return b.String()
+	}
+
+	kb := m.Config.Keys()
+	b.WriteString(styles.Help.Render(fmt.Sprintf("↑/%s ↓/%s choose • %s merge • %s back",
+		kb.Global.MoveUp, kb.Global.MoveDown, kb.List.Select, kb.Global.Quit)))
+
+	return b.String()
+}
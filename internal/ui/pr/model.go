@@ -0,0 +1,326 @@
+// Package pr provides the pull request management TUI component.
+package pr
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ihatemodels/gdev/internal/ai"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/gh"
+	"github.com/ihatemodels/gdev/internal/ui/confirm"
+	"github.com/ihatemodels/gdev/internal/ui/failure"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+	"github.com/ihatemodels/gdev/internal/ui/terminal"
+)
+
+// View represents the current view within the PR component.
+type View int
+
+const (
+	ListView View = iota
+	DetailView
+	MergeView
+	ReviewView
+	TerminalView
+)
+
+// Model is the Bubble Tea model for pull request management.
+type Model struct {
+	Config   *config.Config
+	RepoPath string
+
+	CurrentView View
+	PRs         []gh.PR
+	Cursor      int
+	ListScroll  int
+
+	// For detail view
+	SelectedPR   *gh.PR
+	DetailScroll int
+
+	// Merge strategy selection
+	MergeIdx     int           // index into gh.MergeStrategies
+	MergeConfirm bool          // true when asking for confirmation
+	Confirm      confirm.Model // merge or over-budget confirmation dialog
+
+	// Asking before a review past the AI budget
+	BudgetConfirm bool
+	ReviewDiff    string // reviewed once confirmed
+
+	// AI review summary
+	ReviewPending  bool // true while the AI backend is generating the review
+	ReviewSections []ReviewSection
+	ReviewScroll   int
+
+	// UI state
+	Width   int
+	Height  int
+	ErrMsg  string
+	Loading bool
+
+	// Terminal modal for running gh commands
+	Terminal     terminal.Model
+	PreviousView View
+	TerminalJob  terminalJob              // what its command runs for
+	TerminalDone *terminal.CommandDoneMsg // how it ended, nil while it runs
+}
+
+// jobKind is the kind of work a command in the terminal modal does.
+type jobKind int
+
+const (
+	jobNone     jobKind = iota
+	jobCheckout         // checks out a pull request
+	jobMerge            // merges the selected pull request
+)
+
+// terminalJob is what the command in the terminal modal runs for. Closing
+// the modal hands it the command's CommandDoneMsg, which decides what
+// happens next.
+type terminalJob struct {
+	Kind   jobKind
+	Branch string // checked out
+}
+
+// Message types
+type (
+	PRsLoadedMsg struct {
+		PRs []gh.PR
+	}
+
+	PRDetailLoadedMsg struct {
+		PR *gh.PR
+	}
+
+	PRDiffLoadedMsg struct {
+		Number int
+		Diff   string
+	}
+
+	BackToMenuMsg struct{}
+
+	// CheckedOutMsg signals that a PR branch was checked out locally.
+	CheckedOutMsg struct {
+		Branch string
+	}
+)
+
+// New creates a new Model.
+func New(cfg *config.Config, repoPath string) Model {
+	return Model{
+		Config:      cfg,
+		RepoPath:    repoPath,
+		CurrentView: ListView,
+		Loading:     true,
+	}
+}
+
+// SetSize sets the width and height of the model.
+func (m *Model) SetSize(width, height int) {
+	m.Width = width
+	m.Height = height
+}
+
+// Init implements tea.Model.
+func (m Model) Init() tea.Cmd {
+	return m.LoadPRs()
+}
+
+// LoadPRs returns a command that loads the open pull requests using gh.
+func (m Model) LoadPRs() tea.Cmd {
+	return failure.Cmd("Load pull requests", func() (tea.Msg, error) {
+		prs, err := gh.ListPRs(m.Config.Context(), m.RepoPath)
+		if err != nil {
+			return nil, err
+		}
+		return PRsLoadedMsg{PRs: prs}, nil
+	})
+}
+
+// Update implements tea.Model.
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.Width = msg.Width
+		m.Height = msg.Height
+		m.Terminal.SetSize(msg.Width, msg.Height)
+		return m, nil
+
+	case PRsLoadedMsg:
+		m.PRs = msg.PRs
+		m.Loading = false
+		if m.Cursor >= len(m.PRs) {
+			m.Cursor = max(len(m.PRs)-1, 0)
+		}
+		m.ListScroll = min(m.ListScroll, m.Cursor)
+		return m, nil
+
+	case PRDetailLoadedMsg:
+		if m.SelectedPR != nil && m.SelectedPR.Number == msg.PR.Number {
+			m.SelectedPR = msg.PR
+		}
+		m.Loading = false
+		return m, nil
+
+	case failure.Msg:
+		// The app shows the error screen; stop waiting for the result
+		m.Loading = false
+		return m, nil
+
+	case PRDiffLoadedMsg:
+		if m.SelectedPR == nil || m.SelectedPR.Number != msg.Number {
+			return m, nil
+		}
+		return m.startReview(msg.Diff)
+
+	case terminal.TickMsg:
+		if m.CurrentView == TerminalView {
+			var cmd tea.Cmd
+			m.Terminal, cmd = m.Terminal.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+
+	case terminal.CommandDoneMsg:
+		if msg.ID != m.Terminal.ID {
+			return m, nil
+		}
+		if m.ReviewPending {
+			return m.finishReview(msg), nil
+		}
+		// Kept until the terminal is closed, which is when it is acted on
+		m.TerminalDone = &msg
+		return m, nil
+
+	case tea.KeyMsg:
+		m.ErrMsg = ""
+		return m.handleKeyMsg(msg)
+	}
+	return m, nil
+}
+
+func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.BudgetConfirm {
+		return m.UpdateBudgetConfirm(msg)
+	}
+	switch m.CurrentView {
+	case ListView:
+		return m.UpdateListView(msg)
+	case DetailView:
+		return m.UpdateDetailView(msg)
+	case MergeView:
+		return m.UpdateMergeView(msg)
+	case ReviewView:
+		return m.UpdateReviewView(msg)
+	case TerminalView:
+		return m.UpdateTerminalView(msg)
+	}
+	return m, nil
+}
+
+// UpdateTerminalView handles input for the terminal modal.
+func (m Model) UpdateTerminalView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.Terminal.ShouldClose(msg) {
+		m.Terminal.Cancel()
+		m.CurrentView = m.PreviousView
+		m.ReviewPending = false
+		// A command closed before it was done counts as canceled
+		res := m.Terminal.Unfinished()
+		if m.TerminalDone != nil {
+			res = *m.TerminalDone
+		}
+		job := m.TerminalJob
+		m.TerminalJob, m.TerminalDone = terminalJob{}, nil
+		return m.finishJob(job, res)
+	}
+
+	var cmd tea.Cmd
+	m.Terminal, cmd = m.Terminal.Update(msg)
+	return m, cmd
+}
+
+// openTerminal runs a mutating gh command for job in the terminal modal.
+// What the job does next is decided once the modal is closed.
+func (m Model) openTerminal(title string, job terminalJob, args ...string) (Model, tea.Cmd) {
+	m = m.prepareTerminal(title, job)
+	cmd := m.Terminal.RunMutatingCommand("gh", args...)
+	return m, cmd
+}
+
+// openAITerminal asks backend for the response to prompt in the terminal
+// modal.
+func (m Model) openAITerminal(title string, backend ai.Backend, prompt string) (Model, tea.Cmd) {
+	m = m.prepareTerminal(title, terminalJob{})
+	cmd := m.Terminal.RunCachedFunc(backend.CacheKey(prompt), false, ai.Describe(backend), func(ctx context.Context, w io.Writer) error {
+		return backend.Generate(ctx, prompt, w)
+	})
+	return m, cmd
+}
+
+func (m Model) prepareTerminal(title string, job terminalJob) Model {
+	m.Terminal = terminal.New(m.Config, title)
+	m.Terminal.Dir = m.RepoPath
+	m.Terminal.SetSize(m.Width, m.Height)
+	m.TerminalJob = job
+	m.TerminalDone = nil
+
+	m.PreviousView = m.CurrentView
+	m.CurrentView = TerminalView
+	return m
+}
+
+// finishJob acts on the result of the command run for job once the
+// terminal modal is closed.
+func (m Model) finishJob(job terminalJob, res terminal.CommandDoneMsg) (tea.Model, tea.Cmd) {
+	switch job.Kind {
+	case jobCheckout:
+		return m.finishCheckout(job, res)
+	case jobMerge:
+		return m.finishMerge(res)
+	}
+	return m, nil
+}
+
+// View implements tea.Model.
+func (m Model) View() string {
+	if m.Width == 0 {
+		return "Loading..."
+	}
+
+	if m.CurrentView == TerminalView {
+		return m.Terminal.ViewCentered(m.Width, m.Height)
+	}
+
+	var content strings.Builder
+
+	switch m.CurrentView {
+	case ListView:
+		content.WriteString(m.ViewList())
+	case DetailView:
+		content.WriteString(m.ViewDetail())
+	case MergeView:
+		content.WriteString(m.ViewMerge())
+	case ReviewView:
+		content.WriteString(m.ViewReview())
+	}
+
+	if m.BudgetConfirm {
+		content.WriteString("\n\n")
+		content.WriteString(m.Confirm.View())
+	}
+
+	if m.ErrMsg != "" {
+		content.WriteString("\n\n")
+		content.WriteString(styles.Error.Render("Error: " + m.ErrMsg))
+	}
+
+	return lipgloss.NewStyle().
+		Width(m.Width).
+		Height(m.Height).
+		Padding(1, 2).
+		Render(content.String())
+}
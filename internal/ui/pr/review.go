@@ -0,0 +1,253 @@
+package pr
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ihatemodels/gdev/internal/ai"
+	"github.com/ihatemodels/gdev/internal/budget"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/embedded"
+	"github.com/ihatemodels/gdev/internal/gh"
+	"github.com/ihatemodels/gdev/internal/ui/failure"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+	"github.com/ihatemodels/gdev/internal/ui/terminal"
+)
+
+// The review prompt is passed to the AI backend as one argument, which
+// Linux caps at 128KB. The description is cut to maxReviewBody and the
+// diff to what is left of maxReviewPrompt.
+const (
+	maxReviewPrompt = 120_000
+	maxReviewBody   = 10_000
+)
+
+// ReviewSection is a titled group of points in an AI review summary.
+type ReviewSection struct {
+	Title  string
+	Points []string
+}
+
+// loadReviewDiff fetches the diff of the selected PR for review.
+func (m Model) loadReviewDiff() (tea.Model, tea.Cmd) {
+	ctx, repoPath := m.Config.Context(), m.RepoPath
+	number := m.SelectedPR.Number
+	m.Loading = true
+	return m, failure.Cmd(fmt.Sprintf("Load diff of PR #%d", number), func() (tea.Msg, error) {
+		diff, err := gh.DiffPR(ctx, repoPath, number)
+		if err != nil {
+			return nil, err
+		}
+		return PRDiffLoadedMsg{Number: number, Diff: diff}, nil
+	})
+}
+
+// startReview asks the AI backend to review the PR diff in the terminal
+// modal, once the AI budget allows it.
+func (m Model) startReview(diff string) (Model, tea.Cmd) {
+	m.Loading = false
+	backend, err := budget.Backend(m.Config)
+	if err != nil {
+		m.ErrMsg = err.Error()
+		return m, nil
+	}
+	m, ok := m.budgetAllows(backend, buildReviewPrompt(m.SelectedPR, diff), diff)
+	if !ok {
+		return m, nil
+	}
+	return m.runReview(diff)
+}
+
+// runReview asks the AI backend to review the PR diff in the terminal modal.
+func (m Model) runReview(diff string) (Model, tea.Cmd) {
+	backend, err := budget.Backend(m.Config)
+	if err != nil {
+		m.ErrMsg = err.Error()
+		return m, nil
+	}
+	m.ReviewPending = true
+	m.ReviewSections = nil
+	m.ReviewScroll = 0
+
+	return m.openAITerminal(fmt.Sprintf("Reviewing #%d...", m.SelectedPR.Number),
+		backend, buildReviewPrompt(m.SelectedPR, diff))
+}
+
+// buildReviewPrompt constructs the review prompt with the PR context and diff.
+func buildReviewPrompt(pr *gh.PR, diff string) string {
+	promptTemplate, err := embedded.GetCommandPrompt("review-pr")
+	if err != nil {
+		promptTemplate = "Summarize this pull request: notable changes, risks and suggested tests."
+	}
+
+	body := cutBytes(pr.Body, maxReviewBody, "\n[description truncated]")
+	context := func(diff string) string {
+		return fmt.Sprintf(`## Context
+
+- Pull request: #%d %s
+- Branch: %s → %s
+
+- Description:
+%s
+
+- Diff:
+%s
+
+`, pr.Number, pr.Title, pr.HeadRefName, pr.BaseRefName, body, diff)
+	}
+
+	room := maxReviewPrompt - len(context("")) - len(promptTemplate)
+	return context(cutBytes(diff, room, "\n[diff truncated]")) + promptTemplate
+}
+
+// cutBytes returns s cut to at most n bytes, note included, on a rune
+// boundary. note is appended if s was cut.
+func cutBytes(s string, n int, note string) string {
+	if len(s) <= n {
+		return s
+	}
+	n = max(n-len(note), 0)
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + note
+}
+
+// finishReview parses the AI output once the review command is done.
+func (m Model) finishReview(msg terminal.CommandDoneMsg) Model {
+	m.ReviewPending = false
+	if msg.ExitCode != 0 {
+		// Leave the terminal open so the failure can be read
+		return m
+	}
+
+	m.ReviewSections = parseReview(ai.Result(strings.Join(msg.Output, "\n")))
+	m.ReviewScroll = 0
+	m.CurrentView = ReviewView
+	return m
+}
+
+// parseReview splits the markdown review output into sections by "## " headings.
+// Text before the first heading is grouped under a "Summary" section.
+func parseReview(output string) []ReviewSection {
+	var sections []ReviewSection
+	var current *ReviewSection
+
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "```") {
+			continue
+		}
+
+		if title, ok := strings.CutPrefix(trimmed, "## "); ok {
+			sections = append(sections, ReviewSection{Title: strings.TrimSpace(title)})
+			current = &sections[len(sections)-1]
+			continue
+		}
+
+		if current == nil {
+			sections = append(sections, ReviewSection{Title: "Summary"})
+			current = &sections[len(sections)-1]
+		}
+
+		point := strings.TrimSpace(strings.TrimLeft(trimmed, "-*"))
+		current.Points = append(current.Points, point)
+	}
+
+	return sections
+}
+
+// UpdateReviewView handles input for the review summary view.
+func (m Model) UpdateReviewView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	kb := m.Config.Keys()
+
+	switch {
+	case config.MatchesAny(key, kb.Global.Quit, kb.Global.QuitAlt, kb.Detail.Back):
+		m.CurrentView = DetailView
+
+	case config.MatchesAny(key, kb.Detail.ScrollUp, kb.Global.MoveUp, kb.Global.MoveUpAlt):
+		if m.ReviewScroll > 0 {
+			m.ReviewScroll--
+		}
+
+	case config.MatchesAny(key, kb.Detail.ScrollDown, kb.Global.MoveDown, kb.Global.MoveDownAlt):
+		m.ReviewScroll++
+
+	case config.Matches(key, kb.PR.Review):
+		return m.loadReviewDiff()
+	}
+
+	return m, nil
+}
+
+// ViewReview renders the AI review summary in a scrollable pane.
+func (m Model) ViewReview() string {
+	if m.SelectedPR == nil {
+		return ""
+	}
+
+	wrapWidth := m.Width - 10
+	if wrapWidth < 30 {
+		wrapWidth = 30
+	}
+	wrap := lipgloss.NewStyle().Width(wrapWidth)
+
+	var lines []string
+	lines = append(lines, styles.Title.Render(fmt.Sprintf("  AI Review #%d", m.SelectedPR.Number)))
+	lines = append(lines, styles.Help.Render("─────────────────────────────────────────────────────"))
+
+	if len(m.ReviewSections) == 0 {
+		lines = append(lines, "", styles.Help.Render("  (no review output)"))
+	}
+
+	for _, section := range m.ReviewSections {
+		lines = append(lines, "")
+		titleStyle := styles.Label
+		if strings.EqualFold(section.Title, "Risks") {
+			titleStyle = styles.Confirm
+		}
+		lines = append(lines, titleStyle.Render(section.Title))
+		for _, point := range section.Points {
+			wrapped := strings.Split(wrap.Render(point), "\n")
+			for i, wl := range wrapped {
+				prefix := "    "
+				if i == 0 {
+					prefix = "  • "
+				}
+				lines = append(lines, styles.Help.Render(prefix)+styles.Value.Render(wl))
+			}
+		}
+	}
+
+	visibleLines := m.Height - 8
+	if visibleLines < 5 {
+		visibleLines = 5
+	}
+	scroll := min(m.ReviewScroll, max(len(lines)-visibleLines, 0))
+	end := min(scroll+visibleLines, len(lines))
+
+	var b strings.Builder
+	if scroll > 0 {
+		b.WriteString(styles.Help.Render("  ↑ scroll up for more"))
+		b.WriteString("\n")
+	}
+	for i := scroll; i < end; i++ {
+		b.WriteString(lines[i])
+		b.WriteString("\n")
+	}
+	if end < len(lines) {
+		b.WriteString(styles.Help.Render("  ↓ scroll down for more"))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	kb := m.Config.Keys()
+	b.WriteString(styles.Help.Render(fmt.Sprintf("↑/%s ↓/%s scroll • %s regenerate • %s back",
+		kb.Detail.ScrollUp, kb.Detail.ScrollDown, kb.PR.Review, kb.Detail.Back)))
+
+	return b.String()
+}
@@ -0,0 +1,247 @@
+// Package selector provides a filterable select list with autocomplete,
+// used to pick branches, refs and other values from a long list.
+package selector
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/git"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+)
+
+// maxVisible is the number of items shown at once.
+const maxVisible = 10
+
+// Result is the outcome of a key press in the selector.
+type Result int
+
+const (
+	Pending Result = iota
+	Selected
+	Canceled
+)
+
+// Item is a selectable entry.
+type Item struct {
+	Value  string // returned when selected and matched by the filter
+	Detail string // dimmed text shown after the value
+}
+
+// Model represents a filterable select list.
+// Typing filters the items, tab completes the highlighted item
+// and enter selects it.
+type Model struct {
+	Title string
+	Items []Item // in display order, best candidates first
+	Input string
+
+	// AllowCustom lets enter return the typed text when nothing matches.
+	AllowCustom bool
+
+	Cursor   int
+	Scroll   int
+	Filtered []Item
+
+	Config *config.Config
+
+	value string
+}
+
+// New creates a new selector over items.
+func New(cfg *config.Config, title string, items []Item) Model {
+	m := Model{
+		Title:  title,
+		Items:  items,
+		Config: cfg,
+	}
+	m.filter()
+	return m
+}
+
+// RefItems converts git refs to items, keeping their order.
+func RefItems(refs []git.Ref) []Item {
+	items := make([]Item, len(refs))
+	for i, r := range refs {
+		detail := r.Kind.String()
+		if !r.CommitDate.IsZero() {
+			detail += " • " + r.CommitDate.Format("Jan 2")
+		}
+		items[i] = Item{Value: r.Name, Detail: detail}
+	}
+	return items
+}
+
+// Value returns the selected value.
+func (m Model) Value() string {
+	return m.value
+}
+
+// Update handles a key press and reports whether a value was selected.
+func (m Model) Update(msg tea.KeyMsg) (Model, Result) {
+	key := msg.String()
+	kb := m.Config.Keys()
+
+	switch {
+	case config.Matches(key, kb.Global.Quit):
+		return m, Canceled
+
+	case key == "enter":
+		if len(m.Filtered) > 0 {
+			m.value = m.Filtered[m.Cursor].Value
+			return m, Selected
+		}
+		if m.AllowCustom && strings.TrimSpace(m.Input) != "" {
+			m.value = strings.TrimSpace(m.Input)
+			return m, Selected
+		}
+
+	case key == "tab":
+		if len(m.Filtered) > 0 {
+			m.Input = m.Filtered[m.Cursor].Value
+			m.filter()
+		}
+
+	case config.MatchesAny(key, kb.Global.MoveUpAlt, "ctrl+p"):
+		if m.Cursor > 0 {
+			m.Cursor--
+			if m.Cursor < m.Scroll {
+				m.Scroll = m.Cursor
+			}
+		}
+
+	case config.MatchesAny(key, kb.Global.MoveDownAlt, "ctrl+n"):
+		if m.Cursor < len(m.Filtered)-1 {
+			m.Cursor++
+			if m.Cursor >= m.Scroll+maxVisible {
+				m.Scroll = m.Cursor - maxVisible + 1
+			}
+		}
+
+	case key == "backspace":
+		if len(m.Input) > 0 {
+			m.Input = m.Input[:len(m.Input)-1]
+			m.filter()
+		}
+
+	case key == "space":
+		m.Input += " "
+		m.filter()
+
+	default:
+		if len(key) == 1 && key[0] >= 32 && key[0] < 127 {
+			m.Input += key
+			m.filter()
+		}
+	}
+
+	return m, Pending
+}
+
+// filter rebuilds Filtered from Input and resets the cursor.
+func (m *Model) filter() {
+	m.Filtered = Filter(m.Items, m.Input)
+	m.Cursor = 0
+	m.Scroll = 0
+}
+
+// Filter returns the items matching query, case-insensitively.
+// Items containing query as a substring come first, followed by items
+// matching it as a subsequence ("fb" matches "feature/bar"); each group
+// keeps the original item order.
+func Filter(items []Item, query string) []Item {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return items
+	}
+
+	var exact, fuzzy []Item
+	for _, item := range items {
+		value := strings.ToLower(item.Value)
+		switch {
+		case strings.Contains(value, query):
+			exact = append(exact, item)
+		case isSubsequence(query, value):
+			fuzzy = append(fuzzy, item)
+		}
+	}
+	return append(exact, fuzzy...)
+}
+
+// isSubsequence reports whether all characters of sub appear in s in order.
+func isSubsequence(sub, s string) bool {
+	i := 0
+	for j := 0; i < len(sub) && j < len(s); j++ {
+		if sub[i] == s[j] {
+			i++
+		}
+	}
+	return i == len(sub)
+}
+
+// View renders the selector in a bordered box.
+func (m Model) View() string {
+	var b strings.Builder
+
+	if m.Title != "" {
+		b.WriteString(styles.Title.Render(m.Title))
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString(styles.Prompt.Render("> "))
+	b.WriteString(styles.Input.Render(m.Input))
+	b.WriteString(styles.Cursor.Render("█"))
+	b.WriteString("\n\n")
+
+	if len(m.Filtered) == 0 {
+		if m.AllowCustom && strings.TrimSpace(m.Input) != "" {
+			b.WriteString(styles.Help.Render(fmt.Sprintf("no matches • enter to use %q", strings.TrimSpace(m.Input))))
+		} else {
+			b.WriteString(styles.Help.Render("no matches"))
+		}
+		b.WriteString("\n")
+	}
+
+	if m.Scroll > 0 {
+		b.WriteString(styles.Help.Render("  ↑ more"))
+		b.WriteString("\n")
+	}
+
+	end := m.Scroll + maxVisible
+	if end > len(m.Filtered) {
+		end = len(m.Filtered)
+	}
+	for i := m.Scroll; i < end; i++ {
+		item := m.Filtered[i]
+		if i == m.Cursor {
+			b.WriteString(styles.Cursor.Render("▸ "))
+			b.WriteString(styles.Selected.Render(item.Value))
+		} else {
+			b.WriteString("  ")
+			b.WriteString(styles.Item.Render(item.Value))
+		}
+		if item.Detail != "" {
+			b.WriteString(styles.Help.Render("  " + item.Detail))
+		}
+		b.WriteString("\n")
+	}
+
+	if end < len(m.Filtered) {
+		b.WriteString(styles.Help.Render(fmt.Sprintf("  ↓ %d more", len(m.Filtered)-end)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	kb := m.Config.Keys()
+	b.WriteString(styles.Help.Render(fmt.Sprintf("type to filter • ↑/↓ navigate • tab complete • enter select • %s cancel",
+		kb.Global.Quit)))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.Purple).
+		Padding(0, 1).
+		Render(b.String())
+}
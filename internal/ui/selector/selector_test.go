@@ -0,0 +1,38 @@
+package selector
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	items := []Item{
+		{Value: "main"},
+		{Value: "feature/bar"},
+		{Value: "origin/main"},
+		{Value: "fix-build"},
+		{Value: "admin"},
+	}
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"", []string{"main", "feature/bar", "origin/main", "fix-build", "admin"}},
+		{"main", []string{"main", "origin/main"}},
+		{"MAIN", []string{"main", "origin/main"}},
+		{"fb", []string{"feature/bar", "fix-build"}},
+		{"mi", []string{"admin", "main", "origin/main"}}, // substring before subsequence
+		{"xyz", nil},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, item := range Filter(items, tt.query) {
+			got = append(got, item.Value)
+		}
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("Filter(%q) = %v, want %v", tt.query, got, tt.expected)
+		}
+	}
+}
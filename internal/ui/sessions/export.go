@@ -0,0 +1,72 @@
+package sessions
+
+import (
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/claude"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/ui/failure"
+	"github.com/ihatemodels/gdev/internal/ui/form"
+)
+
+// exportDir is the store subdirectory exported transcripts go to by default.
+const exportDir = "exports"
+
+// openExport asks where to write the markdown transcript of s.
+func (m Model) openExport(s claude.Session) (tea.Model, tea.Cmd) {
+	path := form.Text("path", "Path", filepath.Join(m.Store.Path(), exportDir, s.ID+".md"))
+	path.Required = true
+
+	m.ExportTarget = &s
+	m.ExportForm = form.New(m.Config, "Export Transcript", path)
+	m.PreviousView = m.CurrentView
+	m.CurrentView = ExportView
+	return m, nil
+}
+
+// UpdateExportView handles input for the export path form.
+func (m Model) UpdateExportView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var res form.Result
+	m.ExportForm, res = m.ExportForm.Update(msg)
+
+	switch res {
+	case form.Submitted:
+		s := *m.ExportTarget
+		path := config.ExpandHome(m.ExportForm.Value("path"))
+		repoPath := m.RepoPath
+		store := m.Store
+
+		m.CurrentView = m.PreviousView
+		m.ExportTarget = nil
+		return m, failure.Cmd("Export transcript", func() (tea.Msg, error) {
+			turns, err := claude.LoadTranscript(repoPath, s.ID)
+			if err != nil {
+				return nil, err
+			}
+			data := []byte(claude.Markdown(s, turns))
+			if store.DryRun() {
+				store.Plan("write %s (%d bytes)", path, len(data))
+				return ExportedMsg{Path: path, DryRun: true}, nil
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return nil, err
+			}
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				return nil, err
+			}
+			return ExportedMsg{Path: path}, nil
+		})
+
+	case form.Canceled:
+		m.CurrentView = m.PreviousView
+		m.ExportTarget = nil
+	}
+	return m, nil
+}
+
+// ViewExport renders the export path form.
+func (m Model) ViewExport() string {
+	return m.ExportForm.View()
+}
@@ -0,0 +1,206 @@
+package sessions
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/claude"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+)
+
+// visibleItems returns how many session cards fit on screen.
+func (m Model) visibleItems() int {
+	visible := (m.Height - 10) / 4
+	if visible < 1 {
+		visible = 1
+	}
+	return visible
+}
+
+// UpdateListView handles input for the list view.
+func (m Model) UpdateListView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	visibleItems := m.visibleItems()
+
+	key := msg.String()
+	kb := m.Config.Keys()
+
+	// Handle quit/back
+	if config.MatchesAny(key, kb.Global.Quit, kb.Global.QuitAlt) {
+		return m, func() tea.Msg { return BackToMenuMsg{} }
+	}
+
+	// Handle navigation
+	if config.MatchesAny(key, kb.Global.MoveUp, kb.Global.MoveUpAlt) {
+		if m.Cursor > 0 {
+			m.Cursor--
+			if m.Cursor < m.ListScroll {
+				m.ListScroll = m.Cursor
+			}
+		}
+		return m, nil
+	}
+
+	if config.MatchesAny(key, kb.Global.MoveDown, kb.Global.MoveDownAlt) {
+		if m.Cursor < len(m.Sessions)-1 {
+			m.Cursor++
+			if m.Cursor >= m.ListScroll+visibleItems {
+				m.ListScroll = m.Cursor - visibleItems + 1
+			}
+		}
+		return m, nil
+	}
+
+	switch {
+	case config.Matches(key, kb.List.Top):
+		m.Cursor = 0
+		m.ListScroll = 0
+
+	case config.Matches(key, kb.List.Bottom):
+		if len(m.Sessions) > 0 {
+			m.Cursor = len(m.Sessions) - 1
+			if m.Cursor >= visibleItems {
+				m.ListScroll = m.Cursor - visibleItems + 1
+			}
+		}
+
+	case config.Matches(key, kb.Sessions.Refresh):
+		m.Loading = true
+		return m, m.LoadSessions()
+
+	case config.Matches(key, kb.Sessions.Resume):
+		if len(m.Sessions) > 0 {
+			return m.resume(m.Sessions[m.Cursor])
+		}
+
+	case config.Matches(key, kb.Sessions.Transcript):
+		if len(m.Sessions) > 0 {
+			return m.openTranscript(m.Sessions[m.Cursor])
+		}
+
+	case config.Matches(key, kb.Sessions.Export):
+		if len(m.Sessions) > 0 {
+			return m.openExport(m.Sessions[m.Cursor])
+		}
+
+	case config.Matches(key, kb.Sessions.ResumeModal):
+		if len(m.Sessions) > 0 {
+			return m.resumeInModal(m.Sessions[m.Cursor])
+		}
+	}
+
+	return m, nil
+}
+
+// ViewList renders the list view.
+func (m Model) ViewList() string {
+	var b strings.Builder
+
+	header := "  Claude Sessions"
+	if len(m.Sessions) > 0 {
+		var total claude.Usage
+		for _, s := range m.Sessions {
+			total.Add(s.Usage)
+		}
+		header += styles.Help.Render(fmt.Sprintf(" (%d)  •  %s tokens  •  %s",
+			len(m.Sessions), claude.FormatTokens(total.Total()), claude.FormatCost(total.Cost)))
+	}
+	b.WriteString(styles.Title.Render(header))
+	b.WriteString("\n")
+	b.WriteString(styles.Help.Render("─────────────────────────────────────────"))
+	b.WriteString("\n\n")
+
+	switch {
+	case m.Loading:
+		b.WriteString(styles.Help.Render("  Loading sessions..."))
+		b.WriteString("\n")
+	case len(m.Sessions) == 0:
+		b.WriteString(styles.Help.Render("  No Claude sessions for this repository"))
+		b.WriteString("\n")
+	default:
+		b.WriteString(m.viewSessionCards())
+	}
+
+	b.WriteString("\n\n")
+	kb := m.Config.Keys()
+	b.WriteString(styles.Help.Render(fmt.Sprintf("↑/%s ↓/%s navigate • %s/%s top/bottom",
+		kb.Global.MoveUp, kb.Global.MoveDown, kb.List.Top, kb.List.Bottom)))
+	b.WriteString("\n")
+	b.WriteString(styles.Help.Render(fmt.Sprintf("%s resume • %s resume in modal • %s transcript • %s export • %s refresh • %s back",
+		kb.Sessions.Resume, kb.Sessions.ResumeModal, kb.Sessions.Transcript, kb.Sessions.Export, kb.Sessions.Refresh, kb.Global.Quit)))
+
+	return b.String()
+}
+
+func (m Model) viewSessionCards() string {
+	var b strings.Builder
+
+	visibleItems := m.visibleItems()
+
+	if m.ListScroll > 0 {
+		b.WriteString(styles.Help.Render("  ↑ more above"))
+		b.WriteString("\n\n")
+	}
+
+	endIdx := m.ListScroll + visibleItems
+	if endIdx > len(m.Sessions) {
+		endIdx = len(m.Sessions)
+	}
+
+	titleWidth := m.Width - 16
+	if titleWidth < 20 {
+		titleWidth = 20
+	}
+
+	for i := m.ListScroll; i < endIdx; i++ {
+		s := m.Sessions[i]
+		title := s.Title
+		if title == "" {
+			title = "(untitled session)"
+		}
+		title = truncate(title, titleWidth)
+
+		if i == m.Cursor {
+			b.WriteString(styles.Cursor.Render("▸ "))
+			b.WriteString(styles.Selected.Render("┌─ "))
+			b.WriteString(styles.Selected.Render(title))
+		} else {
+			b.WriteString("  ")
+			b.WriteString(styles.Help.Render("┌─ "))
+			b.WriteString(styles.Item.Render(title))
+		}
+		b.WriteString("\n")
+
+		b.WriteString("  ")
+		b.WriteString(styles.Help.Render("│  "))
+		if s.Branch != "" {
+			b.WriteString(styles.Branch.Render(" " + s.Branch))
+			b.WriteString(styles.Help.Render("  •  "))
+		}
+		b.WriteString(styles.Help.Render(fmt.Sprintf("%d messages  •  %s",
+			s.Messages, s.UpdatedAt.Local().Format(time.DateTime))))
+		if s.Usage.Total() > 0 {
+			b.WriteString(styles.Help.Render("  •  "))
+			b.WriteString(styles.Value.Render(fmt.Sprintf("%s tokens  %s",
+				claude.FormatTokens(s.Usage.Total()), claude.FormatCost(s.Usage.Cost))))
+		}
+		b.WriteString("\n")
+
+		b.WriteString("  ")
+		b.WriteString(styles.Help.Render("└───"))
+		b.WriteString("\n")
+
+		if i < endIdx-1 {
+			b.WriteString("\n")
+		}
+	}
+
+	if endIdx < len(m.Sessions) {
+		b.WriteString("\n")
+		b.WriteString(styles.Help.Render("  ↓ more below"))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,291 @@
+// Package sessions provides the Claude sessions TUI component.
+package sessions
+
+import (
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ihatemodels/gdev/internal/claude"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/store"
+	"github.com/ihatemodels/gdev/internal/ui/failure"
+	"github.com/ihatemodels/gdev/internal/ui/form"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+	"github.com/ihatemodels/gdev/internal/ui/terminal"
+)
+
+// View represents the current view within the sessions component.
+type View int
+
+const (
+	ListView View = iota
+	TranscriptView
+	ExportView
+	TerminalView
+)
+
+// Model is the Bubble Tea model for browsing and resuming Claude sessions.
+type Model struct {
+	Store    *store.Store
+	Config   *config.Config
+	RepoPath string
+
+	CurrentView View
+	Sessions    []claude.Session
+	Cursor      int
+	ListScroll  int
+
+	// Transcript view
+	SelectedSession  *claude.Session
+	Turns            []claude.Turn
+	TranscriptLines  []transcriptLine
+	TranscriptScroll int
+
+	// Transcript search
+	Searching   bool   // true while typing a query
+	SearchInput string // query being typed
+	SearchQuery string // active query
+	Matches     []int  // indexes into TranscriptLines
+	MatchIdx    int
+
+	// Markdown export
+	ExportTarget *claude.Session
+	ExportForm   form.Model
+
+	// UI state
+	Width   int
+	Height  int
+	ErrMsg  string
+	Notice  string // confirmation of the last action
+	Loading bool
+
+	// Terminal modal for resuming a session inline
+	Terminal     terminal.Model
+	PreviousView View
+}
+
+// Message types
+type (
+	SessionsLoadedMsg struct {
+		Sessions []claude.Session
+	}
+
+	TranscriptLoadedMsg struct {
+		ID    string
+		Turns []claude.Turn
+	}
+
+	// ExportedMsg signals that a transcript was written to Path.
+	ExportedMsg struct {
+		Path   string
+		DryRun bool // nothing was written
+	}
+
+	// SessionExitedMsg signals that a resumed session handed the terminal back.
+	SessionExitedMsg struct {
+		Err error
+	}
+
+	BackToMenuMsg struct{}
+)
+
+// New creates a new Model.
+func New(s *store.Store, cfg *config.Config, repoPath string) Model {
+	return Model{
+		Store:       s,
+		Config:      cfg,
+		RepoPath:    repoPath,
+		CurrentView: ListView,
+		Loading:     true,
+	}
+}
+
+// SetSize sets the width and height of the model.
+func (m *Model) SetSize(width, height int) {
+	m.Width = width
+	m.Height = height
+}
+
+// Init implements tea.Model.
+func (m Model) Init() tea.Cmd {
+	return m.LoadSessions()
+}
+
+// LoadSessions returns a command that loads the Claude sessions recorded for the repository.
+func (m Model) LoadSessions() tea.Cmd {
+	return failure.Cmd("Load sessions", func() (tea.Msg, error) {
+		sessions, err := claude.ListSessions(m.RepoPath)
+		if err != nil {
+			return nil, err
+		}
+		return SessionsLoadedMsg{Sessions: sessions}, nil
+	})
+}
+
+// Update implements tea.Model.
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.Width = msg.Width
+		m.Height = msg.Height
+		m.Terminal.SetSize(msg.Width, msg.Height)
+		m.layoutTranscript()
+		return m, nil
+
+	case SessionsLoadedMsg:
+		m.Sessions = msg.Sessions
+		m.Loading = false
+		if m.Cursor >= len(m.Sessions) {
+			m.Cursor = max(len(m.Sessions)-1, 0)
+		}
+		m.ListScroll = min(m.ListScroll, m.Cursor)
+		return m, nil
+
+	case TranscriptLoadedMsg:
+		if m.SelectedSession != nil && m.SelectedSession.ID == msg.ID {
+			m.Turns = msg.Turns
+			m.layoutTranscript()
+		}
+		m.Loading = false
+		return m, nil
+
+	case failure.Msg:
+		// The app shows the error screen; stop waiting for the result
+		m.Loading = false
+		return m, nil
+
+	case ExportedMsg:
+		m.Notice = "Exported to " + msg.Path
+		if msg.DryRun {
+			m.Notice = "Dry run: would export to " + msg.Path
+		}
+		return m, nil
+
+	case SessionExitedMsg:
+		if msg.Err != nil {
+			m.ErrMsg = msg.Err.Error()
+		}
+		// The session may have new messages
+		return m, m.LoadSessions()
+
+	case terminal.TickMsg:
+		if m.CurrentView == TerminalView {
+			var cmd tea.Cmd
+			m.Terminal, cmd = m.Terminal.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		m.ErrMsg = ""
+		m.Notice = ""
+		return m.handleKeyMsg(msg)
+	}
+	return m, nil
+}
+
+func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.CurrentView {
+	case ListView:
+		return m.UpdateListView(msg)
+	case TranscriptView:
+		return m.UpdateTranscriptView(msg)
+	case ExportView:
+		return m.UpdateExportView(msg)
+	case TerminalView:
+		return m.UpdateTerminalView(msg)
+	}
+	return m, nil
+}
+
+// UpdateTerminalView handles input for the terminal modal.
+func (m Model) UpdateTerminalView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.Terminal.ShouldClose(msg) {
+		m.Terminal.Cancel()
+		m.CurrentView = m.PreviousView
+		return m, m.LoadSessions()
+	}
+
+	var cmd tea.Cmd
+	m.Terminal, cmd = m.Terminal.Update(msg)
+	return m, cmd
+}
+
+// resume suspends the TUI and hands the real terminal to `claude --resume`.
+// The TUI is restored when claude exits. Resumed sessions can change the
+// repository, so none are in read-only mode.
+func (m Model) resume(s claude.Session) (tea.Model, tea.Cmd) {
+	if m.Config.ReadOnly() {
+		m.ErrMsg = store.ErrReadOnly.Error()
+		return m, nil
+	}
+	c := exec.Command("claude", claude.ResumeArgs(s.ID)...)
+	c.Dir = m.RepoPath
+	return m, tea.ExecProcess(c, func(err error) tea.Msg {
+		return SessionExitedMsg{Err: err}
+	})
+}
+
+// resumeInModal runs `claude --resume` in the terminal modal.
+func (m Model) resumeInModal(s claude.Session) (tea.Model, tea.Cmd) {
+	if m.Config.ReadOnly() {
+		m.ErrMsg = store.ErrReadOnly.Error()
+		return m, nil
+	}
+	m.Terminal = terminal.New(m.Config, "Resume: "+truncate(s.Title, 40))
+	m.Terminal.Dir = m.RepoPath
+	m.Terminal.SetSize(m.Width, m.Height)
+	m.PreviousView = m.CurrentView
+	m.CurrentView = TerminalView
+
+	cmd := m.Terminal.RunCommand("claude", claude.ResumeArgs(s.ID)...)
+	return m, cmd
+}
+
+// View implements tea.Model.
+func (m Model) View() string {
+	if m.Width == 0 {
+		return "Loading..."
+	}
+
+	if m.CurrentView == TerminalView {
+		return m.Terminal.ViewCentered(m.Width, m.Height)
+	}
+
+	var content strings.Builder
+
+	switch m.CurrentView {
+	case ListView:
+		content.WriteString(m.ViewList())
+	case TranscriptView:
+		content.WriteString(m.ViewTranscript())
+	case ExportView:
+		content.WriteString(m.ViewExport())
+	}
+
+	if m.ErrMsg != "" {
+		content.WriteString("\n\n")
+		content.WriteString(styles.Error.Render("Error: " + m.ErrMsg))
+	} else if m.Notice != "" {
+		content.WriteString("\n\n")
+		content.WriteString(styles.Status.Render(m.Notice))
+	}
+
+	return lipgloss.NewStyle().
+		Width(m.Width).
+		Height(m.Height).
+		Padding(1, 2).
+		Render(content.String())
+}
+
+// truncate shortens s to at most n characters, adding an ellipsis.
+func truncate(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-3]) + "..."
+}
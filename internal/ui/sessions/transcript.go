@@ -0,0 +1,332 @@
+package sessions
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ihatemodels/gdev/internal/claude"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/ui/failure"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+)
+
+// lineKind is the role of a rendered transcript line.
+type lineKind int
+
+const (
+	lineText lineKind = iota
+	lineUser
+	lineAssistant
+	lineTool
+)
+
+// transcriptLine is a wrapped line of the rendered transcript.
+type transcriptLine struct {
+	Text string
+	Kind lineKind
+}
+
+// openTranscript shows the transcript of s, loading it in the background.
+func (m Model) openTranscript(s claude.Session) (tea.Model, tea.Cmd) {
+	m.SelectedSession = &s
+	m.Turns = nil
+	m.TranscriptLines = nil
+	m.TranscriptScroll = 0
+	m.SearchQuery = ""
+	m.SearchInput = ""
+	m.Searching = false
+	m.Matches = nil
+	m.Loading = true
+	m.CurrentView = TranscriptView
+
+	repoPath := m.RepoPath
+	return m, failure.Cmd("Load transcript", func() (tea.Msg, error) {
+		turns, err := claude.LoadTranscript(repoPath, s.ID)
+		if err != nil {
+			return nil, err
+		}
+		return TranscriptLoadedMsg{ID: s.ID, Turns: turns}, nil
+	})
+}
+
+// layoutTranscript wraps the turns to the current width.
+func (m *Model) layoutTranscript() {
+	width := m.Width - 8
+	if width < 20 {
+		width = 20
+	}
+
+	m.TranscriptLines = nil
+	for _, t := range m.Turns {
+		header := transcriptLine{Kind: lineAssistant, Text: "Claude"}
+		if t.Role == "user" {
+			header = transcriptLine{Kind: lineUser, Text: "You"}
+		}
+		if !t.Timestamp.IsZero() {
+			header.Text += "  " + t.Timestamp.Local().Format("Jan 2 15:04")
+		}
+		m.TranscriptLines = append(m.TranscriptLines, header)
+
+		for _, line := range strings.Split(t.Text, "\n") {
+			kind := lineText
+			if strings.HasPrefix(line, "[") && t.Role == "assistant" {
+				kind = lineTool
+			}
+			for _, w := range wrap(strings.ReplaceAll(line, "\t", "    "), width) {
+				m.TranscriptLines = append(m.TranscriptLines, transcriptLine{Text: w, Kind: kind})
+			}
+		}
+		m.TranscriptLines = append(m.TranscriptLines, transcriptLine{})
+	}
+	m.search()
+}
+
+// wrap hard-wraps s to lines of at most width runes.
+func wrap(s string, width int) []string {
+	r := []rune(s)
+	if len(r) <= width {
+		return []string{s}
+	}
+	var lines []string
+	for len(r) > width {
+		lines = append(lines, string(r[:width]))
+		r = r[width:]
+	}
+	return append(lines, string(r))
+}
+
+// search finds the lines matching SearchQuery, case-insensitively.
+func (m *Model) search() {
+	m.Matches = nil
+	m.MatchIdx = 0
+	if m.SearchQuery == "" {
+		return
+	}
+	q := strings.ToLower(m.SearchQuery)
+	for i, l := range m.TranscriptLines {
+		if strings.Contains(strings.ToLower(l.Text), q) {
+			m.Matches = append(m.Matches, i)
+		}
+	}
+}
+
+// jumpToMatch scrolls so the current match is visible.
+func (m *Model) jumpToMatch() {
+	if len(m.Matches) == 0 {
+		return
+	}
+	line := m.Matches[m.MatchIdx]
+	visible := m.transcriptHeight()
+	if line < m.TranscriptScroll || line >= m.TranscriptScroll+visible {
+		m.TranscriptScroll = max(line-visible/3, 0)
+	}
+}
+
+func (m Model) transcriptHeight() int {
+	h := m.Height - 10
+	if h < 5 {
+		h = 5
+	}
+	return h
+}
+
+// UpdateTranscriptView handles input for the transcript view.
+func (m Model) UpdateTranscriptView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	kb := m.Config.Keys()
+
+	if m.Searching {
+		switch {
+		case config.Matches(key, kb.Global.Quit):
+			m.Searching = false
+		case key == "enter":
+			m.Searching = false
+			m.SearchQuery = strings.TrimSpace(m.SearchInput)
+			m.search()
+			m.jumpToMatch()
+		case key == "backspace":
+			if len(m.SearchInput) > 0 {
+				m.SearchInput = m.SearchInput[:len(m.SearchInput)-1]
+			}
+		case key == "space":
+			m.SearchInput += " "
+		default:
+			if len(key) == 1 {
+				m.SearchInput += key
+			}
+		}
+		return m, nil
+	}
+
+	page := m.transcriptHeight()
+	maxScroll := max(len(m.TranscriptLines)-page, 0)
+
+	switch {
+	case config.MatchesAny(key, kb.Global.Quit, kb.Global.QuitAlt, kb.Detail.Back):
+		if m.SearchQuery != "" {
+			m.SearchQuery = ""
+			m.Matches = nil
+			return m, nil
+		}
+		m.CurrentView = ListView
+		m.SelectedSession = nil
+		return m, nil
+
+	case config.MatchesAny(key, kb.Detail.ScrollUp, kb.Global.MoveUp, kb.Global.MoveUpAlt):
+		m.TranscriptScroll--
+
+	case config.MatchesAny(key, kb.Detail.ScrollDown, kb.Global.MoveDown, kb.Global.MoveDownAlt):
+		m.TranscriptScroll++
+
+	case config.Matches(key, kb.List.PageUp):
+		m.TranscriptScroll -= page
+
+	case config.Matches(key, kb.List.PageDown):
+		m.TranscriptScroll += page
+
+	case config.Matches(key, kb.List.Top):
+		m.TranscriptScroll = 0
+
+	case config.Matches(key, kb.List.Bottom):
+		m.TranscriptScroll = maxScroll
+
+	case config.Matches(key, kb.Sessions.Search):
+		m.Searching = true
+		m.SearchInput = m.SearchQuery
+
+	case config.Matches(key, kb.Sessions.NextMatch):
+		if len(m.Matches) > 0 {
+			m.MatchIdx = (m.MatchIdx + 1) % len(m.Matches)
+			m.jumpToMatch()
+		}
+
+	case config.Matches(key, kb.Sessions.PrevMatch):
+		if len(m.Matches) > 0 {
+			m.MatchIdx = (m.MatchIdx - 1 + len(m.Matches)) % len(m.Matches)
+			m.jumpToMatch()
+		}
+
+	case config.Matches(key, kb.Sessions.Resume):
+		if m.SelectedSession != nil {
+			return m.resume(*m.SelectedSession)
+		}
+
+	case config.Matches(key, kb.Sessions.Export):
+		if m.SelectedSession != nil {
+			return m.openExport(*m.SelectedSession)
+		}
+	}
+
+	m.TranscriptScroll = min(max(m.TranscriptScroll, 0), maxScroll)
+	return m, nil
+}
+
+// ViewTranscript renders the transcript view.
+func (m Model) ViewTranscript() string {
+	if m.SelectedSession == nil {
+		return ""
+	}
+
+	var b strings.Builder
+
+	title := m.SelectedSession.Title
+	if title == "" {
+		title = "(untitled session)"
+	}
+	b.WriteString(styles.Title.Render("  " + truncate(title, m.Width-10)))
+	b.WriteString("\n")
+	b.WriteString(styles.Help.Render("─────────────────────────────────────────────────────"))
+	b.WriteString("\n\n")
+
+	if m.Loading {
+		b.WriteString(styles.Help.Render("  Loading transcript..."))
+		b.WriteString("\n")
+	} else if len(m.TranscriptLines) == 0 {
+		b.WriteString(styles.Help.Render("  (empty transcript)"))
+		b.WriteString("\n")
+	}
+
+	current := -1
+	if len(m.Matches) > 0 {
+		current = m.Matches[m.MatchIdx]
+	}
+
+	end := min(m.TranscriptScroll+m.transcriptHeight(), len(m.TranscriptLines))
+	for i := m.TranscriptScroll; i < end; i++ {
+		b.WriteString(m.renderTranscriptLine(m.TranscriptLines[i], i == current))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	kb := m.Config.Keys()
+	switch {
+	case m.Searching:
+		b.WriteString(styles.Prompt.Render("/"))
+		b.WriteString(styles.Input.Render(m.SearchInput))
+		b.WriteString(styles.Cursor.Render("█"))
+		b.WriteString(styles.Help.Render(fmt.Sprintf("  enter search • %s cancel", kb.Global.Quit)))
+	case m.SearchQuery != "":
+		status := "no matches"
+		if len(m.Matches) > 0 {
+			status = fmt.Sprintf("match %d/%d", m.MatchIdx+1, len(m.Matches))
+		}
+		b.WriteString(styles.Help.Render(fmt.Sprintf("/%s  •  %s  •  %s/%s next/prev • %s clear",
+			m.SearchQuery, status, kb.Sessions.NextMatch, kb.Sessions.PrevMatch, kb.Global.Quit)))
+	default:
+		b.WriteString(styles.Help.Render(fmt.Sprintf("↑/%s ↓/%s scroll • %s/%s page • %s search • %s resume • %s export • %s back",
+			kb.Detail.ScrollUp, kb.Detail.ScrollDown, kb.List.PageUp, kb.List.PageDown,
+			kb.Sessions.Search, kb.Sessions.Resume, kb.Sessions.Export, kb.Detail.Back)))
+	}
+
+	return b.String()
+}
+
+func (m Model) renderTranscriptLine(l transcriptLine, current bool) string {
+	var style lipgloss.Style
+	switch l.Kind {
+	case lineUser:
+		return styles.Selected.Render("▍" + l.Text)
+	case lineAssistant:
+		return styles.Prompt.Render("▍" + l.Text)
+	case lineTool:
+		style = styles.Dim
+	default:
+		style = styles.Value
+	}
+
+	text := "  " + l.Text
+	if m.SearchQuery == "" {
+		return style.Render(text)
+	}
+	return highlight(text, m.SearchQuery, style, current)
+}
+
+// highlight renders text with every case-insensitive occurrence of query highlighted.
+// The current match is drawn in a stronger color.
+func highlight(text, query string, base lipgloss.Style, current bool) string {
+	mark := lipgloss.NewStyle().Reverse(true).Foreground(styles.Yellow)
+	if current {
+		mark = mark.Foreground(styles.Pink)
+	}
+
+	lower := strings.ToLower(text)
+	q := strings.ToLower(query)
+	if len(lower) != len(text) {
+		// Lowercasing changed byte offsets; skip highlighting
+		return base.Render(text)
+	}
+
+	var b strings.Builder
+	for {
+		i := strings.Index(lower, q)
+		if i < 0 {
+			b.WriteString(base.Render(text))
+			return b.String()
+		}
+		b.WriteString(base.Render(text[:i]))
+		b.WriteString(mark.Render(text[i : i+len(q)]))
+		text, lower = text[i+len(q):], lower[i+len(q):]
+	}
+}
@@ -0,0 +1,160 @@
+package settings
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/claude"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/gh"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+)
+
+// Account is the login status of a CLI or API gdev integrates with.
+type Account struct {
+	Name     string
+	Status   string // who is logged in, or why not
+	LoggedIn bool
+
+	// Login is the command that starts the login flow, empty if there is none
+	Login []string
+}
+
+// Message types
+type (
+	AccountsLoadedMsg struct {
+		Accounts []Account
+	}
+
+	// LoginDoneMsg signals that a login flow handed the terminal back.
+	LoginDoneMsg struct {
+		Err error
+	}
+)
+
+// loadAccounts checks the login status of gh, claude and the API keys they
+// can use instead. Problems are reported as the status rather than as errors,
+// since a missing tool is a normal setup.
+func loadAccounts() tea.Msg {
+	var accounts []Account
+
+	ghAccount := Account{Name: "GitHub CLI", Login: append([]string{"gh"}, gh.LoginArgs()...)}
+	switch login, err := gh.AuthStatus(); {
+	case err == gh.ErrNotInstalled:
+		ghAccount.Status = "not installed"
+		ghAccount.Login = nil
+	case err != nil:
+		ghAccount.Status = err.Error()
+	case login == "":
+		ghAccount.Status = "not logged in"
+	default:
+		ghAccount.Status = login
+		ghAccount.LoggedIn = true
+	}
+	accounts = append(accounts, ghAccount)
+
+	claudeAccount := Account{Name: "Claude", Login: append([]string{"claude"}, claude.LoginArgs()...)}
+	if _, err := exec.LookPath("claude"); err != nil {
+		claudeAccount.Status = "not installed"
+		claudeAccount.Login = nil
+	} else {
+		switch acct, err := claude.LoggedInAccount(); {
+		case err != nil:
+			claudeAccount.Status = err.Error()
+		case acct == nil:
+			claudeAccount.Status = "not logged in"
+		default:
+			claudeAccount.Status = acct.Email
+			if acct.Organization != "" {
+				claudeAccount.Status += " (" + acct.Organization + ")"
+			}
+			claudeAccount.LoggedIn = true
+		}
+	}
+	accounts = append(accounts, claudeAccount)
+
+	// Only whether keys are set is shown, never their values
+	for _, name := range []string{"GH_TOKEN", "GITHUB_TOKEN", "ANTHROPIC_API_KEY"} {
+		key := Account{Name: name, Status: "not set"}
+		if os.Getenv(name) != "" {
+			key.Status = "set"
+			key.LoggedIn = true
+		}
+		accounts = append(accounts, key)
+	}
+
+	return AccountsLoadedMsg{Accounts: accounts}
+}
+
+// updateAccounts handles input for the account list.
+func (m Model) updateAccounts(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	kb := m.Config.Keys()
+
+	switch {
+	case config.MatchesAny(key, kb.Global.MoveUp, kb.Global.MoveUpAlt):
+		if m.AccountCursor > 0 {
+			m.AccountCursor--
+		}
+
+	case config.MatchesAny(key, kb.Global.MoveDown, kb.Global.MoveDownAlt):
+		if m.AccountCursor < len(m.Accounts)-1 {
+			m.AccountCursor++
+		}
+
+	case config.Matches(key, kb.List.Select):
+		if m.AccountCursor < len(m.Accounts) {
+			return m.login(m.Accounts[m.AccountCursor])
+		}
+	}
+	return m, nil
+}
+
+// login suspends the TUI and runs the account's login flow, which prompts
+// for input and may open a browser, so it needs the real terminal.
+func (m Model) login(a Account) (tea.Model, tea.Cmd) {
+	if len(a.Login) == 0 {
+		return m, nil
+	}
+	if m.Config.DryRun() {
+		m.Notice = "Dry run: would run " + strings.Join(a.Login, " ")
+		return m, nil
+	}
+
+	c := exec.Command(a.Login[0], a.Login[1:]...)
+	return m, tea.ExecProcess(c, func(err error) tea.Msg {
+		return LoginDoneMsg{Err: err}
+	})
+}
+
+// viewAccounts renders the account list.
+func (m Model) viewAccounts() string {
+	var b strings.Builder
+
+	b.WriteString(m.sectionTitle(SectionAccounts, "Accounts"))
+	b.WriteString("\n")
+
+	if m.Accounts == nil {
+		b.WriteString(styles.Help.Render("  Checking..."))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	for i, a := range m.Accounts {
+		status := styles.Error.Render("✗ " + a.Status)
+		if a.LoggedIn {
+			status = styles.Selected.Render("✓ ") + styles.Value.Render(a.Status)
+		}
+		line := fmt.Sprintf("%-18s", a.Name)
+		if m.Section == SectionAccounts && i == m.AccountCursor {
+			b.WriteString(styles.Selected.Render(styles.Cursor.Render("▸ ")+line) + status)
+		} else {
+			b.WriteString(styles.Item.Render("  "+line) + status)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
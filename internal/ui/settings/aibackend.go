@@ -0,0 +1,110 @@
+package settings
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/ai"
+	"github.com/ihatemodels/gdev/internal/ui/form"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+)
+
+// noFallback is the fallback option of the form for none.
+const noFallback = "none"
+
+// openAIBackend opens the form for the backend writing commit messages and
+// the one it falls back to.
+func (m Model) openAIBackend() (tea.Model, tea.Cmd) {
+	st := m.Config.Settings
+	fallback := st.AIFallback
+	if fallback == "" {
+		fallback = noFallback
+	}
+	timeout := form.Text("timeout", "Fall back after (e.g. 2m, 0 to wait)", st.AITimeout)
+	timeout.Validate = func(value string) error {
+		if d, err := time.ParseDuration(strings.TrimSpace(value)); err != nil || d < 0 {
+			return errors.New("not a duration, e.g. 90s or 2m")
+		}
+		return nil
+	}
+	m.Form = form.New(m.Config, "AI Backend",
+		form.Select("backend", "Backend", ai.Backends, st.AIBackend),
+		form.Text("model", "Model (empty for the backend's default)", st.AIModel),
+		form.Select("fallback", "Fallback when it fails", append([]string{noFallback}, ai.Backends...), fallback),
+		form.Text("fallback_model", "Fallback model (empty for its default)", st.AIFallbackModel),
+		timeout,
+	)
+	m.State = StateEditing
+	return m, nil
+}
+
+// updateAIBackend handles input for the AI backend form and saves it once submitted.
+func (m Model) updateAIBackend(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var res form.Result
+	m.Form, res = m.Form.Update(msg)
+
+	switch res {
+	case form.Submitted:
+		m.Config.Settings.AIBackend = m.Form.Value("backend")
+		m.Config.Settings.AIModel = strings.TrimSpace(m.Form.Value("model"))
+		m.Config.Settings.AIFallback = m.Form.Value("fallback")
+		if m.Config.Settings.AIFallback == noFallback {
+			m.Config.Settings.AIFallback = ""
+		}
+		m.Config.Settings.AIFallbackModel = strings.TrimSpace(m.Form.Value("fallback_model"))
+		m.Config.Settings.AITimeout = strings.TrimSpace(m.Form.Value("timeout"))
+		m.State = StateList
+		return m, m.save("Save settings", "ai_backend", m.Config.Settings.AIBackend)
+
+	case form.Canceled:
+		m.State = StateList
+	}
+	return m, nil
+}
+
+// viewAIBackend renders the AI backend section, with why the backend can't
+// be used if it can't.
+func (m Model) viewAIBackend() string {
+	var b strings.Builder
+	st := m.Config.Settings
+
+	b.WriteString(m.sectionTitle(SectionAI, "AI Backend"))
+	b.WriteString("\n")
+
+	value := styles.Value.Render(st.AIBackend)
+	if backend, err := ai.New(st.AIBackend, st.AIModel); err != nil {
+		value = styles.Error.Render("✗ " + err.Error())
+	} else if err := backend.Check(); err != nil {
+		value = styles.Value.Render(ai.Describe(backend)) + styles.Error.Render("  ✗ "+err.Error())
+	} else {
+		value = styles.Value.Render(ai.Describe(backend))
+	}
+
+	line := fmt.Sprintf("%-18s", "Commit messages")
+	if m.Section == SectionAI {
+		b.WriteString(styles.Selected.Render(styles.Cursor.Render("▸ ")+line) + value)
+	} else {
+		b.WriteString(styles.Item.Render("  "+line) + value)
+	}
+	b.WriteString("\n")
+
+	if st.AIFallback != "" {
+		value := styles.Value.Render(st.AIFallback)
+		if fallback, err := ai.New(st.AIFallback, st.AIFallbackModel); err != nil {
+			value = styles.Error.Render("✗ " + err.Error())
+		} else if err := fallback.Check(); err != nil {
+			value = styles.Value.Render(ai.Describe(fallback)) + styles.Error.Render("  ✗ "+err.Error())
+		} else {
+			value = styles.Value.Render(ai.Describe(fallback))
+		}
+		if timeout := st.FallbackTimeout(); timeout > 0 {
+			value += styles.Help.Render(" • after " + timeout.String())
+		}
+		b.WriteString(styles.Item.Render(fmt.Sprintf("  %-18s", "Falls back to")) + value)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
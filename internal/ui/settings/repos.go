@@ -0,0 +1,106 @@
+package settings
+
+import (
+	"fmt"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/ui/confirm"
+	"github.com/ihatemodels/gdev/internal/ui/failure"
+	"github.com/ihatemodels/gdev/internal/ui/selector"
+)
+
+// openForget opens a selector of the repositories gdev keeps data for.
+func (m Model) openForget() (tea.Model, tea.Cmd) {
+	repos, err := m.Config.KnownRepos()
+	if err != nil {
+		return m, func() tea.Msg { return failure.Msg{Op: "List repositories", Err: err} }
+	}
+	if len(repos) == 0 {
+		m.Notice = "No repositories to forget"
+		return m, nil
+	}
+
+	items := make([]selector.Item, len(repos))
+	for i, r := range repos {
+		detail := "opened " + r.LastOpenedAt.Format("Jan 2")
+		if n := m.Config.RepoTodos(r.Path); n > 0 {
+			detail += fmt.Sprintf(" • %s", todoCount(n))
+		}
+		items[i] = selector.Item{Value: r.Path, Detail: detail}
+	}
+	m.Selector = selector.New(m.Config, "Forget repository", items)
+	m.State = StateSelecting
+	return m, nil
+}
+
+// updateForgetSelect handles input for the repository selector. A repository
+// with TODOs first asks whether to export them, then every repository asks
+// before anything is deleted.
+func (m Model) updateForgetSelect(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var res selector.Result
+	m.Selector, res = m.Selector.Update(msg)
+
+	switch res {
+	case selector.Selected:
+		repo := m.Selector.Value()
+		m.State = StateList
+		n := m.Config.RepoTodos(repo)
+		if n == 0 {
+			m.confirmForget(repo, n, false)
+			return m, nil
+		}
+
+		dialog := confirm.New(m.Config, fmt.Sprintf("Export its %s first?", todoCount(n)),
+			"They are copied to ~/.gdev/exports before "+filepath.Base(repo)+" is forgotten.")
+		m.confirm(dialog, func(m *Model) tea.Cmd {
+			m.confirmForget(repo, n, true)
+			return nil
+		})
+		m.OnCancel = func(m *Model) tea.Cmd {
+			m.confirmForget(repo, n, false)
+			return nil
+		}
+
+	case selector.Canceled:
+		m.State = StateList
+	}
+	return m, nil
+}
+
+// confirmForget asks before deleting what gdev keeps for repo, which has
+// todos TODOs, exported first if export is set.
+func (m *Model) confirmForget(repo string, todos int, export bool) {
+	message := "Its remembered positions and audit log are deleted."
+	switch {
+	case export:
+		message = fmt.Sprintf("Its remembered positions and audit log are deleted; its %s are exported first.", todoCount(todos))
+	case todos > 0:
+		message = fmt.Sprintf("Its remembered positions, audit log and %s are deleted.", todoCount(todos))
+	}
+
+	dialog := confirm.New(m.Config, fmt.Sprintf("Forget %s?", filepath.Base(repo)), message)
+	dialog.Destructive = true
+	m.confirm(dialog, func(m *Model) tea.Cmd {
+		exported, err := m.Config.ForgetRepo(repo, export)
+		if err != nil {
+			return func() tea.Msg { return failure.Msg{Op: "Forget repository", Err: err} }
+		}
+		m.Notice = "Forgot " + repo
+		if exported != "" {
+			m.Notice += ", TODOs exported to " + exported
+		}
+		if m.Config.DryRun() {
+			m.Notice = "Dry run: would forget " + repo
+		}
+		return m.checkStorage()
+	})
+}
+
+// todoCount formats a number of TODOs, e.g. "2 TODOs".
+func todoCount(n int) string {
+	if n == 1 {
+		return "1 TODO"
+	}
+	return fmt.Sprintf("%d TODOs", n)
+}
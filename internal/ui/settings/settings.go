@@ -0,0 +1,477 @@
+// Package settings provides the settings TUI component: the login status of
+// integrated tools, the ssh-agent strategy, the AI backend, the storage used
+// in ~/.gdev and an editor for keybindings.
+package settings
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/store"
+	"github.com/ihatemodels/gdev/internal/ui/confirm"
+	"github.com/ihatemodels/gdev/internal/ui/failure"
+	"github.com/ihatemodels/gdev/internal/ui/form"
+	"github.com/ihatemodels/gdev/internal/ui/selector"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+)
+
+// State represents the current state of the settings view.
+type State int
+
+const (
+	StateList       State = iota
+	StateCapturing        // waiting for the new key
+	StateConfirming       // asking before saving a new key or resetting
+	StateEditing          // editing a setting in a form
+	StateSelecting        // picking a repository to forget
+)
+
+// Section is a part of the settings view with its own cursor.
+type Section int
+
+const (
+	SectionAccounts Section = iota
+	SectionSSHAgent
+	SectionAI
+	SectionStorage
+	SectionKeybindings
+
+	sectionCount = iota
+)
+
+// Message types
+type (
+	// SavedMsg signals that the configuration was written after name was
+	// set to value.
+	SavedMsg struct {
+		Name   string
+		Value  string
+		DryRun bool // nothing was written
+	}
+
+	BackToMenuMsg struct{}
+)
+
+// Model represents the settings view state.
+type Model struct {
+	Config *config.Config
+
+	State   State
+	Section Section
+
+	// Accounts, nil until loaded
+	Accounts      []Account
+	AccountCursor int
+
+	// Report on ~/.gdev, nil until checked
+	Storage       *store.Report
+	StorageCursor int
+
+	Bindings   []config.Binding
+	Cursor     int
+	ListScroll int
+
+	// Key captured for the selected binding
+	Candidate string
+
+	// Dialog confirming a change, the change to make once confirmed and
+	// what to do instead if it is declined
+	Confirm   confirm.Model
+	OnConfirm func(m *Model) tea.Cmd
+	OnCancel  func(m *Model) tea.Cmd
+
+	// Selector of the repository to forget
+	Selector selector.Model
+
+	// Form editing a setting
+	Form form.Model
+
+	Notice string // confirmation of the last save
+
+	Width  int
+	Height int
+}
+
+// New creates a new settings model.
+func New(cfg *config.Config) Model {
+	return Model{
+		Config:   cfg,
+		State:    StateList,
+		Bindings: cfg.Keys().Bindings(),
+	}
+}
+
+// SetSize sets the dimensions for the view.
+func (m *Model) SetSize(width, height int) {
+	m.Width = width
+	m.Height = height
+}
+
+// Init implements tea.Model.
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(loadAccounts, m.checkStorage())
+}
+
+// visibleItems returns how many bindings fit on screen.
+func (m Model) visibleItems() int {
+	visible := m.Height - 21 - storageLines - max(len(m.Accounts), 1)
+	if visible < 1 {
+		visible = 1
+	}
+	return visible
+}
+
+// Update implements tea.Model.
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.Width = msg.Width
+		m.Height = msg.Height
+		return m, nil
+
+	case AccountsLoadedMsg:
+		m.Accounts = msg.Accounts
+		m.AccountCursor = min(m.AccountCursor, max(len(m.Accounts)-1, 0))
+		return m, nil
+
+	case StorageCheckedMsg:
+		m.Storage = &msg.Report
+		return m, nil
+
+	case LoginDoneMsg:
+		if msg.Err != nil {
+			m.Notice = "Login failed: " + msg.Err.Error()
+		}
+		return m, loadAccounts
+
+	case SavedMsg:
+		m.Notice = fmt.Sprintf("Saved %s = %s", msg.Name, msg.Value)
+		if msg.DryRun {
+			m.Notice = fmt.Sprintf("Dry run: would save %s = %s", msg.Name, msg.Value)
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		m.Notice = ""
+		switch m.State {
+		case StateList:
+			return m.updateList(msg)
+		case StateCapturing:
+			return m.capture(msg)
+		case StateConfirming:
+			return m.updateConfirm(msg)
+		case StateSelecting:
+			return m.updateForgetSelect(msg)
+		case StateEditing:
+			if m.Section == SectionAI {
+				return m.updateAIBackend(msg)
+			}
+			return m.updateSSHAgent(msg)
+		}
+	}
+	return m, nil
+}
+
+// updateList handles input for the section lists.
+func (m Model) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	kb := m.Config.Keys()
+
+	switch {
+	case config.MatchesAny(key, kb.Global.Quit, kb.Global.QuitAlt):
+		return m, func() tea.Msg { return BackToMenuMsg{} }
+	case config.Matches(key, kb.Form.NextField):
+		m.Section = (m.Section + 1) % sectionCount
+		return m, nil
+	case config.Matches(key, kb.Form.PrevField):
+		m.Section = (m.Section + sectionCount - 1) % sectionCount
+		return m, nil
+	}
+
+	switch m.Section {
+	case SectionAccounts:
+		return m.updateAccounts(msg)
+	case SectionSSHAgent:
+		if config.Matches(key, kb.List.Select) {
+			return m.openSSHAgent()
+		}
+		return m, nil
+	case SectionAI:
+		if config.Matches(key, kb.List.Select) {
+			return m.openAIBackend()
+		}
+		return m, nil
+	case SectionStorage:
+		return m.updateStorage(msg)
+	}
+	return m.updateBindings(msg)
+}
+
+// updateBindings handles input for the binding list.
+func (m Model) updateBindings(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	visibleItems := m.visibleItems()
+
+	key := msg.String()
+	kb := m.Config.Keys()
+
+	switch {
+	case config.MatchesAny(key, kb.Global.MoveUp, kb.Global.MoveUpAlt):
+		if m.Cursor > 0 {
+			m.Cursor--
+			if m.Cursor < m.ListScroll {
+				m.ListScroll = m.Cursor
+			}
+		}
+
+	case config.MatchesAny(key, kb.Global.MoveDown, kb.Global.MoveDownAlt):
+		if m.Cursor < len(m.Bindings)-1 {
+			m.Cursor++
+			if m.Cursor >= m.ListScroll+visibleItems {
+				m.ListScroll = m.Cursor - visibleItems + 1
+			}
+		}
+
+	case config.Matches(key, kb.List.Top):
+		m.Cursor = 0
+		m.ListScroll = 0
+
+	case config.Matches(key, kb.List.Bottom):
+		m.Cursor = len(m.Bindings) - 1
+		if m.Cursor >= visibleItems {
+			m.ListScroll = m.Cursor - visibleItems + 1
+		}
+
+	case config.Matches(key, kb.List.Select):
+		m.State = StateCapturing
+
+	case config.Matches(key, kb.Settings.ResetGroup):
+		group := m.Bindings[m.Cursor].Group
+		m.confirm(
+			confirm.New(m.Config, fmt.Sprintf("Reset %s keybindings?", group),
+				fmt.Sprintf("Every %s.* binding goes back to its default.", group)),
+			func(m *Model) tea.Cmd {
+				return m.reset(fmt.Sprintf("Reset %s keybindings", group), func() error {
+					return m.Config.ResetKeybindingGroup(group)
+				})
+			})
+
+	case config.Matches(key, kb.Settings.ResetAll):
+		dialog := confirm.New(m.Config, "Reset all keybindings?",
+			"Every binding goes back to its default, replacing keybindings.json.")
+		dialog.Destructive = true
+		m.confirm(dialog, func(m *Model) tea.Cmd {
+			return m.reset("Reset keybindings", m.Config.ResetKeybindings)
+		})
+	}
+	return m, nil
+}
+
+// confirm shows dialog and runs onConfirm if it is confirmed.
+func (m *Model) confirm(dialog confirm.Model, onConfirm func(m *Model) tea.Cmd) {
+	m.Confirm = dialog
+	m.OnConfirm = onConfirm
+	m.OnCancel = nil
+	m.State = StateConfirming
+}
+
+// reset runs a reset of the configuration, which also saves it, and reloads
+// the binding list.
+func (m *Model) reset(op string, fn func() error) tea.Cmd {
+	err := fn()
+	m.Bindings = m.Config.Keys().Bindings()
+	if err != nil {
+		return func() tea.Msg { return failure.Msg{Op: op, Err: err} }
+	}
+	m.Notice = op + " to defaults"
+	if m.Config.DryRun() {
+		m.Notice += " (dry run, not saved)"
+	}
+	return nil
+}
+
+// capture takes the next key press as the new key for the selected binding.
+// Every key is accepted, so the binding can be set to esc or enter; the
+// confirmation that follows can still be canceled.
+func (m Model) capture(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	if key == " " {
+		key = "space"
+	}
+	b := m.Bindings[m.Cursor]
+
+	if key == "ctrl+c" || key == b.Key {
+		m.State = StateList
+		return m, nil
+	}
+
+	m.Candidate = key
+	dialog := confirm.New(m.Config, fmt.Sprintf("Bind %s to %s?", b.Name(), key), "")
+	if conflicts := m.Config.Keys().Conflicts(b.Group, b.Action, key); len(conflicts) > 0 {
+		dialog.Message = fmt.Sprintf("%s is already bound to %s.", key, names(conflicts))
+		dialog.Destructive = true
+	}
+	m.confirm(dialog, (*Model).assign)
+	return m, nil
+}
+
+// assign sets the selected binding to the captured key and saves it.
+func (m *Model) assign() tea.Cmd {
+	b := m.Bindings[m.Cursor]
+	b.Key = m.Candidate
+	if err := m.Config.Keys().SetBinding(b.Group, b.Action, b.Key); err != nil {
+		return func() tea.Msg { return failure.Msg{Op: "Set keybinding", Err: err} }
+	}
+	m.Bindings = m.Config.Keys().Bindings()
+	return m.save("Save keybindings", b.Name(), b.Key)
+}
+
+// save writes the configuration after name was set to value.
+func (m *Model) save(op, name, value string) tea.Cmd {
+	cfg := m.Config
+	return failure.Cmd(op, func() (tea.Msg, error) {
+		if err := cfg.Save(); err != nil {
+			return nil, err
+		}
+		return SavedMsg{Name: name, Value: value, DryRun: cfg.DryRun()}, nil
+	})
+}
+
+// updateConfirm handles input for the confirmation dialog and makes the
+// change once confirmed.
+func (m Model) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var res confirm.Result
+	m.Confirm, res = m.Confirm.Update(msg)
+
+	switch res {
+	case confirm.Confirmed:
+		m.State = StateList
+		onConfirm := m.OnConfirm
+		m.OnConfirm, m.OnCancel = nil, nil
+		return m, onConfirm(&m)
+
+	case confirm.Canceled:
+		m.State = StateList
+		onCancel := m.OnCancel
+		m.OnConfirm, m.OnCancel = nil, nil
+		if onCancel != nil {
+			return m, onCancel(&m)
+		}
+	}
+	return m, nil
+}
+
+// View implements tea.Model.
+func (m Model) View() string {
+	if m.State == StateConfirming {
+		return m.Confirm.ViewCentered(m.Width, m.Height)
+	}
+	if m.State == StateEditing {
+		return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, m.Form.View())
+	}
+	if m.State == StateSelecting {
+		return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, m.Selector.View())
+	}
+
+	var b strings.Builder
+	kb := m.Config.Keys()
+
+	b.WriteString(styles.Title.Render("  Settings"))
+	b.WriteString("\n")
+	b.WriteString(styles.Help.Render("─────────────────────────────────────────"))
+	b.WriteString("\n\n")
+
+	b.WriteString(m.viewAccounts())
+	b.WriteString("\n")
+	b.WriteString(m.viewSSHAgent())
+	b.WriteString("\n")
+	b.WriteString(m.viewAIBackend())
+	b.WriteString("\n")
+	b.WriteString(m.viewStorage())
+	b.WriteString("\n")
+	b.WriteString(m.sectionTitle(SectionKeybindings, "Keybindings"))
+	b.WriteString("\n")
+
+	visibleItems := m.visibleItems()
+	if m.ListScroll > 0 {
+		b.WriteString(styles.Help.Render("  ↑ more above"))
+	}
+	b.WriteString("\n")
+
+	endIdx := min(m.ListScroll+visibleItems, len(m.Bindings))
+	for i := m.ListScroll; i < endIdx; i++ {
+		b.WriteString(m.renderBinding(i))
+		b.WriteString("\n")
+	}
+
+	if endIdx < len(m.Bindings) {
+		b.WriteString(styles.Help.Render("  ↓ more below"))
+	}
+	b.WriteString("\n\n")
+
+	switch {
+	case m.State == StateCapturing:
+		b.WriteString(styles.Status.Render(fmt.Sprintf("  Press the new key for %s", m.Bindings[m.Cursor].Name())))
+	case m.Notice != "":
+		b.WriteString(styles.Status.Render("  " + m.Notice))
+	}
+	b.WriteString("\n")
+
+	b.WriteString(styles.Help.Render(fmt.Sprintf("↑/%s ↓/%s navigate • %s/%s switch section • %s back",
+		kb.Global.MoveUp, kb.Global.MoveDown, kb.Form.NextField, kb.Form.PrevField, kb.Global.Quit)))
+	b.WriteString("\n")
+	switch m.Section {
+	case SectionAccounts:
+		b.WriteString(styles.Help.Render(fmt.Sprintf("%s log in", kb.List.Select)))
+	case SectionSSHAgent, SectionAI:
+		b.WriteString(styles.Help.Render(fmt.Sprintf("%s change", kb.List.Select)))
+	case SectionStorage:
+		b.WriteString(styles.Help.Render(fmt.Sprintf("%s %s", kb.List.Select, storageActions[m.StorageCursor])))
+	default:
+		b.WriteString(styles.Help.Render(fmt.Sprintf("%s/%s top/bottom • %s change key • %s reset group • %s reset all",
+			kb.List.Top, kb.List.Bottom, kb.List.Select, kb.Settings.ResetGroup, kb.Settings.ResetAll)))
+	}
+
+	return b.String()
+}
+
+// renderBinding renders the binding at index i, flagging keys that clash
+// with another binding matched in the same view.
+func (m Model) renderBinding(i int) string {
+	b := m.Bindings[i]
+
+	key := b.Key
+	if i == m.Cursor && m.State == StateCapturing {
+		key = "…"
+	}
+	line := fmt.Sprintf("%-10s %-16s %s", b.Group, b.Action, key)
+
+	var warning string
+	if conflicts := m.Config.Keys().Conflicts(b.Group, b.Action, b.Key); len(conflicts) > 0 {
+		warning = styles.Error.Render("  ⚠ also " + names(conflicts))
+	}
+
+	if m.Section == SectionKeybindings && i == m.Cursor {
+		return styles.Selected.Render(styles.Cursor.Render("▸ ")+line) + warning
+	}
+	return styles.Item.Render("  "+line) + warning
+}
+
+// sectionTitle renders the heading of a section, highlighted when active.
+func (m Model) sectionTitle(s Section, title string) string {
+	if m.Section == s {
+		return styles.Label.Render("  " + title)
+	}
+	return styles.Help.Render("  " + title)
+}
+
+// names joins the names of bindings for display.
+func names(bindings []config.Binding) string {
+	names := make([]string, len(bindings))
+	for i, b := range bindings {
+		names[i] = b.Name()
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,75 @@
+package settings
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/sshagent"
+	"github.com/ihatemodels/gdev/internal/ui/form"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+)
+
+// openSSHAgent opens the form for the ssh-agent strategy.
+func (m Model) openSSHAgent() (tea.Model, tea.Cmd) {
+	st := m.Config.Settings
+	options := make([]string, len(sshagent.Strategies))
+	for i, s := range sshagent.Strategies {
+		options[i] = string(s)
+	}
+
+	m.Form = form.New(m.Config, "SSH Agent",
+		form.Select("strategy", "Strategy", options, string(st.SSHAgent)),
+		form.Text("command", "Command (prints the socket path)", st.SSHAgentCommand),
+	)
+	m.State = StateEditing
+	return m, nil
+}
+
+// updateSSHAgent handles input for the ssh-agent form and saves it once submitted.
+func (m Model) updateSSHAgent(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var res form.Result
+	m.Form, res = m.Form.Update(msg)
+
+	switch res {
+	case form.Submitted:
+		strategy := sshagent.Strategy(m.Form.Value("strategy"))
+		command := m.Form.Value("command")
+		if strategy == sshagent.Command && command == "" {
+			m.Form.Field("command").Err = "required by the command strategy"
+			m.Form.Focus = 1
+			return m, nil
+		}
+
+		m.Config.Settings.SSHAgent = strategy
+		m.Config.Settings.SSHAgentCommand = command
+		m.State = StateList
+		return m, m.save("Save settings", "ssh_agent", string(strategy))
+
+	case form.Canceled:
+		m.State = StateList
+	}
+	return m, nil
+}
+
+// viewSSHAgent renders the ssh-agent section.
+func (m Model) viewSSHAgent() string {
+	var b strings.Builder
+	st := m.Config.Settings
+
+	b.WriteString(m.sectionTitle(SectionSSHAgent, "SSH Agent"))
+	b.WriteString("\n")
+
+	value := st.SSHAgent.Describe()
+	if st.SSHAgent == sshagent.Command {
+		value += ": " + st.SSHAgentCommand
+	}
+	line := fmt.Sprintf("%-18s", "Strategy")
+	if m.Section == SectionSSHAgent {
+		b.WriteString(styles.Selected.Render(styles.Cursor.Render("▸ ")+line) + styles.Value.Render(value))
+	} else {
+		b.WriteString(styles.Item.Render("  "+line) + styles.Value.Render(value))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
@@ -0,0 +1,204 @@
+package settings
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/store"
+	"github.com/ihatemodels/gdev/internal/ui/confirm"
+	"github.com/ihatemodels/gdev/internal/ui/failure"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+	"github.com/ihatemodels/gdev/internal/ui/terminal"
+)
+
+// Rows of the storage section, in display order.
+const (
+	storageUsage = iota
+	storageUnreadable
+	storageOrphaned
+	storageRetention
+	storageRepos
+
+	storageRows = iota
+)
+
+// storageActions describe what selecting each row does.
+var storageActions = [storageRows]string{
+	storageUsage:      "check again",
+	storageUnreadable: "delete the unreadable files",
+	storageOrphaned:   "delete the orphaned files",
+	storageRetention:  "apply the retention limits",
+	storageRepos:      "forget a repository",
+}
+
+// storageLines is how many lines the storage section takes.
+const storageLines = storageRows + 2
+
+// StorageCheckedMsg carries a report on the contents of ~/.gdev.
+type StorageCheckedMsg struct {
+	Report store.Report
+}
+
+// checkStorage measures ~/.gdev and looks for problems in it.
+func (m Model) checkStorage() tea.Cmd {
+	cfg := m.Config
+	return failure.Cmd("Check storage", func() (tea.Msg, error) {
+		r, err := cfg.CheckStore()
+		if err != nil {
+			return nil, err
+		}
+		return StorageCheckedMsg{Report: r}, nil
+	})
+}
+
+// updateStorage handles input for the storage section. Select runs the
+// cleanup of the selected row, after confirming it.
+func (m Model) updateStorage(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	kb := m.Config.Keys()
+
+	switch {
+	case config.MatchesAny(key, kb.Global.MoveUp, kb.Global.MoveUpAlt):
+		if m.StorageCursor > 0 {
+			m.StorageCursor--
+		}
+	case config.MatchesAny(key, kb.Global.MoveDown, kb.Global.MoveDownAlt):
+		if m.StorageCursor < storageRows-1 {
+			m.StorageCursor++
+		}
+	case config.Matches(key, kb.List.Select):
+		if m.Storage == nil {
+			return m, nil
+		}
+		switch m.StorageCursor {
+		case storageUsage:
+			m.Storage = nil
+			return m, m.checkStorage()
+		case storageUnreadable:
+			m.confirmRemove("unreadable", m.Storage.Unreadable)
+		case storageOrphaned:
+			m.confirmRemove("orphaned", m.Storage.Orphaned)
+		case storageRetention:
+			dialog := confirm.New(m.Config, "Apply the retention limits now?",
+				"Logs and caches beyond the limits in settings.json are deleted, as on every start.")
+			m.confirm(dialog, (*Model).applyRetention)
+		case storageRepos:
+			return m.openForget()
+		}
+	}
+	return m, nil
+}
+
+// confirmRemove asks before deleting the files of problems.
+func (m *Model) confirmRemove(kind string, problems []store.Problem) {
+	if len(problems) == 0 {
+		m.Notice = fmt.Sprintf("No %s files", kind)
+		return
+	}
+
+	var names []string
+	for i, p := range problems {
+		if i == 5 {
+			names = append(names, fmt.Sprintf("and %d more", len(problems)-i))
+			break
+		}
+		names = append(names, filepath.Base(filepath.Dir(p.Path))+"/"+filepath.Base(p.Path))
+	}
+	dialog := confirm.New(m.Config, fmt.Sprintf("Delete %d %s files?", len(problems), kind), strings.Join(names, ", "))
+	dialog.Destructive = true
+	m.confirm(dialog, func(m *Model) tea.Cmd {
+		paths := make([]string, len(problems))
+		for i, p := range problems {
+			paths[i] = p.Path
+		}
+		if err := m.Config.RemoveStoreFiles(paths...); err != nil {
+			return func() tea.Msg { return failure.Msg{Op: "Delete " + kind + " files", Err: err} }
+		}
+		m.Notice = fmt.Sprintf("Deleted %d %s files", len(paths), kind)
+		if m.Config.DryRun() {
+			m.Notice = fmt.Sprintf("Dry run: would delete %d %s files", len(paths), kind)
+		}
+		return m.checkStorage()
+	})
+}
+
+// applyRetention deletes the logs and caches beyond the retention settings.
+func (m *Model) applyRetention() tea.Cmd {
+	jobs, _ := m.Config.DetachedJobs()
+	reclaimed, err := m.Config.Cleanup(terminal.LogInUse(jobs))
+	if err != nil {
+		return func() tea.Msg { return failure.Msg{Op: "Apply retention limits", Err: err} }
+	}
+
+	var files int
+	var bytes int64
+	for _, r := range reclaimed {
+		files += r.Files
+		bytes += r.Bytes
+	}
+	m.Notice = fmt.Sprintf("Deleted %s, reclaiming %s", fileCount(files), config.FormatSize(bytes))
+	if m.Config.DryRun() {
+		m.Notice = fmt.Sprintf("Dry run: would delete %s, reclaiming %s", fileCount(files), config.FormatSize(bytes))
+	}
+	return m.checkStorage()
+}
+
+// viewStorage renders the storage section.
+func (m Model) viewStorage() string {
+	var b strings.Builder
+
+	b.WriteString(m.sectionTitle(SectionStorage, "Storage"))
+	b.WriteString("\n")
+
+	r := m.Storage
+	if r == nil {
+		b.WriteString(styles.Help.Render("  Checking..."))
+		b.WriteString(strings.Repeat("\n", storageLines-1))
+		return b.String()
+	}
+
+	files, bytes := r.Total()
+	rows := [storageRows]struct{ name, value string }{
+		storageUsage:      {"Usage", styles.Value.Render(fmt.Sprintf("%s in %s", config.FormatSize(bytes), fileCount(files)))},
+		storageUnreadable: {"Integrity", problemStatus(r.Unreadable, "all files readable", "unreadable")},
+		storageOrphaned:   {"Orphaned", problemStatus(r.Orphaned, "none", "for repositories that no longer exist")},
+		storageRetention:  {"Retention", styles.Value.Render("apply the limits now")},
+		storageRepos:      {"Repositories", styles.Value.Render("forget one and its data")},
+	}
+	for i, row := range rows {
+		line := fmt.Sprintf("%-18s", row.name)
+		if m.Section == SectionStorage && i == m.StorageCursor {
+			b.WriteString(styles.Selected.Render(styles.Cursor.Render("▸ ")+line) + row.value)
+		} else {
+			b.WriteString(styles.Item.Render("  "+line) + row.value)
+		}
+		b.WriteString("\n")
+	}
+
+	var usage []string
+	for _, u := range r.Usage {
+		usage = append(usage, fmt.Sprintf("%s %s", u.Category, config.FormatSize(u.Bytes)))
+	}
+	b.WriteString(styles.Help.Render("  " + strings.Join(usage, " • ")))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// problemStatus renders a count of problem files, or ok if there are none.
+func problemStatus(problems []store.Problem, ok, kind string) string {
+	if len(problems) == 0 {
+		return styles.Selected.Render("✓ ") + styles.Value.Render(ok)
+	}
+	return styles.Error.Render(fmt.Sprintf("✗ %s %s", fileCount(len(problems)), kind))
+}
+
+// fileCount formats a number of files, e.g. "2 files".
+func fileCount(n int) string {
+	if n == 1 {
+		return "1 file"
+	}
+	return fmt.Sprintf("%d files", n)
+}
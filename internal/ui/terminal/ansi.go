@@ -0,0 +1,160 @@
+package terminal
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+// ansiEscape matches the escape sequences in output lines: CSI sequences,
+// such as colors and cursor movement, and OSC ones, such as hyperlinks.
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;:?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)`)
+
+// sgr is the text style set by SGR ("Select Graphic Rendition") sequences.
+type sgr struct {
+	bold, faint, italic, underline, reverse, strike bool
+	fg, bg                                          string // "" for the default color
+}
+
+// style returns the lipgloss style rendering text like s.
+func (s sgr) style() lipgloss.Style {
+	style := lipgloss.NewStyle().
+		Bold(s.bold).
+		Faint(s.faint).
+		Italic(s.italic).
+		Underline(s.underline).
+		Reverse(s.reverse).
+		Strikethrough(s.strike)
+	if s.fg != "" {
+		style = style.Foreground(lipgloss.Color(s.fg))
+	}
+	if s.bg != "" {
+		style = style.Background(lipgloss.Color(s.bg))
+	}
+	return style
+}
+
+// apply updates s with the parameters of an SGR sequence, e.g. "1;31".
+// Unsupported parameters are ignored.
+func (s sgr) apply(params string) sgr {
+	var codes []int
+	for _, p := range strings.FieldsFunc(params, func(r rune) bool { return r == ';' || r == ':' }) {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return s
+		}
+		codes = append(codes, n)
+	}
+	if len(codes) == 0 {
+		return sgr{}
+	}
+
+	for i := 0; i < len(codes); i++ {
+		switch c := codes[i]; {
+		case c == 0:
+			s = sgr{}
+		case c == 1:
+			s.bold = true
+		case c == 2:
+			s.faint = true
+		case c == 3:
+			s.italic = true
+		case c == 4:
+			s.underline = true
+		case c == 7:
+			s.reverse = true
+		case c == 9:
+			s.strike = true
+		case c == 22:
+			s.bold, s.faint = false, false
+		case c == 23:
+			s.italic = false
+		case c == 24:
+			s.underline = false
+		case c == 27:
+			s.reverse = false
+		case c == 29:
+			s.strike = false
+		case c >= 30 && c <= 37:
+			s.fg = strconv.Itoa(c - 30)
+		case c >= 90 && c <= 97:
+			s.fg = strconv.Itoa(c - 90 + 8)
+		case c == 39:
+			s.fg = ""
+		case c >= 40 && c <= 47:
+			s.bg = strconv.Itoa(c - 40)
+		case c >= 100 && c <= 107:
+			s.bg = strconv.Itoa(c - 100 + 8)
+		case c == 49:
+			s.bg = ""
+		case c == 38 || c == 48:
+			color, n := extendedColor(codes[i+1:])
+			if c == 38 {
+				s.fg = color
+			} else {
+				s.bg = color
+			}
+			i += n
+		}
+	}
+	return s
+}
+
+// extendedColor reads a 256-color ("5;n") or true color ("2;r;g;b") from
+// the codes after 38 or 48. It returns the color, "" if there is none, and
+// how many codes it took.
+func extendedColor(codes []int) (string, int) {
+	switch {
+	case len(codes) >= 2 && codes[0] == 5:
+		return strconv.Itoa(codes[1]), 2
+	case len(codes) >= 4 && codes[0] == 2:
+		return fmt.Sprintf("#%02x%02x%02x", codes[1]&0xff, codes[2]&0xff, codes[3]&0xff), 4
+	}
+	return "", len(codes)
+}
+
+// renderANSI renders a line of command output with the colors and styles of
+// its SGR sequences, cut to width columns. Other sequences, such as
+// cursor movement, are dropped, and a line redrawn with carriage returns,
+// like a progress bar, shows what was drawn last.
+func renderANSI(line string, width int) string {
+	if i := strings.LastIndex(strings.TrimRight(line, "\r"), "\r"); i >= 0 {
+		line = line[i+1:]
+	}
+
+	var b strings.Builder
+	var style sgr
+	left := width
+	write := func(text string) {
+		if w := runewidth.StringWidth(text); w > left {
+			text = truncate(text, left)
+			left = 0
+		} else {
+			left -= w
+		}
+		if text != "" {
+			b.WriteString(style.style().Render(text))
+		}
+	}
+
+	pos := 0
+	for _, match := range ansiEscape.FindAllStringIndex(line, -1) {
+		if left <= 0 {
+			break
+		}
+		write(line[pos:match[0]])
+		seq := line[match[0]:match[1]]
+		if strings.HasPrefix(seq, "\x1b[") && strings.HasSuffix(seq, "m") {
+			style = style.apply(seq[2 : len(seq)-1])
+		}
+		pos = match[1]
+	}
+	if left > 0 {
+		write(line[pos:])
+	}
+	return b.String()
+}
@@ -0,0 +1,55 @@
+package terminal
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ihatemodels/gdev/internal/ai"
+)
+
+func TestRunCachedFunc_SkipsFallbackResponses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/generate":
+			http.Error(w, "model not loaded", http.StatusServiceUnavailable)
+		case "/chat/completions":
+			fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"feat: add openai\"}}]}\n\ndata: [DONE]\n\n")
+		}
+	}))
+	defer srv.Close()
+	t.Setenv("OPENAI_BASE_URL", srv.URL)
+	t.Setenv("OPENAI_API_KEY", "key")
+	t.Setenv("OLLAMA_HOST", strings.TrimPrefix(srv.URL, "http://"))
+
+	m := newTestModel(t)
+	generate := func(b ai.Backend) []string {
+		t.Helper()
+		cmd := m.RunCachedFunc(b.CacheKey("prompt"), false, ai.Describe(b), func(ctx context.Context, w io.Writer) error {
+			return b.Generate(ctx, "prompt", w)
+		})
+		return runToEnd(t, m, cmd)
+	}
+
+	b, err := ai.NewWithFallback("ollama", "", "openai", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := generate(b); !hasLine(lines, "feat: add openai") {
+		t.Fatalf("output %q lacks the fallback's response", lines)
+	}
+	if _, ok := m.Config.CachedResponse(b.CacheKey("prompt")); ok {
+		t.Error("the fallback's response was cached under the primary's key")
+	}
+
+	// The backend that answered itself is cached as before
+	openai, _ := ai.New("openai", "")
+	generate(openai)
+	if _, ok := m.Config.CachedResponse(openai.CacheKey("prompt")); !ok {
+		t.Error("the response of a backend that didn't fall back wasn't cached")
+	}
+}
@@ -0,0 +1,82 @@
+package terminal
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/store"
+)
+
+// newTestModel returns a terminal with its config in a temporary home.
+func newTestModel(t *testing.T) Model {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	s, err := store.New()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	cfg, err := config.Load(s)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	return New(cfg, "test")
+}
+
+// runToEnd feeds the terminal its ticks until the command started by cmd
+// finishes, and returns its output.
+func runToEnd(t *testing.T, m Model, cmd tea.Cmd) []string {
+	t.Helper()
+	for m.Running && cmd != nil {
+		m, cmd = m.Update(cmd())
+	}
+	if m.Err != nil {
+		t.Fatalf("command failed: %v", m.Err)
+	}
+	return m.Lines
+}
+
+// hasLine reports whether lines has one that is want, ignoring styles.
+func hasLine(lines []string, want string) bool {
+	return slices.ContainsFunc(lines, func(l string) bool {
+		return strings.TrimSpace(ansiEscape.ReplaceAllString(l, "")) == want
+	})
+}
+
+func TestRunCommandWithEnv_KeepsEnvironment(t *testing.T) {
+	m := newTestModel(t)
+	t.Setenv("GDEV_TEST_INHERITED", "inherited")
+
+	cmd := m.RunCommandWithEnv([]string{"GDEV_TEST_ADDED=added"}, "sh", "-c",
+		`echo "$GDEV_TEST_INHERITED $GDEV_TEST_ADDED"; echo "path:$PATH"`)
+	lines := runToEnd(t, m, cmd)
+
+	if !hasLine(lines, "inherited added") {
+		t.Errorf("output %q lacks the inherited and added variables", lines)
+	}
+	if !hasLine(lines, "path:"+os.Getenv("PATH")) {
+		t.Errorf("output %q lacks PATH", lines)
+	}
+}
+
+func TestSetEnv(t *testing.T) {
+	m := newTestModel(t)
+	t.Setenv("GDEV_TEST_SET", "inherited")
+
+	m.SetEnv("GDEV_TEST_SET", "first")
+	m.SetEnv("GDEV_TEST_SET", "second")
+	m.SetEnv("GDEV_TEST_OTHER", "other")
+	if len(m.env) != 2 {
+		t.Errorf("env = %q, want one entry per variable", m.env)
+	}
+
+	// Variables set on the terminal win over those passed to the command
+	cmd := m.RunCommandWithEnv([]string{"GDEV_TEST_OTHER=passed"}, "sh", "-c", `echo "$GDEV_TEST_SET $GDEV_TEST_OTHER"`)
+	lines := runToEnd(t, m, cmd)
+	if !hasLine(lines, "second other") {
+		t.Errorf("output %q, want the variables set last", lines)
+	}
+}
@@ -0,0 +1,100 @@
+package terminal
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+)
+
+// highlightRule is a compiled config.Highlight.
+type highlightRule struct {
+	re    *regexp.Regexp
+	style lipgloss.Style
+}
+
+// highlightColors are the colors highlight styles can name.
+var highlightColors = map[string]lipgloss.Color{
+	"green":  styles.Green,
+	"red":    styles.Red,
+	"yellow": styles.Yellow,
+	"cyan":   styles.Cyan,
+	"pink":   styles.Pink,
+	"purple": styles.Purple,
+	"subtle": styles.Subtle,
+}
+
+// compileHighlights compiles the highlight rules in settings. Rules with an
+// invalid pattern are left out.
+func compileHighlights(highlights []config.Highlight) []highlightRule {
+	var rules []highlightRule
+	for _, h := range highlights {
+		re, err := regexp.Compile(h.Pattern)
+		if err != nil || h.Pattern == "" {
+			continue
+		}
+		rules = append(rules, highlightRule{re: re, style: highlightStyle(h.Style)})
+	}
+	return rules
+}
+
+// highlightStyle parses a style like "red bold". Unknown words are ignored.
+func highlightStyle(spec string) lipgloss.Style {
+	style := lipgloss.NewStyle()
+	for _, word := range strings.Fields(strings.ToLower(spec)) {
+		switch word {
+		case "bold":
+			style = style.Bold(true)
+		case "italic":
+			style = style.Italic(true)
+		case "underline":
+			style = style.Underline(true)
+		default:
+			if c, ok := highlightColors[word]; ok {
+				style = style.Foreground(c)
+			}
+		}
+	}
+	return style
+}
+
+// highlight styles the parts of line matching the rules. Each byte takes the
+// style of the first rule matching it.
+func highlight(line string, rules []highlightRule) string {
+	if len(rules) == 0 || line == "" {
+		return line
+	}
+
+	owner := make([]int, len(line)) // rule index + 1, 0 for none
+	found := false
+	for i, r := range rules {
+		for _, match := range r.re.FindAllStringIndex(line, -1) {
+			for j := match[0]; j < match[1]; j++ {
+				if owner[j] == 0 {
+					owner[j] = i + 1
+					found = true
+				}
+			}
+		}
+	}
+	if !found {
+		return line
+	}
+
+	var b strings.Builder
+	for start := 0; start < len(line); {
+		end := start + 1
+		for end < len(line) && owner[end] == owner[start] {
+			end++
+		}
+		if owner[start] == 0 {
+			b.WriteString(line[start:end])
+		} else {
+			b.WriteString(rules[owner[start]-1].style.Render(line[start:end]))
+		}
+		start = end
+	}
+	return b.String()
+}
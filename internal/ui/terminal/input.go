@@ -0,0 +1,45 @@
+package terminal
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// keySequences are what a terminal sends for keys that aren't text.
+var keySequences = map[tea.KeyType]string{
+	tea.KeyEnter:     "\r",
+	tea.KeyTab:       "\t",
+	tea.KeySpace:     " ",
+	tea.KeyBackspace: "\x7f",
+	tea.KeyDelete:    "\x1b[3~",
+	tea.KeyUp:        "\x1b[A",
+	tea.KeyDown:      "\x1b[B",
+	tea.KeyRight:     "\x1b[C",
+	tea.KeyLeft:      "\x1b[D",
+	tea.KeyHome:      "\x1b[H",
+	tea.KeyEnd:       "\x1b[F",
+	tea.KeyCtrlD:     "\x04",
+	tea.KeyCtrlU:     "\x15",
+	tea.KeyCtrlW:     "\x17",
+}
+
+// handleInputKey types a key into the command's terminal. Esc stops typing.
+func (m Model) handleInputKey(msg tea.KeyMsg) (Model, tea.Cmd) {
+	if msg.Type == tea.KeyEsc {
+		m.Typing = false
+		return m, nil
+	}
+
+	text, ok := keySequences[msg.Type]
+	if msg.Type == tea.KeyRunes {
+		text, ok = string(msg.Runes), true
+	}
+	if !ok {
+		return m, nil
+	}
+	if err := m.output.sendInput(text); err != nil {
+		m.Typing = false
+		m.notice = "Typing failed: " + err.Error()
+	}
+	m.AutoScroll = true
+	return m, nil
+}
@@ -0,0 +1,125 @@
+package terminal
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ihatemodels/gdev/internal/store"
+)
+
+// Job is a command running in a terminal.
+type Job struct {
+	ID      int // of the terminal
+	Title   string
+	Command string
+	Dir     string
+	Log     string // file the output is written to
+	Pid     int
+	Started time.Time
+
+	pty *os.File // master side of its terminal, nil without one
+}
+
+// jobs holds the commands running in all terminals, by terminal ID.
+var jobs = struct {
+	sync.Mutex
+	running map[int]Job
+}{running: make(map[int]Job)}
+
+func addJob(j Job) {
+	jobs.Lock()
+	defer jobs.Unlock()
+	jobs.running[j.ID] = j
+}
+
+func removeJob(id int) {
+	jobs.Lock()
+	defer jobs.Unlock()
+	delete(jobs.running, id)
+}
+
+// Jobs returns the commands running in all terminals, oldest first,
+// including those of terminals no longer shown.
+func Jobs() []Job {
+	jobs.Lock()
+	defer jobs.Unlock()
+	list := make([]Job, 0, len(jobs.running))
+	for _, j := range jobs.running {
+		list = append(list, j)
+	}
+	sort.Slice(list, func(i, k int) bool {
+		return list[i].Started.Before(list[k].Started)
+	})
+	return list
+}
+
+// LogInUse returns a function reporting whether path is the log of a command
+// running in a terminal or of one of the detached jobs still running, which
+// must not be deleted.
+func LogInUse(detached []store.Job) func(path string) bool {
+	return func(path string) bool {
+		for _, j := range Jobs() {
+			if j.Log == path {
+				return true
+			}
+		}
+		for _, j := range detached {
+			if j.Log == path && ProcessAlive(j.Pid) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// RelayCommand is the hidden subcommand gdev runs itself with to keep
+// copying the output of a detached job to its log, see Detach.
+const RelayCommand = "__relay"
+
+// Detach hands the terminals of the running commands to relays, processes
+// that copy their output to the logs once gdev has exited, so gdev can exit
+// leaving them running. Best effort: a command whose relay doesn't start
+// gets a hangup when gdev exits.
+func Detach() {
+	exe, err := os.Executable()
+	if err != nil {
+		return
+	}
+	for _, j := range Jobs() {
+		if j.pty == nil {
+			continue
+		}
+		// Stop copying in gdev, so the output goes to the log once
+		_ = j.pty.SetReadDeadline(time.Now())
+		cmd := exec.Command(exe, RelayCommand, j.Log)
+		cmd.ExtraFiles = []*os.File{j.pty}
+		detachProcess(cmd)
+		if cmd.Start() == nil {
+			cmd.Process.Release()
+		}
+	}
+}
+
+// Relay copies the output of the terminal gdev passed as file descriptor 3
+// to the end of log until the command running in it exits.
+func Relay(log string) error {
+	pty := os.NewFile(3, "pty")
+	defer pty.Close()
+	f, err := os.OpenFile(log, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Reading a terminal whose command has exited fails with EIO
+	if _, err := io.Copy(f, pty); err != nil && !errors.Is(err, syscall.EIO) {
+		return err
+	}
+	return nil
+}
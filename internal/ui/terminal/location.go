@@ -0,0 +1,194 @@
+package terminal
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+)
+
+// locationPattern matches file:line and file:line:column, as in compiler
+// errors, test failures and stack traces.
+var locationPattern = regexp.MustCompile(`(?:^|[\s(\[])((?:~|\.{1,2})?[\w./-]*\w\.\w+):(\d+)(?::(\d+))?`)
+
+// location is a file:line in the output that exists on disk.
+type location struct {
+	line       int // index in Lines
+	start, end int // bytes of the line without colors
+	path       string
+	row, col   int
+	dir        string // the command's directory, for showing path
+}
+
+// String returns the location as it was in the output, but with the path
+// relative to the command's directory where it can be.
+func (l location) String() string {
+	path := l.path
+	if rel, err := filepath.Rel(l.dir, path); err == nil && l.dir != "" && !strings.HasPrefix(rel, "..") {
+		path = rel
+	}
+	if l.col > 0 {
+		return fmt.Sprintf("%s:%d:%d", path, l.row, l.col)
+	}
+	return fmt.Sprintf("%s:%d", path, l.row)
+}
+
+// locations finds the file:line locations in the output, in order. Relative
+// paths are taken from the command's directory; locations of files that
+// don't exist there are left out, so host:port and the like don't count.
+func (m Model) locations() []location {
+	var locs []location
+	exists := make(map[string]bool)
+	for i, line := range m.Lines {
+		plain := ansiEscape.ReplaceAllString(line, "")
+		for _, match := range locationPattern.FindAllStringSubmatchIndex(plain, -1) {
+			path := m.resolvePath(plain[match[2]:match[3]])
+			ok, seen := exists[path]
+			if !seen {
+				info, err := os.Stat(path)
+				ok = err == nil && !info.IsDir()
+				exists[path] = ok
+			}
+			if !ok {
+				continue
+			}
+			loc := location{line: i, start: match[2], end: match[1], path: path, dir: m.Dir}
+			loc.row, _ = strconv.Atoi(plain[match[4]:match[5]])
+			if match[6] >= 0 {
+				loc.col, _ = strconv.Atoi(plain[match[6]:match[7]])
+			}
+			locs = append(locs, loc)
+		}
+	}
+	return locs
+}
+
+// resolvePath returns the file a path in the output refers to.
+func (m Model) resolvePath(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	if filepath.IsAbs(path) || m.Dir == "" {
+		return path
+	}
+	return filepath.Join(m.Dir, path)
+}
+
+// jumpLocation selects the next location after the selected one, or the
+// previous with delta -1, scrolling it into view. Without a selection it
+// starts from the first location, or the last going back.
+func (m Model) jumpLocation(delta int) Model {
+	locs := m.locations()
+	if len(locs) == 0 {
+		m.notice = styles.Help.Render("No file:line locations in the output")
+		return m
+	}
+
+	next := -1
+	if m.jumping {
+		for i, l := range locs {
+			if l.line == m.jump.line && l.start == m.jump.start {
+				next = (i + delta + len(locs)) % len(locs)
+			}
+		}
+	}
+	if next < 0 {
+		next = 0
+		if delta < 0 {
+			next = len(locs) - 1
+		}
+	}
+
+	m.jump = locs[next]
+	m.jumping = true
+	m.AutoScroll = false
+	if m.jump.line < m.ScrollPos || m.jump.line >= m.ScrollPos+m.visibleLines() {
+		m.ScrollPos = min(max(m.jump.line-m.visibleLines()/2, 0), m.maxScroll())
+	}
+	return m
+}
+
+// openLocation opens the selected location in $VISUAL or $EDITOR, which
+// takes over the real terminal until it exits.
+func (m Model) openLocation() (Model, tea.Cmd) {
+	cmd, err := Edit(m.Dir, m.jump.path, m.jump.row, m.jump.col)
+	if err != nil {
+		m.notice = styles.Error.Render("✗ " + err.Error())
+		return m, nil
+	}
+	// The output is still on screen when the editor exits
+	return m, cmd
+}
+
+// Edit returns a command opening path, relative to dir, at row and col
+// in $VISUAL or $EDITOR, or vi. It fails if the editor isn't installed.
+func Edit(dir, path string, row, col int) (tea.Cmd, error) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	fields := strings.Fields(editor)
+	if _, err := exec.LookPath(fields[0]); err != nil {
+		return nil, err
+	}
+
+	args := append(fields[1:], editorArgs(fields[0], path, row, col)...)
+	c := exec.Command(fields[0], args...)
+	c.Dir = dir
+	return tea.ExecProcess(c, func(error) tea.Msg { return nil }), nil
+}
+
+// editorArgs returns the arguments opening path at row and col in editor.
+// Editors that aren't known get "+row path", which most terminal editors
+// understand.
+func editorArgs(editor, path string, row, col int) []string {
+	pos := fmt.Sprintf("%s:%d", path, row)
+	if col > 0 {
+		pos += fmt.Sprintf(":%d", col)
+	}
+	switch filepath.Base(editor) {
+	case "code", "code-insiders", "codium", "cursor":
+		return []string{"--goto", pos}
+	case "subl", "zed", "hx", "helix":
+		return []string{pos}
+	case "idea", "goland", "pycharm", "webstorm":
+		return []string{"--line", strconv.Itoa(row), path}
+	}
+	return []string{fmt.Sprintf("+%d", row), path}
+}
+
+// markLocation renders line without its colors and with the selected
+// location highlighted, cut to width.
+func (m Model) markLocation(line string, width int) string {
+	plain := truncate(ansiEscape.ReplaceAllString(line, ""), width)
+	start, end := min(m.jump.start, len(plain)), min(m.jump.end, len(plain))
+	return plain[:start] + selectedLine.Render(plain[start:end]) + plain[end:]
+}
+
+// handleLocationKey handles the keys selecting and opening locations. It
+// reports whether the key was one of them.
+func (m Model) handleLocationKey(key string) (Model, tea.Cmd, bool) {
+	kb := m.Config.Keys()
+	switch {
+	case config.Matches(key, kb.Terminal.NextLocation):
+		return m.jumpLocation(1), nil, true
+	case config.Matches(key, kb.Terminal.PrevLocation):
+		return m.jumpLocation(-1), nil, true
+	case m.jumping && config.Matches(key, kb.Terminal.Open):
+		m, cmd := m.openLocation()
+		return m, cmd, true
+	}
+	return m, nil, false
+}
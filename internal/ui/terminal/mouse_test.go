@@ -0,0 +1,39 @@
+package terminal
+
+import (
+	"fmt"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMouseWheel(t *testing.T) {
+	m := newTestModel(t)
+	m.SetSize(80, 20)
+	lines := make([]string, 100)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i)
+	}
+	m.Print(lines...)
+
+	wheel := func(button tea.MouseButton) {
+		m, _ = m.Update(tea.MouseMsg{Button: button, Action: tea.MouseActionPress})
+	}
+
+	wheel(tea.MouseButtonWheelDown)
+	if m.ScrollPos != wheelLines {
+		t.Errorf("ScrollPos after wheel down = %d, want %d", m.ScrollPos, wheelLines)
+	}
+	wheel(tea.MouseButtonWheelUp)
+	wheel(tea.MouseButtonWheelUp)
+	if m.ScrollPos != 0 || m.AutoScroll {
+		t.Errorf("ScrollPos = %d, AutoScroll = %v after wheel up past the top; want 0, false", m.ScrollPos, m.AutoScroll)
+	}
+
+	for range 100 {
+		wheel(tea.MouseButtonWheelDown)
+	}
+	if m.ScrollPos != m.maxScroll() || !m.AutoScroll {
+		t.Errorf("ScrollPos = %d, AutoScroll = %v after wheel down past the end; want %d, true", m.ScrollPos, m.AutoScroll, m.maxScroll())
+	}
+}
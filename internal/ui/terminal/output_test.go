@@ -0,0 +1,60 @@
+package terminal
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSharedOutput_Limit(t *testing.T) {
+	s := &sharedOutput{limit: 100}
+	for i := range 1000 {
+		s.addLine(fmt.Sprintf("line %03d", i)) // 9 bytes with the newline
+	}
+
+	lines := s.getLines()
+	if len(lines) != 11 || lines[len(lines)-1] != "line 999" {
+		t.Fatalf("kept %q; want the last 11 lines", lines)
+	}
+	if got := s.trimmedLines(); got != 989 {
+		t.Errorf("trimmedLines() = %d, want 989", got)
+	}
+	if len(s.lines) > 2*len(lines)+1 {
+		t.Errorf("%d lines held for %d kept; want the dropped ones reused", len(s.lines), len(lines))
+	}
+
+	tail, earlier := s.getTail(3)
+	if strings.Join(tail, ",") != "line 997,line 998,line 999" || earlier != 997 {
+		t.Errorf("getTail(3) = %q, %d; want the last 3 lines after 997", tail, earlier)
+	}
+
+	// A line over the limit is still kept
+	s.addLine(strings.Repeat("x", 200))
+	if lines := s.getLines(); len(lines) != 1 {
+		t.Errorf("kept %d lines after a long one, want 1", len(lines))
+	}
+}
+
+func TestRunCommand_TrimsOutput(t *testing.T) {
+	m := newTestModel(t)
+	m.Config.Settings.OutputBuffer = "1KB"
+
+	cmd := m.RunCommand("seq", "1", "2000")
+	for m.Running && cmd != nil {
+		m, cmd = m.Update(cmd())
+	}
+	done, ok := cmd().(CommandDoneMsg)
+	if !ok {
+		t.Fatal("the command didn't finish with a CommandDoneMsg")
+	}
+
+	if done.Trimmed == 0 || done.Output[len(done.Output)-1] != "2000" {
+		t.Errorf("Trimmed = %d, last line %q; want earlier lines dropped and the last kept", done.Trimmed, done.Output[len(done.Output)-1])
+	}
+	if size := len(strings.Join(done.Output, "\n")); size > 1024 {
+		t.Errorf("kept %d bytes of output, want at most 1KB", size)
+	}
+	if !strings.Contains(strings.Join(m.Lines, "\n"), "not shown above • the full output is in "+m.LogFile) {
+		t.Errorf("output %q doesn't say earlier lines were trimmed", m.Lines[:3])
+	}
+}
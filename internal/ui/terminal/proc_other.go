@@ -0,0 +1,28 @@
+//go:build !unix
+
+package terminal
+
+import (
+	"os"
+	"os/exec"
+)
+
+// setProcessGroup does nothing where process groups are not supported;
+// canceling cmd kills only its own process.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// setControllingTerminal does nothing where sessions are not supported.
+func setControllingTerminal(cmd *exec.Cmd) {}
+
+// detachProcess does nothing where process groups are not supported.
+func detachProcess(cmd *exec.Cmd) {}
+
+// ProcessAlive reports whether the process with pid is still running.
+func ProcessAlive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	p.Release()
+	return true
+}
@@ -0,0 +1,55 @@
+//go:build unix
+
+package terminal
+
+import (
+	"errors"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// killDelay is how long a canceled command has to stop after SIGINT before
+// it is killed. It is shorter than gdev waits for commands when it exits.
+const killDelay = time.Second
+
+// setProcessGroup starts cmd in a process group of its own, so canceling it
+// also stops the processes it starts, and a command left running when gdev
+// exits doesn't get the signals meant for gdev's terminal. Canceling
+// interrupts the group, as ctrl+c in a shell would, and kills what is left
+// of it after killDelay.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		pgid := cmd.Process.Pid
+		time.AfterFunc(killDelay, func() {
+			syscall.Kill(-pgid, syscall.SIGKILL)
+		})
+		return syscall.Kill(-pgid, syscall.SIGINT)
+	}
+}
+
+// setControllingTerminal starts cmd in a session of its own, with its
+// standard input, a pseudo-terminal, as the controlling terminal, so it
+// runs as in a shell: /dev/tty opens and programs that ask for a password
+// ask there. The session replaces the process group setProcessGroup starts;
+// its id is the command's pid all the same, so canceling works as before.
+func setControllingTerminal(cmd *exec.Cmd) {
+	cmd.SysProcAttr.Setpgid = false
+	cmd.SysProcAttr.Setsid = true
+	cmd.SysProcAttr.Setctty = true
+	cmd.SysProcAttr.Ctty = 0
+}
+
+// detachProcess starts cmd in a process group of its own, so it keeps
+// running when gdev and the terminal it runs in exit.
+func detachProcess(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// ProcessAlive reports whether the process with pid is still running.
+// A pid reused by a new process also counts as running.
+func ProcessAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
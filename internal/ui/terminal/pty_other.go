@@ -0,0 +1,14 @@
+//go:build !unix
+
+package terminal
+
+import (
+	"errors"
+	"os"
+)
+
+// openPTY fails where pseudo-terminals are not supported; commands then
+// write to their log themselves and run without input, as if it were empty.
+func openPTY() (master, slave *os.File, err error) {
+	return nil, nil, errors.New("pseudo-terminals are not supported on this system")
+}
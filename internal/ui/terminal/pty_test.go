@@ -0,0 +1,20 @@
+//go:build unix
+
+package terminal
+
+import "testing"
+
+func TestRunCommand_ControllingTerminal(t *testing.T) {
+	m := newTestModel(t)
+
+	cmd := m.RunCommand("sh", "-c",
+		`test -t 0 && test -t 1 && test -t 2 && : </dev/tty && echo terminal; echo stderr >&2`)
+	lines := runToEnd(t, m, cmd)
+
+	if !hasLine(lines, "terminal") {
+		t.Errorf("output %q: the command's stdio isn't its controlling terminal", lines)
+	}
+	if !hasLine(lines, "stderr") {
+		t.Errorf("output %q lacks what was written to stderr", lines)
+	}
+}
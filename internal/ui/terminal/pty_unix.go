@@ -0,0 +1,44 @@
+//go:build unix
+
+package terminal
+
+import (
+	"os"
+
+	"github.com/creack/pty"
+	"golang.org/x/sys/unix"
+)
+
+// ptySize is the size of the terminal commands run in. The modal wraps
+// nothing, so it only needs to be a usual one.
+var ptySize = pty.Winsize{Rows: 24, Cols: 80}
+
+// openPTY opens a pseudo-terminal, returning its master side, which gdev
+// reads the command's output from and types into, and its slave side, the
+// command's terminal.
+func openPTY() (master, slave *os.File, err error) {
+	ptmx, slave, err := pty.Open()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer ptmx.Close()
+	if err := pty.Setsize(ptmx, &ptySize); err != nil {
+		slave.Close()
+		return nil, nil, err
+	}
+
+	// pty.Open leaves the master blocking. Opened again non-blocking, reads
+	// can be stopped with a deadline, as Detach does.
+	fd, err := unix.Dup(int(ptmx.Fd()))
+	if err != nil {
+		slave.Close()
+		return nil, nil, err
+	}
+	unix.CloseOnExec(fd)
+	if err := unix.SetNonblock(fd, true); err != nil {
+		unix.Close(fd)
+		slave.Close()
+		return nil, nil, err
+	}
+	return os.NewFile(uintptr(fd), ptmx.Name()), slave, nil
+}
@@ -0,0 +1,66 @@
+package terminal
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/store"
+)
+
+// viewBudget is how long rendering the output of 10,000 lines may take,
+// per call. The screen is redrawn on every tick and key while a command
+// runs; a frame at 60fps is 16ms on a laptop, and the budget leaves room
+// for slower machines and CI while still catching work done per line of
+// output instead of per visible line.
+const viewBudget = 16 * time.Millisecond
+
+func BenchmarkView(b *testing.B) {
+	b.Setenv("HOME", b.TempDir())
+	s, err := store.New()
+	if err != nil {
+		b.Fatalf("Failed to create store: %v", err)
+	}
+	cfg, err := config.Load(s)
+	if err != nil {
+		b.Fatalf("Failed to load config: %v", err)
+	}
+
+	lines := make([]string, 10000)
+	for i := range lines {
+		switch i % 4 {
+		case 0:
+			lines[i] = fmt.Sprintf("=== RUN   TestCase%d", i)
+		case 1:
+			lines[i] = fmt.Sprintf("    case_test.go:%d: \x1b[31mexpected %d, got %d\x1b[0m", i, i, i+1)
+		case 2:
+			lines[i] = fmt.Sprintf("--- FAIL: TestCase%d (0.%02ds)", i, i%100)
+		default:
+			lines[i] = fmt.Sprintf("ok  	github.com/example/pkg%d	0.%03ds", i, i%1000)
+		}
+	}
+
+	m := New(cfg, "go test ./...")
+	m.SetSize(160, 50)
+	m.Print(lines...)
+	m.ScrollPos = m.maxScroll() / 2
+
+	b.ResetTimer()
+	for range b.N {
+		_ = m.View()
+	}
+}
+
+// TestRenderBudget fails when rendering is over its budget. It only runs
+// with GDEV_RENDER_BUDGETS=1: timings depend on the machine and its load.
+func TestRenderBudget(t *testing.T) {
+	if os.Getenv("GDEV_RENDER_BUDGETS") != "1" {
+		t.Skip("set GDEV_RENDER_BUDGETS=1 to check the rendering budgets")
+	}
+	res := testing.Benchmark(BenchmarkView)
+	if got := time.Duration(res.NsPerOp()); got > viewBudget {
+		t.Errorf("View takes %v, over its budget of %v", got, viewBudget)
+	}
+}
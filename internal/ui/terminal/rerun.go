@@ -0,0 +1,101 @@
+package terminal
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/store"
+	"github.com/ihatemodels/gdev/internal/ui/selector"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+)
+
+// record adds a command to the command history of the terminal's directory,
+// unless it only pretends to run it.
+func (m *Model) record(command []string, mutating bool) {
+	if m.Dir == "" || m.Config.DryRun() {
+		return
+	}
+	// Best effort: a failed write only loses the entry
+	_ = m.Config.RecordCommand(m.Dir, command, mutating)
+}
+
+// openRerun opens the picker of the commands run in the terminal's
+// directory, most recent first.
+func (m Model) openRerun() Model {
+	commands, err := m.Config.Commands(m.Dir)
+	if err != nil {
+		m.notice = styles.Error.Render("Couldn't load the commands run here: " + err.Error())
+		return m
+	}
+	if len(commands) == 0 {
+		m.notice = styles.Help.Render("No commands have run here yet")
+		return m
+	}
+
+	items := make([]selector.Item, len(commands))
+	m.rerunCommands = make(map[string]store.Command, len(commands))
+	for i, c := range commands {
+		line := strings.ReplaceAll(ShellQuote(c.Args[0], c.Args[1:]...), "\n", " ")
+		detail := c.Ran.Format("Jan 2 15:04")
+		if c.Mutating {
+			detail += " • audited"
+		}
+		items[i] = selector.Item{Value: line, Detail: detail}
+		m.rerunCommands[line] = c
+	}
+	m.picker = selector.New(m.Config, "Run again", items)
+	m.picking = true
+	return m
+}
+
+// updateRerunPicker handles input for the picker of commands to run again.
+func (m Model) updateRerunPicker(msg tea.KeyMsg) (Model, tea.Cmd) {
+	var res selector.Result
+	m.picker, res = m.picker.Update(msg)
+
+	switch res {
+	case selector.Selected:
+		m.picking = false
+		if c, ok := m.rerunCommands[m.picker.Value()]; ok {
+			return m.rerunCommand(c)
+		}
+	case selector.Canceled:
+		m.picking = false
+	}
+	return m, nil
+}
+
+// rerunCommand runs a command again in a terminal shown in place of this
+// one until it is closed. The output and result of this terminal's own
+// command are kept for the view that opened it, and the new command's
+// CommandDoneMsg carries another ID, so that view doesn't act on it.
+// Mutating commands are audited again; they run with gdev's environment.
+func (m Model) rerunCommand(c store.Command) (Model, tea.Cmd) {
+	rerun := New(m.Config, m.Title)
+	rerun.Dir = m.Dir
+	rerun.fullscreen = m.fullscreen
+	rerun.SetSize(m.screenWidth, m.screenHeight)
+	rerun.env = m.env
+
+	var cmd tea.Cmd
+	if c.Mutating {
+		cmd = rerun.RunMutatingCommands(nil, "", c.Args)
+	} else {
+		cmd = rerun.RunCommand(c.Args[0], c.Args[1:]...)
+	}
+	m.rerun = &rerun
+	return m, cmd
+}
+
+// updateRerun passes a message to the terminal running a command again,
+// closing it on the quit keys.
+func (m Model) updateRerun(msg tea.Msg) (Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && m.rerun.ShouldClose(msg) {
+		m.rerun.Cancel()
+		m.rerun = nil
+		return m, nil
+	}
+	rerun, cmd := m.rerun.Update(msg)
+	m.rerun = &rerun
+	return m, cmd
+}
@@ -0,0 +1,42 @@
+package terminal
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ihatemodels/gdev/internal/store"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+)
+
+// save writes the output of the finished command to a log named after it
+// and lists it in the history, where it can be reopened later. Output
+// trimmed to fit output_buffer is copied whole from the command's log.
+func (m *Model) save() {
+	name := "output"
+	if fields := strings.Fields(m.Command); len(fields) > 0 {
+		name = filepath.Base(fields[0])
+	}
+	r := store.Run{Title: m.Title, Command: m.Command, Dir: m.Dir}
+	if m.Err != nil {
+		r.Err = m.Err.Error()
+	}
+	var output io.Reader = strings.NewReader(strings.Join(m.output.getLines(), "\n") + "\n")
+	if m.output.trimmedLines() > 0 && m.LogFile != "" {
+		log, err := os.Open(m.LogFile)
+		if err != nil {
+			m.notice = styles.Error.Render("✗ Save failed: " + err.Error())
+			return
+		}
+		defer log.Close()
+		output = log
+	}
+
+	r, err := m.Config.SaveRun(r, name, output)
+	if err != nil {
+		m.notice = styles.Error.Render("✗ Save failed: " + err.Error())
+		return
+	}
+	m.notice = styles.Selected.Render("✓ Saved to " + r.Log)
+}
@@ -3,51 +3,125 @@ package terminal
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/ihatemodels/gdev/internal/ai"
+	"github.com/ihatemodels/gdev/internal/askpass"
 	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/git"
+	"github.com/ihatemodels/gdev/internal/store"
+	"github.com/ihatemodels/gdev/internal/ui/selector"
 	"github.com/ihatemodels/gdev/internal/ui/styles"
+	"github.com/ihatemodels/gdev/internal/ui/textedit"
+	"github.com/mattn/go-runewidth"
 )
 
+// ErrCanceled is the error of a command that was canceled before it finished.
+var ErrCanceled = errors.New("canceled")
+
 // TickMsg triggers a UI refresh to show new output lines.
 type TickMsg struct {
 	ID int
 }
 
-// CommandDoneMsg is sent when the command finishes.
+// CommandDoneMsg is sent to the parent once the terminal shows that its
+// command finished, so the parent can act on how it ended.
 type CommandDoneMsg struct {
-	Err    error
-	ID     int
-	Output []string // all output lines
+	ID       int           // of the terminal
+	Err      error         // nil on success, ErrCanceled if it was canceled
+	ExitCode int           // 0 on success, -1 if it couldn't run or was killed
+	Duration time.Duration // from start to finish, to within a tick
+	Output   []string      // the output lines kept, without the status
+	Trimmed  int           // lines dropped before Output, see output_buffer
 }
 
 // sharedOutput holds output lines that can be safely accessed from goroutines.
+// With a limit, it keeps the last lines that fit in it and drops the older
+// ones; the log file still has them.
 type sharedOutput struct {
-	mu    sync.Mutex
-	lines []string
-	done  bool
-	err   error
+	mu      sync.Mutex
+	lines   []string // the lines kept start at head
+	head    int
+	size    int    // bytes of the lines kept, counted with a limit
+	limit   int    // bytes of output kept, 0 for all of it
+	trimmed int    // lines dropped to keep within limit
+	partial string // the last line, while it is still being written
+	done    bool
+	err     error
+
+	prompt *askpass.Prompt // credential prompt waiting to be shown
+	input  *os.File        // the terminal the command reads from, if any
 }
 
 func (s *sharedOutput) addLine(line string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.lines = append(s.lines, line)
+	if s.limit <= 0 {
+		return
+	}
+
+	// Drop the oldest lines, always keeping the last one
+	s.size += len(line) + 1
+	for s.size > s.limit && len(s.lines)-s.head > 1 {
+		s.size -= len(s.lines[s.head]) + 1
+		s.lines[s.head] = ""
+		s.head++
+		s.trimmed++
+	}
+	// Reuse the space of the dropped lines once they take half of it
+	if s.head > len(s.lines)/2 {
+		n := copy(s.lines, s.lines[s.head:])
+		clear(s.lines[n:])
+		s.lines = s.lines[:n]
+		s.head = 0
+	}
+}
+
+func (s *sharedOutput) setPartial(line string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.partial = line
+}
+
+func (s *sharedOutput) getPartial() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.partial
 }
 
 func (s *sharedOutput) getLines() []string {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	result := make([]string, len(s.lines))
-	copy(result, s.lines)
-	return result
+	return slices.Clone(s.lines[s.head:])
+}
+
+// getTail returns the last n lines kept and how many lines came before
+// them, trimmed ones included.
+func (s *sharedOutput) getTail(n int) ([]string, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	start := max(len(s.lines)-n, s.head)
+	return slices.Clone(s.lines[start:]), s.trimmed + start - s.head
+}
+
+// trimmedLines returns how many lines were dropped to keep within the limit.
+func (s *sharedOutput) trimmedLines() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.trimmed
 }
 
 func (s *sharedOutput) setDone(err error) {
@@ -63,12 +137,50 @@ func (s *sharedOutput) isDone() (bool, error) {
 	return s.done, s.err
 }
 
+func (s *sharedOutput) setPrompt(p *askpass.Prompt) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prompt = p
+}
+
+func (s *sharedOutput) setInput(f *os.File) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.input = f
+}
+
+func (s *sharedOutput) hasInput() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.input != nil
+}
+
+// sendInput types text into the command's terminal.
+func (s *sharedOutput) sendInput(text string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.input == nil {
+		return errors.New("the command has finished")
+	}
+	_, err := io.WriteString(s.input, text)
+	return err
+}
+
+func (s *sharedOutput) takePrompt() *askpass.Prompt {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := s.prompt
+	s.prompt = nil
+	return p
+}
+
 // Model represents a terminal popup modal.
 type Model struct {
-	ID       int    // unique identifier for this terminal instance
-	Title    string // title shown in the modal header
-	Command  string // the command being run (for display)
-	Dir      string // working directory for the command
+	ID      int    // unique identifier for this terminal instance
+	Title   string // title shown in the modal header
+	Command string // the command being run (for display)
+	Dir     string // working directory for the command
+	LogFile string // file the command's output is written to
 
 	Lines      []string // output lines
 	ScrollPos  int      // current scroll position
@@ -82,12 +194,53 @@ type Model struct {
 
 	Config *config.Config
 
+	// Credential prompt from git or ssh, and the answer being typed
+	Prompt *askpass.Prompt
+	Input  string
+
+	// Keys are typed to the command, which reads them from a terminal
+	Typing bool
+
+	// Visual mode: lines from SelStart to SelEnd are selected for copying,
+	// and the movement keys move SelEnd
+	Visual   bool
+	SelStart int
+	SelEnd   int
+
 	// Internal state for streaming
-	output *sharedOutput
+	output  *sharedOutput
+	cancel  context.CancelFunc // stops the running command
+	started time.Time          // when the command started
+	dryRun  bool               // the command was only printed
+	cached  bool               // the output came from the AI response cache
+	follow  bool               // the output is the log of a process gdev didn't start
+	notice  string             // shown in the footer in place of the help
+
+	// The file:line selected to open in the editor
+	jump    location
+	jumping bool
+
+	highlights []highlightRule // from the highlights setting
+	env        []string        // variables set with SetEnv, as key=value
+
+	// Picking a command of the directory's history to run again, and the
+	// terminal running it, shown in place of this one
+	picker        selector.Model
+	picking       bool
+	rerunCommands map[string]store.Command // by the picker's value
+	rerun         *Model
+
+	// The modal fills the screen, whose size SetSize was last given
+	fullscreen                bool
+	screenWidth, screenHeight int
 }
 
 var instanceCounter int
 
+// running tracks the commands still running, so gdev can wait for them to
+// stop when it exits.
+var running sync.WaitGroup
+
 // New creates a new terminal model.
 func New(cfg *config.Config, title string) Model {
 	instanceCounter++
@@ -100,11 +253,19 @@ func New(cfg *config.Config, title string) Model {
 		Width:      80,
 		Height:     20,
 		AutoScroll: true,
+		highlights: compileHighlights(cfg.Settings.Highlights),
 	}
 }
 
-// SetSize sets the modal dimensions.
+// SetSize sets the modal dimensions from the screen's.
 func (m *Model) SetSize(width, height int) {
+	m.screenWidth, m.screenHeight = width, height
+	if m.fullscreen {
+		m.Width = max(width-2, 20) // the border is outside the width
+		m.Height = max(height, 10)
+		return
+	}
+
 	// Modal takes up 80% of screen, with min/max bounds
 	m.Width = width * 80 / 100
 	if m.Width < 60 {
@@ -128,21 +289,209 @@ func (m *Model) RunCommand(name string, args ...string) tea.Cmd {
 	return m.RunCommandWithEnv(nil, name, args...)
 }
 
-// RunCommandWithEnv starts executing a command with environment variables.
+// RunCommandWithEnv starts executing a command with environment variables,
+// as key=value, added to gdev's. They override variables of the same name.
 func (m *Model) RunCommandWithEnv(env []string, name string, args ...string) tea.Cmd {
+	m.record(append([]string{name}, args...), false)
+	return m.run(append(os.Environ(), env...), nil, name, args...)
+}
+
+// SetEnv sets an environment variable for the commands the terminal runs
+// from now on, over their environment and any value set before.
+func (m *Model) SetEnv(key, value string) {
+	m.env = slices.DeleteFunc(slices.Clone(m.env), func(kv string) bool {
+		return strings.HasPrefix(kv, key+"=")
+	})
+	m.env = append(m.env, key+"="+value)
+}
+
+// RunCachedCommand runs a command that asks an AI provider, answering it from
+// the response cache if an identical request was made within the cache TTL.
+// Successful responses are cached under key. With fresh set, the cache is
+// not read, so the provider is asked again.
+func (m *Model) RunCachedCommand(key store.CacheKey, fresh bool, name string, args ...string) tea.Cmd {
+	if !fresh {
+		if lines, ok := m.Config.CachedResponse(key); ok {
+			return m.printCached(name+" "+strings.Join(args, " "), lines)
+		}
+	}
+
+	cfg := m.Config
+	return m.run(nil, func(err error, output *sharedOutput) {
+		// A response trimmed to fit output_buffer isn't cached incomplete
+		if err == nil && output.trimmedLines() == 0 {
+			// Best effort: a failed write only means asking again next time
+			_ = cfg.CacheResponse(key, output.getLines())
+		}
+	}, name, args...)
+}
+
+// RunCachedFunc is RunCachedCommand for an AI request made by fn instead of
+// a command, such as a call to an API. What fn writes to w is shown as the
+// output; command describes the request in the header. fn must stop when
+// ctx is canceled. Unlike commands, fn is not a job: it stops when gdev exits.
+func (m *Model) RunCachedFunc(key store.CacheKey, fresh bool, command string, fn func(ctx context.Context, w io.Writer) error) tea.Cmd {
+	if !fresh {
+		if lines, ok := m.Config.CachedResponse(key); ok {
+			return m.printCached(command, lines)
+		}
+	}
+
+	m.Command = command
+	m.Running = true
+	m.started = time.Now()
+	m.Lines = []string{styles.Help.Render("$ " + m.Command), ""}
+	m.ScrollPos = 0
+	m.Err = nil
+	m.output = &sharedOutput{limit: m.Config.Settings.OutputBufferSize()}
+	m.dryRun = false
+	m.cached = false
+	m.follow = false
+	m.Prompt = nil
+
+	output := m.output
+	log, err := m.Config.CreateLog(key.Provider)
+	if err != nil {
+		m.LogFile = ""
+		m.output.setDone(fmt.Errorf("creating the log file: %w", err))
+		return m.tick()
+	}
+	m.LogFile = log.Name()
+
+	ctx, cancel := context.WithCancel(m.Config.Context())
+	m.cancel = cancel
+
+	cfg := m.Config
+	running.Add(1)
+	go func() {
+		defer running.Done()
+		defer cancel()
+		err := executeFuncStreaming(ctx, output, log, fn)
+		log.Close()
+		if lines := output.getLines(); err == nil && output.trimmedLines() == 0 && ai.Cacheable(strings.Join(lines, "\n")) {
+			// Best effort: a failed write only means asking again next time
+			_ = cfg.CacheResponse(key, lines)
+		}
+		output.setDone(err)
+	}()
+
+	return m.tick()
+}
+
+// printCached shows the cached output of command as if it had just run.
+func (m *Model) printCached(command string, lines []string) tea.Cmd {
+	m.Command = command
+	m.Running = true
+	m.started = time.Now()
+	m.Lines = []string{styles.Help.Render("$ " + m.Command), ""}
+	m.ScrollPos = 0
+	m.Err = nil
+	m.dryRun = false
+	m.cached = true
+	m.follow = false
+	m.Prompt = nil
+	m.output = &sharedOutput{lines: lines, done: true}
+	return m.tick()
+}
+
+// run starts executing a command and streams output.
+// If done is set, it is called with the command's error and output when it finishes.
+func (m *Model) run(env []string, done func(err error, output *sharedOutput), name string, args ...string) tea.Cmd {
 	m.Command = name + " " + strings.Join(args, " ")
+	return m.runAll(env, "", nil, done, [][]string{append([]string{name}, args...)})
+}
+
+// runAll runs commands one after another, stopping at the first that fails,
+// and streams their output after note, if set. If ran is set, it is called
+// with each command and its error as it finishes; done is called once all
+// have, with the error of the one that failed. The header shows m.Command.
+func (m *Model) runAll(env []string, note string, ran func(command []string, err error), done func(err error, output *sharedOutput), commands [][]string) tea.Cmd {
 	m.Running = true
+	m.started = time.Now()
 	m.Lines = []string{styles.Help.Render("$ " + m.Command), ""}
 	m.ScrollPos = 0
 	m.Err = nil
-	m.output = &sharedOutput{lines: []string{}}
+	m.output = &sharedOutput{limit: m.Config.Settings.OutputBufferSize()}
+	m.dryRun = false
+	m.cached = false
+	m.follow = false
+	m.Prompt = nil
 
 	dir := m.Dir
 	output := m.output
 
-	// Start the command in a goroutine
+	// The output goes to a log file, which keeps it if the command outlives gdev
+	log, err := m.Config.CreateLog(filepath.Base(commands[0][0]))
+	if err != nil {
+		m.LogFile = ""
+		m.output.setDone(fmt.Errorf("creating the log file: %w", err))
+		return m.tick()
+	}
+	m.LogFile = log.Name()
+	job := Job{ID: m.ID, Title: m.Title, Command: m.Command, Dir: dir, Log: log.Name()}
+
+	ctx, cancel := context.WithCancel(m.Config.Context())
+	m.cancel = cancel
+
+	// Credential prompts are answered in the modal; without a terminal to
+	// prompt on, git would otherwise wait for input that never comes
+	if env == nil {
+		env = os.Environ()
+	}
+	// Commands run in a terminal, where git and others would otherwise page
+	// their output and wait for keys
+	env = slices.Concat(env, []string{"GIT_PAGER=cat", "PAGER=cat"}, m.env, []string{"GIT_TERMINAL_PROMPT=0"})
+	srv, err := askpass.Listen()
+	if err != nil {
+		srv = nil
+	} else {
+		env = append(env, srv.Env()...)
+		go func() {
+			for p := range srv.Prompts() {
+				output.setPrompt(p)
+			}
+		}()
+	}
+
+	// Start the commands in a goroutine
+	running.Add(1)
 	go func() {
-		err := executeCommandStreaming(dir, env, output, name, args...)
+		defer running.Done()
+		defer cancel()
+		defer removeJob(job.ID)
+		if note != "" {
+			fmt.Fprintln(log, note)
+		}
+		err := withLogReader(log, func(logReader io.Reader) error {
+			for _, c := range commands {
+				err := executeCommandStreaming(ctx, dir, env, output, log, logReader, func(pid int, pty *os.File) {
+					job.Pid = pid
+					job.Started = time.Now()
+					job.pty = pty
+					addJob(job)
+				}, c[0], c[1:]...)
+				if ran != nil {
+					ran(c, err)
+				}
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		log.Close()
+		if srv != nil {
+			srv.Close()
+		}
+		if err != nil && !errors.Is(err, ErrCanceled) {
+			if hint := git.CredentialHint(dir, output.getLines()); hint != "" {
+				output.addLine("")
+				output.addLine(hint)
+			}
+		}
+		if done != nil {
+			done(err, output)
+		}
 		output.setDone(err)
 	}()
 
@@ -150,6 +499,285 @@ func (m *Model) RunCommandWithEnv(env []string, name string, args ...string) tea
 	return m.tick()
 }
 
+// RunMutatingCommand runs a command that changes a repository or remote.
+// The command and its exit code are recorded in the repository's audit log.
+// In dry-run mode the command is printed instead of executed, and the
+// terminal finishes as if it had succeeded. In read-only mode it finishes
+// with store.ErrReadOnly instead.
+func (m *Model) RunMutatingCommand(name string, args ...string) tea.Cmd {
+	if m.Config.ReadOnly() {
+		return m.refuse(name + " " + strings.Join(args, " "))
+	}
+	if !m.Config.DryRun() {
+		m.record(append([]string{name}, args...), true)
+		cfg, dir, command := m.Config, m.Dir, ShellQuote(name, args...)
+		return m.run(nil, func(err error, _ *sharedOutput) {
+			// Best effort: a failed write only loses the entry
+			_ = cfg.Audit(dir, command, exitCode(err))
+		}, name, args...)
+	}
+
+	m.Command = name + " " + strings.Join(args, " ")
+	m.Running = true
+	m.started = time.Now()
+	m.Lines = []string{styles.Help.Render("$ " + m.Command), ""}
+	m.ScrollPos = 0
+	m.Err = nil
+	m.dryRun = true
+	m.cached = false
+	m.follow = false
+	m.Prompt = nil
+
+	lines := []string{styles.Status.Render("Dry run: would run in " + m.Dir), ""}
+	lines = append(lines, strings.Split(ShellQuote(name, args...), "\n")...)
+	m.output = &sharedOutput{lines: lines, done: true}
+
+	return m.tick()
+}
+
+// RunMutatingCommands runs commands that change a repository one after
+// another, stopping at the first that fails, each recorded in the audit log
+// as by RunMutatingCommand. They run without a shell, with env as their
+// environment unless it is nil, and the variables set with SetEnv; note, if
+// set, is shown before their output.
+func (m *Model) RunMutatingCommands(env []string, note string, commands ...[]string) tea.Cmd {
+	quoted := make([]string, len(commands))
+	for i, c := range commands {
+		quoted[i] = ShellQuote(c[0], c[1:]...)
+	}
+	m.Command = strings.Join(quoted, " && ")
+
+	if m.Config.ReadOnly() {
+		return m.refuse(m.Command)
+	}
+	if !m.Config.DryRun() {
+		for _, c := range commands {
+			m.record(c, true)
+		}
+		cfg, dir := m.Config, m.Dir
+		return m.runAll(env, note, func(command []string, err error) {
+			// Best effort: a failed write only loses the entry
+			_ = cfg.Audit(dir, ShellQuote(command[0], command[1:]...), exitCode(err))
+		}, nil, commands)
+	}
+
+	m.Running = true
+	m.started = time.Now()
+	m.Lines = []string{styles.Help.Render("$ " + m.Command), ""}
+	m.ScrollPos = 0
+	m.Err = nil
+	m.dryRun = true
+	m.cached = false
+	m.follow = false
+	m.Prompt = nil
+
+	lines := []string{styles.Status.Render("Dry run: would run in " + m.Dir), ""}
+	if note != "" {
+		lines = append(lines, note)
+	}
+	m.output = &sharedOutput{lines: append(lines, quoted...), done: true}
+
+	return m.tick()
+}
+
+// refuse finishes the terminal with store.ErrReadOnly without running
+// command, which is only shown.
+func (m *Model) refuse(command string) tea.Cmd {
+	m.Command = command
+	m.Running = true
+	m.started = time.Now()
+	m.Lines = []string{styles.Help.Render("$ " + m.Command), ""}
+	m.ScrollPos = 0
+	m.Err = nil
+	m.dryRun = false
+	m.cached = false
+	m.follow = false
+	m.Prompt = nil
+
+	lines := []string{styles.Status.Render("Read-only mode: not running in " + m.Dir)}
+	m.output = &sharedOutput{lines: lines, done: true, err: store.ErrReadOnly}
+
+	return m.tick()
+}
+
+// ExecMutatingCommands hands the real terminal to commands that change a
+// repository, such as git commit opening the user's editor, run one after
+// another until one fails. Each is recorded in the audit log of dir. env, if
+// not nil, is their environment. Callers handle dry-run mode themselves; in
+// read-only mode fn gets store.ErrReadOnly and nothing runs.
+func ExecMutatingCommands(cfg *config.Config, dir string, env []string, fn func(err error) tea.Msg, commands ...[]string) tea.Cmd {
+	if cfg.ReadOnly() {
+		return func() tea.Msg { return fn(store.ErrReadOnly) }
+	}
+	return tea.Exec(&execSequence{cfg: cfg, dir: dir, env: env, commands: commands}, fn)
+}
+
+// execSequence runs commands in turn with the real terminal, for tea.Exec.
+type execSequence struct {
+	cfg      *config.Config
+	dir      string
+	env      []string
+	commands [][]string
+
+	stdin          io.Reader
+	stdout, stderr io.Writer
+}
+
+func (s *execSequence) Run() error {
+	for _, command := range s.commands {
+		c := exec.Command(command[0], command[1:]...)
+		c.Dir, c.Env = s.dir, s.env
+		c.Stdin, c.Stdout, c.Stderr = s.stdin, s.stdout, s.stderr
+		err := c.Run()
+		// Best effort: a failed write only loses the entry
+		_ = s.cfg.Audit(s.dir, ShellQuote(command[0], command[1:]...), exitCode(err))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *execSequence) SetStdin(r io.Reader)  { s.stdin = r }
+func (s *execSequence) SetStdout(w io.Writer) { s.stdout = w }
+func (s *execSequence) SetStderr(w io.Writer) { s.stderr = w }
+
+// Cancel stops the running command. Its output so far is kept, and it
+// finishes with ErrCanceled.
+func (m Model) Cancel() {
+	if m.Running && m.cancel != nil {
+		m.cancel()
+	}
+	if m.rerun != nil {
+		m.rerun.Cancel()
+	}
+}
+
+// Unfinished returns the CommandDoneMsg of a command closed before it was
+// done: it is canceled, with the output written until then. Parents use it
+// in place of the message that never arrives.
+func (m Model) Unfinished() CommandDoneMsg {
+	return CommandDoneMsg{
+		ID:       m.ID,
+		Err:      ErrCanceled,
+		ExitCode: -1,
+		Duration: time.Since(m.started),
+		Output:   m.GetRawOutputLines(),
+		Trimmed:  m.trimmedLines(),
+	}
+}
+
+// Wait waits up to timeout for the commands of all terminals to stop, once
+// the context they run with has been canceled.
+func Wait(timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+	}
+}
+
+// Print shows lines as the output of a finished command.
+func (m *Model) Print(lines ...string) {
+	m.Command = ""
+	m.Running = false
+	m.Err = nil
+	m.Lines = lines
+	m.ScrollPos = 0
+	m.output = nil
+}
+
+// Follow shows the log of a command left running by an earlier gdev, and
+// the lines added to it until the process with pid exits. Canceling stops
+// following; the process keeps running.
+func (m *Model) Follow(log string, pid int) tea.Cmd {
+	m.Command = "tail -f " + log
+	m.LogFile = log
+	m.Running = true
+	m.started = time.Now()
+	m.Lines = []string{styles.Help.Render("$ " + m.Command), ""}
+	m.ScrollPos = 0
+	m.Err = nil
+	m.output = &sharedOutput{limit: m.Config.Settings.OutputBufferSize()}
+	m.dryRun = false
+	m.cached = false
+	m.follow = true
+	m.Prompt = nil
+
+	ctx, cancel := context.WithCancel(m.Config.Context())
+	m.cancel = cancel
+	output := m.output
+
+	go func() {
+		defer cancel()
+		f, err := os.Open(log)
+		if err != nil {
+			output.setDone(err)
+			return
+		}
+		defer f.Close()
+
+		exited := make(chan error, 1)
+		go func() {
+			for ProcessAlive(pid) {
+				select {
+				case <-ctx.Done():
+					exited <- ErrCanceled
+					return
+				case <-time.After(500 * time.Millisecond):
+				}
+			}
+			exited <- nil
+		}()
+		output.setDone(tailLog(f, output, exited))
+	}()
+
+	return m.tick()
+}
+
+// done tells the parent how the command ended with a CommandDoneMsg.
+func (m Model) done() tea.Cmd {
+	msg := CommandDoneMsg{
+		ID:       m.ID,
+		Err:      m.Err,
+		ExitCode: exitCode(m.Err),
+		Duration: time.Since(m.started),
+		Output:   m.output.getLines(),
+		Trimmed:  m.output.trimmedLines(),
+	}
+	return func() tea.Msg { return msg }
+}
+
+// exitCode returns the exit code of a finished command,
+// or -1 if it could not be run.
+func exitCode(err error) int {
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &exitErr):
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// ShellQuote formats a command as it would be typed in a shell.
+func ShellQuote(name string, args ...string) string {
+	parts := []string{name}
+	for _, a := range args {
+		if a != "" && !strings.ContainsAny(a, " \t\n'\"\\$`|&;<>(){}*?[]#~!") {
+			parts = append(parts, a)
+			continue
+		}
+		parts = append(parts, "'"+strings.ReplaceAll(a, "'", `'\''`)+"'")
+	}
+	return strings.Join(parts, " ")
+}
+
 func (m Model) tick() tea.Cmd {
 	id := m.ID
 	return tea.Tick(50*time.Millisecond, func(time.Time) tea.Msg {
@@ -157,8 +785,13 @@ func (m Model) tick() tea.Cmd {
 	})
 }
 
-func executeCommandStreaming(dir string, env []string, output *sharedOutput, name string, args ...string) error {
-	cmd := exec.Command(name, args...)
+// executeCommandStreaming runs a command that writes its output to log,
+// adding the output to output line by line. started is called once the
+// process runs, with the master side of its terminal if it has one. The
+// command is killed when ctx is canceled.
+func executeCommandStreaming(ctx context.Context, dir string, env []string, output *sharedOutput, log *os.File, logReader io.Reader, started func(pid int, pty *os.File), name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	setProcessGroup(cmd)
 	if dir != "" {
 		cmd.Dir = dir
 	}
@@ -166,42 +799,137 @@ func executeCommandStreaming(dir string, env []string, output *sharedOutput, nam
 		cmd.Env = env
 	}
 
-	// Get pipes for stdout and stderr
-	stdout, err := cmd.StdoutPipe()
+	// The command runs in a terminal, its controlling one, keys are typed
+	// into in the modal. Its output and what the terminal echoes are copied
+	// to the log, see Detach for commands left running when gdev exits.
+	// Without a terminal it writes to the file itself and input is empty.
+	master, slave, ptyErr := openPTY()
+	if ptyErr == nil {
+		defer master.Close()
+		cmd.Stdin = slave
+		cmd.Stdout = slave
+		cmd.Stderr = slave
+		setControllingTerminal(cmd)
+	} else {
+		cmd.Stdout = log
+		cmd.Stderr = log
+	}
+
+	err := cmd.Start()
+	if ptyErr == nil {
+		slave.Close()
+	}
 	if err != nil {
 		return err
 	}
-	stderr, err := cmd.StderrPipe()
+	copied := make(chan struct{})
+	if ptyErr == nil {
+		go func() {
+			defer close(copied)
+			io.Copy(log, master)
+		}()
+		output.setInput(master)
+		defer output.setInput(nil)
+	} else {
+		close(copied)
+	}
+	started(cmd.Process.Pid, master)
+
+	exited := make(chan error, 1)
+	go func() {
+		err := cmd.Wait()
+		// The output still in the terminal is copied once the command has
+		// exited. Processes it started may keep the terminal open, so only
+		// wait briefly for them.
+		select {
+		case <-copied:
+		case <-time.After(time.Second):
+		}
+		exited <- err
+	}()
+
+	err = tailLog(logReader, output, exited)
+	if ctx.Err() != nil {
+		return ErrCanceled
+	}
+	return err
+}
+
+// withLogReader calls fn with a reader of log from its start. Commands run
+// in turn share it, each reading on from where the last stopped.
+func withLogReader(log *os.File, fn func(r io.Reader) error) error {
+	logReader, err := os.Open(log.Name())
 	if err != nil {
 		return err
 	}
+	defer logReader.Close()
+	return fn(logReader)
+}
 
-	if err := cmd.Start(); err != nil {
+// executeFuncStreaming runs fn, writing to log, and shows what it writes.
+func executeFuncStreaming(ctx context.Context, output *sharedOutput, log *os.File, fn func(ctx context.Context, w io.Writer) error) error {
+	logReader, err := os.Open(log.Name())
+	if err != nil {
 		return err
 	}
+	defer logReader.Close()
 
-	// Read both stdout and stderr
-	var wg sync.WaitGroup
-	wg.Add(2)
-
-	go readPipeToOutput(stdout, output, &wg)
-	go readPipeToOutput(stderr, output, &wg)
-
-	wg.Wait()
+	exited := make(chan error, 1)
+	go func() {
+		exited <- fn(ctx, log)
+	}()
 
-	return cmd.Wait()
+	err = tailLog(logReader, output, exited)
+	if ctx.Err() != nil {
+		return ErrCanceled
+	}
+	return err
 }
 
-func readPipeToOutput(pipe io.ReadCloser, output *sharedOutput, wg *sync.WaitGroup) {
-	defer wg.Done()
-	scanner := bufio.NewScanner(pipe)
-	for scanner.Scan() {
-		output.addLine(scanner.Text())
+// tailLog adds the lines written to r to output as they arrive, until the
+// command exits, and returns its error.
+func tailLog(r io.Reader, output *sharedOutput, exited <-chan error) error {
+	reader := bufio.NewReader(r)
+	var partial string
+	for {
+		// Read the complete lines written so far
+		for {
+			line, err := reader.ReadString('\n')
+			partial += line
+			if err != nil {
+				break
+			}
+			output.addLine(strings.TrimRight(partial, "\r\n"))
+			partial = ""
+		}
+		output.setPartial(partial)
+
+		select {
+		case err := <-exited:
+			rest, _ := io.ReadAll(reader)
+			lines := strings.Split(partial+string(rest), "\n")
+			if lines[len(lines)-1] == "" {
+				lines = lines[:len(lines)-1]
+			}
+			for _, line := range lines {
+				output.addLine(strings.TrimRight(line, "\r"))
+			}
+			output.setPartial("")
+			return err
+		case <-time.After(50 * time.Millisecond):
+		}
 	}
 }
 
 // Update handles input for the terminal modal.
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if m.rerun != nil {
+		if msg, ok := msg.(tea.WindowSizeMsg); ok {
+			m.SetSize(msg.Width, msg.Height)
+		}
+		return m.updateRerun(msg)
+	}
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.SetSize(msg.Width, msg.Height)
@@ -213,6 +941,9 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		}
 		return m.handleTick()
 
+	case tea.MouseMsg:
+		return m.handleMouse(msg), nil
+
 	case tea.KeyMsg:
 		return m.handleKey(msg)
 	}
@@ -224,17 +955,21 @@ func (m Model) handleTick() (Model, tea.Cmd) {
 		return m, nil
 	}
 
-	// Get latest lines from shared output
-	newLines := m.output.getLines()
+	// Get the last lines from shared output, with the one still being
+	// written, such as a question waiting for an answer. With the command
+	// header and a line saying how many came before, they fit in MaxLines.
+	newLines, earlier := m.output.getTail(m.MaxLines - 3)
+	if partial := m.output.getPartial(); partial != "" {
+		newLines = append(newLines, partial)
+	}
 
 	// Update lines, keeping the command header
 	if len(newLines) > 0 {
-		m.Lines = append([]string{styles.Help.Render("$ " + m.Command), ""}, newLines...)
-	}
-
-	// Trim to max lines
-	if len(m.Lines) > m.MaxLines {
-		m.Lines = m.Lines[len(m.Lines)-m.MaxLines:]
+		header := []string{styles.Help.Render("$ " + m.Command), ""}
+		if earlier > 0 {
+			header = append(header, m.earlierNotice(earlier))
+		}
+		m.Lines = append(header, newLines...)
 	}
 
 	// Auto-scroll to bottom if enabled
@@ -242,14 +977,41 @@ func (m Model) handleTick() (Model, tea.Cmd) {
 		m.ScrollPos = m.maxScroll()
 	}
 
+	// Show the next credential prompt
+	if m.Prompt == nil {
+		if p := m.output.takePrompt(); p != nil {
+			m.Prompt = p
+			m.Input = ""
+		}
+	}
+
 	// Check if command is done
 	done, err := m.output.isDone()
 	if done {
+		m.Prompt = nil
+		m.Typing = false
 		m.Running = false
+		m.notice = ""
 		m.Err = err
-		if err != nil {
+		if m.follow && err == nil {
+			m.Lines = append(m.Lines, "")
+			m.Lines = append(m.Lines, styles.Selected.Render("✓ Job finished"))
+		} else if m.follow && errors.Is(err, ErrCanceled) {
+			m.Err = nil
+			m.Lines = append(m.Lines, "")
+			m.Lines = append(m.Lines, styles.Help.Render("Stopped following; the job keeps running"))
+		} else if errors.Is(err, ErrCanceled) {
+			m.Lines = append(m.Lines, "")
+			m.Lines = append(m.Lines, styles.Confirm.Render("✗ Canceled"))
+		} else if err != nil {
 			m.Lines = append(m.Lines, "")
 			m.Lines = append(m.Lines, styles.Error.Render("Error: "+err.Error()))
+		} else if m.dryRun {
+			m.Lines = append(m.Lines, "")
+			m.Lines = append(m.Lines, styles.Selected.Render("✓ Dry run complete, nothing was executed"))
+		} else if m.cached {
+			m.Lines = append(m.Lines, "")
+			m.Lines = append(m.Lines, styles.Selected.Render("✓ Cached response, the command was not run"))
 		} else {
 			m.Lines = append(m.Lines, "")
 			m.Lines = append(m.Lines, styles.Selected.Render("✓ Command completed"))
@@ -257,17 +1019,98 @@ func (m Model) handleTick() (Model, tea.Cmd) {
 		if m.AutoScroll {
 			m.ScrollPos = m.maxScroll()
 		}
-		return m, nil
+		return m, m.done()
 	}
 
 	// Continue ticking while running
 	return m, m.tick()
 }
 
+// wheelLines is how many lines a notch of the mouse wheel scrolls.
+const wheelLines = 3
+
+// handleMouse scrolls the output with the mouse wheel, as the up and down
+// keys do. The picker and line selection keep to the keys.
+func (m Model) handleMouse(msg tea.MouseMsg) Model {
+	if msg.Action != tea.MouseActionPress || m.picking || m.Visual {
+		return m
+	}
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		m.AutoScroll = false
+		m.ScrollPos = max(m.ScrollPos-wheelLines, 0)
+	case tea.MouseButtonWheelDown:
+		m.ScrollPos = min(m.ScrollPos+wheelLines, m.maxScroll())
+		// Re-enable auto-scroll if at bottom
+		if m.ScrollPos >= m.maxScroll() {
+			m.AutoScroll = true
+		}
+	}
+	return m
+}
+
 func (m Model) handleKey(msg tea.KeyMsg) (Model, tea.Cmd) {
 	key := msg.String()
 	kb := m.Config.Keys()
 
+	if m.Prompt != nil {
+		return m.handlePromptKey(msg)
+	}
+	if m.picking {
+		return m.updateRerunPicker(msg)
+	}
+
+	if config.Matches(key, kb.Global.Cancel) {
+		if m.Running && m.cancel != nil {
+			// The command is interrupted and may take a moment to stop
+			m.Typing = false
+			m.notice = styles.Confirm.Render("Canceling...")
+		}
+		m.Cancel()
+		return m, nil
+	}
+
+	if m.Typing {
+		return m.handleInputKey(msg)
+	}
+	if config.Matches(key, kb.Terminal.Input) && m.Running && m.output.hasInput() {
+		m.Typing = true
+		m.AutoScroll = true
+		m.ScrollPos = m.maxScroll()
+		return m, nil
+	}
+
+	m.notice = ""
+	if m.Visual {
+		return m.handleVisualKey(msg)
+	}
+	if config.Matches(key, kb.Terminal.Visual) {
+		return m.startVisual(), nil
+	}
+	if config.Matches(key, kb.Terminal.Copy) {
+		m.copyLines(m.Lines)
+		return m, nil
+	}
+	if config.Matches(key, kb.Terminal.Save) && !m.Running && m.output != nil {
+		m.save()
+		return m, nil
+	}
+	if config.Matches(key, kb.Terminal.Rerun) && !m.Running && m.Dir != "" {
+		return m.openRerun(), nil
+	}
+	if config.Matches(key, kb.Terminal.Fullscreen) {
+		m.fullscreen = !m.fullscreen
+		m.SetSize(m.screenWidth, m.screenHeight)
+		m.ScrollPos = min(m.ScrollPos, m.maxScroll())
+		if m.AutoScroll {
+			m.ScrollPos = m.maxScroll()
+		}
+		return m, nil
+	}
+	if m, cmd, ok := m.handleLocationKey(key); ok {
+		return m, cmd
+	}
+
 	// Disable auto-scroll when user scrolls manually
 	if config.MatchesAny(key, kb.Global.MoveUp, kb.Global.MoveUpAlt) || msg.Type == tea.KeyUp {
 		m.AutoScroll = false
@@ -325,8 +1168,42 @@ func (m Model) handleKey(msg tea.KeyMsg) (Model, tea.Cmd) {
 	return m, nil
 }
 
+// handlePromptKey handles typing the answer to a credential prompt.
+func (m Model) handlePromptKey(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.Prompt.Answer(m.Input)
+		m.Prompt = nil
+		m.Input = ""
+	case tea.KeyEsc, tea.KeyCtrlC:
+		m.Prompt.Cancel()
+		m.Prompt = nil
+		m.Input = ""
+	case tea.KeyBackspace:
+		m.Input, _ = textedit.Backspace(m.Input, len(m.Input))
+	case tea.KeySpace:
+		m.Input += " "
+	case tea.KeyRunes:
+		m.Input += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// Prompting reports whether a credential prompt is waiting for an answer,
+// or keys are being typed to the command. Keys must then reach the
+// terminal, even ones that would close it.
+func (m Model) Prompting() bool {
+	return m.Prompt != nil || m.Typing
+}
+
 // ShouldClose returns true if the user pressed a quit key.
+// It never closes while a credential prompt is waiting, lines are being
+// selected or a command is being run again. Callers closing the terminal
+// should Cancel it, so the command doesn't keep running unseen.
 func (m Model) ShouldClose(msg tea.KeyMsg) bool {
+	if m.Prompting() || m.Selecting() || m.picking || m.rerun != nil {
+		return false
+	}
 	key := msg.String()
 	kb := m.Config.Keys()
 	return config.MatchesAny(key, kb.Global.Quit, kb.Global.QuitAlt)
@@ -352,6 +1229,33 @@ func (m Model) GetRawOutput() string {
 	return strings.Join(lines, "\n")
 }
 
+// Partial returns the line of output still being written, such as the
+// text a model is generating, which GetRawOutput doesn't include yet.
+func (m Model) Partial() string {
+	if m.output == nil {
+		return ""
+	}
+	return m.output.getPartial()
+}
+
+// earlierNotice is the line saying n earlier lines of output aren't shown.
+func (m Model) earlierNotice(n int) string {
+	text := fmt.Sprintf("… %s not shown above", lineCount(n))
+	if m.trimmedLines() > 0 && m.LogFile != "" {
+		text += " • the full output is in " + m.LogFile
+	}
+	return styles.Help.Render(text)
+}
+
+// trimmedLines returns how many lines of output were dropped to keep
+// within output_buffer.
+func (m Model) trimmedLines() int {
+	if m.output == nil {
+		return 0
+	}
+	return m.output.trimmedLines()
+}
+
 // GetRawOutputLines returns the raw command output lines as a slice.
 func (m Model) GetRawOutputLines() []string {
 	if m.output == nil {
@@ -384,11 +1288,19 @@ func (m Model) maxScroll() int {
 
 // View renders the terminal modal.
 func (m Model) View() string {
+	if m.rerun != nil {
+		return m.rerun.View()
+	}
+	if m.picking {
+		return m.picker.View()
+	}
+
 	// Calculate content area
 	contentWidth := m.Width - 4 // borders + padding
 	visibleLines := m.visibleLines()
 
 	// Build header
+	kb := m.Config.Keys()
 	status := styles.Selected.Render("✓ Done")
 	if m.Running {
 		status = styles.Confirm.Render("● Running...")
@@ -396,12 +1308,16 @@ func (m Model) View() string {
 		status = styles.Error.Render("✗ Failed")
 	}
 
-	titleText := m.Title
-	if len(titleText) > contentWidth-15 {
-		titleText = titleText[:contentWidth-18] + "..."
-	}
+	titleText := runewidth.Truncate(m.Title, contentWidth-15, "...")
 
 	header := fmt.Sprintf(" %s  %s", styles.Title.Render(titleText), status)
+	toggle := kb.Terminal.Fullscreen + " fullscreen"
+	if m.fullscreen {
+		toggle = kb.Terminal.Fullscreen + " shrink"
+	}
+	if gap := contentWidth - lipgloss.Width(header) - lipgloss.Width(toggle); gap > 0 {
+		header += strings.Repeat(" ", gap) + styles.Help.Render(toggle)
+	}
 
 	// Build content
 	var content strings.Builder
@@ -415,9 +1331,21 @@ func (m Model) View() string {
 
 	for i := start; i < end; i++ {
 		line := m.Lines[i]
-		// Truncate long lines
-		if len(line) > contentWidth {
-			line = line[:contentWidth-3] + "..."
+		selected := m.inSelection(i)
+		if selected {
+			line = ansiEscape.ReplaceAllString(line, "")
+		}
+		switch {
+		case selected:
+			line = selectedLine.Render(truncate(line, contentWidth))
+		case m.jumping && i == m.jump.line:
+			line = m.markLocation(line, contentWidth)
+		case strings.ContainsAny(line, "\x1b\r"):
+			// Colored output, including what gdev styled itself, such as
+			// the status
+			line = renderANSI(line, contentWidth)
+		default:
+			line = highlight(truncate(line, contentWidth), m.highlights)
 		}
 		content.WriteString(line)
 		if i < end-1 {
@@ -431,13 +1359,48 @@ func (m Model) View() string {
 	}
 
 	// Build footer with help text
-	kb := m.Config.Keys()
 	scrollInfo := fmt.Sprintf(" %d/%d ", m.ScrollPos+1, max(len(m.Lines), 1))
 	helpText := fmt.Sprintf("%s/%s scroll • %s/%s page • %s close",
 		kb.Global.MoveUp, kb.Global.MoveDown,
 		kb.List.PageUp, kb.List.PageDown,
 		kb.Global.Quit)
+	if m.Running && m.cancel != nil {
+		helpText += fmt.Sprintf(" • %s cancel", kb.Global.Cancel)
+		if m.output.hasInput() {
+			helpText += fmt.Sprintf(" • %s type input", kb.Terminal.Input)
+		}
+	} else {
+		helpText += fmt.Sprintf(" • %s select • %s/%s files", kb.Terminal.Visual, kb.Terminal.NextLocation, kb.Terminal.PrevLocation)
+		if m.output != nil {
+			helpText += fmt.Sprintf(" • %s save", kb.Terminal.Save)
+		}
+		if m.Dir != "" {
+			helpText += fmt.Sprintf(" • %s run again", kb.Terminal.Rerun)
+		}
+	}
 	footer := styles.Help.Render(scrollInfo + " │ " + helpText)
+	if m.jumping {
+		footer = styles.Cursor.Render(" "+m.jump.String()) + styles.Help.Render(fmt.Sprintf(" │ %s open in editor • %s/%s next/previous • %s close",
+			kb.Terminal.Open, kb.Terminal.NextLocation, kb.Terminal.PrevLocation, kb.Global.Quit))
+	}
+	if m.Visual {
+		start, end := m.selection()
+		footer = styles.Confirm.Render(" VISUAL ") + styles.Help.Render(fmt.Sprintf("%s │ %s/%s extend • %s copy • %s stop selecting",
+			lineCount(end-start+1), kb.Global.MoveUp, kb.Global.MoveDown, kb.Terminal.Copy, kb.Global.Quit))
+	}
+	if m.Typing {
+		footer = styles.Confirm.Render(" INPUT ") + styles.Help.Render(fmt.Sprintf("│ keys go to the command • esc stop typing • %s cancel", kb.Global.Cancel))
+	}
+	if m.notice != "" {
+		footer = " " + m.notice
+	}
+	if m.Prompt != nil {
+		footer = m.viewPrompt(contentWidth)
+	}
+	if m.fullscreen {
+		// A wrapped footer would push the top of the modal off the screen
+		footer = lipgloss.NewStyle().MaxWidth(contentWidth).Render(footer)
+	}
 
 	// Create the modal box
 	borderStyle := lipgloss.NewStyle().
@@ -462,6 +1425,27 @@ func (m Model) View() string {
 	return borderStyle.Render(box)
 }
 
+// viewPrompt renders the credential prompt in place of the footer.
+// Secret answers are not echoed, only counted.
+func (m Model) viewPrompt(width int) string {
+	input := m.Input
+	if m.Prompt.Secret {
+		input = strings.Repeat("•", len([]rune(m.Input)))
+	}
+	text := runewidth.Truncate(m.Prompt.Text, width, "...")
+	return lipgloss.JoinVertical(lipgloss.Left,
+		styles.Confirm.Render(text),
+		styles.Prompt.Render("> ")+styles.Input.Render(input+"█"),
+		styles.Help.Render("enter send • esc cancel"),
+	)
+}
+
+// truncate cuts line to width columns, ending it with "..." if it was
+// longer. Wide characters, such as CJK, take two columns.
+func truncate(line string, width int) string {
+	return runewidth.Truncate(line, width, "...")
+}
+
 // ViewCentered renders the terminal modal centered on screen.
 func (m Model) ViewCentered(screenWidth, screenHeight int) string {
 	modal := m.View()
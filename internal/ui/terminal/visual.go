@@ -0,0 +1,116 @@
+package terminal
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ihatemodels/gdev/internal/clipboard"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+)
+
+// selectedLine highlights the lines of a visual selection.
+var selectedLine = lipgloss.NewStyle().Reverse(true)
+
+// Selecting reports whether lines are being selected in visual mode.
+// Keys must then reach the terminal, even ones that would close it.
+func (m Model) Selecting() bool {
+	return m.Visual
+}
+
+// startVisual starts selecting at the last line on screen.
+func (m Model) startVisual() Model {
+	if len(m.Lines) == 0 {
+		return m
+	}
+	m.Visual = true
+	m.AutoScroll = false
+	m.SelStart = min(m.ScrollPos+m.visibleLines()-1, len(m.Lines)-1)
+	m.SelEnd = m.SelStart
+	return m
+}
+
+// handleVisualKey moves the end of the selection with the movement keys and
+// copies the selected lines.
+func (m Model) handleVisualKey(msg tea.KeyMsg) (Model, tea.Cmd) {
+	key := msg.String()
+	kb := m.Config.Keys()
+
+	switch {
+	case config.MatchesAny(key, kb.Global.Quit, kb.Global.QuitAlt, kb.Terminal.Visual):
+		m.Visual = false
+	case config.MatchesAny(key, kb.Global.MoveUp, kb.Global.MoveUpAlt) || msg.Type == tea.KeyUp:
+		m.moveSelection(-1)
+	case config.MatchesAny(key, kb.Global.MoveDown, kb.Global.MoveDownAlt) || msg.Type == tea.KeyDown:
+		m.moveSelection(1)
+	case config.Matches(key, kb.List.PageUp):
+		m.moveSelection(-m.visibleLines())
+	case config.Matches(key, kb.List.PageDown):
+		m.moveSelection(m.visibleLines())
+	case config.Matches(key, kb.List.Top):
+		m.moveSelection(-len(m.Lines))
+	case config.Matches(key, kb.List.Bottom):
+		m.moveSelection(len(m.Lines))
+	case config.Matches(key, kb.Terminal.Copy):
+		start, end := m.selection()
+		m.copyLines(m.Lines[start : end+1])
+		m.Visual = false
+	}
+	return m, nil
+}
+
+// moveSelection moves the end of the selection by delta lines, scrolling to
+// keep it on screen.
+func (m *Model) moveSelection(delta int) {
+	m.SelEnd = max(min(m.SelEnd+delta, len(m.Lines)-1), 0)
+	if m.SelEnd < m.ScrollPos {
+		m.ScrollPos = m.SelEnd
+	}
+	if m.SelEnd >= m.ScrollPos+m.visibleLines() {
+		m.ScrollPos = m.SelEnd - m.visibleLines() + 1
+	}
+}
+
+// selection returns the first and last selected line. The buffer may have
+// been trimmed since the selection started, so both are kept in range.
+func (m Model) selection() (start, end int) {
+	start, end = m.SelStart, m.SelEnd
+	if start > end {
+		start, end = end, start
+	}
+	last := len(m.Lines) - 1
+	return max(min(start, last), 0), max(min(end, last), 0)
+}
+
+// inSelection reports whether line i is selected.
+func (m Model) inSelection(i int) bool {
+	if !m.Visual {
+		return false
+	}
+	start, end := m.selection()
+	return i >= start && i <= end
+}
+
+// copyLines puts lines on the clipboard without their colors and reports how
+// it went in the footer.
+func (m *Model) copyLines(lines []string) {
+	plain := make([]string, len(lines))
+	for i, line := range lines {
+		plain[i] = ansiEscape.ReplaceAllString(line, "")
+	}
+	if err := clipboard.Copy(strings.Join(plain, "\n")); err != nil {
+		m.notice = styles.Error.Render("✗ Copy failed: " + err.Error())
+		return
+	}
+	m.notice = styles.Selected.Render(fmt.Sprintf("✓ Copied %s", lineCount(len(lines))))
+}
+
+// lineCount formats a number of lines, e.g. "2 lines".
+func lineCount(n int) string {
+	if n == 1 {
+		return "1 line"
+	}
+	return fmt.Sprintf("%d lines", n)
+}
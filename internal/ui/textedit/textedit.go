@@ -0,0 +1,93 @@
+// Package textedit edits text at a cursor. The cursor is a byte offset into
+// the text that is always on a rune boundary, so text can be sliced at it
+// and multibyte characters are typed, moved over and deleted whole.
+package textedit
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Typed returns the printable text msg types, such as "ä" or a pasted
+// line, and whether it types any.
+func Typed(msg tea.KeyMsg) (string, bool) {
+	switch {
+	case msg.Type == tea.KeySpace:
+		return " ", true
+	case msg.Type != tea.KeyRunes || msg.Alt:
+		return "", false
+	}
+	text := strings.Map(func(r rune) rune {
+		if !unicode.IsPrint(r) {
+			return -1
+		}
+		return r
+	}, string(msg.Runes))
+	return text, text != ""
+}
+
+// Insert inserts s at cursor and returns the text with the cursor after s.
+func Insert(text string, cursor int, s string) (string, int) {
+	return text[:cursor] + s + text[cursor:], cursor + len(s)
+}
+
+// Backspace deletes the character before cursor.
+func Backspace(text string, cursor int) (string, int) {
+	prev := Left(text, cursor)
+	return text[:prev] + text[cursor:], prev
+}
+
+// Delete deletes the character at cursor.
+func Delete(text string, cursor int) string {
+	return text[:cursor] + text[Right(text, cursor):]
+}
+
+// Left returns the cursor moved back a character.
+func Left(text string, cursor int) int {
+	if cursor <= 0 {
+		return 0
+	}
+	_, size := utf8.DecodeLastRuneInString(text[:cursor])
+	return cursor - size
+}
+
+// Right returns the cursor moved forward a character.
+func Right(text string, cursor int) int {
+	if cursor >= len(text) {
+		return len(text)
+	}
+	_, size := utf8.DecodeRuneInString(text[cursor:])
+	return cursor + size
+}
+
+// LineStart returns the start of the line cursor is on.
+func LineStart(text string, cursor int) int {
+	return strings.LastIndexByte(text[:cursor], '\n') + 1
+}
+
+// LineEnd returns the end of the line cursor is on.
+func LineEnd(text string, cursor int) int {
+	if i := strings.IndexByte(text[cursor:], '\n'); i >= 0 {
+		return cursor + i
+	}
+	return len(text)
+}
+
+// Column returns how many characters cursor is from the start of its line.
+func Column(text string, cursor int) int {
+	return utf8.RuneCountInString(text[LineStart(text, cursor):cursor])
+}
+
+// AtColumn returns the cursor col characters into the line that starts at
+// start, or at the line's end if it is shorter.
+func AtColumn(text string, start, col int) int {
+	end := LineEnd(text, start)
+	cursor := start
+	for ; col > 0 && cursor < end; col-- {
+		cursor = Right(text, cursor)
+	}
+	return cursor
+}
@@ -0,0 +1,59 @@
+package textedit
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestEdit_Multibyte(t *testing.T) {
+	text, cursor := "", 0
+	for _, r := range "añ\n漢é" {
+		if r == '\n' {
+			text, cursor = Insert(text, cursor, "\n")
+			continue
+		}
+		typed, ok := Typed(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		if !ok {
+			t.Fatalf("Typed(%q) typed nothing", r)
+		}
+		text, cursor = Insert(text, cursor, typed)
+	}
+	if text != "añ\n漢é" || cursor != len(text) {
+		t.Fatalf("typed %q at %d", text, cursor)
+	}
+
+	cursor = Left(text, cursor)
+	if Column(text, cursor) != 1 {
+		t.Errorf("Column() = %d, want 1", Column(text, cursor))
+	}
+	text, cursor = Backspace(text, cursor)
+	if text != "añ\né" || cursor != len("añ\n") {
+		t.Errorf("after backspace %q at %d", text, cursor)
+	}
+
+	// Up a line keeps the column, counted in characters
+	cursor = AtColumn(text, 0, Column(text, Right(text, cursor)))
+	if cursor != len("a") {
+		t.Errorf("AtColumn() = %d, want %d", cursor, len("a"))
+	}
+	text = Delete(text, cursor)
+	if text != "a\né" {
+		t.Errorf("after delete %q", text)
+	}
+	if LineEnd(text, 0) != 1 || LineStart(text, len(text)) != 2 {
+		t.Errorf("LineEnd() = %d, LineStart() = %d", LineEnd(text, 0), LineStart(text, len(text)))
+	}
+}
+
+func TestTyped_SkipsControls(t *testing.T) {
+	if _, ok := Typed(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}, Alt: true}); ok {
+		t.Error("Typed(alt+x) typed text")
+	}
+	if got, _ := Typed(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a\tb\x1b")}); got != "ab" {
+		t.Errorf("Typed() = %q, want %q", got, "ab")
+	}
+	if got, _ := Typed(tea.KeyMsg{Type: tea.KeySpace}); got != " " {
+		t.Errorf("Typed(space) = %q", got)
+	}
+}
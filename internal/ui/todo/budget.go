@@ -0,0 +1,59 @@
+package todo
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/ai"
+	"github.com/ihatemodels/gdev/internal/budget"
+	"github.com/ihatemodels/gdev/internal/store"
+	"github.com/ihatemodels/gdev/internal/ui/confirm"
+)
+
+// budgetAllows reports whether requests to backend, cached under keys, may
+// go ahead. Past the budget of a provider backend may ask it asks first
+// (confirm.OverBudget), and once confirmed calls retry, which sends them
+// without asking again. Requests whose responses are all cached cost
+// nothing and always go ahead.
+func (m Model) budgetAllows(backend ai.Backend, keys []store.CacheKey, retry func(Model) (tea.Model, tea.Cmd)) (Model, bool) {
+	cached := true
+	for _, key := range keys {
+		if _, ok := m.Config.CachedResponse(key); !ok {
+			cached = false
+		}
+	}
+	if cached {
+		return m, true
+	}
+	status, err := budget.ForBackend(m.Config, backend)
+	if err != nil || status.Level != budget.Over {
+		// An unreadable usage log doesn't stop the work
+		return m, true
+	}
+	m.confirmOverBudget(status, retry)
+	return m, false
+}
+
+// confirmOverBudget asks before going past the budget of status, and calls
+// retry once confirmed.
+func (m *Model) confirmOverBudget(status budget.Status, retry func(Model) (tea.Model, tea.Cmd)) {
+	m.Confirm = confirm.OverBudget(m.Config, status)
+	m.OnBudgetConfirm = retry
+	m.Views.Push(BudgetConfirmView)
+}
+
+// UpdateBudgetConfirm handles input for the over-budget confirmation.
+func (m Model) UpdateBudgetConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var res confirm.Result
+	m.Confirm, res = m.Confirm.Update(msg)
+
+	switch res {
+	case confirm.Confirmed:
+		m.Views.Pop()
+		retry := m.OnBudgetConfirm
+		m.OnBudgetConfirm = nil
+		return retry(m)
+	case confirm.Canceled:
+		m.Views.Pop()
+		m.OnBudgetConfirm = nil
+	}
+	return m, nil
+}
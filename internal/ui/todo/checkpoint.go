@@ -0,0 +1,195 @@
+package todo
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/git"
+	"github.com/ihatemodels/gdev/internal/ui/confirm"
+	"github.com/ihatemodels/gdev/internal/ui/diffview"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+	"github.com/ihatemodels/gdev/internal/ui/terminal"
+)
+
+// checkpoint is the working tree recorded during a run, see git.Checkpoint.
+type checkpoint struct {
+	Label  string // when it was taken, e.g. "after prompt 2 (done)"
+	Commit string
+}
+
+// checkpointRef returns the ref the checkpoints of the run started at
+// started are kept under. Each checkpoint is the parent of the next, so the
+// ref keeps them all from being garbage collected.
+func checkpointRef(started time.Time) string {
+	return "refs/gdev/checkpoints/" + started.UTC().Format("20060102-150405.000")
+}
+
+// checkpointing reports whether the run records checkpoints. Dry runs
+// and read-only mode don't, as their prompts change nothing.
+func (m Model) checkpointing() bool {
+	return m.Config.Settings.TodoCheckpoints && !m.Config.DryRun() && !m.Config.ReadOnly()
+}
+
+// checkpoint records the working tree as a checkpoint of the run. Once one
+// fails no more are taken, so the ones listed never skip a step.
+func (m *Model) checkpoint(label string) {
+	if !m.checkpointing() || m.RunCheckpointErr != nil {
+		return
+	}
+	var parent string
+	if n := len(m.RunCheckpoints); n > 0 {
+		parent = m.RunCheckpoints[n-1].Commit
+	}
+	commit, err := git.Checkpoint(m.RepoPath, checkpointRef(m.RunStarted),
+		fmt.Sprintf("gdev checkpoint: %s, %s", m.RunTodo.Name, label), parent)
+	if err != nil {
+		m.RunCheckpointErr = err
+		return
+	}
+	m.RunCheckpoints = append(m.RunCheckpoints, checkpoint{Label: label, Commit: commit})
+}
+
+// openCheckpointDiff shows the changes made to the working tree since the
+// selected checkpoint, which rolling back to it undoes.
+func (m Model) openCheckpointDiff() (tea.Model, tea.Cmd) {
+	c := m.Summary.Checkpoints[m.Summary.Cursor]
+	now, err := git.Snapshot(m.RepoPath)
+	if err != nil {
+		m.ErrMsg = "Couldn't record the working tree: " + err.Error()
+		return m, nil
+	}
+	m.DiffView = diffview.New(m.Config, "Changes since "+c.Label, m.RepoPath, c.Commit, now)
+	m.DiffView.SetSize(m.Width-4, m.Height-4)
+	m.Views.Push(CheckpointDiffView)
+	return m, m.DiffView.Init()
+}
+
+// UpdateCheckpointDiff handles input for the changes since a checkpoint.
+func (m Model) UpdateCheckpointDiff(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	var res diffview.Result
+	m.DiffView, cmd, res = m.DiffView.Update(msg)
+	if res == diffview.Closed {
+		m.Views.Pop()
+	}
+	return m, cmd
+}
+
+// openRollback asks before rolling the working tree back to the selected
+// checkpoint.
+func (m *Model) openRollback() {
+	c := m.Summary.Checkpoints[m.Summary.Cursor]
+	m.Confirm = confirm.New(m.Config, "Roll back to this checkpoint?",
+		fmt.Sprintf("%s %s\nChanges to the working tree since are undone. It is recorded as a\ncheckpoint first, so you can roll forward again.", c.Commit[:7], c.Label))
+	m.Confirm.Destructive = true
+	m.Views.Push(RollbackConfirmView)
+}
+
+// UpdateRollbackConfirm handles input for the rollback confirmation.
+func (m Model) UpdateRollbackConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var res confirm.Result
+	m.Confirm, res = m.Confirm.Update(msg)
+
+	switch res {
+	case confirm.Confirmed:
+		m.Views.Pop()
+		return m.rollback()
+	case confirm.Canceled:
+		m.Views.Pop()
+	}
+	return m, nil
+}
+
+// rollback makes the working tree match the selected checkpoint: files are
+// restored from it and those added since are deleted. The index is left
+// alone. The working tree is recorded as a checkpoint first.
+func (m Model) rollback() (tea.Model, tea.Cmd) {
+	c := m.Summary.Checkpoints[m.Summary.Cursor]
+	name := m.Summary.Todo
+	if !m.Config.DryRun() {
+		last := m.Summary.Checkpoints[len(m.Summary.Checkpoints)-1]
+		label := "before rolling back to " + c.Label
+		backup, err := git.Checkpoint(m.RepoPath, checkpointRef(m.Summary.Started),
+			fmt.Sprintf("gdev checkpoint: %s, %s", name, label), last.Commit)
+		if err != nil {
+			m.ErrMsg = "Couldn't record the working tree, nothing was rolled back: " + err.Error()
+			return m, nil
+		}
+		m.Summary.Checkpoints = append(slices.Clone(m.Summary.Checkpoints), checkpoint{Label: label, Commit: backup})
+	}
+
+	commands, err := rollbackCommands(m.RepoPath, c.Commit)
+	if err != nil {
+		m.ErrMsg = "Couldn't tell which files were added since: " + err.Error()
+		return m, nil
+	}
+
+	m.openTerminal("Roll back to "+c.Label, terminalJob{Kind: jobRollback, Checkpoint: c, Todo: name})
+	return m, m.Terminal.RunMutatingCommands(nil, "", commands...)
+}
+
+// rollbackCommands returns the git commands making the working tree match
+// commit: restoring its files, then deleting those added since. Paths are
+// literal, so a file named like a glob deletes only itself.
+func rollbackCommands(repoRoot, commit string) ([][]string, error) {
+	now, err := git.Snapshot(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	added, err := git.AddedFiles(repoRoot, commit, now)
+	if err != nil {
+		return nil, err
+	}
+	commands := [][]string{{"git", "--literal-pathspecs", "restore", "--source=" + commit, "--worktree", "--", "."}}
+	if len(added) > 0 {
+		// Files the index tracks are gone already; the rest are untracked
+		commands = append(commands, append([]string{"git", "--literal-pathspecs", "clean", "-f", "-q", "--"}, added...))
+	}
+	return commands, nil
+}
+
+// finishRollback records a rollback that went through in the activity of
+// the todo once its terminal is closed.
+func (m Model) finishRollback(job terminalJob, res terminal.CommandDoneMsg) (tea.Model, tea.Cmd) {
+	if res.Err != nil || m.Config.DryRun() {
+		return m, nil
+	}
+	c := job.Checkpoint
+	return m, m.saveActivity("Rolled back to "+c.Label,
+		fmt.Sprintf("# Rolled back to %s: %s\n\nThe working tree was restored from checkpoint `%s`.\n", c.Label, job.Todo, c.Commit))
+}
+
+// viewCheckpoints lists the checkpoints of the run summary.
+func (s runSummary) viewCheckpoints() string {
+	var b strings.Builder
+	b.WriteString(styles.Label.Render("Checkpoints:"))
+	b.WriteString("\n")
+	for i, c := range s.Checkpoints {
+		if i == s.Cursor {
+			b.WriteString(styles.Cursor.Render("▸ ") + styles.Help.Render(c.Commit[:7]) + " " + styles.Selected.Render(c.Label))
+		} else {
+			b.WriteString("  " + styles.Help.Render(c.Commit[:7]) + " " + styles.Value.Render(c.Label))
+		}
+		b.WriteString("\n")
+	}
+	if s.CheckpointErr != nil {
+		b.WriteString("  " + styles.Error.Render("No more checkpoints: "+s.CheckpointErr.Error()))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// markdownCheckpoints lists the checkpoints in markdown.
+func (s runSummary) markdownCheckpoints() string {
+	var b strings.Builder
+	for _, c := range s.Checkpoints {
+		fmt.Fprintf(&b, "- `%s` %s\n", c.Commit, c.Label)
+	}
+	if s.CheckpointErr != nil {
+		fmt.Fprintf(&b, "- No more checkpoints: %s\n", s.CheckpointErr)
+	}
+	return b.String()
+}
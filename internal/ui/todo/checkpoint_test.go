@@ -0,0 +1,82 @@
+package todo
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ihatemodels/gdev/internal/git"
+)
+
+// scratchRepo returns a new repository with tracked.txt committed.
+func scratchRepo(t *testing.T) string {
+	t.Helper()
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "gdev")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gdev@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gdev")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gdev@example.com")
+
+	dir := t.TempDir()
+	writeFile(t, dir, "tracked.txt", "one")
+	for _, args := range [][]string{{"init", "-q"}, {"add", "tracked.txt"}, {"commit", "-q", "-m", "first"}} {
+		if out, err := gitIn(dir, args...); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+	return dir
+}
+
+func gitIn(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	return cmd.CombinedOutput()
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRollbackCommands(t *testing.T) {
+	dir := scratchRepo(t)
+	writeFile(t, dir, "keep.log", "untracked at the checkpoint")
+	commit, err := git.Checkpoint(dir, "refs/gdev/checkpoints/test", "checkpoint", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, dir, "tracked.txt", "two")
+	writeFile(t, dir, "keep.log", "changed since")
+	writeFile(t, dir, "*.log", "added since")
+	writeFile(t, dir, "added.txt", "added since")
+
+	commands, err := rollbackCommands(dir, commit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range commands {
+		cmd := exec.Command(c[0], c[1:]...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("%s: %v\n%s", strings.Join(c, " "), err, out)
+		}
+	}
+
+	for name, want := range map[string]string{"tracked.txt": "one", "keep.log": "untracked at the checkpoint"} {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil || string(got) != want {
+			t.Errorf("%s = %q, %v, want %q", name, got, err, want)
+		}
+	}
+	for _, name := range []string{"*.log", "added.txt"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("%s added since the checkpoint is still there", name)
+		}
+	}
+}
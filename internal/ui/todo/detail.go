@@ -3,10 +3,17 @@ package todo
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/claude"
 	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/todo"
+	"github.com/ihatemodels/gdev/internal/ui/confirm"
+	"github.com/ihatemodels/gdev/internal/ui/failure"
+	"github.com/ihatemodels/gdev/internal/ui/markdown"
 	"github.com/ihatemodels/gdev/internal/ui/styles"
+	"github.com/mattn/go-runewidth"
 )
 
 // UpdateDetailView handles input for the detail view.
@@ -16,7 +23,7 @@ func (m Model) UpdateDetailView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	// Handle back/quit
 	if config.MatchesAny(key, kb.Global.Quit, kb.Global.QuitAlt, kb.Detail.Back) {
-		m.CurrentView = ListView
+		m.Views.Pop()
 		m.SelectedTodo = nil
 		return m, nil
 	}
@@ -54,71 +61,69 @@ func (m Model) UpdateDetailView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	case config.Matches(key, kb.Detail.Edit):
 		if m.SelectedTodo != nil {
-			m.FormEditingTodo = m.SelectedTodo
-			m.FormBranch = m.SelectedTodo.Branch
-			m.FormName = m.SelectedTodo.Name
-			m.FormDescription = m.SelectedTodo.Description
-			m.FormPrompts = make([]string, len(m.SelectedTodo.Prompts))
-			copy(m.FormPrompts, m.SelectedTodo.Prompts)
-			if len(m.FormPrompts) == 0 {
-				m.FormPrompts = []string{""}
-			}
-			m.FormField = FieldBranch
-			m.FormPromptIdx = 0
-			m.CurrentView = EditView
+			m.openEditForm(m.SelectedTodo)
 		}
 
 	case config.Matches(key, kb.Detail.Delete):
 		if m.SelectedTodo != nil {
-			m.DeleteTarget = m.SelectedTodo
-			m.CurrentView = DeleteConfirmView
+			m.openDeleteConfirm(m.SelectedTodo)
+		}
+
+	case config.Matches(key, kb.Detail.Run):
+		if m.SelectedTodo != nil {
+			return m.startRun(m.SelectedTodo)
+		}
+
+	case config.Matches(key, kb.Detail.Subtask):
+		if m.SelectedTodo != nil && len(m.SelectedTodo.Subtasks) > 0 {
+			m.SubtaskCursor = (m.SubtaskCursor + 1) % len(m.SelectedTodo.Subtasks)
+		}
+
+	case config.Matches(key, kb.Detail.Toggle):
+		if m.SelectedTodo != nil && m.SubtaskCursor < len(m.SelectedTodo.Subtasks) {
+			return m, m.toggleSubtask(m.SubtaskCursor)
 		}
 	}
 
 	return m, nil
 }
 
+// SubtasksSavedMsg is sent once a subtask toggled in the detail view is
+// saved.
+type SubtasksSavedMsg struct{}
+
+// openDetail shows the details of t.
+func (m *Model) openDetail(t todo.Todo) {
+	m.SelectedTodo = &t
+	m.SubtaskCursor = 0
+	m.DetailScroll = 0
+	m.Views.Push(DetailView)
+}
+
+// toggleSubtask marks the selected todo's subtask at idx done, or not done,
+// and saves it. The details stay open.
+func (m Model) toggleSubtask(idx int) tea.Cmd {
+	m.SelectedTodo.ToggleSubtask(idx)
+	t := *m.SelectedTodo
+	return failure.Cmd("Update subtask", func() (tea.Msg, error) {
+		if err := m.Store.UpdateTodo(m.RepoPath, &t); err != nil {
+			return nil, err
+		}
+		return SubtasksSavedMsg{}, nil
+	})
+}
+
 // ViewDetail renders the detail view.
 func (m Model) ViewDetail() string {
 	if m.SelectedTodo == nil {
 		return ""
 	}
-	t := m.SelectedTodo
-
-	var lines []string
-
-	lines = append(lines, styles.Title.Render("  TODO Details"))
-	lines = append(lines, styles.Help.Render("─────────────────────────────────────────────────────"))
-	lines = append(lines, "")
-
-	lines = append(lines, styles.Label.Render("Name: ")+styles.Value.Render(t.Name))
-	lines = append(lines, styles.Label.Render("Branch: ")+styles.Branch.Render(" "+t.Branch))
-	lines = append(lines, "")
-
-	lines = append(lines, styles.Label.Render("Description:"))
-	if t.Description != "" {
-		descLines := strings.Split(t.Description, "\n")
-		for _, dl := range descLines {
-			lines = append(lines, "  "+styles.Value.Render(dl))
-		}
-	} else {
-		lines = append(lines, "  "+styles.Help.Render("(no description)"))
-	}
-	lines = append(lines, "")
-
-	lines = append(lines, styles.Label.Render("Prompts:"))
-	if len(t.Prompts) == 0 {
-		lines = append(lines, "  "+styles.Help.Render("(no prompts)"))
-	} else {
-		for i, p := range t.Prompts {
-			lines = append(lines, "")
-			lines = append(lines, styles.Prompt.Render(fmt.Sprintf("  ─── Prompt %d ───", i+1)))
-			promptLines := strings.Split(p, "\n")
-			for _, pl := range promptLines {
-				lines = append(lines, "  "+styles.Value.Render(pl))
-			}
-		}
+	lines := []string{
+		styles.Title.Render("  TODO Details"),
+		styles.Help.Render("─────────────────────────────────────────────────────"),
+		"",
 	}
+	lines = append(lines, detailLines(m.SelectedTodo, m.Width-4, m.SubtaskCursor)...)
 
 	visibleLines := m.Height - 8
 	if visibleLines < 5 {
@@ -164,27 +169,160 @@ func (m Model) ViewDetail() string {
 	b.WriteString(styles.Help.Render(fmt.Sprintf("↑/%s ↓/%s scroll • %s/%s top/bottom • %s/%s page",
 		kb.Detail.ScrollUp, kb.Detail.ScrollDown, kb.List.Top, kb.List.Bottom, kb.List.PageUp, kb.List.PageDown)))
 	b.WriteString("\n")
-	b.WriteString(styles.Help.Render(fmt.Sprintf("%s edit • %s delete • %s back",
-		kb.Detail.Edit, kb.Detail.Delete, kb.Detail.Back)))
+	if len(m.SelectedTodo.Subtasks) > 0 {
+		b.WriteString(styles.Help.Render(fmt.Sprintf("%s next subtask • %s done/not done • ", kb.Detail.Subtask, kb.Detail.Toggle)))
+	}
+	b.WriteString(styles.Help.Render(fmt.Sprintf("%s edit • %s delete • %s run prompts • %s back",
+		kb.Detail.Edit, kb.Detail.Delete, kb.Detail.Run, kb.Detail.Back)))
 
 	return b.String()
 }
 
+// detailLines renders the fields of t, one line each, with the markdown of
+// the description and prompts wrapped to width. The subtask at cursor is
+// marked as selected; -1 marks none.
+func detailLines(t *todo.Todo, width, cursor int) []string {
+	var lines []string
+	lines = append(lines, styles.Label.Render("Name: ")+styles.Value.Render(t.Name))
+	lines = append(lines, styles.Label.Render("Branch: ")+styles.Branch.Render(" "+t.Branch))
+	lines = append(lines, styles.Label.Render("Status: ")+statusMark(t.Status)+" "+styles.Value.Render(t.Status.String()))
+	if t.DueDate != nil {
+		due := t.DueDate.Format("Mon, Jan 2 2006")
+		if t.IsOverdue(time.Now()) {
+			lines = append(lines, styles.Label.Render("Due: ")+styles.Error.Render(due+" (overdue)"))
+		} else {
+			lines = append(lines, styles.Label.Render("Due: ")+styles.Value.Render(due))
+		}
+	}
+	if t.Priority != todo.PriorityNone {
+		lines = append(lines, styles.Label.Render("Priority: ")+priorityMark(t.Priority))
+	}
+	if t.Model != "" {
+		lines = append(lines, styles.Label.Render("Model: ")+styles.Value.Render(t.Model))
+	}
+	if t.Usage != nil {
+		lines = append(lines, styles.Label.Render("AI usage: ")+styles.Value.Render(fmt.Sprintf("%s tokens • %s in %s",
+			claude.FormatTokens(t.Usage.Tokens), claude.FormatCost(t.Usage.Cost), runCount(t.Usage.Runs))))
+	}
+	if t.IsSnoozed(time.Now()) {
+		lines = append(lines, styles.Label.Render("Snoozed until: ")+styles.Help.Render(t.SnoozedUntil.Format("Mon, Jan 2 2006")))
+	}
+	lines = append(lines, "")
+
+	lines = append(lines, styles.Label.Render("Description:"))
+	if t.Description != "" {
+		for _, dl := range markdown.Render(t.Description, width-2) {
+			lines = append(lines, "  "+dl)
+		}
+	} else {
+		lines = append(lines, "  "+styles.Help.Render("(no description)"))
+	}
+	lines = append(lines, "")
+
+	if len(t.Subtasks) > 0 {
+		done, total := t.Progress()
+		lines = append(lines, styles.Label.Render("Subtasks: ")+progressMark(done, total))
+		for i, s := range t.Subtasks {
+			line := "  "
+			if i == cursor {
+				line = styles.Cursor.Render("▸ ")
+			}
+			text := runewidth.Truncate(s.Text, width-6, "…")
+			if s.Done {
+				line += styles.Selected.Render("✓ ") + styles.Dim.Render(text)
+			} else {
+				line += styles.Help.Render("○ ") + styles.Value.Render(text)
+			}
+			lines = append(lines, line)
+		}
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, styles.Label.Render("Prompts:"))
+	if len(t.Prompts) == 0 {
+		lines = append(lines, "  "+styles.Help.Render("(no prompts)"))
+	} else {
+		for i, p := range t.Prompts {
+			lines = append(lines, "")
+			header := styles.Prompt.Render(fmt.Sprintf("  ─── Prompt %d ───", i+1))
+			last, runs := t.LastExecution(p)
+			if last != nil {
+				header += " " + executionMark(last, runs)
+			}
+			lines = append(lines, header)
+			for _, pl := range markdown.Render(p, width-2) {
+				lines = append(lines, "  "+pl)
+			}
+			if last != nil && last.Output != "" {
+				lines = append(lines, "  "+styles.Label.Render("Last output:"))
+				for _, ol := range tailLines(last.Output, executionOutputLines) {
+					lines = append(lines, "    "+styles.Help.Render(runewidth.Truncate(ol, width-4, "…")))
+				}
+			}
+		}
+	}
+
+	if len(t.Activity) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, styles.Label.Render("Activity:"))
+		for i := len(t.Activity) - 1; i >= 0; i-- {
+			a := t.Activity[i]
+			lines = append(lines, "  "+styles.Help.Render(a.At.Format("Jan 2 15:04"))+"  "+styles.Value.Render(a.Title))
+		}
+	}
+	return lines
+}
+
+// executionOutputLines is how many lines of a prompt's last output the
+// details show.
+const executionOutputLines = 6
+
+// executionMark renders how the last run of a prompt ended, when, and how
+// many times it ran.
+func executionMark(e *todo.Execution, runs int) string {
+	text := fmt.Sprintf("%s %s", e.Status, e.At.Format("Jan 2 15:04"))
+	if runs > 1 {
+		text += fmt.Sprintf(" • ran %d times", runs)
+	}
+	if e.Status == runDone.String() {
+		return styles.Selected.Render("✓ " + text)
+	}
+	return styles.Error.Render("✗ " + text)
+}
+
+// tailLines returns the last n lines of s.
+func tailLines(s string, n int) []string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	return lines[max(len(lines)-n, 0):]
+}
+
+// openDeleteConfirm asks for confirmation before deleting t.
+func (m *Model) openDeleteConfirm(t *todo.Todo) {
+	m.DeleteTarget = t
+	m.Confirm = confirm.New(m.Config, "Delete TODO?",
+		fmt.Sprintf("\"%s\"\n %s", t.Name, t.Branch))
+	m.Confirm.Destructive = true
+	m.Views.Push(DeleteConfirmView)
+}
+
 // UpdateDeleteConfirmView handles input for the delete confirmation view.
 func (m Model) UpdateDeleteConfirmView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "y", "Y":
+	var res confirm.Result
+	m.Confirm, res = m.Confirm.Update(msg)
+
+	switch res {
+	case confirm.Confirmed:
 		if m.DeleteTarget != nil {
 			target := m.DeleteTarget
-			return m, func() tea.Msg {
+			return m, failure.Cmd("Delete TODO", func() (tea.Msg, error) {
 				if err := m.Store.DeleteTodo(m.RepoPath, target.ID); err != nil {
-					return TodoErrorMsg{Err: err}
+					return nil, err
 				}
-				return TodoDeletedMsg{}
-			}
+				return TodoDeletedMsg{}, nil
+			})
 		}
-	case "n", "N", "esc":
-		m.CurrentView = ListView
+	case confirm.Canceled:
+		m.Views.Pop()
 		m.DeleteTarget = nil
 	}
 	return m, nil
@@ -192,19 +330,5 @@ func (m Model) UpdateDeleteConfirmView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 // ViewDeleteConfirm renders the delete confirmation view.
 func (m Model) ViewDeleteConfirm() string {
-	var b strings.Builder
-
-	b.WriteString(styles.Confirm.Render("  Delete TODO?"))
-	b.WriteString("\n\n")
-
-	if m.DeleteTarget != nil {
-		b.WriteString(styles.Value.Render(fmt.Sprintf("  \"%s\"", m.DeleteTarget.Name)))
-		b.WriteString("\n")
-		b.WriteString(styles.Branch.Render(fmt.Sprintf("   %s", m.DeleteTarget.Branch)))
-	}
-
-	b.WriteString("\n\n")
-	b.WriteString(styles.Help.Render("y confirm • n cancel"))
-
-	return b.String()
+	return m.Confirm.View()
 }
@@ -5,8 +5,12 @@ import (
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/ui/markdown"
 	"github.com/ihatemodels/gdev/internal/ui/styles"
+	"github.com/ihatemodels/gdev/internal/ui/textedit"
+	"github.com/mattn/go-runewidth"
 )
 
 // UpdatePromptEditor handles input for the prompt editor view.
@@ -23,76 +27,56 @@ func (m Model) UpdatePromptEditor(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.EditorCursorPos = m.moveCursorVertical(1)
 		return m, nil
 	case tea.KeyLeft:
-		if m.EditorCursorPos > 0 {
-			m.EditorCursorPos--
-		}
+		m.EditorCursorPos = textedit.Left(m.EditorContent, m.EditorCursorPos)
 		return m, nil
 	case tea.KeyRight:
-		if m.EditorCursorPos < len(m.EditorContent) {
-			m.EditorCursorPos++
-		}
+		m.EditorCursorPos = textedit.Right(m.EditorContent, m.EditorCursorPos)
 		return m, nil
 	case tea.KeyHome:
-		for m.EditorCursorPos > 0 && m.EditorContent[m.EditorCursorPos-1] != '\n' {
-			m.EditorCursorPos--
-		}
+		m.EditorCursorPos = textedit.LineStart(m.EditorContent, m.EditorCursorPos)
 		return m, nil
 	case tea.KeyEnd:
-		for m.EditorCursorPos < len(m.EditorContent) && m.EditorContent[m.EditorCursorPos] != '\n' {
-			m.EditorCursorPos++
-		}
+		m.EditorCursorPos = textedit.LineEnd(m.EditorContent, m.EditorCursorPos)
 		return m, nil
 	}
 
 	// Handle configurable keybindings
 	switch {
 	case config.Matches(key, kb.Editor.Cancel):
-		m.CurrentView = m.PreviousView
+		m.Views.Pop()
 		return m, nil
 
 	case config.Matches(key, kb.Editor.Save):
 		m.FormPrompts[m.FormPromptIdx] = m.EditorContent
-		m.CurrentView = m.PreviousView
+		m.Views.Pop()
+		return m, nil
+
+	case config.Matches(key, kb.Editor.Preview):
+		m.EditorPreview = !m.EditorPreview
 		return m, nil
 
 	case config.Matches(key, kb.Editor.NewLine):
-		m.EditorContent = m.EditorContent[:m.EditorCursorPos] + "\n" + m.EditorContent[m.EditorCursorPos:]
-		m.EditorCursorPos++
+		m.EditorContent, m.EditorCursorPos = textedit.Insert(m.EditorContent, m.EditorCursorPos, "\n")
 		return m, nil
 
 	case key == "backspace":
-		if m.EditorCursorPos > 0 {
-			m.EditorContent = m.EditorContent[:m.EditorCursorPos-1] + m.EditorContent[m.EditorCursorPos:]
-			m.EditorCursorPos--
-		}
+		m.EditorContent, m.EditorCursorPos = textedit.Backspace(m.EditorContent, m.EditorCursorPos)
 
 	case key == "delete":
-		if m.EditorCursorPos < len(m.EditorContent) {
-			m.EditorContent = m.EditorContent[:m.EditorCursorPos] + m.EditorContent[m.EditorCursorPos+1:]
-		}
+		m.EditorContent = textedit.Delete(m.EditorContent, m.EditorCursorPos)
 
 	case config.Matches(key, kb.Editor.LineStart):
-		for m.EditorCursorPos > 0 && m.EditorContent[m.EditorCursorPos-1] != '\n' {
-			m.EditorCursorPos--
-		}
+		m.EditorCursorPos = textedit.LineStart(m.EditorContent, m.EditorCursorPos)
 
 	case config.Matches(key, kb.Editor.LineEnd):
-		for m.EditorCursorPos < len(m.EditorContent) && m.EditorContent[m.EditorCursorPos] != '\n' {
-			m.EditorCursorPos++
-		}
-
-	case key == "space":
-		m.EditorContent = m.EditorContent[:m.EditorCursorPos] + " " + m.EditorContent[m.EditorCursorPos:]
-		m.EditorCursorPos++
+		m.EditorCursorPos = textedit.LineEnd(m.EditorContent, m.EditorCursorPos)
 
 	case key == "tab":
-		m.EditorContent = m.EditorContent[:m.EditorCursorPos] + "    " + m.EditorContent[m.EditorCursorPos:]
-		m.EditorCursorPos += 4
+		m.EditorContent, m.EditorCursorPos = textedit.Insert(m.EditorContent, m.EditorCursorPos, "    ")
 
 	default:
-		if len(key) == 1 && key[0] >= 32 && key[0] < 127 {
-			m.EditorContent = m.EditorContent[:m.EditorCursorPos] + key + m.EditorContent[m.EditorCursorPos:]
-			m.EditorCursorPos++
+		if typed, ok := textedit.Typed(msg); ok {
+			m.EditorContent, m.EditorCursorPos = textedit.Insert(m.EditorContent, m.EditorCursorPos, typed)
 		}
 	}
 
@@ -100,39 +84,19 @@ func (m Model) UpdatePromptEditor(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) moveCursorVertical(direction int) int {
-	lines := strings.Split(m.EditorContent, "\n")
-
-	pos := 0
-	currentLine := 0
-	currentCol := 0
-	for i, line := range lines {
-		if pos+len(line) >= m.EditorCursorPos {
-			currentLine = i
-			currentCol = m.EditorCursorPos - pos
-			break
-		}
-		pos += len(line) + 1
-	}
-
-	targetLine := currentLine + direction
-	if targetLine < 0 {
-		targetLine = 0
-	}
-	if targetLine >= len(lines) {
-		targetLine = len(lines) - 1
-	}
+	text, cursor := m.EditorContent, m.EditorCursorPos
+	col := textedit.Column(text, cursor)
+	start := textedit.LineStart(text, cursor)
 
-	newPos := 0
-	for i := 0; i < targetLine; i++ {
-		newPos += len(lines[i]) + 1
-	}
-	targetCol := currentCol
-	if targetCol > len(lines[targetLine]) {
-		targetCol = len(lines[targetLine])
+	switch {
+	case direction < 0 && start > 0:
+		start = textedit.LineStart(text, start-1)
+	case direction > 0:
+		if end := textedit.LineEnd(text, cursor); end < len(text) {
+			start = end + 1
+		}
 	}
-	newPos += targetCol
-
-	return newPos
+	return textedit.AtColumn(text, start, col)
 }
 
 // ViewPromptEditor renders the prompt editor view.
@@ -146,21 +110,27 @@ func (m Model) ViewPromptEditor() string {
 	if editorWidth > 120 {
 		editorWidth = 120
 	}
+	fullWidth := editorWidth
+	if m.EditorPreview {
+		// The text and the preview share the width
+		editorWidth = max((m.Width-12)/2, 30)
+	}
 	editorHeight := m.Height - 12
 	if editorHeight < 10 {
 		editorHeight = 10
 	}
-	contentWidth := editorWidth - 4
+	contentWidth := editorWidth - 2 // inside the borders and their padding
 
 	// Header
 	b.WriteString(styles.Title.Render("  Edit Prompt"))
 	b.WriteString("\n")
-	b.WriteString(styles.Help.Render(strings.Repeat("─", editorWidth+4)))
+	b.WriteString(styles.Help.Render(strings.Repeat("─", fullWidth+4)))
 	b.WriteString("\n\n")
 
 	// Top border
-	b.WriteString(styles.Help.Render("  ┌" + strings.Repeat("─", editorWidth) + "┐"))
-	b.WriteString("\n")
+	var box strings.Builder
+	box.WriteString(styles.Help.Render("  ┌" + strings.Repeat("─", editorWidth) + "┐"))
+	box.WriteString("\n")
 
 	// Create display lines with wrapping
 	displayLines, cursorDisplayLine, cursorDisplayCol := m.wrapEditorContent(contentWidth)
@@ -174,30 +144,31 @@ func (m Model) ViewPromptEditor() string {
 	// Render lines
 	for i := 0; i < editorHeight; i++ {
 		lineIdx := startLine + i
-		b.WriteString(styles.Help.Render("  │ "))
+		box.WriteString(styles.Help.Render("  │ "))
 
 		if lineIdx < len(displayLines) {
 			line := displayLines[lineIdx]
 
 			if lineIdx == cursorDisplayLine {
-				b.WriteString(m.renderLineWithCursor(line, cursorDisplayCol, contentWidth))
+				box.WriteString(m.renderLineWithCursor(line, cursorDisplayCol, contentWidth))
 			} else {
-				b.WriteString(styles.Input.Render(line))
-				padding := contentWidth - len(line)
-				if padding > 0 {
-					b.WriteString(strings.Repeat(" ", padding))
-				}
+				box.WriteString(styles.Input.Render(runewidth.FillRight(line, contentWidth)))
 			}
 		} else {
-			b.WriteString(strings.Repeat(" ", contentWidth))
+			box.WriteString(strings.Repeat(" ", contentWidth))
 		}
 
-		b.WriteString(styles.Help.Render(" │"))
-		b.WriteString("\n")
+		box.WriteString(styles.Help.Render(" │"))
+		box.WriteString("\n")
 	}
 
 	// Bottom border
-	b.WriteString(styles.Help.Render("  └" + strings.Repeat("─", editorWidth) + "┘"))
+	box.WriteString(styles.Help.Render("  └" + strings.Repeat("─", editorWidth) + "┘"))
+	if m.EditorPreview {
+		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, box.String(), " ", m.viewEditorPreview(editorWidth, editorHeight)))
+	} else {
+		b.WriteString(box.String())
+	}
 	b.WriteString("\n")
 
 	// Character count
@@ -206,8 +177,8 @@ func (m Model) ViewPromptEditor() string {
 
 	// Help text
 	kb := m.Config.Keys()
-	b.WriteString(styles.Help.Render(fmt.Sprintf("%s new line • %s save • %s cancel",
-		kb.Editor.NewLine, kb.Editor.Save, kb.Editor.Cancel)))
+	b.WriteString(styles.Help.Render(fmt.Sprintf("%s new line • %s save • %s cancel • %s preview",
+		kb.Editor.NewLine, kb.Editor.Save, kb.Editor.Cancel, kb.Editor.Preview)))
 	b.WriteString("\n")
 	b.WriteString(styles.Help.Render(fmt.Sprintf("←/→ move • ↑/↓ line • %s/%s line start/end",
 		kb.Editor.LineStart, kb.Editor.LineEnd)))
@@ -215,61 +186,68 @@ func (m Model) ViewPromptEditor() string {
 	return b.String()
 }
 
+// viewEditorPreview renders the markdown of the prompt being edited, as the
+// detail view shows it, in a box as large as the editor's. Long prompts
+// scroll with the cursor.
+func (m Model) viewEditorPreview(width, height int) string {
+	lines := markdown.Render(m.EditorContent, width-2)
+	if len(lines) > height {
+		// Keep the rendered lines at about the same place as the cursor
+		at := strings.Count(m.EditorContent[:m.EditorCursorPos], "\n")
+		total := strings.Count(m.EditorContent, "\n") + 1
+		start := at*len(lines)/total - height/2
+		start = min(max(start, 0), len(lines)-height)
+		lines = lines[start : start+height]
+	}
+	return lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(styles.Subtle).
+		Padding(0, 1).
+		Width(width).
+		Height(height).
+		Render(strings.Join(lines, "\n"))
+}
+
+// wrapEditorContent splits the prompt being edited into lines at most
+// contentWidth columns wide, and finds the line of the cursor and its byte
+// offset in it. Wide characters, such as CJK, take two columns and are never
+// split.
 func (m Model) wrapEditorContent(contentWidth int) ([]string, int, int) {
 	var displayLines []string
-	var cursorDisplayLine, cursorDisplayCol int
-
-	currentLine := ""
-	charIdx := 0
-	cursorFound := false
-
-	for i, ch := range m.EditorContent {
-		if ch == '\n' {
-			for len(currentLine) > contentWidth {
-				displayLines = append(displayLines, currentLine[:contentWidth])
-				if !cursorFound && charIdx <= m.EditorCursorPos && m.EditorCursorPos <= charIdx+contentWidth {
-					cursorDisplayLine = len(displayLines) - 1
-					cursorDisplayCol = m.EditorCursorPos - charIdx
-					cursorFound = true
-				}
-				charIdx += contentWidth
-				currentLine = currentLine[contentWidth:]
-			}
-			if !cursorFound && i >= m.EditorCursorPos {
-				cursorDisplayLine = len(displayLines)
-				cursorDisplayCol = m.EditorCursorPos - charIdx
-				cursorFound = true
-			}
-			displayLines = append(displayLines, currentLine)
-			charIdx = i + 1
-			currentLine = ""
-		} else {
-			currentLine += string(ch)
+	cursorDisplayLine, cursorDisplayCol := -1, 0
+
+	content := m.EditorContent
+	start, width := 0, 0
+	// endLine ends the line at byte end. The cursor at the end of a wrapped
+	// line shows at the start of the next one.
+	endLine := func(end int, wrapped bool) {
+		pos := m.EditorCursorPos
+		if cursorDisplayLine < 0 && pos >= start && (pos < end || !wrapped && pos == end) {
+			cursorDisplayLine = len(displayLines)
+			cursorDisplayCol = pos - start
 		}
+		displayLines = append(displayLines, content[start:end])
 	}
 
-	for len(currentLine) > contentWidth {
-		displayLines = append(displayLines, currentLine[:contentWidth])
-		if !cursorFound && charIdx <= m.EditorCursorPos && m.EditorCursorPos < charIdx+contentWidth {
-			cursorDisplayLine = len(displayLines) - 1
-			cursorDisplayCol = m.EditorCursorPos - charIdx
-			cursorFound = true
-		}
-		charIdx += contentWidth
-		currentLine = currentLine[contentWidth:]
-	}
-	displayLines = append(displayLines, currentLine)
-	if !cursorFound {
-		cursorDisplayLine = len(displayLines) - 1
-		cursorDisplayCol = m.EditorCursorPos - charIdx
-		if cursorDisplayCol < 0 {
-			cursorDisplayCol = 0
+	for i, ch := range content {
+		if ch == '\n' {
+			endLine(i, false)
+			start, width = i+1, 0
+			continue
 		}
-		if cursorDisplayCol > len(currentLine) {
-			cursorDisplayCol = len(currentLine)
+		w := runewidth.RuneWidth(ch)
+		if width > 0 && width+w > contentWidth {
+			endLine(i, true)
+			start, width = i, 0
 		}
+		width += w
 	}
+	endLine(len(content), false)
 
+	if cursorDisplayLine < 0 {
+		cursorDisplayLine = len(displayLines) - 1
+		cursorDisplayCol = len(displayLines[cursorDisplayLine])
+	}
 	return displayLines, cursorDisplayLine, cursorDisplayCol
 }
 
@@ -284,13 +262,13 @@ func (m Model) renderLineWithCursor(line string, cursorCol, contentWidth int) st
 		if cursorCol < len(line) {
 			b.WriteString(styles.Input.Render(line[cursorCol:]))
 		}
-		padding := contentWidth - len(line) - 1
+		padding := contentWidth - runewidth.StringWidth(line) - 1
 		if padding > 0 {
 			b.WriteString(strings.Repeat(" ", padding))
 		}
 	} else {
 		b.WriteString(styles.Input.Render(line))
-		padding := contentWidth - len(line) - 1
+		padding := contentWidth - runewidth.StringWidth(line) - 1
 		if padding > 0 {
 			b.WriteString(strings.Repeat(" ", padding))
 		}
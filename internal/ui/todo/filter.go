@@ -0,0 +1,218 @@
+package todo
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/store"
+	"github.com/ihatemodels/gdev/internal/todo"
+)
+
+// Sort orders of the list, in the order the sort key cycles through them.
+// The empty one keeps the order todos were created in.
+var sorts = []string{"", "updated", "due", "name", "branch", "priority"}
+
+// quickFilter is a filter of the list toggled by a key of its own. Those
+// turned on all apply.
+type quickFilter struct {
+	Name  string // as remembered in store.TodoView
+	Label string // shown in its chip under the header
+	Match func(m *Model, t *todo.Todo, now time.Time) bool
+}
+
+// dueSoon is how close a due date is for the due filter; overdue todos
+// are due soon too.
+const dueSoon = 7 * 24 * time.Hour
+
+// quickFilters are the filters of the list, in the order of their chips.
+var quickFilters = []quickFilter{
+	{"branch", "my branch", func(m *Model, t *todo.Todo, _ time.Time) bool {
+		return t.Branch == m.Branch
+	}},
+	{"due", "due soon", func(_ *Model, t *todo.Todo, now time.Time) bool {
+		return t.DueDate != nil && t.DueDate.Before(now.Add(dueSoon))
+	}},
+	{"prompts", "has prompts", func(_ *Model, t *todo.Todo, _ time.Time) bool {
+		return len(t.Prompts) > 0
+	}},
+	{"active", "not snoozed", func(_ *Model, t *todo.Todo, now time.Time) bool {
+		return !t.IsSnoozed(now)
+	}},
+}
+
+// statusFilters are what the status filter cycles through, as remembered in
+// store.TodoView: every todo, those not done, then each status.
+var statusFilters = []string{"", "undone", "open", string(todo.StatusInProgress), string(todo.StatusDone)}
+
+// matchStatus reports whether t has a status the status filter f shows.
+func matchStatus(f string, t *todo.Todo) bool {
+	switch f {
+	case "":
+		return true
+	case "undone":
+		return t.Status != todo.StatusDone
+	case "open":
+		return t.Status == todo.StatusOpen
+	}
+	return string(t.Status) == f
+}
+
+// describeStatusFilter returns the status filter f for display.
+func describeStatusFilter(f string) string {
+	switch f {
+	case "undone":
+		return "not done"
+	case "open":
+		return "open"
+	}
+	return todo.Status(f).String()
+}
+
+// ViewChangedMsg is sent when the list's sort order or filters change, so
+// they can be remembered for the repository.
+type ViewChangedMsg struct {
+	View store.TodoView
+}
+
+// describeSort returns the sort order for display, e.g. "due date".
+func describeSort(s string) string {
+	switch s {
+	case "updated":
+		return "recently updated"
+	case "due":
+		return "due date"
+	case "name":
+		return "name"
+	case "branch":
+		return "branch"
+	case "priority":
+		return "priority"
+	}
+	return "created"
+}
+
+// SetView sets the sort order and filters of the list.
+func (m *Model) SetView(v store.TodoView) {
+	m.Sort = v.Sort
+	m.Filters = v.Filters
+	m.Status = v.Status
+	m.applyView()
+}
+
+// cycleSort moves to the next of the sort orders.
+func (m *Model) cycleSort() tea.Cmd {
+	idx := 0
+	for i, s := range sorts {
+		if s == m.Sort {
+			idx = i
+		}
+	}
+	m.Sort = sorts[(idx+1)%len(sorts)]
+	m.applyView()
+	return m.viewChanged()
+}
+
+// toggleFilter turns the quick filter called name on or off.
+func (m *Model) toggleFilter(name string) tea.Cmd {
+	if m.filtering(name) {
+		m.Filters = slices.DeleteFunc(slices.Clone(m.Filters), func(f string) bool { return f == name })
+	} else {
+		m.Filters = append(slices.Clone(m.Filters), name)
+	}
+	m.applyView()
+	return m.viewChanged()
+}
+
+// cycleStatusFilter moves to the next of the status filters.
+func (m *Model) cycleStatusFilter() tea.Cmd {
+	idx := max(slices.Index(statusFilters, m.Status), 0)
+	m.Status = statusFilters[(idx+1)%len(statusFilters)]
+	m.applyView()
+	return m.viewChanged()
+}
+
+// filtering reports whether the quick filter called name is on.
+func (m Model) filtering(name string) bool {
+	return slices.Contains(m.Filters, name)
+}
+
+// viewChanged reports the sort order and filters so they are remembered.
+func (m Model) viewChanged() tea.Cmd {
+	v := store.TodoView{Sort: m.Sort, Filters: m.Filters, Status: m.Status}
+	return func() tea.Msg { return ViewChangedMsg{View: v} }
+}
+
+// matches reports whether t passes the quick filters that are on and the
+// status filter.
+func (m *Model) matches(t *todo.Todo, now time.Time) bool {
+	if !matchStatus(m.Status, t) {
+		return false
+	}
+	for _, f := range quickFilters {
+		if m.filtering(f.Name) && !f.Match(m, t, now) {
+			return false
+		}
+	}
+	return true
+}
+
+// applyView sets Todos to All, filtered and sorted, or ranked by the
+// search query if there is one, keeping the selected
+// todo under the cursor if it is still listed.
+func (m *Model) applyView() {
+	var selected string
+	if m.Cursor < len(m.Todos) {
+		selected = m.Todos[m.Cursor].ID
+	}
+
+	now := time.Now()
+	m.Todos = make([]todo.Todo, 0, len(m.All))
+	for _, t := range m.All {
+		if m.matches(&t, now) {
+			m.Todos = append(m.Todos, t)
+		}
+	}
+
+	switch m.Sort {
+	case "updated":
+		sort.SliceStable(m.Todos, func(i, j int) bool {
+			return m.Todos[i].UpdatedAt.After(m.Todos[j].UpdatedAt)
+		})
+	case "due":
+		// Todos without a due date go last
+		sort.SliceStable(m.Todos, func(i, j int) bool {
+			a, b := m.Todos[i].DueDate, m.Todos[j].DueDate
+			return a != nil && (b == nil || a.Before(*b))
+		})
+	case "name":
+		sort.SliceStable(m.Todos, func(i, j int) bool {
+			return strings.ToLower(m.Todos[i].Name) < strings.ToLower(m.Todos[j].Name)
+		})
+	case "branch":
+		sort.SliceStable(m.Todos, func(i, j int) bool {
+			return m.Todos[i].Branch < m.Todos[j].Branch
+		})
+	case "priority":
+		// Most urgent first; todos without a priority go last
+		sort.SliceStable(m.Todos, func(i, j int) bool {
+			return m.Todos[i].Priority.Rank() > m.Todos[j].Priority.Rank()
+		})
+	}
+	m.search()
+
+	for i, t := range m.Todos {
+		if t.ID == selected {
+			m.Cursor = i
+		}
+	}
+	if m.Cursor >= len(m.Todos) {
+		m.Cursor = max(len(m.Todos)-1, 0)
+	}
+	m.ListScroll = min(m.ListScroll, m.Cursor)
+	if visible := m.visibleItems(); m.Cursor >= m.ListScroll+visible {
+		m.ListScroll = m.Cursor - visible + 1
+	}
+}
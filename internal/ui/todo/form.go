@@ -1,12 +1,21 @@
 package todo
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"slices"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/ai"
+	"github.com/ihatemodels/gdev/internal/budget"
 	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/store"
 	"github.com/ihatemodels/gdev/internal/todo"
+	"github.com/ihatemodels/gdev/internal/ui/failure"
+	"github.com/ihatemodels/gdev/internal/ui/form"
 	"github.com/ihatemodels/gdev/internal/ui/styles"
 	"github.com/ihatemodels/gdev/internal/ui/terminal"
 )
@@ -23,9 +32,15 @@ func (m Model) UpdateFormView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	// Navigation mode - handle shortcuts and navigation
 
+	// Cancel improving all prompts before leaving the form
+	if m.ImproveCancel != nil && config.Matches(key, kb.Form.Cancel) {
+		m.cancelImprove()
+		return m, nil
+	}
+
 	// Handle cancel (exit form)
 	if config.Matches(key, kb.Form.Cancel) {
-		m.CurrentView = ListView
+		m.Views.Pop()
 		return m, nil
 	}
 
@@ -88,12 +103,25 @@ func (m Model) UpdateFormView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			// For prompts, open the full editor
 			m.EditorContent = m.FormPrompts[m.FormPromptIdx]
 			m.EditorCursorPos = len(m.EditorContent)
-			m.PreviousView = m.CurrentView
-			m.CurrentView = PromptEditorView
-		} else {
-			// For simple fields, enter inline edit mode
+			m.Views.Push(PromptEditorView)
+		} else if f := &m.FormFields[m.FormField]; f.Editable() {
+			// For text and date fields, enter inline edit mode
+			f.StartEdit(m.Config)
 			m.FormEditing = true
+		} else {
+			// Select and toggle fields change value directly
+			f.Activate()
+		}
+		return m, nil
+	}
+
+	// Handle select fields cycling with left/right
+	if m.FormField < FieldPrompts && (key == "left" || key == "right") {
+		delta := 1
+		if key == "left" {
+			delta = -1
 		}
+		m.FormFields[m.FormField].Cycle(delta)
 		return m, nil
 	}
 
@@ -106,7 +134,8 @@ func (m Model) UpdateFormView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case config.Matches(key, kb.Form.DeletePrompt):
-			if len(m.FormPrompts) > 1 {
+			// Results of a batch are matched to prompts by position
+			if len(m.FormPrompts) > 1 && m.ImproveCancel == nil {
 				m.FormPrompts = append(m.FormPrompts[:m.FormPromptIdx], m.FormPrompts[m.FormPromptIdx+1:]...)
 				if m.FormPromptIdx >= len(m.FormPrompts) {
 					m.FormPromptIdx = len(m.FormPrompts) - 1
@@ -119,108 +148,211 @@ func (m Model) UpdateFormView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				return m.openImprovePromptTerminal()
 			}
 			return m, nil
+
+		case config.Matches(key, kb.Form.ImproveAll):
+			if !m.Improving {
+				return m.improveAll()
+			}
+			return m, nil
 		}
 	}
 
 	return m, nil
 }
 
-// openImprovePromptTerminal opens the terminal modal to run the improve prompt command.
+// openImprovePromptTerminal asks the AI backend to improve the selected
+// prompt in the terminal modal, once the AI budget allows it.
 func (m Model) openImprovePromptTerminal() (tea.Model, tea.Cmd) {
-	m.Improving = true
-	prompt := m.FormPrompts[m.FormPromptIdx]
+	backend, err := budget.Backend(m.Config)
+	if err != nil {
+		m.ErrMsg = err.Error()
+		return m, nil
+	}
 	idx := m.FormPromptIdx
+	prompt := improvePrompt(m.FormPrompts[idx])
+	m, ok := m.budgetAllows(backend, []store.CacheKey{backend.CacheKey(prompt)}, func(m Model) (tea.Model, tea.Cmd) {
+		return m.runImprove(backend, idx, prompt)
+	})
+	if !ok {
+		return m, nil
+	}
+	return m.runImprove(backend, idx, prompt)
+}
 
-	systemPrompt := `You are a prompt rewriter. Rewrite the user's prompt to be clearer and more effective for LLMs.
-
-CRITICAL: Output ONLY the rewritten prompt. No introductions, no explanations, no "Here is...", no markdown formatting, no quotes around it. Just the raw improved prompt text and nothing else.
+// runImprove asks backend for prompt, improving the prompt at idx, in the
+// terminal modal.
+func (m Model) runImprove(backend ai.Backend, idx int, prompt string) (tea.Model, tea.Cmd) {
+	m.Improving = true
 
-Guidelines for rewriting:
-- Keep the original intent
-- Be more specific and explicit
-- Use clear structure if helpful
-- Remove vague language`
+	// Closing the terminal returns to the form
+	m.openTerminal("Improve Prompt", terminalJob{Kind: jobImprove, Prompt: idx})
 
-	// Create terminal modal
-	m.Terminal = terminal.New(m.Config, "Improve Prompt")
-	m.Terminal.Dir = m.RepoPath
-	m.Terminal.SetSize(m.Width, m.Height)
+	cmd := m.Terminal.RunCachedFunc(backend.CacheKey(prompt), false, ai.Describe(backend), func(ctx context.Context, w io.Writer) error {
+		return backend.Generate(ctx, prompt, w)
+	})
+	return m, cmd
+}
 
-	// Set callback to handle the improved prompt when terminal closes
-	m.TerminalCallback = func(model *Model, output string) {
-		model.Improving = false
-		improved := strings.TrimSpace(output)
-		if improved != "" && idx >= 0 && idx < len(model.FormPrompts) {
-			model.FormPrompts[idx] = improved
-		}
+// finishImprove reviews the improved prompt once its terminal is closed,
+// if the command finished and wrote one.
+func (m Model) finishImprove(job terminalJob, res terminal.CommandDoneMsg) (tea.Model, tea.Cmd) {
+	m.Improving = false
+	if res.Err != nil {
+		return m, nil
 	}
-
-	// Store the current view to return to
-	m.PreviousView = m.CurrentView
-	m.CurrentView = TerminalView
-
-	// Start the command
-	cmd := m.Terminal.RunCommand("claude", "-p", prompt, "--system-prompt", systemPrompt)
-	return m, cmd
+	improved := ai.Result(strings.Join(res.Output, "\n"))
+	idx := job.Prompt
+	if improved == "" || idx < 0 || idx >= len(m.FormPrompts) {
+		return m, nil
+	}
+	m.ReviewIdx = idx
+	m.ReviewOriginal = m.FormPrompts[idx]
+	m.ReviewImproved = improved
+	m.Views.Push(ImproveReviewView)
+	return m, nil
 }
 
 // handleFormEditMode handles input when editing a simple field inline.
 func (m Model) handleFormEditMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	key := msg.String()
-	kb := m.Config.Keys()
-
-	// Cancel exits edit mode without saving (though changes are already in the field)
-	if config.Matches(key, kb.Form.Cancel) {
+	if m.FormField >= FieldPrompts {
 		m.FormEditing = false
 		return m, nil
 	}
 
-	// Enter/newline confirms and exits edit mode
-	if config.Matches(key, kb.Editor.NewLine) {
+	if m.FormFields[m.FormField].HandleKey(msg, m.Config.Keys()) {
 		m.FormEditing = false
-		return m, nil
 	}
+	return m, nil
+}
 
-	// Handle text input for the current field
-	switch m.FormField {
-	case FieldBranch:
-		m.FormBranch = handleTextInput(m.FormBranch, msg)
-	case FieldName:
-		m.FormName = handleTextInput(m.FormName, msg)
-	case FieldDescription:
-		m.FormDescription = handleTextInput(m.FormDescription, msg)
+// models are the claude models a todo's prompts can run with, by alias.
+// "default" leaves it to claude.
+var models = []string{"default", "haiku", "sonnet", "opus"}
+
+// newFormFields returns the simple form fields for a todo, in FormField order.
+func newFormFields(branch, name, description string, due *time.Time, model string, priority todo.Priority, subtasks []todo.Subtask) []form.Field {
+	branchField := form.Text("branch", "Branch", branch)
+	branchField.Required = true
+
+	nameField := form.Text("name", "Name", name)
+	nameField.Required = true
+
+	return []form.Field{
+		branchField,
+		nameField,
+		form.Multiline("description", "Description", description),
+		form.Date("due", "Due", formatDate(due)),
+		form.Select("model", "Model", modelOptions(model), model),
+		form.Select("priority", "Priority", priorityOptions, priority.String()),
+		form.Multiline("subtasks", "Subtasks, one per line", subtaskLines(subtasks)),
 	}
+}
 
-	return m, nil
+// subtaskLines returns the text of subtasks, one per line.
+func subtaskLines(subtasks []todo.Subtask) string {
+	texts := make([]string, len(subtasks))
+	for i, s := range subtasks {
+		texts[i] = s.Text
+	}
+	return strings.Join(texts, "\n")
 }
 
-func handleTextInput(current string, msg tea.KeyMsg) string {
-	key := msg.String()
-	switch key {
-	case "backspace":
-		if len(current) > 0 {
-			return current[:len(current)-1]
-		}
-	case "space":
-		return current + " "
-	default:
-		if len(key) == 1 {
-			return current + key
-		}
+// modelOptions returns the models to pick from, with model, which may be
+// a full model name set in the todo list file, among them.
+func modelOptions(model string) []string {
+	if model == "" || slices.Contains(models, model) {
+		return models
 	}
-	return current
+	return append(slices.Clone(models), model)
 }
 
-func (m Model) saveForm() (tea.Model, tea.Cmd) {
-	if m.FormName == "" {
-		m.ErrMsg = "Name is required"
-		return m, nil
+// priorityOptions are the priorities to pick from, least urgent first.
+var priorityOptions = func() []string {
+	options := make([]string, len(todo.Priorities))
+	for i, p := range todo.Priorities {
+		options[i] = p.String()
+	}
+	return options
+}()
+
+// formModel returns the model picked in the form, "" for the default.
+func (m Model) formModel() string {
+	if model := m.formValue(FieldModel); model != "default" {
+		return model
 	}
-	if m.FormBranch == "" {
-		m.ErrMsg = "Branch is required"
+	return ""
+}
+
+// formatDate formats an optional date for a form date field.
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(form.DateLayout)
+}
+
+// formDate returns the parsed value of a date field, or nil if empty.
+func (m Model) formDate(field FormField) *time.Time {
+	t, err := m.FormFields[field].Time()
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// formValue returns the trimmed value of a simple form field.
+func (m Model) formValue(field FormField) string {
+	return strings.TrimSpace(m.FormFields[field].Value)
+}
+
+// openCreateForm resets the form for a new todo on the current branch.
+func (m *Model) openCreateForm() {
+	m.FormFields = newFormFields(m.Branch, "", "", nil, "", todo.PriorityNone, nil)
+	m.FormPrompts = []string{""}
+	m.FormField = FieldBranch
+	m.FormPromptIdx = 0
+	m.FormEditing = false
+	m.FormEditingTodo = nil
+	m.Views.Push(CreateView)
+}
+
+// openEditForm fills the form from an existing todo.
+func (m *Model) openEditForm(t *todo.Todo) {
+	m.FormEditingTodo = t
+	m.FormFields = newFormFields(t.Branch, t.Name, t.Description, t.DueDate, t.Model, t.Priority, t.Subtasks)
+	m.FormPrompts = make([]string, len(t.Prompts))
+	copy(m.FormPrompts, t.Prompts)
+	if len(m.FormPrompts) == 0 {
+		m.FormPrompts = []string{""}
+	}
+	m.FormField = FieldBranch
+	m.FormPromptIdx = 0
+	m.FormEditing = false
+	m.Views.Push(EditView)
+}
+
+func (m Model) saveForm() (tea.Model, tea.Cmd) {
+	if !form.Validate(m.FormFields) {
+		for i, f := range m.FormFields {
+			if f.Err != "" {
+				m.FormField = FormField(i)
+				break
+			}
+		}
 		return m, nil
 	}
 
+	// Prompts still being improved keep their current text
+	m.cancelImprove()
+
+	branch := m.formValue(FieldBranch)
+	name := m.formValue(FieldName)
+	description := m.FormFields[FieldDescription].Value
+	due := m.formDate(FieldDue)
+	model := m.formModel()
+	priority := todo.ParsePriority(m.formValue(FieldPriority))
+	subtasks := strings.Split(m.FormFields[FieldSubtasks].Value, "\n")
+
 	var prompts []string
 	for _, p := range m.FormPrompts {
 		if strings.TrimSpace(p) != "" {
@@ -228,35 +360,43 @@ func (m Model) saveForm() (tea.Model, tea.Cmd) {
 		}
 	}
 
-	if m.CurrentView == EditView && m.FormEditingTodo != nil {
-		m.FormEditingTodo.Branch = m.FormBranch
-		m.FormEditingTodo.Name = m.FormName
-		m.FormEditingTodo.Description = m.FormDescription
+	if m.Views.Is(EditView) && m.FormEditingTodo != nil {
+		m.FormEditingTodo.Branch = branch
+		m.FormEditingTodo.Name = name
+		m.FormEditingTodo.Description = description
+		m.FormEditingTodo.DueDate = due
+		m.FormEditingTodo.Model = model
+		m.FormEditingTodo.Priority = priority
 		m.FormEditingTodo.Prompts = prompts
+		m.FormEditingTodo.SetSubtasks(subtasks)
 		m.FormEditingTodo.Update()
 
-		return m, func() tea.Msg {
+		return m, failure.Cmd("Save TODO", func() (tea.Msg, error) {
 			if err := m.Store.UpdateTodo(m.RepoPath, m.FormEditingTodo); err != nil {
-				return TodoErrorMsg{Err: err}
+				return nil, err
 			}
-			return TodoSavedMsg{}
-		}
+			return TodoSavedMsg{}, nil
+		})
 	}
 
-	t := todo.NewTodo(m.FormBranch, m.FormName, m.FormDescription, prompts)
-	return m, func() tea.Msg {
+	t := todo.NewTodo(branch, name, description, prompts)
+	t.DueDate = due
+	t.Model = model
+	t.Priority = priority
+	t.SetSubtasks(subtasks)
+	return m, failure.Cmd("Create TODO", func() (tea.Msg, error) {
 		if err := m.Store.AddTodo(m.RepoPath, t); err != nil {
-			return TodoErrorMsg{Err: err}
+			return nil, err
 		}
-		return TodoSavedMsg{}
-	}
+		return TodoSavedMsg{}, nil
+	})
 }
 
 // AutoSavePrompt saves just the updated prompt without changing view.
 func (m Model) AutoSavePrompt() tea.Cmd {
-	return func() tea.Msg {
+	return failure.Cmd("Save prompt", func() (tea.Msg, error) {
 		if m.FormEditingTodo == nil {
-			return nil
+			return nil, nil
 		}
 		var prompts []string
 		for _, p := range m.FormPrompts {
@@ -267,11 +407,8 @@ func (m Model) AutoSavePrompt() tea.Cmd {
 		m.FormEditingTodo.Prompts = prompts
 		m.FormEditingTodo.Update()
 
-		if err := m.Store.UpdateTodo(m.RepoPath, m.FormEditingTodo); err != nil {
-			return TodoErrorMsg{Err: err}
-		}
-		return nil
-	}
+		return nil, m.Store.UpdateTodo(m.RepoPath, m.FormEditingTodo)
+	})
 }
 
 // ViewForm renders the create/edit form view.
@@ -284,14 +421,11 @@ func (m Model) ViewForm(title string) string {
 	}
 	b.WriteString("\n\n")
 
-	// Branch field
-	b.WriteString(m.renderFormField("Branch", m.FormBranch, FieldBranch))
-
-	// Name field
-	b.WriteString(m.renderFormField("Name", m.FormName, FieldName))
-
-	// Description field
-	b.WriteString(m.renderFormField("Description", m.FormDescription, FieldDescription))
+	// Simple fields
+	for i, f := range m.FormFields {
+		field := FormField(i)
+		b.WriteString(f.View(m.FormField == field, m.FormEditing && m.FormField == field))
+	}
 	b.WriteString("\n")
 
 	// Prompts field
@@ -321,7 +455,11 @@ func (m Model) ViewForm(title string) string {
 		displayP = strings.ReplaceAll(displayP, "\n", " ")
 		b.WriteString(styles.Input.Render(displayP))
 
-		if m.FormField == FieldPrompts && i == m.FormPromptIdx {
+		if m.ImproveCancel != nil {
+			if m.isImproving(i) {
+				b.WriteString(styles.Confirm.Render(" improving..."))
+			}
+		} else if m.FormField == FieldPrompts && i == m.FormPromptIdx {
 			if m.Improving {
 				b.WriteString(styles.Confirm.Render(" improving..."))
 			}
@@ -333,15 +471,25 @@ func (m Model) ViewForm(title string) string {
 	kb := m.Config.Keys()
 
 	var help string
-	if m.FormEditing {
+	if m.FormEditing && m.FormFields[m.FormField].Kind == form.KindDate {
+		// Date picker help is shown inside the calendar
+		help = "pick a date in the calendar above"
+	} else if m.FormEditing && m.FormFields[m.FormField].Kind == form.KindMultiline {
+		// Multi-line edit mode help
+		help = fmt.Sprintf("type to edit • %s new line • %s done",
+			kb.Editor.NewLine, kb.Form.Cancel)
+	} else if m.FormEditing {
 		// Edit mode help
 		help = fmt.Sprintf("type to edit • %s confirm • %s cancel",
 			kb.Editor.NewLine, kb.Form.Cancel)
+	} else if m.ImproveCancel != nil {
+		// Improving all prompts
+		help = fmt.Sprintf("improved %d/%d prompts • %s cancel", m.improvedCount(len(m.ImproveIdxs)), len(m.ImproveIdxs), kb.Form.Cancel)
 	} else if m.FormField == FieldPrompts {
 		// Prompts navigation help
-		help = fmt.Sprintf("%s/%s nav • %s edit • %s improve • %s add • %s del • %s save",
+		help = fmt.Sprintf("%s/%s nav • %s edit • %s improve • %s improve all • %s add • %s del • %s save",
 			kb.Global.MoveUp, kb.Global.MoveDown, kb.Form.EditPrompt,
-			kb.Form.ImprovePrompt, kb.Form.AddPrompt, kb.Form.DeletePrompt, kb.Form.Submit)
+			kb.Form.ImprovePrompt, kb.Form.ImproveAll, kb.Form.AddPrompt, kb.Form.DeletePrompt, kb.Form.Submit)
 	} else {
 		// Field navigation help
 		help = fmt.Sprintf("%s/%s navigate • %s edit • %s save • %s cancel",
@@ -351,29 +499,3 @@ func (m Model) ViewForm(title string) string {
 
 	return b.String()
 }
-
-// renderFormField renders a single form field with appropriate styling.
-func (m Model) renderFormField(label, value string, field FormField) string {
-	var b strings.Builder
-
-	isSelected := m.FormField == field
-	isEditing := isSelected && m.FormEditing
-
-	// Label
-	if isSelected {
-		b.WriteString(styles.Selected.Render(fmt.Sprintf("▸ %s: ", label)))
-	} else {
-		b.WriteString(styles.Label.Render(fmt.Sprintf("  %s: ", label)))
-	}
-
-	// Value
-	b.WriteString(styles.Input.Render(value))
-
-	// Cursor (only show when editing this field)
-	if isEditing {
-		b.WriteString(styles.Cursor.Render("█"))
-	}
-
-	b.WriteString("\n")
-	return b.String()
-}
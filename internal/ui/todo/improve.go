@@ -0,0 +1,201 @@
+package todo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/ai"
+	"github.com/ihatemodels/gdev/internal/budget"
+	"github.com/ihatemodels/gdev/internal/store"
+	"github.com/ihatemodels/gdev/internal/workpool"
+)
+
+// improveWorkers is how many prompts are improved at once.
+const improveWorkers = 4
+
+// improveInstructions tell the AI backend to rewrite a prompt and print only
+// the result.
+const improveInstructions = `You are a prompt rewriter. Rewrite the user's prompt to be clearer and more effective for LLMs.
+
+CRITICAL: Output ONLY the rewritten prompt, in a fenced block with the info string "result" (four backticks, ` + "````result" + `, if the prompt contains fenced code). No introductions, no explanations, no "Here is...", no quotes around it. Nothing before or after the block.
+
+Guidelines for rewriting:
+- Keep the original intent
+- Be more specific and explicit
+- Use clear structure if helpful
+- Remove vague language`
+
+// improvePrompt returns the request that improves prompt.
+func improvePrompt(prompt string) string {
+	return improveInstructions + "\n\nThe user's prompt:\n\n" + prompt
+}
+
+// ImprovedPrompt is an improved prompt waiting to be reviewed.
+type ImprovedPrompt struct {
+	Idx      int // into FormPrompts
+	Original string
+	Improved string
+}
+
+// PromptImprovedMsg carries the result for one prompt of a batch, or
+// reports that the batch finished.
+type PromptImprovedMsg struct {
+	Result workpool.Result
+	Done   bool
+
+	results <-chan workpool.Result
+}
+
+// improveAll improves every prompt of the form at once. Results are queued
+// for review as they arrive; canceling keeps the ones that finished.
+func (m Model) improveAll() (tea.Model, tea.Cmd) {
+	var idxs []int
+	var originals []string
+	for i, p := range m.FormPrompts {
+		if strings.TrimSpace(p) != "" {
+			idxs = append(idxs, i)
+			originals = append(originals, p)
+		}
+	}
+	if len(idxs) == 0 {
+		return m, nil
+	}
+
+	backend, err := budget.Backend(m.Config)
+	if err != nil {
+		m.ErrMsg = err.Error()
+		return m, nil
+	}
+	keys := make([]store.CacheKey, len(originals))
+	for i, p := range originals {
+		keys[i] = backend.CacheKey(improvePrompt(p))
+	}
+	m, ok := m.budgetAllows(backend, keys, func(m Model) (tea.Model, tea.Cmd) {
+		return m.runImproveAll(backend, idxs, originals)
+	})
+	if !ok {
+		return m, nil
+	}
+	return m.runImproveAll(backend, idxs, originals)
+}
+
+// runImproveAll asks backend to improve originals, the prompts at idxs, at
+// once.
+func (m Model) runImproveAll(backend ai.Backend, idxs []int, originals []string) (tea.Model, tea.Cmd) {
+	ctx, cancel := context.WithCancel(m.Config.Context())
+	cfg := m.Config
+	results := workpool.Run(ctx, improveWorkers, len(idxs), func(ctx context.Context, i int) (string, error) {
+		prompt := improvePrompt(originals[i])
+		key := backend.CacheKey(prompt)
+		if lines, ok := cfg.CachedResponse(key); ok {
+			return strings.Join(lines, "\n"), nil
+		}
+		var out strings.Builder
+		if err := backend.Generate(ctx, prompt, &out); err != nil {
+			return "", err
+		}
+		output := out.String()
+		if ai.Cacheable(output) {
+			cfg.CacheResponse(key, strings.Split(strings.TrimRight(output, "\n"), "\n"))
+		}
+		return output, nil
+	})
+
+	m.Improving = true
+	m.ImproveCancel = cancel
+	m.ImproveIdxs = idxs
+	m.ImprovePending = make(map[int]bool, len(idxs))
+	for _, idx := range idxs {
+		m.ImprovePending[idx] = true
+	}
+	m.ImproveFailed = 0
+	m.ReviewQueue = nil
+	return m, waitForImproved(results)
+}
+
+// waitForImproved returns a command that receives the next result of a batch.
+func waitForImproved(results <-chan workpool.Result) tea.Cmd {
+	return func() tea.Msg {
+		r, ok := <-results
+		if !ok {
+			return PromptImprovedMsg{Done: true}
+		}
+		return PromptImprovedMsg{Result: r, results: results}
+	}
+}
+
+// handlePromptImproved queues an improved prompt for review, and starts
+// reviewing once the batch has finished.
+func (m Model) handlePromptImproved(msg PromptImprovedMsg) (tea.Model, tea.Cmd) {
+	if !msg.Done {
+		r := msg.Result
+		improved := ai.Result(r.Output)
+		idx := m.ImproveIdxs[r.Index]
+		delete(m.ImprovePending, idx)
+		switch {
+		case r.Err != nil:
+			m.ImproveFailed++
+		case improved != "" && idx < len(m.FormPrompts) && improved != m.FormPrompts[idx]:
+			m.ReviewQueue = append(m.ReviewQueue, ImprovedPrompt{Idx: idx, Original: m.FormPrompts[idx], Improved: improved})
+		}
+		return m, waitForImproved(msg.results)
+	}
+
+	total := len(m.ImproveIdxs)
+	canceled := len(m.ImprovePending) > 0
+	m.ImproveCancel()
+	m.ImproveCancel = nil
+	m.ImprovePending = nil
+	m.Improving = false
+
+	switch {
+	case canceled:
+		m.ErrMsg = fmt.Sprintf("Canceled: %d of %d prompts improved", m.improvedCount(total), total)
+	case m.ImproveFailed > 0:
+		m.ErrMsg = fmt.Sprintf("%d of %d prompts could not be improved", m.ImproveFailed, len(m.ImproveIdxs))
+	}
+
+	// Reviews go in prompt order, whichever finished first
+	sort.Slice(m.ReviewQueue, func(i, j int) bool {
+		return m.ReviewQueue[i].Idx < m.ReviewQueue[j].Idx
+	})
+	// The form may have been saved in the meantime
+	if m.Views.Is(CreateView) || m.Views.Is(EditView) {
+		m.nextReview()
+	}
+	return m, nil
+}
+
+// improvedCount returns how many of the total prompts of the batch have been
+// improved so far.
+func (m Model) improvedCount(total int) int {
+	return total - len(m.ImprovePending) - m.ImproveFailed
+}
+
+// isImproving reports whether the prompt at idx is still being improved.
+func (m Model) isImproving(idx int) bool {
+	return m.ImprovePending[idx]
+}
+
+// cancelImprove stops the running batch. Prompts already improved are kept.
+func (m *Model) cancelImprove() {
+	if m.ImproveCancel != nil {
+		m.ImproveCancel()
+	}
+}
+
+// nextReview opens the review of the next queued prompt, if any.
+func (m *Model) nextReview() {
+	if len(m.ReviewQueue) == 0 {
+		return
+	}
+	next := m.ReviewQueue[0]
+	m.ReviewQueue = m.ReviewQueue[1:]
+	m.ReviewIdx = next.Idx
+	m.ReviewOriginal = next.Original
+	m.ReviewImproved = next.Improved
+	m.Views.Push(ImproveReviewView)
+}
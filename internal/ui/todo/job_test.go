@@ -0,0 +1,78 @@
+package todo
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/todo"
+	"github.com/ihatemodels/gdev/internal/ui/terminal"
+)
+
+// promptModel returns a model sending the first of two prompts of a run,
+// without starting the command.
+func promptModel(t *testing.T) Model {
+	m := benchModel(t, 0)
+	m.RunTodo = todo.NewTodo("feature", "Fix it", "", []string{"Find it", "Fix it"})
+	m.RunStatus = make([]runStatus, 2)
+	m.RunOutputs = make([]string, 2)
+	m.RunSession = "session"
+	m.openTerminal("Prompt 1 of 2", terminalJob{Kind: jobRunPrompt, Prompt: 0, NewSession: true})
+	return m
+}
+
+// closeTerminal presses quit in the terminal modal.
+func closeTerminal(t *testing.T, m Model) Model {
+	t.Helper()
+	if !m.Views.Is(TerminalView) {
+		t.Fatal("the terminal modal isn't open")
+	}
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	return model.(Model)
+}
+
+func TestFinishPrompt_Done(t *testing.T) {
+	m := promptModel(t)
+	model, _ := m.Update(terminal.CommandDoneMsg{ID: m.Terminal.ID, Output: []string{"found it", ""}})
+	m = closeTerminal(t, model.(Model))
+
+	if m.RunStatus[0] != runDone {
+		t.Errorf("status = %v, want %v", m.RunStatus[0], runDone)
+	}
+	if m.RunOutputs[0] != "found it" {
+		t.Errorf("output = %q, want %q", m.RunOutputs[0], "found it")
+	}
+	if m.RunIdx != 1 || !m.Views.Is(RunGateView) {
+		t.Errorf("RunIdx = %d, gate open = %v; want the gate before prompt 2", m.RunIdx, m.Views.Is(RunGateView))
+	}
+	if m.RunSession != "session" {
+		t.Errorf("session = %q, want it kept for the next prompt", m.RunSession)
+	}
+	if last, runs := m.RunTodo.LastExecution("Find it"); last == nil || runs != 1 || last.Status != "done" || last.Output != "found it" {
+		t.Errorf("LastExecution() = %+v, %d; want one done run with its output", last, runs)
+	}
+	if last, _ := m.RunTodo.LastExecution("Fix it"); last != nil {
+		t.Errorf("LastExecution() of the next prompt = %+v, want none", last)
+	}
+}
+
+func TestFinishPrompt_ClosedWhileRunning(t *testing.T) {
+	m := closeTerminal(t, promptModel(t))
+
+	if m.RunStatus[0] != runCanceled {
+		t.Errorf("status = %v, want %v", m.RunStatus[0], runCanceled)
+	}
+	if m.RunSession != "" {
+		t.Errorf("session = %q, want it dropped as it may not exist", m.RunSession)
+	}
+}
+
+func TestFinishPrompt_IgnoresOtherTerminals(t *testing.T) {
+	m := promptModel(t)
+	// A command run again from the modal finishes with its own ID
+	model, _ := m.Update(terminal.CommandDoneMsg{ID: m.Terminal.ID + 1, Output: []string{"other"}})
+	m = closeTerminal(t, model.(Model))
+
+	if m.RunStatus[0] != runCanceled || m.RunOutputs[0] != "" {
+		t.Errorf("status = %v, output = %q; want the prompt canceled without output", m.RunStatus[0], m.RunOutputs[0])
+	}
+}
@@ -3,22 +3,45 @@ package todo
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/todo"
+	"github.com/ihatemodels/gdev/internal/ui/failure"
 	"github.com/ihatemodels/gdev/internal/ui/styles"
 )
 
+// moveCursor moves the cursor by delta todos, scrolling to keep it in view.
+func (m *Model) moveCursor(delta int) {
+	m.Cursor = max(min(m.Cursor+delta, len(m.Todos)-1), 0)
+	if m.Cursor < m.ListScroll {
+		m.ListScroll = m.Cursor
+	}
+	if visibleItems := m.visibleItems(); m.Cursor >= m.ListScroll+visibleItems {
+		m.ListScroll = m.Cursor - visibleItems + 1
+	}
+}
+
 // UpdateListView handles input for the list view.
 func (m Model) UpdateListView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	visibleItems := (m.Height - 10) / 5
-	if visibleItems < 1 {
-		visibleItems = 1
-	}
+	visibleItems := m.visibleItems()
 
 	key := msg.String()
 	kb := m.Config.Keys()
 
+	if m.Searching {
+		return m.updateSearch(msg)
+	}
+
+	// Quit clears the search before going back
+	if m.Query != "" && config.Matches(key, kb.Global.Quit) {
+		m.Query = ""
+		m.applyView()
+		return m, nil
+	}
+
 	// Handle quit/back
 	if config.MatchesAny(key, kb.Global.Quit, kb.Global.QuitAlt) {
 		return m, func() tea.Msg { return BackToMenuMsg{} }
@@ -26,22 +49,12 @@ func (m Model) UpdateListView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	// Handle navigation
 	if config.MatchesAny(key, kb.Global.MoveUp, kb.Global.MoveUpAlt) {
-		if m.Cursor > 0 {
-			m.Cursor--
-			if m.Cursor < m.ListScroll {
-				m.ListScroll = m.Cursor
-			}
-		}
+		m.moveCursor(-1)
 		return m, nil
 	}
 
 	if config.MatchesAny(key, kb.Global.MoveDown, kb.Global.MoveDownAlt) {
-		if m.Cursor < len(m.Todos)-1 {
-			m.Cursor++
-			if m.Cursor >= m.ListScroll+visibleItems {
-				m.ListScroll = m.Cursor - visibleItems + 1
-			}
-		}
+		m.moveCursor(1)
 		return m, nil
 	}
 
@@ -81,70 +94,218 @@ func (m Model) UpdateListView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case config.Matches(key, kb.List.Select):
 		if len(m.Todos) > 0 {
 			t := m.Todos[m.Cursor]
-			m.FormEditingTodo = &t
-			m.FormBranch = t.Branch
-			m.FormName = t.Name
-			m.FormDescription = t.Description
-			m.FormPrompts = make([]string, len(t.Prompts))
-			copy(m.FormPrompts, t.Prompts)
-			if len(m.FormPrompts) == 0 {
-				m.FormPrompts = []string{""}
-			}
-			m.FormField = FieldBranch
-			m.FormPromptIdx = 0
-			m.CurrentView = EditView
+			m.openEditForm(&t)
 		}
 
 	case config.Matches(key, kb.List.New):
-		m.CurrentView = CreateView
-		m.FormBranch = m.Branch
-		m.FormName = ""
-		m.FormDescription = ""
-		m.FormPrompts = []string{""}
-		m.FormField = FieldBranch
-		m.FormPromptIdx = 0
-		m.FormEditingTodo = nil
+		m.openCreateForm()
 
 	case config.Matches(key, kb.List.Delete):
 		if len(m.Todos) > 0 {
-			m.DeleteTarget = &m.Todos[m.Cursor]
-			m.CurrentView = DeleteConfirmView
+			m.openDeleteConfirm(&m.Todos[m.Cursor])
+		}
+
+	case config.Matches(key, kb.List.Snooze):
+		if len(m.Todos) > 0 {
+			m.openSnooze(&m.Todos[m.Cursor])
+		}
+
+	case config.Matches(key, kb.List.Details):
+		if len(m.Todos) > 0 {
+			m.openDetail(m.Todos[m.Cursor])
+		}
+
+	case config.Matches(key, kb.List.Stats):
+		m.Views.Push(StatsView)
+
+	case config.Matches(key, kb.List.Status):
+		if len(m.Todos) > 0 {
+			return m, m.cycleStatus(m.Todos[m.Cursor])
+		}
+
+	case config.Matches(key, kb.Detail.Run):
+		if len(m.Todos) > 0 {
+			return m.startRun(&m.Todos[m.Cursor])
 		}
+
+	case config.Matches(key, kb.List.Sort):
+		return m, m.cycleSort()
+
+	case config.Matches(key, kb.List.Search):
+		m.Searching = true
+
+	case config.Matches(key, kb.List.FilterBranch):
+		return m, m.toggleFilter("branch")
+
+	case config.Matches(key, kb.List.FilterDue):
+		return m, m.toggleFilter("due")
+
+	case config.Matches(key, kb.List.FilterPrompts):
+		return m, m.toggleFilter("prompts")
+
+	case config.Matches(key, kb.List.FilterActive):
+		return m, m.toggleFilter("active")
+
+	case config.Matches(key, kb.List.FilterStatus):
+		return m, m.cycleStatusFilter()
 	}
 
 	return m, nil
 }
 
+// cycleStatus moves t on to the next status and saves it.
+func (m Model) cycleStatus(t todo.Todo) tea.Cmd {
+	t.SetStatus(t.Status.Next())
+	return failure.Cmd("Update TODO status", func() (tea.Msg, error) {
+		if err := m.Store.UpdateTodo(m.RepoPath, &t); err != nil {
+			return nil, err
+		}
+		return TodoSavedMsg{}, nil
+	})
+}
+
+// The list is laid out with the selected TODO's detail beside it on
+// terminals at least splitWidth wide, with the cards taking listWidth.
+// Narrower ones show the detail below the list when there are
+// stackedHeight lines for both.
+const (
+	splitWidth    = 110
+	listWidth     = 60
+	stackedHeight = 36
+)
+
+// layout returns whether the detail pane is beside the list, and how many
+// lines it has, 0 when there is no room for it.
+func (m Model) layout() (split bool, height int) {
+	switch {
+	case m.Width >= splitWidth:
+		return true, max(m.Height-8, 5)
+	case m.Height >= stackedHeight:
+		return false, m.Height / 3
+	}
+	return false, 0
+}
+
+// visibleItems returns how many TODO cards fit on screen.
+func (m Model) visibleItems() int {
+	height := m.Height
+	if split, pane := m.layout(); !split && pane > 0 {
+		height -= pane + 3
+	}
+	if len(m.Filters) > 0 || m.Status != "" {
+		height-- // the chips
+	}
+	if m.Searching || m.Query != "" {
+		height-- // the query
+	}
+	if m.Config.Settings.TodoCards.Compact {
+		return max(height-10, 1)
+	}
+	return max((height-10)/5, 1)
+}
+
+// viewDetailPane renders t's detail in a pane width columns wide and
+// height lines high, to the right of the list when split and below it
+// otherwise.
+func viewDetailPane(t *todo.Todo, split bool, width, height int) string {
+	lines := detailLines(t, width-3, -1)
+	if len(lines) > height {
+		lines = append(lines[:height-1], styles.Help.Render(fmt.Sprintf("… %d more lines", len(lines)-height+1)))
+	}
+	content := strings.Join(lines, "\n")
+
+	style := lipgloss.NewStyle().BorderStyle(lipgloss.NormalBorder()).BorderForeground(styles.Subtle)
+	if split {
+		return style.BorderLeft(true).PaddingLeft(2).Width(width).MaxHeight(height).Render(content)
+	}
+	return style.BorderTop(true).PaddingTop(1).Width(width).MaxHeight(height + 2).Render(content)
+}
+
 // ViewList renders the list view.
 func (m Model) ViewList() string {
 	var b strings.Builder
+	kb := m.Config.Keys()
 
 	header := "  TODOs"
 	if len(m.Todos) > 0 {
 		header += styles.Help.Render(fmt.Sprintf(" (%d)", len(m.Todos)))
 	}
+	if m.Query != "" {
+		header += styles.Help.Render(" • best matches first")
+	} else if m.Sort != "" {
+		header += styles.Help.Render(" • by " + describeSort(m.Sort))
+	}
 	b.WriteString(styles.Title.Render(header))
 	b.WriteString("\n")
+	if chips := m.viewChips(); chips != "" {
+		b.WriteString(chips)
+		b.WriteString("\n")
+	}
+	if search := m.viewSearch(); search != "" {
+		b.WriteString(search)
+		b.WriteString("\n")
+	}
 	b.WriteString(styles.Help.Render("─────────────────────────────────────────"))
 	b.WriteString("\n\n")
 
-	if len(m.Todos) == 0 {
+	if len(m.Todos) == 0 && m.Query != "" {
+		b.WriteString(styles.Help.Render(fmt.Sprintf("  No TODOs match %q", strings.TrimSpace(m.Query))))
+		b.WriteString("\n")
+	} else if len(m.Todos) == 0 && len(m.All) > 0 {
+		b.WriteString(styles.Help.Render(fmt.Sprintf("  No TODOs match the filters (%s/%s/%s/%s/%s to change them)",
+			kb.List.FilterBranch, kb.List.FilterDue, kb.List.FilterPrompts, kb.List.FilterActive, kb.List.FilterStatus)))
+		b.WriteString("\n")
+	} else if len(m.Todos) == 0 {
 		b.WriteString(m.viewEmptyState())
 	} else {
 		b.WriteString(m.viewTodoCards())
 	}
 
+	// The selected TODO's detail, beside the cards or below them
+	if split, height := m.layout(); height > 0 && len(m.Todos) > 0 {
+		if split {
+			pane := viewDetailPane(&m.Todos[m.Cursor], split, m.Width-listWidth-8, height)
+			list := lipgloss.NewStyle().Width(listWidth).Render(b.String())
+			b.Reset()
+			b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, list, pane))
+		} else {
+			b.WriteString("\n\n")
+			b.WriteString(viewDetailPane(&m.Todos[m.Cursor], split, m.Width-4, height))
+		}
+	}
+
 	b.WriteString("\n\n")
-	kb := m.Config.Keys()
 	b.WriteString(styles.Help.Render(fmt.Sprintf("↑/%s ↓/%s navigate • %s/%s top/bottom • %s/%s page",
 		kb.Global.MoveUp, kb.Global.MoveDown, kb.List.Top, kb.List.Bottom, kb.List.PageUp, kb.List.PageDown)))
 	b.WriteString("\n")
-	b.WriteString(styles.Help.Render(fmt.Sprintf("%s edit • %s new • %s delete • %s back",
-		kb.List.Select, kb.List.New, kb.List.Delete, kb.Global.Quit)))
+	b.WriteString(styles.Help.Render(fmt.Sprintf("%s edit • %s details • %s new • %s delete • %s status • %s snooze • %s run • %s search • %s sort • %s/%s/%s/%s/%s filter • %s stats • %s back",
+		kb.List.Select, kb.List.Details, kb.List.New, kb.List.Delete, kb.List.Status, kb.List.Snooze, kb.Detail.Run, kb.List.Search, kb.List.Sort,
+		kb.List.FilterBranch, kb.List.FilterDue, kb.List.FilterPrompts, kb.List.FilterActive, kb.List.FilterStatus, kb.List.Stats, kb.Global.Quit)))
 
 	return b.String()
 }
 
+// chip renders a quick filter that is on.
+var chip = lipgloss.NewStyle().Foreground(styles.Purple).Reverse(true).Padding(0, 1)
+
+// viewChips renders the quick filters that are on and the status filter,
+// "" if none is.
+func (m Model) viewChips() string {
+	var chips []string
+	if m.Status != "" {
+		chips = append(chips, chip.Render(describeStatusFilter(m.Status)))
+	}
+	for _, f := range quickFilters {
+		if m.filtering(f.Name) {
+			chips = append(chips, chip.Render(f.Label))
+		}
+	}
+	if len(chips) == 0 {
+		return ""
+	}
+	return "  " + strings.Join(chips, " ")
+}
+
 func (m Model) viewEmptyState() string {
 	var b strings.Builder
 
@@ -173,10 +334,7 @@ func (m Model) viewEmptyState() string {
 func (m Model) viewTodoCards() string {
 	var b strings.Builder
 
-	visibleItems := (m.Height - 10) / 5
-	if visibleItems < 1 {
-		visibleItems = 1
-	}
+	visibleItems := m.visibleItems()
 	if visibleItems > len(m.Todos) {
 		visibleItems = len(m.Todos)
 	}
@@ -191,32 +349,63 @@ func (m Model) viewTodoCards() string {
 		endIdx = len(m.Todos)
 	}
 
+	now := time.Now()
+	layout := m.Config.Settings.TodoCards
 	for i := m.ListScroll; i < endIdx; i++ {
 		t := m.Todos[i]
 		isSelected := i == m.Cursor
 
+		corner := "┌─ "
+		if layout.Compact {
+			corner = ""
+		}
+		mark := statusMark(t.Status) + " "
 		if isSelected {
 			b.WriteString(styles.Cursor.Render("▸ "))
-			b.WriteString(styles.Selected.Render("┌─ "))
+			b.WriteString(styles.Selected.Render(corner))
+			b.WriteString(mark)
 			b.WriteString(styles.Selected.Render(t.Name))
 		} else {
 			b.WriteString("  ")
-			b.WriteString(styles.Help.Render("┌─ "))
-			b.WriteString(styles.Item.Render(t.Name))
+			b.WriteString(styles.Help.Render(corner))
+			b.WriteString(mark)
+			if t.Status == todo.StatusDone {
+				b.WriteString(styles.Help.Render(t.Name))
+			} else {
+				b.WriteString(styles.Item.Render(t.Name))
+			}
+		}
+
+		var fields []string
+		description := false
+		for _, name := range layout.Fields {
+			if name == "description" {
+				description = true
+			} else if f := cardField(&t, name, now); f != "" {
+				fields = append(fields, f)
+			}
+		}
+		separator := styles.Help.Render("  •  ")
+
+		if layout.Compact {
+			if len(fields) > 0 {
+				b.WriteString(separator)
+				b.WriteString(strings.Join(fields, separator))
+			}
+			b.WriteString("\n")
+			continue
 		}
 		b.WriteString("\n")
 
 		prefix := "  "
-		b.WriteString(prefix)
-		b.WriteString(styles.Help.Render("│  "))
-		b.WriteString(styles.Branch.Render(" " + t.Branch))
-		b.WriteString(styles.Help.Render(fmt.Sprintf("  •  %d prompt", len(t.Prompts))))
-		if len(t.Prompts) != 1 {
-			b.WriteString(styles.Help.Render("s"))
+		if len(fields) > 0 {
+			b.WriteString(prefix)
+			b.WriteString(styles.Help.Render("│  "))
+			b.WriteString(strings.Join(fields, separator))
+			b.WriteString("\n")
 		}
-		b.WriteString("\n")
 
-		if t.Description != "" {
+		if description && t.Description != "" {
 			desc := strings.Split(t.Description, "\n")[0]
 			if len(desc) > 40 {
 				desc = desc[:37] + "..."
@@ -243,3 +432,92 @@ func (m Model) viewTodoCards() string {
 
 	return b.String()
 }
+
+// statusMark renders the mark of a status shown before a todo's name.
+func statusMark(s todo.Status) string {
+	switch s {
+	case todo.StatusInProgress:
+		return styles.Confirm.Render("◐")
+	case todo.StatusDone:
+		return styles.Selected.Render("✓")
+	}
+	return styles.Help.Render("○")
+}
+
+// progressMark renders how many subtasks are done of total, e.g. "☑ 3/5",
+// highlighted once all are.
+func progressMark(done, total int) string {
+	text := fmt.Sprintf("☑ %d/%d", done, total)
+	if done == total {
+		return styles.Selected.Render(text)
+	}
+	return styles.Help.Render(text)
+}
+
+// priorityMark renders a priority with a style for its urgency.
+func priorityMark(p todo.Priority) string {
+	switch p {
+	case todo.PriorityHigh:
+		return styles.Error.Render("▲ high")
+	case todo.PriorityMedium:
+		return styles.Confirm.Render("▲ medium")
+	case todo.PriorityLow:
+		return styles.Help.Render("▽ low")
+	}
+	return styles.Help.Render(p.String())
+}
+
+// cardField renders the named field of a todo card, "" if t has none or
+// the name is unknown. Overdue todos are highlighted; snoozed todos are
+// dimmed.
+func cardField(t *todo.Todo, name string, now time.Time) string {
+	switch name {
+	case "branch":
+		return styles.Branch.Render(" " + t.Branch)
+	case "priority":
+		if t.Priority == todo.PriorityNone {
+			return ""
+		}
+		return priorityMark(t.Priority)
+	case "subtasks":
+		if len(t.Subtasks) == 0 {
+			return ""
+		}
+		return progressMark(t.Progress())
+	case "prompts":
+		if len(t.Prompts) == 1 {
+			return styles.Help.Render("1 prompt")
+		}
+		return styles.Help.Render(fmt.Sprintf("%d prompts", len(t.Prompts)))
+	case "due":
+		if t.DueDate == nil {
+			return ""
+		}
+		due := "due " + t.DueDate.Format("Jan 2")
+		if t.IsOverdue(now) {
+			return styles.Error.Render(due)
+		}
+		return styles.Help.Render(due)
+	case "snoozed":
+		if !t.IsSnoozed(now) {
+			return ""
+		}
+		return styles.Help.Render("💤 until " + t.SnoozedUntil.Format("Jan 2"))
+	case "model":
+		if t.Model == "" {
+			return ""
+		}
+		return styles.Prompt.Render("◆ " + t.Model)
+	case "created":
+		if t.CreatedAt.IsZero() {
+			return ""
+		}
+		return styles.Help.Render("created " + t.CreatedAt.Format("Jan 2"))
+	case "updated":
+		if t.UpdatedAt.IsZero() {
+			return ""
+		}
+		return styles.Help.Render("updated " + t.UpdatedAt.Format("Jan 2"))
+	}
+	return ""
+}
@@ -2,13 +2,23 @@
 package todo
 
 import (
+	"context"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/ihatemodels/gdev/internal/budget"
+	"github.com/ihatemodels/gdev/internal/claude"
 	"github.com/ihatemodels/gdev/internal/config"
 	"github.com/ihatemodels/gdev/internal/store"
 	"github.com/ihatemodels/gdev/internal/todo"
+	"github.com/ihatemodels/gdev/internal/ui/confirm"
+	"github.com/ihatemodels/gdev/internal/ui/datepicker"
+	"github.com/ihatemodels/gdev/internal/ui/diffview"
+	"github.com/ihatemodels/gdev/internal/ui/failure"
+	"github.com/ihatemodels/gdev/internal/ui/form"
+	"github.com/ihatemodels/gdev/internal/ui/nav"
 	"github.com/ihatemodels/gdev/internal/ui/styles"
 	"github.com/ihatemodels/gdev/internal/ui/terminal"
 )
@@ -24,15 +34,28 @@ const (
 	DeleteConfirmView
 	PromptEditorView
 	TerminalView
+	ImproveReviewView
+	SnoozeView
+	RunGateView
+	RunSummaryView
+	CheckpointDiffView
+	RollbackConfirmView
+	StatsView
+	BudgetConfirmView
 )
 
 // FormField represents which field is being edited in a form.
+// Fields before FieldPrompts index into Model.FormFields.
 type FormField int
 
 const (
 	FieldBranch FormField = iota
 	FieldName
 	FieldDescription
+	FieldDue
+	FieldModel
+	FieldPriority
+	FieldSubtasks
 	FieldPrompts
 )
 
@@ -43,17 +66,27 @@ type Model struct {
 	RepoPath string
 	Branch   string // current branch for defaults
 
-	CurrentView View
-	Todos       []todo.Todo
-	Cursor      int
+	Views  nav.Stack[View] // open views, ListView at the bottom
+	All    []todo.Todo     // every todo of the repository
+	Todos  []todo.Todo     // All as sorted and filtered for the list
+	Cursor int
+
+	// How the list is sorted and filtered, see sorts, quickFilters and
+	// statusFilters
+	Sort    string
+	Filters []string
+	Status  string
+
+	// Search of the list, see searchScore
+	Searching bool   // true while typing the query
+	Query     string // narrows the list as it is typed
 
 	// For detail view
-	SelectedTodo *todo.Todo
+	SelectedTodo  *todo.Todo
+	SubtaskCursor int // the subtask toggled in the detail view
 
 	// Form fields
-	FormBranch      string
-	FormName        string
-	FormDescription string
+	FormFields      []form.Field // simple fields, indexed by FormField
 	FormPrompts     []string
 	FormField       FormField
 	FormPromptIdx   int  // which prompt is selected when editing prompts
@@ -62,11 +95,16 @@ type Model struct {
 
 	// Delete confirmation
 	DeleteTarget *todo.Todo
+	Confirm      confirm.Model
+
+	// Snooze date selection
+	SnoozeTarget *todo.Todo
+	DatePicker   datepicker.Model
 
 	// Prompt editor state
 	EditorContent   string
 	EditorCursorPos int
-	PreviousView    View
+	EditorPreview   bool // the rendered markdown is shown beside the text
 
 	// Scrolling state
 	ListScroll   int // scroll offset for list view
@@ -79,9 +117,85 @@ type Model struct {
 	Loading   bool
 	Improving bool // true when LLM is improving a prompt
 
+	// Improved prompt review state
+	ReviewIdx      int    // index of the prompt being reviewed
+	ReviewOriginal string // prompt before improvement
+	ReviewImproved string // prompt proposed by the LLM
+	ReviewScroll   int    // scroll offset for both panes
+
+	// Called once a request past the AI budget is confirmed
+	OnBudgetConfirm func(Model) (tea.Model, tea.Cmd)
+
+	// Improving all prompts at once: the prompt of each job, those still
+	// running, and the results waiting to be reviewed
+	ImproveCancel  context.CancelFunc // nil unless a batch is running
+	ImproveIdxs    []int
+	ImprovePending map[int]bool
+	ImproveFailed  int
+	ReviewQueue    []ImprovedPrompt
+
+	// Running a todo's prompts in turn: the todo, the prompt waiting at the
+	// gate, the claude session the prompts share and every one started, how
+	// each prompt ended and what it wrote, when the run started and the
+	// working tree then, and what it was estimated to use
+	RunTodo     *todo.Todo
+	RunIdx      int
+	RunSession  string
+	RunSessions []string
+	RunStatus   []runStatus
+	RunOutputs  []string
+	RunStarted  time.Time
+	RunBase     string // see git.Snapshot
+	RunEstimate claude.Usage
+	RunBudget   budget.Status // of claude when the gate opened
+	RunRecorded claude.Usage  // of the run's sessions, already in the usage log
+
+	// Checkpoints of the working tree taken during the run, if they are
+	// turned on in settings, and why they stopped if one failed
+	RunCheckpoints   []checkpoint
+	RunCheckpointErr error
+
+	// What the last run did, shown once its prompts have been handled
+	Summary runSummary
+
+	// Changes since a checkpoint of the run
+	DiffView diffview.Model
+
 	// Terminal modal for running commands
-	Terminal         terminal.Model
-	TerminalCallback func(m *Model, output string) // callback when terminal closes
+	Terminal     terminal.Model
+	TerminalJob  terminalJob              // what its command runs for
+	TerminalDone *terminal.CommandDoneMsg // how it ended, nil while it runs
+}
+
+// jobKind is the kind of work a command in the terminal modal does.
+type jobKind int
+
+const (
+	jobNone      jobKind = iota
+	jobRunPrompt         // sends a prompt of a run, see runPrompt
+	jobImprove           // improves a prompt of the form
+	jobRollback          // rolls the working tree back to a checkpoint
+)
+
+// terminalJob is what the command in the terminal modal runs for. Closing
+// the modal hands it the command's CommandDoneMsg, which decides what
+// happens next.
+type terminalJob struct {
+	Kind       jobKind
+	Prompt     int        // index of the prompt sent or improved
+	NewSession bool       // the prompt starts the claude session of the run
+	Checkpoint checkpoint // rolled back to
+	Todo       string     // name of the todo rolled back
+}
+
+// openTerminal shows the terminal modal for a command run for job.
+func (m *Model) openTerminal(title string, job terminalJob) {
+	m.Terminal = terminal.New(m.Config, title)
+	m.Terminal.Dir = m.RepoPath
+	m.Terminal.SetSize(m.Width, m.Height)
+	m.TerminalJob = job
+	m.TerminalDone = nil
+	m.Views.Push(TerminalView)
 }
 
 // Message types
@@ -90,14 +204,13 @@ type (
 		Todos []todo.Todo
 	}
 
-	TodoErrorMsg struct {
-		Err error
-	}
-
 	TodoSavedMsg struct{}
 
 	TodoDeletedMsg struct{}
 
+	// CapturedMsg is sent once a TODO added with Capture is saved.
+	CapturedMsg struct{}
+
 	BackToMenuMsg struct{}
 )
 
@@ -108,7 +221,7 @@ func New(s *store.Store, cfg *config.Config, repoPath, branch string) Model {
 		Config:      cfg,
 		RepoPath:    repoPath,
 		Branch:      branch,
-		CurrentView: ListView,
+		Views:       nav.NewStack(ListView),
 		FormPrompts: []string{""},
 	}
 }
@@ -121,16 +234,32 @@ func (m *Model) SetSize(width, height int) {
 
 // Init implements tea.Model.
 func (m Model) Init() tea.Cmd {
-	return m.LoadTodos
+	return m.LoadTodos()
 }
 
-// LoadTodos loads the todos from the store.
-func (m Model) LoadTodos() tea.Msg {
-	list, err := m.Store.GetTodos(m.RepoPath)
-	if err != nil {
-		return TodoErrorMsg{Err: err}
+// LoadTodos returns a command that loads the todos from the store.
+func (m Model) LoadTodos() tea.Cmd {
+	return failure.Cmd("Load TODOs", func() (tea.Msg, error) {
+		list, err := m.Store.GetTodos(m.RepoPath)
+		if err != nil {
+			return nil, err
+		}
+		return TodosLoadedMsg{Todos: list.Todos}, nil
+	})
+}
+
+// Capture adds a TODO named name on the current branch, as `gdev capture`
+// does, and calls done with the result of saving it.
+func (m Model) Capture(name string, done func(error)) tea.Cmd {
+	t := todo.NewTodo(m.Branch, name, "", nil)
+	return func() tea.Msg {
+		err := m.Store.AddTodo(m.RepoPath, t)
+		done(err)
+		if err != nil {
+			return nil
+		}
+		return CapturedMsg{}
 	}
-	return TodosLoadedMsg{Todos: list.Todos}
 }
 
 // Update implements tea.Model.
@@ -140,37 +269,70 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.Width = msg.Width
 		m.Height = msg.Height
 		m.Terminal.SetSize(msg.Width, msg.Height)
+		m.DiffView.SetSize(msg.Width-4, msg.Height-4)
 		return m, nil
 
 	case TodosLoadedMsg:
-		m.Todos = msg.Todos
+		m.All = msg.Todos
 		m.Loading = false
+		m.applyView()
 		return m, nil
 
-	case TodoErrorMsg:
-		m.ErrMsg = msg.Err.Error()
+	case failure.Msg:
+		// The app shows the error screen; stop waiting for the result
 		m.Loading = false
 		return m, nil
 
 	case TodoSavedMsg:
-		m.CurrentView = ListView
+		m.Views.Reset()
 		m.ErrMsg = ""
-		return m, m.LoadTodos
+		return m, m.LoadTodos()
+
+	case CapturedMsg:
+		return m, m.LoadTodos()
 
 	case TodoDeletedMsg:
-		m.CurrentView = ListView
+		m.Views.Reset()
 		m.DeleteTarget = nil
-		return m, m.LoadTodos
+		return m, m.LoadTodos()
+
+	case PromptImprovedMsg:
+		return m.handlePromptImproved(msg)
+
+	case RunRecapMsg:
+		return m.handleRunRecap(msg)
+
+	case RunSavedMsg, SubtasksSavedMsg:
+		return m, m.LoadTodos()
+
+	case diffview.FilesLoadedMsg, diffview.FileDiffLoadedMsg:
+		var cmd tea.Cmd
+		m.DiffView, cmd, _ = m.DiffView.Update(msg)
+		return m, cmd
+
+	case RunExportedMsg:
+		m.Summary.Exported = msg.Path
+		return m, nil
 
 	case terminal.TickMsg:
 		// Forward tick messages to terminal
-		if m.CurrentView == TerminalView {
+		if m.Views.Is(TerminalView) {
 			var cmd tea.Cmd
 			m.Terminal, cmd = m.Terminal.Update(msg)
 			return m, cmd
 		}
 		return m, nil
 
+	case terminal.CommandDoneMsg:
+		// Kept until the terminal is closed, which is when it is acted on
+		if msg.ID == m.Terminal.ID {
+			m.TerminalDone = &msg
+		}
+		return m, nil
+
+	case tea.MouseMsg:
+		return m.handleMouseMsg(msg)
+
 	case tea.KeyMsg:
 		m.ErrMsg = ""
 		return m.handleKeyMsg(msg)
@@ -178,8 +340,43 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// wheelLines is how many lines a notch of the mouse wheel scrolls the
+// detail view.
+const wheelLines = 3
+
+// handleMouseMsg scrolls with the mouse wheel: the terminal modal and the
+// detail view by lines, the list a todo at a time. Other views keep to
+// the keys.
+func (m Model) handleMouseMsg(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.Views.Is(TerminalView) {
+		var cmd tea.Cmd
+		m.Terminal, cmd = m.Terminal.Update(msg)
+		return m, cmd
+	}
+	if msg.Action != tea.MouseActionPress {
+		return m, nil
+	}
+
+	var delta int
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		delta = -1
+	case tea.MouseButtonWheelDown:
+		delta = 1
+	default:
+		return m, nil
+	}
+	switch m.Views.Current() {
+	case ListView:
+		m.moveCursor(delta)
+	case DetailView:
+		m.DetailScroll = max(m.DetailScroll+delta*wheelLines, 0)
+	}
+	return m, nil
+}
+
 func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch m.CurrentView {
+	switch m.Views.Current() {
 	case ListView:
 		return m.UpdateListView(msg)
 	case DetailView:
@@ -192,6 +389,22 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.UpdatePromptEditor(msg)
 	case TerminalView:
 		return m.UpdateTerminalView(msg)
+	case ImproveReviewView:
+		return m.UpdateImproveReview(msg)
+	case SnoozeView:
+		return m.UpdateSnoozeView(msg)
+	case RunGateView:
+		return m.UpdateRunGate(msg)
+	case RunSummaryView:
+		return m.UpdateRunSummary(msg)
+	case CheckpointDiffView:
+		return m.UpdateCheckpointDiff(msg)
+	case RollbackConfirmView:
+		return m.UpdateRollbackConfirm(msg)
+	case StatsView:
+		return m.UpdateStats(msg)
+	case BudgetConfirmView:
+		return m.UpdateBudgetConfirm(msg)
 	}
 	return m, nil
 }
@@ -200,15 +413,16 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (m Model) UpdateTerminalView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Check if user wants to close the terminal
 	if m.Terminal.ShouldClose(msg) {
-		// Get raw output before closing (without status messages)
-		output := m.Terminal.GetRawOutput()
-		// Execute callback if set
-		if m.TerminalCallback != nil {
-			m.TerminalCallback(&m, output)
+		m.Terminal.Cancel()
+		m.Views.Pop()
+		// A command closed before it was done counts as canceled
+		res := m.Terminal.Unfinished()
+		if m.TerminalDone != nil {
+			res = *m.TerminalDone
 		}
-		m.CurrentView = m.PreviousView
-		m.TerminalCallback = nil
-		return m, nil
+		job := m.TerminalJob
+		m.TerminalJob, m.TerminalDone = terminalJob{}, nil
+		return m.finishJob(job, res)
 	}
 
 	// Forward other keys to terminal for scrolling
@@ -217,6 +431,20 @@ func (m Model) UpdateTerminalView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// finishJob acts on the result of the command run for job once the
+// terminal modal is closed; it may switch to a follow-up view.
+func (m Model) finishJob(job terminalJob, res terminal.CommandDoneMsg) (tea.Model, tea.Cmd) {
+	switch job.Kind {
+	case jobRunPrompt:
+		return m.finishPrompt(job, res)
+	case jobImprove:
+		return m.finishImprove(job, res)
+	case jobRollback:
+		return m.finishRollback(job, res)
+	}
+	return m, nil
+}
+
 // View implements tea.Model.
 func (m Model) View() string {
 	if m.Width == 0 {
@@ -224,13 +452,17 @@ func (m Model) View() string {
 	}
 
 	// Terminal view renders as a centered modal overlay
-	if m.CurrentView == TerminalView {
+	if m.Views.Is(TerminalView) {
 		return m.Terminal.ViewCentered(m.Width, m.Height)
 	}
 
 	var content strings.Builder
 
-	switch m.CurrentView {
+	// The breadcrumb takes the place of the top padding
+	content.WriteString(m.Breadcrumb())
+	content.WriteString("\n")
+
+	switch m.Views.Current() {
 	case ListView:
 		content.WriteString(m.ViewList())
 	case DetailView:
@@ -243,6 +475,22 @@ func (m Model) View() string {
 		content.WriteString(m.ViewDeleteConfirm())
 	case PromptEditorView:
 		content.WriteString(m.ViewPromptEditor())
+	case ImproveReviewView:
+		content.WriteString(m.ViewImproveReview())
+	case SnoozeView:
+		content.WriteString(m.ViewSnooze())
+	case RunGateView:
+		content.WriteString(m.ViewRunGate())
+	case RunSummaryView:
+		content.WriteString(m.ViewRunSummary())
+	case CheckpointDiffView:
+		content.WriteString(m.DiffView.View())
+	case RollbackConfirmView:
+		content.WriteString(m.Confirm.View())
+	case StatsView:
+		content.WriteString(m.ViewStats())
+	case BudgetConfirmView:
+		content.WriteString(m.Confirm.View())
 	}
 
 	if m.ErrMsg != "" {
@@ -253,6 +501,53 @@ func (m Model) View() string {
 	return lipgloss.NewStyle().
 		Width(m.Width).
 		Height(m.Height).
-		Padding(1, 2).
+		Padding(0, 2, 1).
 		Render(content.String())
 }
+
+// String returns the name of the view shown in breadcrumbs.
+func (v View) String() string {
+	switch v {
+	case ListView:
+		return "TODOs"
+	case DetailView:
+		return "Details"
+	case CreateView:
+		return "New"
+	case EditView:
+		return "Edit"
+	case DeleteConfirmView:
+		return "Delete"
+	case PromptEditorView:
+		return "Prompt"
+	case TerminalView:
+		return "Terminal"
+	case ImproveReviewView:
+		return "Review"
+	case SnoozeView:
+		return "Snooze"
+	case RunGateView:
+		return "Run"
+	case RunSummaryView:
+		return "Summary"
+	case CheckpointDiffView:
+		return "Changes"
+	case RollbackConfirmView:
+		return "Roll back"
+	case StatsView:
+		return "Stats"
+	case BudgetConfirmView:
+		return "Budget"
+	}
+	return ""
+}
+
+// Breadcrumb renders the path of open views, e.g. "TODOs › Edit › Prompt".
+func (m Model) Breadcrumb() string {
+	views := m.Views.Views()
+	crumbs := make([]string, len(views))
+	for i, v := range views {
+		crumbs[i] = v.String()
+	}
+	return nav.Breadcrumb(crumbs...)
+}
@@ -0,0 +1,125 @@
+package todo
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/store"
+	"github.com/ihatemodels/gdev/internal/todo"
+)
+
+// Rendering budgets, per call. Keys are handled and the screen redrawn
+// within a frame at 60fps (16ms) on a laptop; the budgets leave room for
+// slower machines and CI, while still catching accidentally quadratic work.
+const (
+	listViewBudget   = 16 * time.Millisecond // the list of 1,000 todos
+	editorWrapBudget = 16 * time.Millisecond // a 50KB prompt
+)
+
+// benchModel returns a model showing n todos, with its config in a
+// temporary home.
+func benchModel(tb testing.TB, n int) Model {
+	tb.Setenv("HOME", tb.TempDir())
+	s, err := store.New()
+	if err != nil {
+		tb.Fatalf("Failed to create store: %v", err)
+	}
+	cfg, err := config.Load(s)
+	if err != nil {
+		tb.Fatalf("Failed to load config: %v", err)
+	}
+
+	m := New(s, cfg, "/repo", "main")
+	m.SetSize(160, 50)
+	due := time.Now().Add(48 * time.Hour)
+	for i := range n {
+		t := todo.NewTodo(fmt.Sprintf("feature-%d", i%20), fmt.Sprintf("Todo %d", i),
+			"Fix the **flaky** test in `internal/store` and\n- check retries\n- [x] add logging",
+			[]string{"Find why the test fails", "Fix it"})
+		if i%3 == 0 {
+			t.DueDate = &due
+		}
+		if i%2 == 0 {
+			t.SetSubtasks([]string{"Reproduce it", "Fix it", "Add a test"})
+			t.ToggleSubtask(0)
+		}
+		m.All = append(m.All, *t)
+	}
+	m.applyView()
+	return m
+}
+
+// benchPrompt returns a markdown prompt of about size bytes, with the
+// occasional line too long for the editor.
+func benchPrompt(size int) string {
+	var b strings.Builder
+	for i := 0; b.Len() < size; i++ {
+		if i%10 == 0 {
+			b.WriteString(strings.Repeat("a very long line that has to be wrapped ", 8))
+		} else {
+			fmt.Fprintf(&b, "- step %d: change `file%d.go` and run the tests", i, i)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func BenchmarkViewList(b *testing.B) {
+	m := benchModel(b, 1000)
+	b.ResetTimer()
+	for range b.N {
+		_ = m.View()
+	}
+}
+
+func BenchmarkWrapEditorContent(b *testing.B) {
+	m := benchModel(b, 0)
+	m.EditorContent = benchPrompt(50 << 10)
+	m.EditorCursorPos = len(m.EditorContent) / 2
+	b.ResetTimer()
+	for range b.N {
+		_, _, _ = m.wrapEditorContent(100)
+	}
+}
+
+// TestRenderBudget fails when a rendering hot path is over its budget. It
+// only runs with GDEV_RENDER_BUDGETS=1: timings depend on the machine and
+// its load.
+func TestRenderBudget(t *testing.T) {
+	if os.Getenv("GDEV_RENDER_BUDGETS") != "1" {
+		t.Skip("set GDEV_RENDER_BUDGETS=1 to check the rendering budgets")
+	}
+	budgets := []struct {
+		name   string
+		bench  func(*testing.B)
+		budget time.Duration
+	}{
+		{"ViewList", BenchmarkViewList, listViewBudget},
+		{"WrapEditorContent", BenchmarkWrapEditorContent, editorWrapBudget},
+	}
+	for _, tt := range budgets {
+		res := testing.Benchmark(tt.bench)
+		if got := time.Duration(res.NsPerOp()); got > tt.budget {
+			t.Errorf("%s takes %v, over its budget of %v", tt.name, got, tt.budget)
+		}
+	}
+}
+
+func TestWrapEditorContent_Wide(t *testing.T) {
+	m := benchModel(t, 0)
+	m.EditorContent = "ab漢字かな\n絵"
+	m.EditorCursorPos = strings.Index(m.EditorContent, "か")
+
+	lines, line, col := m.wrapEditorContent(6)
+	want := []string{"ab漢字", "かな", "絵"}
+	if strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Errorf("lines = %q; want %q", lines, want)
+	}
+	if line != 1 || col != 0 {
+		t.Errorf("cursor at line %d, byte %d; want line 1, byte 0", line, col)
+	}
+}
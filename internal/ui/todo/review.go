@@ -0,0 +1,128 @@
+package todo
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+)
+
+// sideBySideMinWidth is the minimum width for showing both prompts side by side.
+const sideBySideMinWidth = 100
+
+// UpdateImproveReview handles input for the improved prompt review view.
+func (m Model) UpdateImproveReview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	kb := m.Config.Keys()
+
+	switch {
+	case config.Matches(key, kb.Review.Accept):
+		if m.ReviewIdx >= 0 && m.ReviewIdx < len(m.FormPrompts) {
+			m.FormPrompts[m.ReviewIdx] = m.ReviewImproved
+			m.FormPromptIdx = m.ReviewIdx
+		}
+		m.closeImproveReview()
+
+	case config.MatchesAny(key, kb.Review.Reject, kb.Global.Quit):
+		m.closeImproveReview()
+
+	case config.Matches(key, kb.Review.Edit):
+		// Open the improved prompt in the editor; saving writes it back
+		m.FormPromptIdx = m.ReviewIdx
+		m.EditorContent = m.ReviewImproved
+		m.EditorCursorPos = len(m.EditorContent)
+		m.closeImproveReview()
+		m.Views.Push(PromptEditorView)
+
+	case config.MatchesAny(key, kb.Global.MoveUp, kb.Global.MoveUpAlt):
+		if m.ReviewScroll > 0 {
+			m.ReviewScroll--
+		}
+
+	case config.MatchesAny(key, kb.Global.MoveDown, kb.Global.MoveDownAlt):
+		m.ReviewScroll++
+	}
+
+	return m, nil
+}
+
+// closeImproveReview clears review state and moves on to the next improved
+// prompt, or returns to the form.
+func (m *Model) closeImproveReview() {
+	m.ReviewOriginal = ""
+	m.ReviewImproved = ""
+	m.ReviewScroll = 0
+	m.Views.Pop()
+	m.nextReview()
+}
+
+// ViewImproveReview renders the original and improved prompts for comparison.
+func (m Model) ViewImproveReview() string {
+	var b strings.Builder
+
+	b.WriteString(styles.Title.Render("  Review Improved Prompt"))
+	b.WriteString("\n")
+	b.WriteString(styles.Help.Render("─────────────────────────────────────────────────────"))
+	b.WriteString("\n\n")
+
+	if m.Width >= sideBySideMinWidth {
+		paneWidth := (m.Width-10)/2 - 2
+		paneHeight := m.Height - 12
+		left := m.renderReviewPane("Original", m.ReviewOriginal, paneWidth, paneHeight, styles.Subtle)
+		right := m.renderReviewPane("Improved", m.ReviewImproved, paneWidth, paneHeight, styles.Green)
+		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, left, "  ", right))
+	} else {
+		paneWidth := m.Width - 10
+		paneHeight := (m.Height - 16) / 2
+		b.WriteString(m.renderReviewPane("Original", m.ReviewOriginal, paneWidth, paneHeight, styles.Subtle))
+		b.WriteString("\n")
+		b.WriteString(m.renderReviewPane("Improved", m.ReviewImproved, paneWidth, paneHeight, styles.Green))
+	}
+
+	b.WriteString("\n\n")
+	kb := m.Config.Keys()
+	b.WriteString(styles.Help.Render(fmt.Sprintf("%s accept • %s reject • %s edit • ↑/%s ↓/%s scroll",
+		kb.Review.Accept, kb.Review.Reject, kb.Review.Edit, kb.Global.MoveUp, kb.Global.MoveDown)))
+
+	return b.String()
+}
+
+// renderReviewPane renders a titled, bordered pane with wrapped and scrolled text.
+func (m Model) renderReviewPane(title, text string, width, height int, border lipgloss.Color) string {
+	if width < 20 {
+		width = 20
+	}
+	if height < 3 {
+		height = 3
+	}
+
+	wrapped := lipgloss.NewStyle().Width(width).Render(text)
+	lines := strings.Split(wrapped, "\n")
+
+	scroll := m.ReviewScroll
+	if maxScroll := len(lines) - height; scroll > maxScroll {
+		scroll = max(maxScroll, 0)
+	}
+	end := min(scroll+height, len(lines))
+
+	var body strings.Builder
+	body.WriteString(styles.Label.Render(title))
+	body.WriteString("\n")
+	for i := scroll; i < end; i++ {
+		body.WriteString(styles.Value.Render(lines[i]))
+		if i < end-1 {
+			body.WriteString("\n")
+		}
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(border).
+		Padding(0, 1).
+		Width(width + 2).
+		Height(height + 1).
+		Render(body.String())
+}
@@ -0,0 +1,314 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/budget"
+	"github.com/ihatemodels/gdev/internal/claude"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/git"
+	"github.com/ihatemodels/gdev/internal/todo"
+	"github.com/ihatemodels/gdev/internal/ui/failure"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+	"github.com/ihatemodels/gdev/internal/ui/terminal"
+	"github.com/mattn/go-runewidth"
+)
+
+// runStatus is how a prompt of a run ended.
+type runStatus int
+
+const (
+	runPending runStatus = iota
+	runDone
+	runFailed
+	runCanceled
+	runSkipped
+)
+
+// String returns how a prompt ended, e.g. "skipped".
+func (s runStatus) String() string {
+	switch s {
+	case runDone:
+		return "done"
+	case runFailed:
+		return "failed"
+	case runCanceled:
+		return "canceled"
+	case runSkipped:
+		return "skipped"
+	}
+	return "pending"
+}
+
+// summaryLines is how many lines of a prompt's output the gate shows.
+const summaryLines = 12
+
+// startRun runs the prompts of t against claude one after another. Each one
+// waits at a gate for approval before it is sent, the first with what the
+// run is estimated to use, and the run ends with a summary of what it did.
+// Near claude's budget the gates say so; past it, sending a prompt has to be
+// confirmed on its own.
+func (m Model) startRun(t *todo.Todo) (tea.Model, tea.Cmd) {
+	if len(t.Prompts) == 0 {
+		m.ErrMsg = "This TODO has no prompts to run"
+		return m, nil
+	}
+	m.RunTodo = t
+	m.RunIdx = 0
+	m.RunSession = ""
+	m.RunSessions = nil
+	m.RunStatus = make([]runStatus, len(t.Prompts))
+	m.RunOutputs = make([]string, len(t.Prompts))
+	m.RunStarted = time.Now()
+	m.RunBase = ""
+	m.RunCheckpoints = nil
+	m.RunCheckpointErr = nil
+	m.RunRecorded = claude.Usage{}
+
+	// Without a base the summary can't tell which files the run changed
+	m.checkpoint("before the run")
+	if len(m.RunCheckpoints) > 0 {
+		m.RunBase = m.RunCheckpoints[0].Commit
+	} else {
+		m.RunBase, _ = git.Snapshot(m.RepoPath)
+	}
+	m.RunEstimate = claude.EstimatePrompts(m.RepoPath, t.Model, t.Prompts)
+	m.openRunGate()
+	return m, nil
+}
+
+// openRunGate opens the gate before the prompt at RunIdx, with claude's
+// budget as it is now.
+func (m *Model) openRunGate() {
+	// An unreadable usage log doesn't stop the run
+	m.RunBudget, _ = budget.For(m.Config, "claude")
+	m.Views.Push(RunGateView)
+}
+
+// runPrompt sends the prompt at RunIdx in the terminal modal. The prompts of
+// a run share a claude session, so each one sees what the ones before did.
+func (m Model) runPrompt() (tea.Model, tea.Cmd) {
+	idx := m.RunIdx
+	args := []string{"-p", m.RunTodo.Prompts[idx], "--permission-mode", "acceptEdits"}
+	if m.RunTodo.Model != "" {
+		args = append(args, "--model", m.RunTodo.Model)
+	}
+	newSession := m.RunSession == ""
+	if newSession {
+		m.RunSession = claude.NewSessionID()
+		m.RunSessions = append(m.RunSessions, m.RunSession)
+		args = append(args, "--session-id", m.RunSession)
+	} else {
+		args = append(args, "--resume", m.RunSession)
+	}
+
+	m.openTerminal(fmt.Sprintf("Prompt %d of %d", idx+1, len(m.RunTodo.Prompts)),
+		terminalJob{Kind: jobRunPrompt, Prompt: idx, NewSession: newSession})
+	return m, m.Terminal.RunMutatingCommand("claude", args...)
+}
+
+// finishPrompt records how the prompt of a run ended once its terminal is
+// closed, then opens the gate before the next prompt, or the summary after
+// the last one.
+func (m Model) finishPrompt(job terminalJob, res terminal.CommandDoneMsg) (tea.Model, tea.Cmd) {
+	idx := job.Prompt
+	if m.RunTodo == nil || idx >= len(m.RunStatus) {
+		return m, nil
+	}
+	switch {
+	case errors.Is(res.Err, terminal.ErrCanceled):
+		m.RunStatus[idx] = runCanceled
+	case res.Err != nil:
+		m.RunStatus[idx] = runFailed
+	default:
+		m.RunStatus[idx] = runDone
+	}
+	if job.NewSession && m.RunStatus[idx] != runDone {
+		// The session may not have been created; the next prompt starts one
+		m.RunSession = ""
+	}
+	m.RunOutputs[idx] = strings.TrimSpace(strings.Join(res.Output, "\n"))
+	m.recordRunUsage()
+	m.checkpoint(fmt.Sprintf("after prompt %d (%s)", idx+1, m.RunStatus[idx]))
+	saved := m.saveExecution(idx)
+	m.RunIdx = idx + 1
+	if m.RunIdx >= len(m.RunStatus) {
+		return m, tea.Sequence(saved, m.finishRun())
+	}
+	m.openRunGate()
+	return m, saved
+}
+
+// recordRunUsage adds what the run's sessions used since it was last called
+// to the usage log, so the budget knows of each prompt as soon as it ends,
+// whether or not the run is finished.
+func (m *Model) recordRunUsage() {
+	u, err := m.runUsage()
+	if err != nil {
+		return
+	}
+	tokens, cost := u.Total()-m.RunRecorded.Total(), u.Cost-m.RunRecorded.Cost
+	if tokens <= 0 {
+		return
+	}
+	m.Config.RecordUsage("claude", m.RunTodo.Model, tokens, cost)
+	m.RunRecorded = u
+}
+
+// saveExecution marks the prompt at idx as executed, with its output, and
+// saves the todo, so it is kept even if the run is stopped before its
+// summary.
+func (m *Model) saveExecution(idx int) tea.Cmd {
+	m.RunTodo.AddExecution(m.RunTodo.Prompts[idx], m.RunStatus[idx].String(), m.RunOutputs[idx])
+	t := *m.RunTodo
+	s, repo := m.Store, m.RepoPath
+	return failure.Cmd("Save TODO prompt run", func() (tea.Msg, error) {
+		if err := s.UpdateTodo(repo, &t); err != nil {
+			return nil, err
+		}
+		return RunSavedMsg{}, nil
+	})
+}
+
+// UpdateRunGate handles input for the gate between the prompts of a run:
+// send the next prompt, skip it, or stop the run.
+func (m Model) UpdateRunGate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	kb := m.Config.Keys()
+
+	switch {
+	case config.MatchesAny(key, kb.Global.Quit, kb.Global.QuitAlt):
+		m.Views.Pop()
+		m.RunTodo = nil
+		m.RunStatus = nil
+
+	case config.Matches(key, kb.Review.Accept):
+		if m.RunBudget.Level == budget.Over {
+			m.confirmOverBudget(m.RunBudget, func(m Model) (tea.Model, tea.Cmd) {
+				m.Views.Pop()
+				return m.runPrompt()
+			})
+			return m, nil
+		}
+		m.Views.Pop()
+		return m.runPrompt()
+
+	case config.Matches(key, kb.Review.Reject):
+		m.RunStatus[m.RunIdx] = runSkipped
+		m.RunIdx++
+		if m.RunIdx >= len(m.RunStatus) {
+			m.Views.Pop()
+			return m, m.finishRun()
+		}
+	}
+	return m, nil
+}
+
+// lastOutput returns what the last prompt that ran before the gate wrote.
+func (m Model) lastOutput() string {
+	for i := m.RunIdx - 1; i >= 0; i-- {
+		if m.RunStatus[i] != runSkipped {
+			return m.RunOutputs[i]
+		}
+	}
+	return ""
+}
+
+// ViewRunGate renders how the prompts of the run went, the output of the
+// last one that ran and the prompt waiting to be sent.
+func (m Model) ViewRunGate() string {
+	var b strings.Builder
+	kb := m.Config.Keys()
+	width := max(m.Width-10, 30)
+
+	b.WriteString(styles.Title.Render("  Run Prompts"))
+	b.WriteString(styles.Help.Render("  " + m.RunTodo.Name))
+	b.WriteString("\n")
+	b.WriteString(styles.Help.Render("─────────────────────────────────────────────────────"))
+	b.WriteString("\n\n")
+	b.WriteString(viewRunPrompts(m.RunTodo.Prompts, m.RunStatus, m.RunIdx, width))
+
+	b.WriteString("\n")
+	if m.RunIdx == 0 {
+		b.WriteString(m.viewEstimate())
+	} else if output := m.lastOutput(); output == "" {
+		b.WriteString(styles.Label.Render("Output of the last prompt:"))
+		b.WriteString("\n")
+		b.WriteString(styles.Help.Render("  (no output)"))
+		b.WriteString("\n")
+	} else {
+		b.WriteString(styles.Label.Render("Output of the last prompt:"))
+		b.WriteString("\n")
+		lines := strings.Split(output, "\n")
+		if len(lines) > summaryLines {
+			b.WriteString(styles.Help.Render(fmt.Sprintf("  … %d earlier lines", len(lines)-summaryLines)))
+			b.WriteString("\n")
+			lines = lines[len(lines)-summaryLines:]
+		}
+		for _, line := range lines {
+			b.WriteString("  " + styles.Value.Render(runewidth.Truncate(line, width, "...")) + "\n")
+		}
+	}
+
+	b.WriteString("\n")
+	ask := fmt.Sprintf("Send prompt %d?", m.RunIdx+1)
+	switch m.RunBudget.Level {
+	case budget.Over:
+		b.WriteString(styles.Confirm.Render("⚠ " + m.RunBudget.String() + ", which is used up"))
+		b.WriteString("\n")
+		ask = fmt.Sprintf("Send prompt %d anyway? It has to be confirmed.", m.RunIdx+1)
+	case budget.Warn:
+		b.WriteString(styles.Confirm.Render("⚠ " + m.RunBudget.String()))
+		b.WriteString("\n")
+	}
+	b.WriteString(styles.Confirm.Render(ask))
+	b.WriteString("\n\n")
+	b.WriteString(styles.Help.Render(fmt.Sprintf("%s send • %s skip • %s stop the run",
+		kb.Review.Accept, kb.Review.Reject, kb.Global.Quit)))
+	return b.String()
+}
+
+// viewEstimate renders what the run is estimated to use, shown before the
+// first prompt is sent.
+func (m Model) viewEstimate() string {
+	var b strings.Builder
+	b.WriteString(styles.Label.Render("Estimate: "))
+	b.WriteString(styles.Value.Render(fmt.Sprintf("at least %s tokens • %s",
+		claude.FormatTokens(m.RunEstimate.Total()), claude.FormatCost(m.RunEstimate.Cost))))
+	b.WriteString("\n")
+	b.WriteString(styles.Help.Render("  The prompts with claude's system prompt, tools and CLAUDE.md files;"))
+	b.WriteString("\n")
+	b.WriteString(styles.Help.Render("  what the model reads and writes while working comes on top"))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// viewRunPrompts lists the prompts of a run with how each one ended, and a
+// cursor at the one at next.
+func viewRunPrompts(prompts []string, status []runStatus, next, width int) string {
+	var b strings.Builder
+	for i, p := range prompts {
+		first := runewidth.Truncate(strings.Split(strings.TrimSpace(p), "\n")[0], width-20, "...")
+		line := fmt.Sprintf("Prompt %d: %s", i+1, first)
+		switch {
+		case status[i] == runDone:
+			b.WriteString(styles.Selected.Render("  ✓ ") + styles.Value.Render(line))
+		case status[i] == runFailed:
+			b.WriteString(styles.Error.Render("  ✗ ") + styles.Value.Render(line) + styles.Error.Render(" (failed)"))
+		case status[i] == runCanceled:
+			b.WriteString(styles.Error.Render("  ✗ ") + styles.Value.Render(line) + styles.Help.Render(" (canceled)"))
+		case status[i] == runSkipped:
+			b.WriteString(styles.Help.Render("  ↷ " + line + " (skipped)"))
+		case i == next:
+			b.WriteString(styles.Cursor.Render("▸ ") + styles.Selected.Render(line))
+		default:
+			b.WriteString(styles.Help.Render("  · " + line))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
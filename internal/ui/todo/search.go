@@ -0,0 +1,113 @@
+package todo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/todo"
+	"github.com/ihatemodels/gdev/internal/ui/filefinder"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+)
+
+// searchScore rates how well t matches query, each of whose words must
+// match: fuzzily in the name, which counts most, or the branch, or as a
+// substring of the description or a prompt, whose text is too long for a
+// fuzzy match to mean anything.
+func searchScore(t *todo.Todo, query string) (int, bool) {
+	total := 0
+	for _, word := range strings.Fields(query) {
+		if score, ok := filefinder.Score(word, t.Name); ok {
+			total += 1000 + score
+			continue
+		}
+		if score, ok := filefinder.Score(word, t.Branch); ok {
+			total += 100 + score
+			continue
+		}
+		w := strings.ToLower(word)
+		found := strings.Contains(strings.ToLower(t.Description), w)
+		for _, p := range t.Prompts {
+			found = found || strings.Contains(strings.ToLower(p), w)
+		}
+		if !found {
+			return 0, false
+		}
+		total++
+	}
+	return total, true
+}
+
+// search keeps the todos of the list matching Query, best matches first.
+func (m *Model) search() {
+	query := strings.TrimSpace(m.Query)
+	if query == "" {
+		return
+	}
+	scores := make(map[string]int, len(m.Todos))
+	kept := m.Todos[:0]
+	for _, t := range m.Todos {
+		if score, ok := searchScore(&t, query); ok {
+			scores[t.ID] = score
+			kept = append(kept, t)
+		}
+	}
+	m.Todos = kept
+	sort.SliceStable(m.Todos, func(i, j int) bool {
+		return scores[m.Todos[i].ID] > scores[m.Todos[j].ID]
+	})
+}
+
+// viewSearch renders the query being typed, or the one narrowing the list,
+// "" if there is none.
+func (m Model) viewSearch() string {
+	kb := m.Config.Keys()
+	if m.Searching {
+		return "  " + styles.Prompt.Render("/") + styles.Input.Render(m.Query) + styles.Cursor.Render("█") +
+			styles.Help.Render(fmt.Sprintf("  name, branch, description & prompts • enter keep • %s clear", kb.Global.Quit))
+	}
+	if m.Query != "" {
+		return styles.Help.Render(fmt.Sprintf("  /%s • %s edit • %s clear", m.Query, kb.List.Search, kb.Global.Quit))
+	}
+	return ""
+}
+
+// updateSearch handles input while typing the query, narrowing the list
+// with every key. Enter keeps the query to work with the todos found;
+// quit clears it.
+func (m Model) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	kb := m.Config.Keys()
+	key := msg.String()
+
+	switch {
+	case config.Matches(key, kb.Global.Quit):
+		m.Searching = false
+		m.Query = ""
+	case key == "enter":
+		m.Searching = false
+		m.Query = strings.TrimSpace(m.Query)
+	case config.Matches(key, kb.Global.MoveUpAlt):
+		m.moveCursor(-1)
+		return m, nil
+	case config.Matches(key, kb.Global.MoveDownAlt):
+		m.moveCursor(1)
+		return m, nil
+	case key == "backspace":
+		if r := []rune(m.Query); len(r) > 0 {
+			m.Query = string(r[:len(r)-1])
+		}
+	case msg.Type == tea.KeySpace:
+		m.Query += " "
+	case msg.Type == tea.KeyRunes:
+		m.Query += string(msg.Runes)
+	default:
+		return m, nil
+	}
+	// The best match is selected
+	m.applyView()
+	m.Cursor = 0
+	m.ListScroll = 0
+	return m, nil
+}
@@ -0,0 +1,65 @@
+package todo
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/todo"
+)
+
+func TestSearchScore(t *testing.T) {
+	td := todo.NewTodo("fix/login-timeout", "Retry flaky uploads", "Seen on **CI** only",
+		[]string{"Add exponential backoff to the uploader"})
+
+	tests := []struct {
+		query string
+		match bool
+	}{
+		{"rfu", true},           // fuzzy in the name
+		{"logtime", true},       // fuzzy in the branch
+		{"ci", true},            // in the description
+		{"backoff", true},       // in a prompt
+		{"retry backoff", true}, // every word matches somewhere
+		{"retry deploy", false},
+		{"bkf", false}, // prompts only match whole substrings
+	}
+	for _, tt := range tests {
+		if _, ok := searchScore(td, tt.query); ok != tt.match {
+			t.Errorf("searchScore(%q) matched = %v, want %v", tt.query, ok, tt.match)
+		}
+	}
+
+	name, _ := searchScore(td, "retry")
+	prompt, _ := searchScore(td, "uploader")
+	if name <= prompt {
+		t.Errorf("a match in the name (%d) should rank above one in a prompt (%d)", name, prompt)
+	}
+}
+
+func TestSearch_NarrowsWhileTyping(t *testing.T) {
+	m := benchModel(t, 30)
+	m.All[7].Name = "Upgrade the parser"
+	m.applyView()
+
+	var model tea.Model = m
+	model, _ = model.(Model).UpdateListView(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	for _, r := range "parser" {
+		model, _ = model.(Model).UpdateListView(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	m = model.(Model)
+	if len(m.Todos) != 1 || m.Todos[0].Name != "Upgrade the parser" {
+		t.Fatalf("Todos = %d, want only the parser todo", len(m.Todos))
+	}
+
+	// Enter keeps the query, quit then clears it
+	model, _ = m.UpdateListView(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(Model)
+	if m.Searching || m.Query != "parser" || len(m.Todos) != 1 {
+		t.Fatalf("after enter: searching %v, query %q, %d todos", m.Searching, m.Query, len(m.Todos))
+	}
+	model, _ = m.UpdateListView(tea.KeyMsg{Type: tea.KeyEsc})
+	m = model.(Model)
+	if m.Query != "" || len(m.Todos) != 30 {
+		t.Errorf("after quit: query %q, %d todos, want all 30", m.Query, len(m.Todos))
+	}
+}
@@ -0,0 +1,58 @@
+package todo
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/todo"
+	"github.com/ihatemodels/gdev/internal/ui/datepicker"
+	"github.com/ihatemodels/gdev/internal/ui/failure"
+)
+
+// openSnooze opens the date picker to snooze t.
+func (m *Model) openSnooze(t *todo.Todo) {
+	m.SnoozeTarget = t
+
+	initial := time.Now().AddDate(0, 0, 1)
+	if t.SnoozedUntil != nil {
+		initial = *t.SnoozedUntil
+	}
+	m.DatePicker = datepicker.New(m.Config, "Snooze "+t.Name+" until", initial)
+	m.Views.Push(SnoozeView)
+}
+
+// UpdateSnoozeView handles input for the snooze date picker.
+// Picking a date snoozes the todo; clearing it wakes the todo up.
+func (m Model) UpdateSnoozeView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var res datepicker.Result
+	m.DatePicker, res = m.DatePicker.Update(msg)
+
+	switch res {
+	case datepicker.Picked:
+		until := m.DatePicker.Value()
+		m.SnoozeTarget.SnoozedUntil = &until
+	case datepicker.Cleared:
+		m.SnoozeTarget.SnoozedUntil = nil
+	case datepicker.Canceled:
+		m.Views.Pop()
+		m.SnoozeTarget = nil
+		return m, nil
+	default:
+		return m, nil
+	}
+
+	t := m.SnoozeTarget
+	t.Update()
+	m.SnoozeTarget = nil
+	return m, failure.Cmd("Snooze TODO", func() (tea.Msg, error) {
+		if err := m.Store.UpdateTodo(m.RepoPath, t); err != nil {
+			return nil, err
+		}
+		return TodoSavedMsg{}, nil
+	})
+}
+
+// ViewSnooze renders the snooze date picker.
+func (m Model) ViewSnooze() string {
+	return m.DatePicker.View()
+}
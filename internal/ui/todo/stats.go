@@ -0,0 +1,91 @@
+package todo
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/claude"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/todo"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+	"github.com/mattn/go-runewidth"
+)
+
+// UpdateStats handles input for the usage stats.
+func (m Model) UpdateStats(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	kb := m.Config.Keys()
+	if config.MatchesAny(key, kb.Global.Quit, kb.Global.QuitAlt) {
+		m.Views.Pop()
+	}
+	return m, nil
+}
+
+// ViewStats renders what the runs of each todo used, the most expensive
+// first, so the ones burning the budget stand out.
+func (m Model) ViewStats() string {
+	var b strings.Builder
+	kb := m.Config.Keys()
+	width := max(m.Width-10, 30)
+
+	b.WriteString(styles.Title.Render("  AI Usage"))
+	b.WriteString("\n")
+	b.WriteString(styles.Help.Render("─────────────────────────────────────────────────────"))
+	b.WriteString("\n\n")
+
+	var used []todo.Todo
+	var total todo.Usage
+	for _, t := range m.All {
+		if t.Usage == nil {
+			continue
+		}
+		used = append(used, t)
+		total.Runs += t.Usage.Runs
+		total.Tokens += t.Usage.Tokens
+		total.Cost += t.Usage.Cost
+	}
+	slices.SortStableFunc(used, func(a, b todo.Todo) int {
+		return cmp.Or(cmp.Compare(b.Usage.Cost, a.Usage.Cost), cmp.Compare(b.Usage.Tokens, a.Usage.Tokens))
+	})
+
+	if len(used) == 0 {
+		b.WriteString(styles.Help.Render("  No runs recorded yet; running a TODO's prompts records what they use"))
+		b.WriteString("\n\n")
+	} else {
+		b.WriteString(styles.Label.Render("Total: "))
+		b.WriteString(styles.Value.Render(fmt.Sprintf("%s tokens • %s in %s of %d TODOs",
+			claude.FormatTokens(total.Tokens), claude.FormatCost(total.Cost), runCount(total.Runs), len(used))))
+		b.WriteString("\n\n")
+
+		rows := max(m.Height-12, 5)
+		for i, t := range used {
+			if i == rows {
+				b.WriteString(styles.Help.Render(fmt.Sprintf("  … %d more", len(used)-i)))
+				b.WriteString("\n")
+				break
+			}
+			name := runewidth.Truncate(t.Name, width-36, "...")
+			b.WriteString(styles.Value.Render(fmt.Sprintf("  %8s", claude.FormatCost(t.Usage.Cost))))
+			b.WriteString(styles.Help.Render(fmt.Sprintf("  %7s tokens  %-8s  ", claude.FormatTokens(t.Usage.Tokens), runCount(t.Usage.Runs))))
+			b.WriteString(styles.Selected.Render(name))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+		b.WriteString(styles.Help.Render("  Costs are estimated from list prices"))
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString(styles.Help.Render(fmt.Sprintf("%s back", kb.Global.Quit)))
+	return b.String()
+}
+
+// runCount formats a number of runs, e.g. "2 runs".
+func runCount(n int) string {
+	if n == 1 {
+		return "1 run"
+	}
+	return fmt.Sprintf("%d runs", n)
+}
@@ -0,0 +1,426 @@
+package todo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ihatemodels/gdev/internal/ai"
+	"github.com/ihatemodels/gdev/internal/budget"
+	"github.com/ihatemodels/gdev/internal/claude"
+	"github.com/ihatemodels/gdev/internal/config"
+	"github.com/ihatemodels/gdev/internal/git"
+	"github.com/ihatemodels/gdev/internal/ui/failure"
+	"github.com/ihatemodels/gdev/internal/ui/styles"
+)
+
+// recapOutput is how much of the end of each prompt's output the recap is
+// written from.
+const recapOutput = 4000
+
+// runSummary is what a run of a todo's prompts did.
+type runSummary struct {
+	Todo     string
+	Prompts  []string
+	Status   []runStatus
+	Outputs  []string
+	Started  time.Time
+	Duration time.Duration
+
+	Files    []git.FileChange // changed between the start and the end
+	FilesErr error
+
+	Estimate claude.Usage // made before the run
+	Usage    claude.Usage // of the claude sessions the prompts ran in
+	UsageErr error
+
+	Checkpoints   []checkpoint
+	CheckpointErr error
+	Cursor        int // the selected checkpoint
+
+	Recap     string // written by the AI backend
+	RecapErr  error
+	Recapping bool
+	FellBack  string // which backend wrote the recap instead, and why (see ai.FallbackNote)
+
+	Exported string // path the summary was last exported to
+}
+
+// Message types of the run summary
+type (
+	// RunRecapMsg carries the recap of a run written by the AI backend.
+	RunRecapMsg struct {
+		Recap    string
+		FellBack string
+		Err      error
+	}
+
+	// RunSavedMsg signals that the summary, or another entry, was added to
+	// the todo's activity log.
+	RunSavedMsg struct{}
+
+	// RunExportedMsg signals that the summary was written to Path.
+	RunExportedMsg struct {
+		Path string
+	}
+)
+
+// finishRun shows the summary of the run once its last prompt has been
+// handled, and asks the AI backend for a recap of it. The summary goes to
+// the todo's activity log when the recap is done.
+func (m *Model) finishRun() tea.Cmd {
+	s := runSummary{
+		Todo:     m.RunTodo.Name,
+		Prompts:  m.RunTodo.Prompts,
+		Status:   m.RunStatus,
+		Outputs:  m.RunOutputs,
+		Started:  m.RunStarted,
+		Duration: time.Since(m.RunStarted).Round(time.Second),
+
+		Checkpoints:   m.RunCheckpoints,
+		CheckpointErr: m.RunCheckpointErr,
+
+		Estimate: m.RunEstimate,
+	}
+	s.Usage, s.UsageErr = m.runUsage()
+	if s.UsageErr == nil && s.Usage.Total() > 0 {
+		// Saved with the summary
+		m.RunTodo.AddUsage(s.Usage.Total(), s.Usage.Cost)
+	}
+	// Prompts that ended are in the usage log already
+	m.recordRunUsage()
+	if m.RunBase == "" {
+		s.FilesErr = errors.New("the working tree couldn't be recorded when the run started")
+	} else if end, err := git.Snapshot(m.RepoPath); err != nil {
+		s.FilesErr = err
+	} else {
+		s.Files, s.FilesErr = git.ChangedFiles(m.RepoPath, m.RunBase, end)
+	}
+	m.Views.Push(RunSummaryView)
+
+	backend, err := budget.Backend(m.Config)
+	if err == nil {
+		err = backend.Check()
+	}
+	if err != nil {
+		s.RecapErr = err
+		m.Summary = s
+		return m.saveSummary()
+	}
+	s.Recapping = true
+	m.Summary = s
+
+	ctx, prompt := m.Config.Context(), s.recapPrompt()
+	return func() tea.Msg {
+		var out bytes.Buffer
+		err := backend.Generate(ctx, prompt, &out)
+		return RunRecapMsg{Recap: ai.Result(out.String()), FellBack: ai.FallbackNote(out.String()), Err: err}
+	}
+}
+
+// runUsage sums the usage of the claude sessions the run's prompts ran in.
+func (m Model) runUsage() (claude.Usage, error) {
+	var u claude.Usage
+	for _, id := range m.RunSessions {
+		su, err := claude.SessionUsage(m.RepoPath, id)
+		if errors.Is(err, fs.ErrNotExist) {
+			// claude failed before starting it, or only printed it in a dry run
+			continue
+		}
+		if err != nil {
+			return u, err
+		}
+		u.Add(su)
+	}
+	return u, nil
+}
+
+// handleRunRecap adds the recap to the summary and saves it.
+func (m Model) handleRunRecap(msg RunRecapMsg) (tea.Model, tea.Cmd) {
+	m.Summary.Recapping = false
+	m.Summary.Recap = msg.Recap
+	m.Summary.RecapErr = msg.Err
+	m.Summary.FellBack = msg.FellBack
+	return m, m.saveSummary()
+}
+
+// saveSummary adds the summary to the activity log of the todo that ran.
+func (m *Model) saveSummary() tea.Cmd {
+	return m.saveActivity(m.Summary.title(), m.Summary.markdown())
+}
+
+// saveActivity adds an entry to the activity log of the todo that ran.
+func (m *Model) saveActivity(title, text string) tea.Cmd {
+	if m.RunTodo == nil {
+		return nil
+	}
+	m.RunTodo.AddActivity(title, text)
+	t := *m.RunTodo
+	s, repo := m.Store, m.RepoPath
+	return failure.Cmd("Save TODO activity", func() (tea.Msg, error) {
+		if err := s.UpdateTodo(repo, &t); err != nil {
+			return nil, err
+		}
+		return RunSavedMsg{}, nil
+	})
+}
+
+// exportSummary writes the summary as markdown to ~/.gdev/exports.
+func (m Model) exportSummary() tea.Cmd {
+	data := []byte(m.Summary.markdown())
+	name := "run-" + m.Summary.Started.Format("20060102-150405") + ".md"
+	s := m.Store
+	return failure.Cmd("Export run summary", func() (tea.Msg, error) {
+		exports, err := s.SubDir("exports")
+		if err != nil {
+			return nil, err
+		}
+		if err := exports.Write(name, data); err != nil {
+			return nil, err
+		}
+		return RunExportedMsg{Path: filepath.Join(exports.Path(), name)}, nil
+	})
+}
+
+// UpdateRunSummary handles input for the run summary.
+func (m Model) UpdateRunSummary(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	kb := m.Config.Keys()
+
+	switch {
+	case config.MatchesAny(key, kb.Global.Quit, kb.Global.QuitAlt):
+		// The recap, if still being written, is saved when it arrives
+		m.Views.Pop()
+
+	case config.Matches(key, kb.Detail.Export):
+		if !m.Summary.Recapping {
+			return m, m.exportSummary()
+		}
+
+	case len(m.Summary.Checkpoints) == 0:
+		// The rest act on the selected checkpoint
+
+	case config.MatchesAny(key, kb.Global.MoveUp, kb.Global.MoveUpAlt):
+		m.Summary.Cursor = max(m.Summary.Cursor-1, 0)
+
+	case config.MatchesAny(key, kb.Global.MoveDown, kb.Global.MoveDownAlt):
+		m.Summary.Cursor = min(m.Summary.Cursor+1, len(m.Summary.Checkpoints)-1)
+
+	case config.Matches(key, kb.List.Select):
+		return m.openCheckpointDiff()
+
+	case config.Matches(key, kb.Detail.Rollback):
+		m.openRollback()
+	}
+	return m, nil
+}
+
+// ViewRunSummary renders what the run did.
+func (m Model) ViewRunSummary() string {
+	var b strings.Builder
+	s := m.Summary
+	kb := m.Config.Keys()
+	width := max(m.Width-10, 30)
+
+	b.WriteString(styles.Title.Render("  Run Summary"))
+	b.WriteString(styles.Help.Render("  " + s.Todo))
+	b.WriteString("\n")
+	b.WriteString(styles.Help.Render("─────────────────────────────────────────────────────"))
+	b.WriteString("\n\n")
+
+	b.WriteString(styles.Label.Render("Prompts: ") + styles.Value.Render(s.describeStatus()))
+	b.WriteString("\n")
+	b.WriteString(styles.Label.Render("Duration: ") + styles.Value.Render(s.Duration.String()))
+	b.WriteString("\n")
+	b.WriteString(styles.Label.Render("Usage: "))
+	if s.UsageErr != nil {
+		b.WriteString(styles.Error.Render(s.UsageErr.Error()))
+	} else {
+		b.WriteString(styles.Value.Render(s.describeUsage()))
+	}
+	b.WriteString("\n\n")
+	b.WriteString(viewRunPrompts(s.Prompts, s.Status, -1, width))
+
+	b.WriteString("\n")
+	b.WriteString(styles.Label.Render("Files changed:"))
+	b.WriteString("\n")
+	switch {
+	case s.FilesErr != nil:
+		b.WriteString("  " + styles.Error.Render(s.FilesErr.Error()))
+		b.WriteString("\n")
+	case len(s.Files) == 0:
+		b.WriteString(styles.Help.Render("  (none)"))
+		b.WriteString("\n")
+	}
+	for i, f := range s.Files {
+		if i == summaryLines {
+			b.WriteString(styles.Help.Render(fmt.Sprintf("  … %d more files", len(s.Files)-i)))
+			b.WriteString("\n")
+			break
+		}
+		b.WriteString("  " + styles.Value.Render(f.Path))
+		if f.Binary {
+			b.WriteString(styles.Help.Render(" binary"))
+		} else {
+			b.WriteString(" " + lipgloss.NewStyle().Foreground(styles.Green).Render(fmt.Sprintf("+%d", f.Additions)))
+			b.WriteString(" " + lipgloss.NewStyle().Foreground(styles.Red).Render(fmt.Sprintf("-%d", f.Deletions)))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(s.Checkpoints) > 0 || s.CheckpointErr != nil {
+		b.WriteString("\n")
+		b.WriteString(s.viewCheckpoints())
+	}
+
+	b.WriteString("\n")
+	b.WriteString(styles.Label.Render("Recap:"))
+	b.WriteString("\n")
+	switch {
+	case s.Recapping:
+		b.WriteString(styles.Help.Render("  Writing a recap..."))
+	case s.RecapErr != nil:
+		b.WriteString("  " + styles.Error.Render("No recap: "+s.RecapErr.Error()))
+	default:
+		b.WriteString(lipgloss.NewStyle().Width(width).PaddingLeft(2).Render(styles.Value.Render(s.Recap)))
+		if s.FellBack != "" {
+			b.WriteString("\n" + styles.Help.Render("  Written by "+s.FellBack))
+		}
+	}
+	b.WriteString("\n\n")
+
+	if s.Exported != "" {
+		b.WriteString(styles.Selected.Render("✓ Exported to " + s.Exported))
+		b.WriteString("\n")
+	}
+	if len(s.Checkpoints) > 0 {
+		b.WriteString(styles.Help.Render(fmt.Sprintf("↑/↓ checkpoint • %s changes since it • %s roll back to it",
+			kb.List.Select, kb.Detail.Rollback)))
+		b.WriteString("\n")
+	}
+	if s.Recapping {
+		b.WriteString(styles.Help.Render(fmt.Sprintf("%s close", kb.Global.Quit)))
+	} else {
+		b.WriteString(styles.Help.Render(fmt.Sprintf("Saved to the TODO's activity • %s export markdown • %s close",
+			kb.Detail.Export, kb.Global.Quit)))
+	}
+	return b.String()
+}
+
+// count returns how many prompts ended with status.
+func (s runSummary) count(status runStatus) int {
+	n := 0
+	for _, st := range s.Status {
+		if st == status {
+			n++
+		}
+	}
+	return n
+}
+
+// title returns the title of the summary in the activity log.
+func (s runSummary) title() string {
+	return fmt.Sprintf("Ran %d of %d prompts", len(s.Status)-s.count(runSkipped), len(s.Status))
+}
+
+// describeStatus returns how the prompts ended, e.g. "2 done, 1 skipped".
+func (s runSummary) describeStatus() string {
+	var parts []string
+	for _, st := range []runStatus{runDone, runFailed, runCanceled, runSkipped} {
+		if n := s.count(st); n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, st))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// describeUsage returns the tokens and cost of the run next to the
+// estimate, e.g. "52.3k tokens • $0.21 (estimated at least 21.0k • $0.07)".
+func (s runSummary) describeUsage() string {
+	return fmt.Sprintf("%s tokens • %s (estimated at least %s • %s)",
+		claude.FormatTokens(s.Usage.Total()), claude.FormatCost(s.Usage.Cost),
+		claude.FormatTokens(s.Estimate.Total()), claude.FormatCost(s.Estimate.Cost))
+}
+
+// recapPrompt asks for a recap of the run from its prompts, what they
+// wrote and the files they changed.
+func (s runSummary) recapPrompt() string {
+	var b strings.Builder
+	b.WriteString("Claude Code ran the prompts below one after another in a git repository. ")
+	b.WriteString("Write a short recap of what the run did for the developer: what changed, what failed or was left undone, and anything to check before committing. ")
+	b.WriteString("Output only the recap, in a few sentences or bullet points.\n\n")
+	for i, p := range s.Prompts {
+		fmt.Fprintf(&b, "## Prompt %d (%s)\n\n%s\n\n", i+1, s.Status[i], strings.TrimSpace(p))
+		if out := s.Outputs[i]; out != "" {
+			if len(out) > recapOutput {
+				out = "…" + out[len(out)-recapOutput:]
+			}
+			fmt.Fprintf(&b, "Output:\n\n%s\n\n", out)
+		}
+	}
+	b.WriteString("## Files changed\n\n")
+	b.WriteString(s.markdownFiles())
+	return b.String()
+}
+
+// markdown returns the summary as a markdown document.
+func (s runSummary) markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s: %s\n\n", s.title(), s.Todo)
+	fmt.Fprintf(&b, "Started %s, took %s.\n\n", s.Started.Format("Mon, Jan 2 2006 15:04"), s.Duration)
+	if s.UsageErr != nil {
+		fmt.Fprintf(&b, "Usage unknown: %s.\n\n", s.UsageErr)
+	} else {
+		fmt.Fprintf(&b, "Used %s.\n\n", s.describeUsage())
+	}
+
+	b.WriteString("## Prompts\n\n")
+	for i, p := range s.Prompts {
+		first := strings.Split(strings.TrimSpace(p), "\n")[0]
+		fmt.Fprintf(&b, "%d. %s (%s)\n", i+1, first, s.Status[i])
+	}
+
+	b.WriteString("\n## Files changed\n\n")
+	b.WriteString(s.markdownFiles())
+
+	if len(s.Checkpoints) > 0 || s.CheckpointErr != nil {
+		b.WriteString("\n## Checkpoints\n\n")
+		b.WriteString(s.markdownCheckpoints())
+	}
+
+	b.WriteString("\n## Recap\n\n")
+	if s.RecapErr != nil {
+		fmt.Fprintf(&b, "No recap: %s\n", s.RecapErr)
+	} else {
+		b.WriteString(s.Recap + "\n")
+		if s.FellBack != "" {
+			fmt.Fprintf(&b, "\n_Written by %s_\n", s.FellBack)
+		}
+	}
+	return b.String()
+}
+
+// markdownFiles lists the changed files in markdown.
+func (s runSummary) markdownFiles() string {
+	if s.FilesErr != nil {
+		return fmt.Sprintf("Unknown: %s\n", s.FilesErr)
+	}
+	if len(s.Files) == 0 {
+		return "None.\n"
+	}
+	var b strings.Builder
+	for _, f := range s.Files {
+		if f.Binary {
+			fmt.Fprintf(&b, "- `%s` (binary)\n", f.Path)
+		} else {
+			fmt.Fprintf(&b, "- `%s` +%d -%d\n", f.Path, f.Additions, f.Deletions)
+		}
+	}
+	return b.String()
+}
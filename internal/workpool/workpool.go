@@ -0,0 +1,59 @@
+// Package workpool runs independent jobs, such as AI calls, concurrently on a
+// bounded number of goroutines.
+package workpool
+
+import (
+	"context"
+	"sync"
+)
+
+// Result is the outcome of one job.
+type Result struct {
+	Index  int // of the job, in the order given to Run
+	Output string
+	Err    error
+}
+
+// Run runs fn for jobs 0 to n-1 on at most workers goroutines and sends each
+// result as it finishes. The channel is closed once every job has finished.
+//
+// Canceling ctx stops jobs that haven't started yet; running jobs see the
+// canceled ctx, and their results are still sent. Results are buffered, so
+// the jobs finish even if nothing reads them.
+func Run(ctx context.Context, workers, n int, fn func(ctx context.Context, i int) (string, error)) <-chan Result {
+	results := make(chan Result, n)
+	jobs := make(chan int)
+	workers = max(min(workers, n), 1)
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				output, err := fn(ctx, i)
+				results <- Result{Index: i, Output: output, Err: err}
+			}
+		}()
+	}
+
+	go func() {
+		defer func() {
+			close(jobs)
+			wg.Wait()
+			close(results)
+		}()
+		for i := range n {
+			if ctx.Err() != nil {
+				return
+			}
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return results
+}
@@ -0,0 +1,55 @@
+package workpool
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	var running, peak atomic.Int32
+	results := Run(context.Background(), 3, 10, func(ctx context.Context, i int) (string, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+		}
+		time.Sleep(5 * time.Millisecond)
+		return string(rune('a' + i)), nil
+	})
+
+	seen := make(map[int]string)
+	for r := range results {
+		seen[r.Index] = r.Output
+	}
+	if len(seen) != 10 || seen[2] != "c" {
+		t.Errorf("results = %v; want all 10 jobs", seen)
+	}
+	if peak.Load() > 3 {
+		t.Errorf("%d jobs ran at once; want at most 3", peak.Load())
+	}
+}
+
+func TestRunCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	results := Run(ctx, 2, 10, func(ctx context.Context, i int) (string, error) {
+		if i == 0 {
+			return "done", nil
+		}
+		cancel()
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+
+	var done, canceled int
+	for r := range results {
+		if r.Err != nil {
+			canceled++
+		} else {
+			done++
+		}
+	}
+	if done != 1 || done+canceled == 10 {
+		t.Errorf("got %d done and %d canceled; want the first kept and the rest skipped", done, canceled)
+	}
+}
@@ -1,20 +1,46 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"slices"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ihatemodels/gdev/internal/askpass"
 	"github.com/ihatemodels/gdev/internal/config"
 	"github.com/ihatemodels/gdev/internal/git"
+	"github.com/ihatemodels/gdev/internal/instance"
+	"github.com/ihatemodels/gdev/internal/network"
 	"github.com/ihatemodels/gdev/internal/store"
+	"github.com/ihatemodels/gdev/internal/todo"
 	"github.com/ihatemodels/gdev/internal/ui/app"
 	"github.com/ihatemodels/gdev/internal/ui/styles"
+	"github.com/ihatemodels/gdev/internal/ui/terminal"
 )
 
 var Version = "dev"
 
+// dryRunFlag shows mutating operations instead of performing them.
+const dryRunFlag = "--dry-run"
+
+// readOnlyFlag refuses mutating operations, see store.SetReadOnly.
+const readOnlyFlag = "--read-only"
+
+// debugFlag enables diagnostics of gdev itself, see config.SetDebug.
+const debugFlag = "--debug"
+
 func main() {
+	// git and ssh run gdev as the askpass helper to ask for credentials
+	if askpass.IsHelper(os.Args[0]) {
+		runAskpass(os.Getenv(askpass.EnvSocket), strings.Join(os.Args[1:], " "))
+		return
+	}
+
 	startView := parseArgs()
 	if startView < 0 {
 		return
@@ -26,6 +52,7 @@ func main() {
 		fmt.Printf("%v\n", err)
 		os.Exit(1)
 	}
+	s.SetDryRun(slices.Contains(os.Args[1:], dryRunFlag))
 
 	cfg, err := config.Load(s)
 	if err != nil {
@@ -33,6 +60,17 @@ func main() {
 		fmt.Printf("%v\n", err)
 		os.Exit(1)
 	}
+	s.SetReadOnly(slices.Contains(os.Args[1:], readOnlyFlag) || cfg.Settings.ReadOnly)
+	cfg.SetDebug(slices.Contains(os.Args[1:], debugFlag))
+	if err := network.Apply(cfg.Settings); err != nil {
+		fail("failed to apply network settings", err)
+	}
+
+	// Best effort: files over the retention limits are removed next time
+	if !s.DryRun() && !s.ReadOnly() {
+		jobs, _ := s.GetJobs()
+		_, _ = cfg.Cleanup(terminal.LogInUse(jobs))
+	}
 
 	ri := loadRepoInfo(s)
 
@@ -42,21 +80,147 @@ func main() {
 		os.Exit(1)
 	}
 
-	p := tea.NewProgram(app.New(s, cfg, ri, Version, startView), tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+	// With single_instance, a gdev already running in the repository is
+	// sent the command line instead
+	var srv *instance.Server
+	if cfg.Settings.SingleInstance && ri != nil {
+		srv = listenInstance(s, ri.Repo.Root)
+	}
+
+	// Commands still running when gdev exits are stopped with the context
+	ctx, cancel := context.WithCancel(context.Background())
+	cfg.SetContext(ctx)
+
+	p := tea.NewProgram(app.New(s, cfg, ri, Version, startView), tea.WithAltScreen(), tea.WithMouseCellMotion(), tea.WithContext(ctx))
+	if srv != nil {
+		go func() {
+			for r := range srv.Requests() {
+				p.Send(app.ForwardedMsg{Request: r})
+			}
+		}()
+	}
+	final, err := p.Run()
+	if srv != nil {
+		srv.Close()
+	}
+	if am, ok := final.(app.Model); ok && am.Detached() {
+		jobs := terminal.Jobs()
+		terminal.Detach()
+		saveDetached(s, jobs)
+		printDetached(jobs, cfg.Keys().Global.Jobs)
+		return
+	}
+	cancel()
+	terminal.Wait(2 * time.Second)
+	if err != nil {
 		fmt.Printf("Error: %v", err)
 		os.Exit(1)
 	}
 }
 
+// printDetached lists the commands left running when gdev quit, and where
+// their output goes.
+func printDetached(jobs []terminal.Job, jobsKey string) {
+	fmt.Printf("%d commands are still running:\n", len(jobs))
+	for _, j := range jobs {
+		fmt.Printf("  %s (pid %d)\n", j.Title, j.Pid)
+		fmt.Println(styles.Dim.Render("    output: " + j.Log))
+	}
+	fmt.Printf("Run gdev again and press %s on the main menu to follow them.\n", jobsKey)
+}
+
+// saveDetached records the commands left running, so the next run of gdev
+// can show their output and whether they finished.
+func saveDetached(s *store.Store, jobs []terminal.Job) {
+	records := make([]store.Job, 0, len(jobs))
+	for _, j := range jobs {
+		records = append(records, store.Job{
+			Title:   j.Title,
+			Command: j.Command,
+			Dir:     j.Dir,
+			Log:     j.Log,
+			Pid:     j.Pid,
+			Started: j.Started,
+		})
+	}
+	if err := s.AddJobs(records...); err != nil {
+		fmt.Println(styles.Error.Render("Warning: failed to save the running commands: " + err.Error()))
+	}
+}
+
+// listenInstance starts the server of the gdev running in the repository at
+// root. If one is running there already, the command line is sent to it
+// and gdev exits with its reply.
+func listenInstance(s *store.Store, root string) *instance.Server {
+	socket, err := s.InstanceSocket(root)
+	if err != nil {
+		fmt.Println(styles.Error.Render("Warning: failed to check for a running gdev: " + err.Error()))
+		return nil
+	}
+	srv, err := instance.Listen(socket)
+	if errors.Is(err, instance.ErrRunning) {
+		reply, err := instance.Send(socket, commandArgs())
+		if err != nil {
+			fail("failed to forward the command", err)
+		}
+		fmt.Println(reply)
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Println(styles.Error.Render("Warning: failed to check for a running gdev: " + err.Error()))
+		return nil
+	}
+	return srv
+}
+
+// runAskpass forwards a credential prompt to the running gdev and prints the answer.
+func runAskpass(socket, prompt string) {
+	if socket == "" {
+		fmt.Fprintf(os.Stderr, "gdev: %s is only run by commands gdev starts\n", askpass.Helper)
+		os.Exit(1)
+	}
+	answer, err := askpass.Ask(socket, prompt)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gdev: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(answer)
+}
+
+// commandArgs returns the command line without the program name and flags.
+func commandArgs() []string {
+	return slices.DeleteFunc(slices.Clone(os.Args[1:]), func(arg string) bool {
+		return arg == dryRunFlag || arg == readOnlyFlag || arg == debugFlag
+	})
+}
+
 func parseArgs() app.View {
-	if len(os.Args) <= 1 {
+	args := commandArgs()
+	if len(args) == 0 {
 		return app.MainMenuView
 	}
 
-	switch os.Args[1] {
+	switch args[0] {
 	case "todo", "todos":
 		return app.TodosView
+	case "config":
+		runConfig(args[1:])
+		return -1
+	case "doctor":
+		runDoctor()
+		return -1
+	case "cleanup":
+		runCleanup()
+		return -1
+	case "capture":
+		runCapture(args[1:])
+		return -1
+	case terminal.RelayCommand:
+		// Not listed in the help: run by terminal.Detach
+		if len(args) == 2 {
+			_ = terminal.Relay(args[1])
+		}
+		return -1
 	case "help", "--help", "-h":
 		printHelp()
 		return -1
@@ -69,15 +233,226 @@ func parseArgs() app.View {
 }
 
 func printHelp() {
-	fmt.Println("Usage: gdev [command]")
+	fmt.Println("Usage: gdev [--dry-run] [--read-only] [--debug] [command]")
 	fmt.Println()
 	fmt.Println("Commands:")
-	fmt.Println("  todo    Start directly in TODO management")
-	fmt.Println("  help    Show this help message")
+	fmt.Println("  todo                   Start directly in TODO management")
+	fmt.Println("  config export [file]   Write keybindings and settings to a file (default: stdout)")
+	fmt.Println("  config import [file]   Replace keybindings and settings from a file (default: stdin)")
+	fmt.Println("  doctor                 Check proxy and certificate settings and API connectivity")
+	fmt.Println("  cleanup                Delete logs and other files beyond the retention settings")
+	fmt.Println("  capture <name>         Add a TODO on the current branch")
+	fmt.Println("  help                   Show this help message")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  --dry-run    Show git, gh and file changes instead of making them")
+	fmt.Println("  --read-only  Browse without committing, pushing, editing TODOs or changing files")
+	fmt.Println("  --debug      Let the diagnostics key show how long updates and rendering take")
 	fmt.Println()
 	fmt.Println("Run without arguments to show the main menu.")
 }
 
+// runConfig runs the config export and import commands.
+func runConfig(args []string) {
+	if len(args) == 0 || (args[0] != "export" && args[0] != "import") {
+		fmt.Println("Usage: gdev config export [file] | gdev config import [file]")
+		os.Exit(1)
+	}
+
+	s, cfg := loadConfig()
+
+	file := "-"
+	if len(args) > 1 {
+		file = args[1]
+	}
+
+	switch args[0] {
+	case "export":
+		data, err := cfg.Export()
+		if err != nil {
+			fail("failed to export config", err)
+		}
+		data = append(data, '\n')
+		if file == "-" {
+			os.Stdout.Write(data)
+			return
+		}
+		if s.DryRun() {
+			fmt.Printf("Dry run: would write %s (%d bytes)\n", file, len(data))
+			return
+		}
+		if err := os.WriteFile(file, data, 0644); err != nil {
+			fail("failed to export config", err)
+		}
+		fmt.Printf("Exported config to %s\n", file)
+
+	case "import":
+		var data []byte
+		var err error
+		if file == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(file)
+		}
+		if err != nil {
+			fail("failed to read config bundle", err)
+		}
+		if err := cfg.Import(data); err != nil {
+			fail("failed to import config", err)
+		}
+		if plan := s.TakePlan(); len(plan) > 0 {
+			fmt.Println("Dry run: would")
+			for _, action := range plan {
+				fmt.Println("  " + action)
+			}
+			return
+		}
+		fmt.Printf("Imported config into %s\n", s.Path())
+	}
+}
+
+// runDoctor shows the network settings in effect and checks that the APIs
+// gdev's integrations use can be reached with them.
+func runDoctor() {
+	_, cfg := loadConfig()
+	st := cfg.Settings
+	if err := network.Apply(st); err != nil {
+		fail("failed to apply network settings", err)
+	}
+
+	fmt.Println(styles.Title.Render("Network"))
+	for _, name := range []string{"HTTPS_PROXY", "HTTP_PROXY", "NO_PROXY", "SSL_CERT_FILE", "NODE_EXTRA_CA_CERTS", "GIT_SSL_CAINFO"} {
+		value := os.Getenv(name)
+		if value == "" {
+			value = os.Getenv(strings.ToLower(name))
+		}
+		if value == "" {
+			value = styles.Dim.Render("not set")
+		}
+		fmt.Printf("  %-20s %s\n", name, value)
+	}
+	fmt.Println()
+
+	fmt.Println(styles.Title.Render("Connectivity"))
+	client, err := network.Client(st.CABundle)
+	if err != nil {
+		fail("failed to load ca_bundle", err)
+	}
+	failed := false
+	for _, url := range network.Endpoints {
+		c := network.CheckEndpoint(client, url)
+		via := "direct"
+		if c.Proxy != "" {
+			via = "via " + c.Proxy
+		}
+		if c.Err != nil {
+			failed = true
+			fmt.Printf("  %s %-28s %s (%s)\n", styles.Error.Render("✗"), url, c.Err, via)
+			continue
+		}
+		fmt.Printf("  %s %-28s %s, %s, %s\n", styles.Selected.Render("✓"), url, c.Status, via, c.Elapsed.Round(time.Millisecond))
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// runCleanup applies the retention settings and reports the space reclaimed.
+func runCleanup() {
+	s, cfg := loadConfig()
+
+	jobs, _ := s.GetJobs()
+	reclaimed, err := cfg.Cleanup(terminal.LogInUse(jobs))
+	if err != nil {
+		fail("failed to clean up", err)
+	}
+
+	verb := "Removed"
+	if s.DryRun() {
+		verb = "Would remove"
+	}
+	var files int
+	var bytes int64
+	for _, r := range reclaimed {
+		fmt.Printf("  %-10s %s, %s\n", r.Dir, fileCount(r.Files), config.FormatSize(r.Bytes))
+		files += r.Files
+		bytes += r.Bytes
+	}
+	fmt.Printf("%s %s, reclaiming %s\n", verb, fileCount(files), config.FormatSize(bytes))
+}
+
+// runCapture adds a TODO to the repository's list. A gdev running in the
+// repository with single_instance adds it, so the list is written by one
+// process only.
+func runCapture(args []string) {
+	name := strings.TrimSpace(strings.Join(args, " "))
+	if name == "" {
+		fmt.Println("Usage: gdev capture <name>")
+		os.Exit(1)
+	}
+
+	s, _ := loadConfig()
+	repo, err := git.GetRepo()
+	if err != nil {
+		fail("not in a git repository", err)
+	}
+
+	if socket, err := s.InstanceSocket(repo.Root); err == nil {
+		reply, err := instance.Send(socket, []string{"capture", name})
+		switch {
+		case err == nil:
+			fmt.Println(reply)
+			return
+		case !errors.Is(err, instance.ErrNotRunning):
+			fail("failed to capture the TODO", err)
+		}
+	}
+
+	if err := s.AddTodo(repo.Root, todo.NewTodo(repo.Branch, name, "", nil)); err != nil {
+		fail("failed to capture the TODO", err)
+	}
+	if plan := s.TakePlan(); len(plan) > 0 {
+		fmt.Println("Dry run: would")
+		for _, action := range plan {
+			fmt.Println("  " + action)
+		}
+		return
+	}
+	fmt.Printf("Added TODO %q\n", name)
+}
+
+// fileCount formats a number of files, e.g. "2 files".
+func fileCount(n int) string {
+	if n == 1 {
+		return "1 file"
+	}
+	return fmt.Sprintf("%d files", n)
+}
+
+// loadConfig loads the store and configuration for a command, in dry-run
+// or read-only mode if asked for, exiting on failure.
+func loadConfig() (*store.Store, *config.Config) {
+	s, err := store.New()
+	if err != nil {
+		fail("failed to initialize store", err)
+	}
+	s.SetDryRun(slices.Contains(os.Args[1:], dryRunFlag))
+
+	cfg, err := config.Load(s)
+	if err != nil {
+		fail("failed to load config", err)
+	}
+	s.SetReadOnly(slices.Contains(os.Args[1:], readOnlyFlag) || cfg.Settings.ReadOnly)
+	return s, cfg
+}
+
+// fail prints an error and exits.
+func fail(msg string, err error) {
+	fmt.Println(styles.Error.Render("Error: " + msg))
+	fmt.Printf("%v\n", err)
+	os.Exit(1)
+}
+
 func loadRepoInfo(s *store.Store) *app.RepoInfo {
 	repo, err := git.GetRepo()
 	if err != nil {